@@ -10,6 +10,10 @@ import (
 // Configuration defines
 type Configuration struct {
 	BucketName         string
+	BucketType         string
+	BucketRegion       string
+	AWSKey             string
+	AWSSecret          string
 	DatabaseName       string
 	MongoDBURI         string
 	MongoDBDialTimeout time.Duration
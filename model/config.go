@@ -20,6 +20,11 @@ type CedarConfig struct {
 	Slack  SlackConfig               `bson:"slack" json:"slack" yaml:"slack"`
 	Flags  OperationalFlags          `bson:"flags" json:"flags" yaml:"flags"`
 
+	PerfRollups PerfRollupsConfig `bson:"perf_rollups" json:"perf_rollups" yaml:"perf_rollups"`
+	PerfNoise   PerfNoiseConfig   `bson:"perf_noise" json:"perf_noise" yaml:"perf_noise"`
+
+	PerfRegressions PerfRegressionsConfig `bson:"perf_regressions" json:"perf_regressions" yaml:"perf_regressions"`
+
 	populated bool
 	env       cedar.Environment
 }
@@ -39,6 +44,11 @@ var (
 	cedarConfigurationSplunkKey = bsonutil.MustHaveTag(CedarConfig{}, "Splunk")
 	cedarConfigurationSlackKey  = bsonutil.MustHaveTag(CedarConfig{}, "Slack")
 	cedarConfigurationFlagsKey  = bsonutil.MustHaveTag(CedarConfig{}, "Flags")
+
+	cedarConfigurationPerfRollupsKey = bsonutil.MustHaveTag(CedarConfig{}, "PerfRollups")
+	cedarConfigurationPerfNoiseKey   = bsonutil.MustHaveTag(CedarConfig{}, "PerfNoise")
+
+	cedarConfigurationPerfRegressionsKey = bsonutil.MustHaveTag(CedarConfig{}, "PerfRegressions")
 )
 
 type SlackConfig struct {
@@ -53,6 +63,196 @@ var (
 	cedarSlackConfigLevelKey   = bsonutil.MustHaveTag(SlackConfig{}, "Level")
 )
 
+// PerfRollupsConfig controls how rollups are calculated from the
+// timeseries data of performance results.
+type PerfRollupsConfig struct {
+	// MetricNames renames metrics that do not have a default
+	// rollup calculation. Metrics without a mapping use their
+	// FTDC key as the name.
+	MetricNames []PerfMetricName `bson:"metric_names" json:"metric_names" yaml:"metric_names"`
+
+	// RecalculationBatchSize limits the number of results whose
+	// rollups are recalculated by each run of the scheduled
+	// recalculation job, after a calculator version changes.
+	RecalculationBatchSize int `bson:"recalculation_batch_size" json:"recalculation_batch_size" yaml:"recalculation_batch_size"`
+
+	// DerivedRollups are calculated from the other rollups of a
+	// result whenever its default rollups are calculated.
+	DerivedRollups []PerfDerivedRollup `bson:"derived_rollups" json:"derived_rollups" yaml:"derived_rollups"`
+}
+
+var (
+	cedarPerfRollupsConfigMetricNamesKey            = bsonutil.MustHaveTag(PerfRollupsConfig{}, "MetricNames")
+	cedarPerfRollupsConfigRecalculationBatchSizeKey = bsonutil.MustHaveTag(PerfRollupsConfig{}, "RecalculationBatchSize")
+	cedarPerfRollupsConfigDerivedRollupsKey         = bsonutil.MustHaveTag(PerfRollupsConfig{}, "DerivedRollups")
+)
+
+// PerfDerivedRollup defines a rollup as an arithmetic expression over
+// the other rollups of a result, for example
+// "totalOperations / avgWorkers". The rollups of additional FTDC
+// metrics are referred to by their names, e.g. "counters.hits.max",
+// and a metric without a suffix refers to its mean.
+type PerfDerivedRollup struct {
+	// Project limits the rollup to the results of one project. A
+	// rollup without a project applies to every project that does
+	// not define a rollup with the same name.
+	Project    string     `bson:"project" json:"project" yaml:"project"`
+	Name       string     `bson:"name" json:"name" yaml:"name"`
+	Expression string     `bson:"expression" json:"expression" yaml:"expression"`
+	MetricType MetricType `bson:"type" json:"type" yaml:"type"`
+}
+
+var (
+	cedarPerfDerivedRollupProjectKey    = bsonutil.MustHaveTag(PerfDerivedRollup{}, "Project")
+	cedarPerfDerivedRollupNameKey       = bsonutil.MustHaveTag(PerfDerivedRollup{}, "Name")
+	cedarPerfDerivedRollupExpressionKey = bsonutil.MustHaveTag(PerfDerivedRollup{}, "Expression")
+	cedarPerfDerivedRollupMetricTypeKey = bsonutil.MustHaveTag(PerfDerivedRollup{}, "MetricType")
+)
+
+// DerivedRollupsForProject returns the derived rollups that apply to
+// the project, in the order that they are defined, so that a rollup
+// may refer to the rollups defined before it.
+func (c *PerfRollupsConfig) DerivedRollupsForProject(project string) []PerfDerivedRollup {
+	overridden := map[string]bool{}
+	for _, d := range c.DerivedRollups {
+		if d.Project != "" && d.Project == project {
+			overridden[d.Name] = true
+		}
+	}
+
+	out := []PerfDerivedRollup{}
+	for _, d := range c.DerivedRollups {
+		if d.Name == "" || d.Expression == "" {
+			continue
+		}
+		if (d.Project == "" && !overridden[d.Name]) || (d.Project != "" && d.Project == project) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// PerfNoiseConfig controls how the noise of performance test series
+// is scored. Zero values use the defaults.
+type PerfNoiseConfig struct {
+	// Threshold is the noise score, relative to the mean, above
+	// which a series is flagged as too noisy for regressions to be
+	// detected reliably.
+	Threshold float64 `bson:"threshold" json:"threshold" yaml:"threshold"`
+
+	// Versions is the number of recent versions of each series
+	// that are scored.
+	Versions int `bson:"versions" json:"versions" yaml:"versions"`
+
+	// WindowDays limits scoring to the series with results from
+	// the given number of recent days.
+	WindowDays int `bson:"window_days" json:"window_days" yaml:"window_days"`
+}
+
+var (
+	cedarPerfNoiseConfigThresholdKey  = bsonutil.MustHaveTag(PerfNoiseConfig{}, "Threshold")
+	cedarPerfNoiseConfigVersionsKey   = bsonutil.MustHaveTag(PerfNoiseConfig{}, "Versions")
+	cedarPerfNoiseConfigWindowDaysKey = bsonutil.MustHaveTag(PerfNoiseConfig{}, "WindowDays")
+)
+
+// PerfRegressionsConfig holds the rules that decide which changes in
+// the rollups of a new version are reported as regressions.
+type PerfRegressionsConfig struct {
+	Rules []PerfRegressionRule `bson:"rules" json:"rules" yaml:"rules"`
+
+	// URL is the base URL of the REST API, for example
+	// "https://cedar.example.com/rest", which is used to link
+	// notifications to the comparison of the versions.
+	URL string `bson:"url" json:"url" yaml:"url"`
+}
+
+var (
+	cedarPerfRegressionsConfigRulesKey = bsonutil.MustHaveTag(PerfRegressionsConfig{}, "Rules")
+	cedarPerfRegressionsConfigURLKey   = bsonutil.MustHaveTag(PerfRegressionsConfig{}, "URL")
+)
+
+// PerfBaselinePrevious selects the previous version of a series as
+// the baseline of a regression rule.
+const PerfBaselinePrevious = "previous"
+
+// PerfRegressionRule reports a regression of a rollup of the tests of
+// a project when the value of a new version is worse than the value
+// of the baseline by at least the threshold percentage, or when a
+// change point for the worse was detected at the new version with at
+// least the given confidence. A zero threshold or confidence disables
+// that check.
+//
+// Whether a change is for the worse depends on the type of the
+// rollup: lower throughput and higher latency are worse, and a change
+// in either direction is reported for the other types.
+type PerfRegressionRule struct {
+	Project          string  `bson:"project" json:"project" yaml:"project"`
+	Metric           string  `bson:"metric" json:"metric" yaml:"metric"`
+	ThresholdPercent float64 `bson:"threshold_percent" json:"threshold_percent" yaml:"threshold_percent"`
+	Confidence       float64 `bson:"confidence" json:"confidence" yaml:"confidence"`
+
+	// Baseline selects the version that a new version is compared
+	// to, which is either PerfBaselinePrevious or the id of a
+	// version. By default, the baseline set for the task is used,
+	// and the previous version when none is set.
+	Baseline string `bson:"baseline" json:"baseline" yaml:"baseline"`
+
+	// SlackChannel is the channel of the notifications of the rule,
+	// which defaults to the channel of the Slack configuration.
+	SlackChannel string `bson:"slack_channel" json:"slack_channel" yaml:"slack_channel"`
+}
+
+var (
+	cedarPerfRegressionRuleProjectKey          = bsonutil.MustHaveTag(PerfRegressionRule{}, "Project")
+	cedarPerfRegressionRuleMetricKey           = bsonutil.MustHaveTag(PerfRegressionRule{}, "Metric")
+	cedarPerfRegressionRuleThresholdPercentKey = bsonutil.MustHaveTag(PerfRegressionRule{}, "ThresholdPercent")
+	cedarPerfRegressionRuleConfidenceKey       = bsonutil.MustHaveTag(PerfRegressionRule{}, "Confidence")
+	cedarPerfRegressionRuleBaselineKey         = bsonutil.MustHaveTag(PerfRegressionRule{}, "Baseline")
+	cedarPerfRegressionRuleSlackChannelKey     = bsonutil.MustHaveTag(PerfRegressionRule{}, "SlackChannel")
+)
+
+// RulesForProject returns the rules of the project that check a
+// metric for regressions.
+func (c *PerfRegressionsConfig) RulesForProject(project string) []PerfRegressionRule {
+	out := []PerfRegressionRule{}
+	for _, rule := range c.Rules {
+		if rule.Project == "" || rule.Project != project || rule.Metric == "" {
+			continue
+		}
+		if rule.ThresholdPercent <= 0 && rule.Confidence <= 0 {
+			continue
+		}
+		out = append(out, rule)
+	}
+	return out
+}
+
+// PerfMetricName maps an FTDC metric key (e.g. "counters.hits") to
+// the name used for its rollups. The mapping is stored as a list
+// because metric keys contain dots, which are not valid in document
+// field names.
+type PerfMetricName struct {
+	Key  string `bson:"key" json:"key" yaml:"key"`
+	Name string `bson:"name" json:"name" yaml:"name"`
+}
+
+var (
+	cedarPerfMetricNameKeyKey  = bsonutil.MustHaveTag(PerfMetricName{}, "Key")
+	cedarPerfMetricNameNameKey = bsonutil.MustHaveTag(PerfMetricName{}, "Name")
+)
+
+// MetricNameMap returns the metric name mapping as a map from FTDC
+// key to rollup name.
+func (c *PerfRollupsConfig) MetricNameMap() map[string]string {
+	out := make(map[string]string, len(c.MetricNames))
+	for _, n := range c.MetricNames {
+		if n.Key != "" && n.Name != "" {
+			out[n.Key] = n.Name
+		}
+	}
+	return out
+}
+
 func (c *CedarConfig) Setup(e cedar.Environment) { c.env = e }
 func (c *CedarConfig) IsNil() bool               { return !c.populated }
 func (c *CedarConfig) Find() error {
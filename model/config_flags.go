@@ -10,19 +10,31 @@ import (
 )
 
 type OperationalFlags struct {
-	DisableCostReportingJob bool `bson:"disable_cost_reporting" json:"disable_cost_reporting" yaml:"disable_cost_reporting"`
+	DisableCostReportingJob       bool `bson:"disable_cost_reporting" json:"disable_cost_reporting" yaml:"disable_cost_reporting"`
+	DisableRollupRecalculationJob bool `bson:"disable_rollup_recalculation" json:"disable_rollup_recalculation" yaml:"disable_rollup_recalculation"`
+	DisableNoiseScoringJob        bool `bson:"disable_noise_scoring" json:"disable_noise_scoring" yaml:"disable_noise_scoring"`
+	DisableRegressionDetectionJob bool `bson:"disable_regression_detection" json:"disable_regression_detection" yaml:"disable_regression_detection"`
 
 	env cedar.Environment
 }
 
 var (
-	opsFlagsDisableCostReporting = bsonutil.MustHaveTag(OperationalFlags{}, "DisableCostReportingJob")
+	opsFlagsDisableCostReporting       = bsonutil.MustHaveTag(OperationalFlags{}, "DisableCostReportingJob")
+	opsFlagsDisableRollupRecalculation = bsonutil.MustHaveTag(OperationalFlags{}, "DisableRollupRecalculationJob")
+	opsFlagsDisableNoiseScoring        = bsonutil.MustHaveTag(OperationalFlags{}, "DisableNoiseScoringJob")
+	opsFlagsDisableRegressionDetection = bsonutil.MustHaveTag(OperationalFlags{}, "DisableRegressionDetectionJob")
 )
 
 func (f *OperationalFlags) findAndSet(name string, v bool) error {
 	switch name {
 	case "disable_cost_reporting":
 		return f.SetDisableCostReportingJob(v)
+	case "disable_rollup_recalculation":
+		return f.SetDisableRollupRecalculationJob(v)
+	case "disable_noise_scoring":
+		return f.SetDisableNoiseScoringJob(v)
+	case "disable_regression_detection":
+		return f.SetDisableRegressionDetectionJob(v)
 	default:
 		return errors.Errorf("%s is not a known feature flag name", name)
 	}
@@ -44,6 +56,30 @@ func (f *OperationalFlags) SetDisableCostReportingJob(v bool) error {
 	return nil
 }
 
+func (f *OperationalFlags) SetDisableRollupRecalculationJob(v bool) error {
+	if err := f.update(opsFlagsDisableRollupRecalculation, v); err != nil {
+		return errors.WithStack(err)
+	}
+	f.DisableRollupRecalculationJob = v
+	return nil
+}
+
+func (f *OperationalFlags) SetDisableNoiseScoringJob(v bool) error {
+	if err := f.update(opsFlagsDisableNoiseScoring, v); err != nil {
+		return errors.WithStack(err)
+	}
+	f.DisableNoiseScoringJob = v
+	return nil
+}
+
+func (f *OperationalFlags) SetDisableRegressionDetectionJob(v bool) error {
+	if err := f.update(opsFlagsDisableRegressionDetection, v); err != nil {
+		return errors.WithStack(err)
+	}
+	f.DisableRegressionDetectionJob = v
+	return nil
+}
+
 func (f *OperationalFlags) update(key string, value bool) error {
 	conf, session, err := cedar.GetSessionWithConfig(f.env)
 	if err != nil {
@@ -107,6 +107,15 @@ func TestCedarConfig(t *testing.T) {
 
 			assert.NoError(t, conf.Flags.SetDisableCostReportingJob(true))
 			assert.True(t, conf.Flags.DisableCostReportingJob)
+
+			assert.NoError(t, conf.Flags.SetTrue("disable_rollup_recalculation"))
+			assert.True(t, conf.Flags.DisableRollupRecalculationJob)
+
+			assert.NoError(t, conf.Flags.SetTrue("disable_noise_scoring"))
+			assert.True(t, conf.Flags.DisableNoiseScoringJob)
+
+			assert.NoError(t, conf.Flags.SetTrue("disable_regression_detection"))
+			assert.True(t, conf.Flags.DisableRegressionDetectionJob)
 		},
 		"SetFlagWithBadConfiguration": func(ctx context.Context, t *testing.T, env cedar.Environment, conf *CedarConfig) {
 			assert.Error(t, conf.Flags.SetDisableCostReportingJob(true))
@@ -122,3 +131,51 @@ func TestCedarConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestDerivedRollupsForProject(t *testing.T) {
+	conf := PerfRollupsConfig{
+		DerivedRollups: []PerfDerivedRollup{
+			{Name: "opsPerWorker", Expression: "totalOperations / avgWorkers"},
+			{Name: "errorRate", Expression: "totalErrors / totalOperations"},
+			{Project: "project", Name: "errorRate", Expression: "totalErrors / totalSamples"},
+			{Project: "other", Name: "hitRate", Expression: "counters.hits / totalOperations"},
+			{Name: "empty"},
+		},
+	}
+
+	derived := conf.DerivedRollupsForProject("project")
+	require.Len(t, derived, 2)
+	assert.Equal(t, "opsPerWorker", derived[0].Name)
+	assert.Equal(t, "totalErrors / totalSamples", derived[1].Expression)
+
+	derived = conf.DerivedRollupsForProject("other")
+	require.Len(t, derived, 3)
+	assert.Equal(t, "totalErrors / totalOperations", derived[1].Expression)
+	assert.Equal(t, "hitRate", derived[2].Name)
+
+	assert.Len(t, conf.DerivedRollupsForProject(""), 2)
+}
+
+func TestRegressionRulesForProject(t *testing.T) {
+	conf := PerfRegressionsConfig{
+		Rules: []PerfRegressionRule{
+			{Project: "project", Metric: "ops_per_sec", ThresholdPercent: 5},
+			{Project: "project", Metric: "latency", Confidence: 0.9},
+			{Project: "project", Metric: "size"},
+			{Project: "project", ThresholdPercent: 5},
+			{Project: "other", Metric: "ops_per_sec", ThresholdPercent: 10},
+			{Metric: "ops_per_sec", ThresholdPercent: 1},
+		},
+	}
+
+	rules := conf.RulesForProject("project")
+	require.Len(t, rules, 2)
+	assert.Equal(t, "ops_per_sec", rules[0].Metric)
+	assert.Equal(t, "latency", rules[1].Metric)
+
+	rules = conf.RulesForProject("other")
+	require.Len(t, rules, 1)
+	assert.Equal(t, 10.0, rules[0].ThresholdPercent)
+
+	assert.Empty(t, conf.RulesForProject(""))
+}
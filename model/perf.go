@@ -3,9 +3,9 @@ package model
 import (
 	"crypto/sha1"
 	"fmt"
-	"hash"
 	"io"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/evergreen-ci/cedar"
@@ -17,6 +17,7 @@ import (
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 )
 
@@ -48,6 +49,11 @@ type PerformanceResult struct {
 
 	Rollups *PerfRollups `bson:"rollups,omitempty"`
 
+	// TrialSummaries aggregate the rollups of the trials of the
+	// tests that are children of the result.
+	TrialSummaries          []PerfTrialSummary `bson:"trial_summaries,omitempty"`
+	TrialSummariesUpdatedAt time.Time          `bson:"trial_summaries_updated_at,omitempty"`
+
 	env       cedar.Environment
 	populated bool
 }
@@ -97,6 +103,9 @@ func (result *PerformanceResult) Find() error {
 	}
 
 	result.populated = true
+	if result.Rollups == nil {
+		result.Rollups = &PerfRollups{}
+	}
 	result.Rollups.id = result.ID
 
 	return nil
@@ -108,10 +117,10 @@ func (result *PerformanceResult) Save() error {
 	}
 
 	if result.ID == "" {
-		result.ID = result.Info.ID()
-		if result.ID == "" {
-			return errors.New("cannot ")
+		if err := result.Info.ValidateSchema(); err != nil {
+			return errors.Wrap(err, "cannot compute id of perf result")
 		}
+		result.ID = result.Info.ID()
 	}
 
 	conf, session, err := cedar.GetSessionWithConfig(result.env)
@@ -130,6 +139,90 @@ func (result *PerformanceResult) Save() error {
 	return errors.Wrap(err, "problem saving perf result to collection")
 }
 
+// SetArtifactConversionError records that the artifact of the result
+// with the given path could not be converted to FTDC, so that it is
+// not converted again.
+func (result *PerformanceResult) SetArtifactConversionError(path string, convErr error) error {
+	if result.ID == "" {
+		return errors.New("cannot update the artifacts of a result without an id")
+	}
+	if convErr == nil {
+		return errors.New("must specify the conversion error")
+	}
+
+	conf, session, err := cedar.GetSessionWithConfig(result.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	query := bson.M{
+		perfIDKey: result.ID,
+		bsonutil.GetDottedKeyName(perfArtifactsKey, artifactInfoPathKey): path,
+	}
+	update := bson.M{"$set": bson.M{
+		bsonutil.GetDottedKeyName(perfArtifactsKey, "$", artifactInfoConversionErrorKey): convErr.Error(),
+	}}
+	err = session.DB(conf.DatabaseName).C(perfResultCollection).Update(query, update)
+	if db.ResultsNotFound(err) {
+		return errors.Errorf("could not find artifact '%s' of result '%s' in the database", path, result.ID)
+	} else if err != nil {
+		return errors.Wrapf(err, "problem recording conversion error of '%s'", path)
+	}
+
+	for idx := range result.Artifacts {
+		if result.Artifacts[idx].Path == path {
+			result.Artifacts[idx].ConversionError = convErr.Error()
+		}
+	}
+	grip.Debug(message.Fields{
+		"ns":   model.Namespace{DB: conf.DatabaseName, Collection: perfResultCollection},
+		"id":   result.ID,
+		"path": path,
+		"op":   "set perf result artifact conversion error",
+	})
+
+	return nil
+}
+
+// AppendArtifacts adds the artifacts to the result document in the
+// database without overwriting any other fields, which makes it safe
+// to use when the in-memory copy of the result may be stale (e.g. at
+// the end of a long running stream.)
+func (result *PerformanceResult) AppendArtifacts(artifacts ...ArtifactInfo) error {
+	if len(artifacts) == 0 {
+		return nil
+	}
+
+	if result.ID == "" {
+		return errors.New("cannot append artifacts to a result without an id")
+	}
+
+	conf, session, err := cedar.GetSessionWithConfig(result.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	update := bson.M{"$push": bson.M{perfArtifactsKey: bson.M{"$each": artifacts}}}
+	err = session.DB(conf.DatabaseName).C(perfResultCollection).UpdateId(result.ID, update)
+	if db.ResultsNotFound(err) {
+		return errors.Errorf("could not find result record '%s' in the database", result.ID)
+	} else if err != nil {
+		return errors.Wrapf(err, "problem appending artifacts to '%s'", result.ID)
+	}
+
+	result.Artifacts = append(result.Artifacts, artifacts...)
+	grip.Debug(message.Fields{
+		"ns":    model.Namespace{DB: conf.DatabaseName, Collection: perfResultCollection},
+		"id":    result.ID,
+		"count": len(artifacts),
+		"op":    "append perf result artifacts",
+	})
+
+	return nil
+}
+
 ////////////////////////////////////////////////////////////////////////
 //
 // Component Types
@@ -163,55 +256,189 @@ var (
 	perfResultInfoArgumentsKey = bsonutil.MustHaveTag(PerformanceResultInfo{}, "Arguments")
 )
 
+// perfResultIDSchemas maps the supported result id schemas to the
+// functions that write the fields of the info to the id's hash. The
+// ids of stored results depend on these functions, so a schema must
+// never change once it is in use: add a new schema instead.
+var perfResultIDSchemas = map[int]func(io.Writer, *PerformanceResultInfo){
+	0: writePerfResultIDSchema0,
+	1: writePerfResultIDSchema1,
+}
+
+// SupportedPerfResultIDSchemas returns the supported result id
+// schemas, in order.
+func SupportedPerfResultIDSchemas() []int {
+	schemas := make([]int, 0, len(perfResultIDSchemas))
+	for schema := range perfResultIDSchemas {
+		schemas = append(schemas, schema)
+	}
+	sort.Ints(schemas)
+
+	return schemas
+}
+
+// LatestPerfResultIDSchema returns the newest supported result id
+// schema.
+func LatestPerfResultIDSchema() int {
+	schemas := SupportedPerfResultIDSchemas()
+	return schemas[len(schemas)-1]
+}
+
+// ValidateSchema returns an error if the result id schema of the info
+// is not supported.
+func (id *PerformanceResultInfo) ValidateSchema() error {
+	if _, ok := perfResultIDSchemas[id.Schema]; !ok {
+		return errors.Errorf("result id schema %d is not supported, supported schemas are %v",
+			id.Schema, SupportedPerfResultIDSchemas())
+	}
+
+	return nil
+}
+
+// ID returns the id of the result described by the info, computed
+// with the info's schema, or an empty string if the schema is not
+// supported. Use ValidateSchema to check the schema of untrusted
+// info.
 func (id *PerformanceResultInfo) ID() string {
-	var hash hash.Hash
-
-	if id.Schema == 0 {
-		hash = sha1.New()
-		_, _ = io.WriteString(hash, id.Project)
-		_, _ = io.WriteString(hash, id.Version)
-		_, _ = io.WriteString(hash, id.Variant)
-		_, _ = io.WriteString(hash, id.TaskName)
-		_, _ = io.WriteString(hash, id.TaskID)
-		_, _ = io.WriteString(hash, fmt.Sprint(id.Execution))
-		_, _ = io.WriteString(hash, id.TestName)
-		_, _ = io.WriteString(hash, fmt.Sprint(id.Trial))
-		_, _ = io.WriteString(hash, id.Parent)
-
-		sort.Strings(id.Tags)
-		for _, str := range id.Tags {
+	write, ok := perfResultIDSchemas[id.Schema]
+	if !ok {
+		return ""
+	}
+
+	hash := sha1.New()
+	write(hash, id)
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+func writePerfResultIDSchema0(hash io.Writer, id *PerformanceResultInfo) {
+	_, _ = io.WriteString(hash, id.Project)
+	_, _ = io.WriteString(hash, id.Version)
+	_, _ = io.WriteString(hash, id.Variant)
+	_, _ = io.WriteString(hash, id.TaskName)
+	_, _ = io.WriteString(hash, id.TaskID)
+	_, _ = io.WriteString(hash, fmt.Sprint(id.Execution))
+	_, _ = io.WriteString(hash, id.TestName)
+	_, _ = io.WriteString(hash, fmt.Sprint(id.Trial))
+	_, _ = io.WriteString(hash, id.Parent)
+
+	sort.Strings(id.Tags)
+	for _, str := range id.Tags {
+		_, _ = io.WriteString(hash, str)
+	}
+
+	if len(id.Arguments) > 0 {
+		args := []string{}
+		for k, v := range id.Arguments {
+			args = append(args, fmt.Sprintf("%s=%d", k, v))
+		}
+
+		sort.Strings(args)
+		for _, str := range args {
 			_, _ = io.WriteString(hash, str)
 		}
+	}
+}
 
-		if len(id.Arguments) > 0 {
-			args := []string{}
-			for k, v := range id.Arguments {
-				args = append(args, fmt.Sprintf("%s=%d", k, v))
-			}
+// writePerfResultIDSchema1 writes one quoted, labeled field per line,
+// so that values cannot run into each other, and writes arguments in
+// the order of their names. Tags are normalized, so that
+// neither their order, duplicates, nor surrounding whitespace change
+// the id.
+func writePerfResultIDSchema1(hash io.Writer, id *PerformanceResultInfo) {
+	_, _ = fmt.Fprintf(hash, "project=%q\n", id.Project)
+	_, _ = fmt.Fprintf(hash, "version=%q\n", id.Version)
+	_, _ = fmt.Fprintf(hash, "variant=%q\n", id.Variant)
+	_, _ = fmt.Fprintf(hash, "task_name=%q\n", id.TaskName)
+	_, _ = fmt.Fprintf(hash, "task_id=%q\n", id.TaskID)
+	_, _ = fmt.Fprintf(hash, "execution=%d\n", id.Execution)
+	_, _ = fmt.Fprintf(hash, "test_name=%q\n", id.TestName)
+	_, _ = fmt.Fprintf(hash, "trial=%d\n", id.Trial)
+	_, _ = fmt.Fprintf(hash, "parent=%q\n", id.Parent)
+
+	for _, tag := range normalizePerfResultTags(id.Tags) {
+		_, _ = fmt.Fprintf(hash, "tag=%q\n", tag)
+	}
+
+	keys := make([]string, 0, len(id.Arguments))
+	for k := range id.Arguments {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(hash, "arg.%q=%d\n", k, id.Arguments[k])
+	}
+}
 
-			sort.Strings(args)
-			for _, str := range args {
-				_, _ = io.WriteString(hash, str)
-			}
+// normalizePerfResultTags returns a sorted copy of the tags without
+// surrounding whitespace, empty tags, or duplicates.
+func normalizePerfResultTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
 		}
-	} else {
-		panic("unsupported schema")
+		seen[tag] = true
+		out = append(out, tag)
 	}
+	sort.Strings(out)
 
-	return fmt.Sprintf("%x", hash.Sum(nil))
+	return out
 }
 
 type PerformanceResults struct {
-	Results   []PerformanceResult `bson:"results"`
+	Results []PerformanceResult `bson:"results"`
+
+	// NextCursor is the cursor of the page following the results
+	// of a limited find, and is empty when there are no more
+	// results.
+	NextCursor string `bson:"-"`
+
 	env       cedar.Environment
 	populated bool
 }
 
+// PerfFindOptions describe the results to find. Limit, Sort,
+// Descending, and Cursor page through the results of queries that do
+// not specify a parent: results are sorted by Sort (and then by id)
+// and a Limit of 0 returns all results. The Cursor is the NextCursor
+// of the previous page, and must come from a query with the same sort
+// order.
 type PerfFindOptions struct {
 	Interval    util.TimeRange
 	Info        PerformanceResultInfo
 	MaxDepth    int
 	GraphLookup bool
+	Limit       int
+	Sort        PerfSortKey
+	Descending  bool
+	Cursor      string
+}
+
+// Validate checks the pagination options.
+func (opts *PerfFindOptions) Validate() error {
+	if opts.Limit < 0 {
+		return errors.New("limit must not be negative")
+	}
+
+	if opts.Sort == "" {
+		opts.Sort = PerfSortCreatedAt
+	}
+	if err := opts.Sort.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if opts.Cursor != "" {
+		cursor, err := ParsePerfCursor(opts.Cursor)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		return errors.WithStack(cursor.Matches(opts.Sort, opts.Descending))
+	}
+
+	return nil
 }
 
 func (r *PerformanceResults) Setup(e cedar.Environment) { r.env = e }
@@ -232,11 +459,19 @@ func (r *PerformanceResults) Find(options PerfFindOptions) error {
 		if options.Interval.IsZero() || !options.Interval.IsValid() {
 			return errors.New("invalid time range given")
 		}
+		if err = options.Validate(); err != nil {
+			return errors.Wrap(err, "invalid find options")
+		}
 		search = r.createFindQuery(options)
 	}
 
 	r.populated = false
-	err = session.DB(conf.DatabaseName).C(perfResultCollection).Find(search).All(&r.Results)
+	r.NextCursor = ""
+	if options.Info.Parent != "" {
+		err = session.DB(conf.DatabaseName).C(perfResultCollection).Find(search).All(&r.Results)
+	} else {
+		err = r.findPage(session.DB(conf.DatabaseName).C(perfResultCollection), search, options)
+	}
 	if options.Info.Parent != "" && len(r.Results) > 0 && options.MaxDepth > -1 { // i.e. the parent fits the search criteria
 		if options.GraphLookup {
 			err = r.findAllChildrenGraphLookup(options.Info.Parent, options.MaxDepth, options.Info.Tags)
@@ -290,9 +525,38 @@ func (r *PerformanceResults) createFindQuery(options PerfFindOptions) map[string
 		}
 		search[bsonutil.GetDottedKeyName("info", "args")] = bson.M{"$in": args}
 	}
+	if options.Cursor != "" {
+		// the options are validated before the query is created
+		cursor, _ := ParsePerfCursor(options.Cursor)
+		for key, val := range cursor.query() {
+			search[key] = val
+		}
+	}
 	return search
 }
 
+// findPage runs the query in sorted order and, when the options have
+// a limit, reads one extra result to determine whether there is a
+// next page.
+func (r *PerformanceResults) findPage(c *mgo.Collection, search map[string]interface{}, options PerfFindOptions) error {
+	query := c.Find(search).Sort(perfSortFields(options.Sort, options.Descending)...)
+	if options.Limit > 0 {
+		query = query.Limit(options.Limit + 1)
+	}
+
+	if err := query.All(&r.Results); err != nil {
+		return err
+	}
+
+	if options.Limit > 0 && len(r.Results) > options.Limit {
+		r.Results = r.Results[:options.Limit]
+		last := &r.Results[len(r.Results)-1]
+		r.NextCursor = NewPerfCursor(options.Sort, options.Descending, options.Sort.value(last), last.ID).String()
+	}
+
+	return nil
+}
+
 // All children of parent are recursively added to r.Results
 func (r *PerformanceResults) findAllChildren(parent string, depth int) error {
 	if depth < 0 {
@@ -0,0 +1,510 @@
+package model
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/ftdc/bsonx"
+	"github.com/mongodb/ftdc/events"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// Convertible returns true when the artifact is stored in a format
+// other than FTDC that can be converted to FTDC.
+func (a *ArtifactInfo) Convertible() bool {
+	switch a.Format {
+	case FileBSON, FileJSON, FileCSV:
+		return true
+	default:
+		return false
+	}
+}
+
+// Documents opens the artifact and returns an iterator over its
+// samples, decoded according to the format and compression of the
+// artifact. Closing the iterator closes the artifact.
+//
+// JSON artifacts hold either an array of objects or a stream of
+// objects, and CSV artifacts have a header of field names, where
+// dotted names describe nested documents. In both cases, numbers,
+// booleans, and RFC3339 timestamps are decoded to their natural BSON
+// types.
+func (a *ArtifactInfo) Documents(ctx context.Context, env cedar.Environment) (ftdc.Iterator, error) {
+	switch a.Format {
+	case FileFTDC, FileBSON, FileJSON, FileCSV:
+	case FileText:
+		return nil, errors.Errorf("cannot read samples from %s artifact '%s'", a.Format, a.Path)
+	default:
+		return nil, errors.Errorf("'%s' is not a valid format", a.Format)
+	}
+
+	r, err := a.Open(ctx, env)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return readDocuments(ctx, a.Format, r), nil
+}
+
+// readDocuments returns an iterator over the samples in the reader,
+// taking ownership of the reader such that closing the iterator
+// closes the reader.
+func readDocuments(ctx context.Context, format FileDataFormat, r io.ReadCloser) ftdc.Iterator {
+	switch format {
+	case FileFTDC:
+		return &ftdcDocumentIterator{Iterator: ftdc.ReadStructuredMetrics(ctx, r), closer: r}
+	case FileBSON:
+		return &documentIterator{ctx: ctx, next: bsonDocumentReader(r), closer: r}
+	case FileJSON:
+		return &documentIterator{ctx: ctx, next: jsonDocumentReader(r), closer: r}
+	default:
+		return &documentIterator{ctx: ctx, next: csvDocumentReader(r), closer: r}
+	}
+}
+
+// ftdcDocumentIterator closes the underlying reader along with the
+// FTDC iterator.
+type ftdcDocumentIterator struct {
+	ftdc.Iterator
+	closer io.Closer
+}
+
+func (iter *ftdcDocumentIterator) Close() {
+	iter.Iterator.Close()
+	grip.Warning(iter.closer.Close())
+}
+
+// documentIterator implements ftdc.Iterator for formats that decode
+// one document at a time; next returns io.EOF once the data is
+// exhausted.
+type documentIterator struct {
+	ctx    context.Context
+	next   func() (*bsonx.Document, error)
+	closer io.Closer
+	doc    *bsonx.Document
+	count  int
+	err    error
+}
+
+func (iter *documentIterator) Next() bool {
+	if iter.err != nil || iter.next == nil {
+		return false
+	}
+	if err := iter.ctx.Err(); err != nil {
+		iter.err = errors.Wrap(err, "operation canceled")
+		return false
+	}
+
+	doc, err := iter.next()
+	if err == io.EOF {
+		iter.next = nil
+		return false
+	}
+	if err != nil {
+		iter.err = errors.Wrapf(err, "problem reading sample %d", iter.count)
+		return false
+	}
+
+	iter.doc = doc
+	iter.count++
+	return true
+}
+
+func (iter *documentIterator) Document() *bsonx.Document { return iter.doc }
+func (iter *documentIterator) Metadata() *bsonx.Document { return nil }
+func (iter *documentIterator) Err() error                { return iter.err }
+func (iter *documentIterator) Close()                    { grip.Warning(iter.closer.Close()) }
+
+func bsonDocumentReader(r io.Reader) func() (*bsonx.Document, error) {
+	buf := bufio.NewReader(r)
+	return func() (*bsonx.Document, error) {
+		doc := bsonx.NewDocument()
+		if _, err := doc.ReadFrom(buf); err != nil {
+			return nil, err
+		}
+
+		return doc, nil
+	}
+}
+
+func jsonDocumentReader(r io.Reader) func() (*bsonx.Document, error) {
+	buf := bufio.NewReader(r)
+	dec := json.NewDecoder(buf)
+	dec.UseNumber()
+
+	started := false
+	array := false
+	return func() (*bsonx.Document, error) {
+		if !started {
+			started = true
+			first, err := peekNonSpace(buf)
+			if err != nil {
+				return nil, err
+			}
+			if first == '[' {
+				array = true
+				if _, err = dec.Token(); err != nil {
+					return nil, errors.Wrap(err, "problem reading json array")
+				}
+			}
+		}
+
+		if array && !dec.More() {
+			if _, err := dec.Token(); err != nil {
+				return nil, errors.Wrap(err, "problem reading json array")
+			}
+			return nil, io.EOF
+		}
+
+		sample := map[string]interface{}{}
+		if err := dec.Decode(&sample); err != nil {
+			if err == io.EOF && !array {
+				return nil, io.EOF
+			}
+			return nil, errors.Wrap(err, "problem decoding json")
+		}
+
+		return jsonDocument(sample)
+	}
+}
+
+// peekNonSpace returns the first byte of the reader that is not
+// whitespace, without consuming it.
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+
+		switch b[0] {
+		case ' ', '\t', '\n', '\r':
+			if _, err = r.ReadByte(); err != nil {
+				return 0, err
+			}
+		default:
+			return b[0], nil
+		}
+	}
+}
+
+// jsonDocument converts a decoded JSON object into a document. Since
+// objects are unordered, keys are sorted so that every sample has the
+// same schema.
+func jsonDocument(in map[string]interface{}) (*bsonx.Document, error) {
+	keys := make([]string, 0, len(in))
+	for key := range in {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	doc := bsonx.NewDocument()
+	for _, key := range keys {
+		value, err := jsonValue(in[key])
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem converting field '%s'", key)
+		}
+		doc.Append(bsonx.EC.FromValue(key, value))
+	}
+
+	return doc, nil
+}
+
+func jsonValue(in interface{}) (*bsonx.Value, error) {
+	switch v := in.(type) {
+	case nil:
+		return bsonx.VC.Null(), nil
+	case bool:
+		return bsonx.VC.Boolean(v), nil
+	case json.Number:
+		return parseNumber(string(v))
+	case string:
+		if ts, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return bsonx.VC.Time(ts), nil
+		}
+		return bsonx.VC.String(v), nil
+	case map[string]interface{}:
+		doc, err := jsonDocument(v)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return bsonx.VC.Document(doc), nil
+	case []interface{}:
+		array := bsonx.NewArray()
+		for idx := range v {
+			value, err := jsonValue(v[idx])
+			if err != nil {
+				return nil, errors.Wrapf(err, "problem converting array element %d", idx)
+			}
+			array.Append(value)
+		}
+		return bsonx.VC.Array(array), nil
+	default:
+		return nil, errors.Errorf("unsupported json type %T", in)
+	}
+}
+
+func parseNumber(in string) (*bsonx.Value, error) {
+	if i, err := strconv.ParseInt(in, 10, 64); err == nil {
+		return bsonx.VC.Int64(i), nil
+	}
+
+	f, err := strconv.ParseFloat(in, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "'%s' is not a valid number", in)
+	}
+
+	return bsonx.VC.Double(f), nil
+}
+
+func csvDocumentReader(r io.Reader) func() (*bsonx.Document, error) {
+	csvr := csv.NewReader(r)
+	var fields [][]string
+	return func() (*bsonx.Document, error) {
+		if fields == nil {
+			header, err := csvr.Read()
+			if err != nil {
+				return nil, err
+			}
+
+			fields = make([][]string, len(header))
+			for idx, name := range header {
+				fields[idx] = strings.Split(strings.TrimSpace(name), ".")
+			}
+		}
+
+		record, err := csvr.Read()
+		if err != nil {
+			return nil, err
+		}
+
+		doc := bsonx.NewDocument()
+		for idx, value := range record {
+			if err = setNested(doc, fields[idx], csvValue(value)); err != nil {
+				return nil, errors.Wrapf(err, "problem setting field '%s'", strings.Join(fields[idx], "."))
+			}
+		}
+
+		return doc, nil
+	}
+}
+
+func csvValue(in string) *bsonx.Value {
+	in = strings.TrimSpace(in)
+	if value, err := parseNumber(in); err == nil {
+		return value
+	}
+	if b, err := strconv.ParseBool(in); err == nil {
+		return bsonx.VC.Boolean(b)
+	}
+	if ts, err := time.Parse(time.RFC3339Nano, in); err == nil {
+		return bsonx.VC.Time(ts)
+	}
+
+	return bsonx.VC.String(in)
+}
+
+// setNested adds the value to the document at the given path,
+// creating sub-documents as needed.
+func setNested(doc *bsonx.Document, path []string, value *bsonx.Value) error {
+	for _, key := range path[:len(path)-1] {
+		elem := doc.LookupElement(key)
+		if elem == nil {
+			sub := bsonx.NewDocument()
+			doc.Append(bsonx.EC.SubDocument(key, sub))
+			doc = sub
+			continue
+		}
+
+		if elem.Value().Type() != bsonx.TypeEmbeddedDocument {
+			return errors.Errorf("field '%s' is not a document", key)
+		}
+		doc = elem.Value().MutableDocument()
+	}
+
+	key := path[len(path)-1]
+	if doc.LookupElement(key) != nil {
+		return errors.Errorf("duplicate field '%s'", key)
+	}
+	doc.Append(bsonx.EC.FromValue(key, value))
+
+	return nil
+}
+
+// PerformanceEventIterator decodes the samples of an artifact with
+// the raw events schema, or one of the schemas derived from raw
+// events, into performance events.
+type PerformanceEventIterator struct {
+	docs  ftdc.Iterator
+	event *events.Performance
+	err   error
+}
+
+// PerformanceEvents opens the artifact and returns an iterator over
+// its samples as performance events. The artifact must have the raw
+// events, interval summary or collapsed events schema.
+func (a *ArtifactInfo) PerformanceEvents(ctx context.Context, env cedar.Environment) (*PerformanceEventIterator, error) {
+	switch a.Schema {
+	case SchemaRawEvents, SchemaIntervalSummary, SchemaCollapsedEvents:
+	default:
+		return nil, errors.Errorf("cannot read performance events from %s artifact '%s'", a.Schema, a.Path)
+	}
+
+	docs, err := a.Documents(ctx, env)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return &PerformanceEventIterator{docs: docs}, nil
+}
+
+// ReadPerformanceEvents returns an iterator over the performance
+// events in the reader, which holds raw events in the given format, as
+// they would be read from an artifact in that format. Closing the
+// iterator closes the reader.
+func ReadPerformanceEvents(ctx context.Context, format FileDataFormat, r io.ReadCloser) *PerformanceEventIterator {
+	return &PerformanceEventIterator{docs: readDocuments(ctx, format, r)}
+}
+
+// Next advances the iterator, returning false when there are no more
+// events or an error occurred.
+func (iter *PerformanceEventIterator) Next() bool {
+	if iter.err != nil || !iter.docs.Next() {
+		return false
+	}
+
+	data, err := iter.docs.Document().MarshalBSON()
+	if err != nil {
+		iter.err = errors.Wrap(err, "problem marshaling sample")
+		return false
+	}
+
+	event := &events.Performance{}
+	if err = bson.Unmarshal(data, event); err != nil {
+		iter.err = errors.Wrap(err, "problem decoding performance event")
+		return false
+	}
+	iter.event = event
+
+	return true
+}
+
+// Event returns the current event.
+func (iter *PerformanceEventIterator) Event() *events.Performance { return iter.event }
+
+// Err returns the first error encountered by the iterator.
+func (iter *PerformanceEventIterator) Err() error {
+	if iter.err != nil {
+		return iter.err
+	}
+
+	return errors.WithStack(iter.docs.Err())
+}
+
+// Close closes the underlying artifact.
+func (iter *PerformanceEventIterator) Close() { iter.docs.Close() }
+
+// ConvertToFTDC writes the samples of the artifact to a new,
+// uncompressed FTDC artifact with the same schema and tags, stored
+// next to the original, and returns the description of the new
+// artifact. The caller is responsible for attaching it to a result.
+func (a *ArtifactInfo) ConvertToFTDC(ctx context.Context, env cedar.Environment) (*ArtifactInfo, error) {
+	if !a.Convertible() {
+		return nil, errors.Errorf("cannot convert %s artifact '%s' to ftdc", a.Format, a.Path)
+	}
+
+	docs, err := a.Documents(ctx, env)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer docs.Close()
+
+	converted := &ArtifactInfo{
+		Type:        a.Type,
+		Bucket:      a.Bucket,
+		Path:        a.ConvertedPath(),
+		Format:      FileFTDC,
+		Compression: FileUncompressed,
+		Schema:      a.Schema,
+		Tags:        a.Tags,
+		CreatedAt:   time.Now(),
+	}
+	output, err := converted.Writer(ctx, env)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	collector := ftdc.NewStreamingCollector(defaultPointsPerChunk, output)
+	count := 0
+	for docs.Next() {
+		if err = collector.Add(docs.Document()); err != nil {
+			catcher.Add(errors.Wrapf(err, "problem adding sample %d to ftdc", count))
+			break
+		}
+		count++
+	}
+	catcher.Add(docs.Err())
+	if !catcher.HasErrors() && count == 0 {
+		catcher.Add(errors.New("artifact has no samples"))
+	}
+	catcher.Add(errors.Wrap(ftdc.FlushCollector(collector, output), "problem flushing ftdc data"))
+	catcher.Add(errors.Wrapf(output.Close(), "problem closing '%s'", converted.Path))
+	if catcher.HasErrors() {
+		return nil, errors.Wrapf(catcher.Resolve(), "problem converting '%s'", a.Path)
+	}
+
+	return converted, nil
+}
+
+// ConvertedPath is the path of the FTDC artifact that ConvertToFTDC
+// creates for the artifact.
+func (a *ArtifactInfo) ConvertedPath() string {
+	return fmt.Sprintf("%s.%s", a.Path, FileFTDC)
+}
+
+// UnconvertedArtifacts returns the artifacts of the result that can
+// be converted to FTDC but that have not yet been converted, nor
+// failed to convert.
+func (result *PerformanceResult) UnconvertedArtifacts() []ArtifactInfo {
+	converted := map[string]bool{}
+	for _, artifact := range result.Artifacts {
+		if artifact.Format == FileFTDC {
+			converted[artifact.Path] = true
+		}
+	}
+
+	out := []ArtifactInfo{}
+	for _, artifact := range result.Artifacts {
+		if artifact.Convertible() && artifact.ConversionError == "" && !converted[artifact.ConvertedPath()] {
+			out = append(out, artifact)
+		}
+	}
+
+	return out
+}
+
+// FailedConversions returns the artifacts of the result that could not
+// be converted to FTDC.
+func (result *PerformanceResult) FailedConversions() []ArtifactInfo {
+	out := []ArtifactInfo{}
+	for _, artifact := range result.Artifacts {
+		if artifact.ConversionError != "" {
+			out = append(out, artifact)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,190 @@
+package model
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/mongodb/ftdc/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestArtifactReaders(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tempDir, err := ioutil.TempDir("", "cedar-artifact-reader")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := []events.Performance{}
+	for i := 0; i < 3; i++ {
+		point := events.Performance{Timestamp: start.Add(time.Duration(i) * time.Second)}
+		point.Counters.Operations = int64(10 * (i + 1))
+		point.Counters.Number = int64(i + 1)
+		point.Timers.Duration = time.Duration(i+1) * time.Millisecond
+		point.Gauges.Workers = 4
+		points = append(points, point)
+	}
+
+	ftdcData := &bytes.Buffer{}
+	stream := make(chan events.Performance, len(points))
+	for _, point := range points {
+		stream <- point
+	}
+	close(stream)
+	require.NoError(t, DumpPerformanceSeries(ctx, stream, nil, ftdcData))
+
+	bsonData := &bytes.Buffer{}
+	for _, point := range points {
+		data, err := bson.Marshal(point)
+		require.NoError(t, err)
+		_, err = bsonData.Write(data)
+		require.NoError(t, err)
+	}
+
+	jsonArray := `[
+		{"ts": "2019-01-01T00:00:00Z", "counters": {"n": 1, "ops": 10}, "timers": {"dur": 1000000}, "gauges": {"workers": 4}},
+		{"ts": "2019-01-01T00:00:01Z", "counters": {"n": 2, "ops": 20}, "timers": {"dur": 2000000}, "gauges": {"workers": 4}},
+		{"ts": "2019-01-01T00:00:02Z", "counters": {"n": 3, "ops": 30}, "timers": {"dur": 3000000}, "gauges": {"workers": 4}}
+	]`
+	jsonStream := `{"ts": "2019-01-01T00:00:00Z", "counters": {"n": 1, "ops": 10}, "timers": {"dur": 1000000}, "gauges": {"workers": 4}}
+{"ts": "2019-01-01T00:00:01Z", "counters": {"n": 2, "ops": 20}, "timers": {"dur": 2000000}, "gauges": {"workers": 4}}
+{"ts": "2019-01-01T00:00:02Z", "counters": {"n": 3, "ops": 30}, "timers": {"dur": 3000000}, "gauges": {"workers": 4}}
+`
+	csvData := `ts,counters.n,counters.ops,timers.dur,gauges.workers
+2019-01-01T00:00:00Z,1,10,1000000,4
+2019-01-01T00:00:01Z,2,20,2000000,4
+2019-01-01T00:00:02Z,3,30,3000000,4
+`
+
+	gzCSV := &bytes.Buffer{}
+	gw := gzip.NewWriter(gzCSV)
+	_, err = gw.Write([]byte(csvData))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	for _, test := range []struct {
+		name        string
+		format      FileDataFormat
+		compression FileCompression
+		data        []byte
+		hasErr      bool
+	}{
+		{name: "FTDC", format: FileFTDC, data: ftdcData.Bytes()},
+		{name: "BSON", format: FileBSON, data: bsonData.Bytes()},
+		{name: "JSONArray", format: FileJSON, data: []byte(jsonArray)},
+		{name: "JSONStream", format: FileJSON, data: []byte(jsonStream)},
+		{name: "CSV", format: FileCSV, data: []byte(csvData)},
+		{name: "GzippedCSV", format: FileCSV, compression: FileGz, data: gzCSV.Bytes()},
+		{name: "InvalidJSON", format: FileJSON, data: []byte(`[{"ts": 1}, `), hasErr: true},
+		{name: "TruncatedBSON", format: FileBSON, data: bsonData.Bytes()[:bsonData.Len()-1], hasErr: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			artifact := &ArtifactInfo{
+				Type:        PailLocal,
+				Bucket:      tempDir,
+				Path:        test.name,
+				Format:      test.format,
+				Compression: test.compression,
+				Schema:      SchemaRawEvents,
+			}
+			w, err := artifact.Writer(ctx, nil)
+			require.NoError(t, err)
+			_, err = w.Write(test.data)
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+
+			iter, err := artifact.PerformanceEvents(ctx, nil)
+			require.NoError(t, err)
+			defer iter.Close()
+
+			read := []events.Performance{}
+			for iter.Next() {
+				read = append(read, *iter.Event())
+			}
+			if test.hasErr {
+				assert.Error(t, iter.Err())
+				return
+			}
+			require.NoError(t, iter.Err())
+			require.Len(t, read, len(points))
+			for idx := range points {
+				assert.True(t, points[idx].Timestamp.Equal(read[idx].Timestamp))
+				assert.Equal(t, points[idx].Counters, read[idx].Counters)
+				assert.Equal(t, points[idx].Timers, read[idx].Timers)
+				assert.Equal(t, points[idx].Gauges, read[idx].Gauges)
+			}
+
+			if !artifact.Convertible() {
+				return
+			}
+
+			converted, err := artifact.ConvertToFTDC(ctx, nil)
+			require.NoError(t, err)
+			assert.Equal(t, FileFTDC, converted.Format)
+			assert.Equal(t, FileUncompressed, converted.Compression)
+			assert.Equal(t, SchemaRawEvents, converted.Schema)
+			assert.Equal(t, artifact.ConvertedPath(), converted.Path)
+
+			iter, err = converted.PerformanceEvents(ctx, nil)
+			require.NoError(t, err)
+			defer iter.Close()
+			count := 0
+			for iter.Next() {
+				assert.Equal(t, points[count].Counters, iter.Event().Counters)
+				count++
+			}
+			require.NoError(t, iter.Err())
+			assert.Equal(t, len(points), count)
+		})
+	}
+	t.Run("Text", func(t *testing.T) {
+		artifact := &ArtifactInfo{Type: PailLocal, Bucket: tempDir, Path: "text", Format: FileText}
+		_, err := artifact.Documents(ctx, nil)
+		assert.Error(t, err)
+		assert.False(t, artifact.Convertible())
+	})
+	t.Run("CSVDuplicateField", func(t *testing.T) {
+		artifact := &ArtifactInfo{Type: PailLocal, Bucket: tempDir, Path: "dup.csv", Format: FileCSV}
+		w, err := artifact.Writer(ctx, nil)
+		require.NoError(t, err)
+		_, err = w.Write([]byte("a,a.b\n1,2\n"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		iter, err := artifact.Documents(ctx, nil)
+		require.NoError(t, err)
+		defer iter.Close()
+		assert.False(t, iter.Next())
+		assert.Error(t, iter.Err())
+	})
+}
+
+func TestUnconvertedArtifacts(t *testing.T) {
+	result := &PerformanceResult{
+		Artifacts: []ArtifactInfo{
+			{Path: "events.ftdc", Format: FileFTDC},
+			{Path: "failed.csv", Format: FileCSV, ConversionError: "bad row"},
+			{Path: "pending.json", Format: FileJSON},
+			{Path: "done.bson", Format: FileBSON},
+		},
+	}
+	done := result.Artifacts[3]
+	result.Artifacts = append(result.Artifacts, ArtifactInfo{Path: done.ConvertedPath(), Format: FileFTDC})
+
+	unconverted := result.UnconvertedArtifacts()
+	require.Len(t, unconverted, 1)
+	assert.Equal(t, "pending.json", unconverted[0].Path)
+
+	failed := result.FailedConversions()
+	require.Len(t, failed, 1)
+	assert.Equal(t, "failed.csv", failed[0].Path)
+}
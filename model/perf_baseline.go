@@ -0,0 +1,191 @@
+package model
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/anser/db"
+	"github.com/mongodb/anser/model"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const perfBaselineCollection = "perf_baselines"
+
+// PerfBaselineKey identifies the tasks of a project and variant that
+// share a baseline.
+type PerfBaselineKey struct {
+	Project  string `bson:"project"`
+	Variant  string `bson:"variant"`
+	TaskName string `bson:"task_name"`
+}
+
+// ID returns a stable hash of the key.
+func (k *PerfBaselineKey) ID() string {
+	hash := sha1.New()
+	_, _ = io.WriteString(hash, k.Project)
+	_, _ = io.WriteString(hash, k.Variant)
+	_, _ = io.WriteString(hash, k.TaskName)
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// Validate checks that every field of the key is set.
+func (k *PerfBaselineKey) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	if k.Project == "" {
+		catcher.Add(errors.New("must specify a project"))
+	}
+	if k.Variant == "" {
+		catcher.Add(errors.New("must specify a variant"))
+	}
+	if k.TaskName == "" {
+		catcher.Add(errors.New("must specify a task"))
+	}
+
+	return catcher.Resolve()
+}
+
+// BaselineKey returns the key of the baseline that the series is
+// compared to.
+func (k *PerfSeriesKey) BaselineKey() PerfBaselineKey {
+	return PerfBaselineKey{
+		Project:  k.Project,
+		Variant:  k.Variant,
+		TaskName: k.TaskName,
+	}
+}
+
+// PerfBaseline designates the version, or the specific performance
+// result, that new results of the tasks are compared to by default,
+// along with the history of the baselines that were set.
+type PerfBaseline struct {
+	ID  string          `bson:"_id"`
+	Key PerfBaselineKey `bson:"key"`
+	// Version is the version of the baseline, which is also set when
+	// the baseline is a specific result.
+	Version      string    `bson:"version"`
+	PerfResultID string    `bson:"perf_result_id,omitempty"`
+	SetBy        string    `bson:"set_by"`
+	SetAt        time.Time `bson:"set_at"`
+
+	History []PerfBaselineChange `bson:"history"`
+
+	env       cedar.Environment
+	populated bool
+}
+
+// PerfBaselineChange records a baseline that was set.
+type PerfBaselineChange struct {
+	Version      string    `bson:"version"`
+	PerfResultID string    `bson:"perf_result_id,omitempty"`
+	SetBy        string    `bson:"set_by"`
+	SetAt        time.Time `bson:"set_at"`
+}
+
+var (
+	perfBaselineKeyKey          = bsonutil.MustHaveTag(PerfBaseline{}, "Key")
+	perfBaselineVersionKey      = bsonutil.MustHaveTag(PerfBaseline{}, "Version")
+	perfBaselinePerfResultIDKey = bsonutil.MustHaveTag(PerfBaseline{}, "PerfResultID")
+	perfBaselineSetByKey        = bsonutil.MustHaveTag(PerfBaseline{}, "SetBy")
+	perfBaselineSetAtKey        = bsonutil.MustHaveTag(PerfBaseline{}, "SetAt")
+	perfBaselineHistoryKey      = bsonutil.MustHaveTag(PerfBaseline{}, "History")
+)
+
+// CreatePerfBaseline returns the baseline for the key, with an id
+// derived from the key.
+func CreatePerfBaseline(key PerfBaselineKey) *PerfBaseline {
+	return &PerfBaseline{
+		ID:  key.ID(),
+		Key: key,
+	}
+}
+
+func (b *PerfBaseline) Setup(e cedar.Environment) { b.env = e }
+
+// IsNil returns true when no baseline was found for the key.
+func (b *PerfBaseline) IsNil() bool { return !b.populated }
+
+// Find loads the baseline with the id of the baseline. It is not an
+// error for no baseline to be set, in which case IsNil is true.
+func (b *PerfBaseline) Find() error {
+	conf, session, err := cedar.GetSessionWithConfig(b.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	b.populated = false
+	err = session.DB(conf.DatabaseName).C(perfBaselineCollection).FindId(b.ID).One(b)
+	if db.ResultsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrapf(err, "problem finding baseline '%s'", b.ID)
+	}
+	b.populated = true
+
+	return nil
+}
+
+// Set replaces the baseline with the given change and appends the
+// change to the history of the baseline. The change must have a
+// version and a user.
+func (b *PerfBaseline) Set(change PerfBaselineChange) error {
+	if err := b.Key.Validate(); err != nil {
+		return errors.Wrap(err, "invalid baseline key")
+	}
+	if change.Version == "" {
+		return errors.New("cannot set a baseline without a version")
+	}
+	if change.SetBy == "" {
+		return errors.New("cannot set a baseline without a user")
+	}
+	if change.SetAt.IsZero() {
+		change.SetAt = time.Now()
+	}
+
+	conf, session, err := cedar.GetSessionWithConfig(b.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	b.ID = b.Key.ID()
+	changeInfo, err := session.DB(conf.DatabaseName).C(perfBaselineCollection).UpsertId(b.ID, bson.M{
+		"$set": bson.M{
+			perfBaselineKeyKey:          b.Key,
+			perfBaselineVersionKey:      change.Version,
+			perfBaselinePerfResultIDKey: change.PerfResultID,
+			perfBaselineSetByKey:        change.SetBy,
+			perfBaselineSetAtKey:        change.SetAt,
+		},
+		"$push": bson.M{perfBaselineHistoryKey: change},
+	})
+	grip.DebugWhen(err == nil, message.Fields{
+		"ns":      model.Namespace{DB: conf.DatabaseName, Collection: perfBaselineCollection},
+		"id":      b.ID,
+		"version": change.Version,
+		"perf_id": change.PerfResultID,
+		"set_by":  change.SetBy,
+		"change":  changeInfo,
+		"op":      "set perf baseline",
+	})
+	if err != nil {
+		return errors.Wrapf(err, "problem setting baseline '%s'", b.ID)
+	}
+
+	b.Version = change.Version
+	b.PerfResultID = change.PerfResultID
+	b.SetBy = change.SetBy
+	b.SetAt = change.SetAt
+	b.History = append(b.History, change)
+	b.populated = true
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerfBaselineKey(t *testing.T) {
+	series := PerfSeriesKey{
+		Project:   "project",
+		Variant:   "variant",
+		TaskName:  "task",
+		TestName:  "test",
+		Arguments: map[string]int32{"threads": 8},
+	}
+	key := series.BaselineKey()
+	assert.Equal(t, PerfBaselineKey{Project: "project", Variant: "variant", TaskName: "task"}, key)
+	assert.NoError(t, key.Validate())
+
+	other := series
+	other.TestName = "other"
+	other.Arguments = nil
+	otherKey := other.BaselineKey()
+	assert.Equal(t, key.ID(), otherKey.ID())
+
+	other.Variant = "other"
+	otherKey = other.BaselineKey()
+	assert.NotEqual(t, key.ID(), otherKey.ID())
+
+	assert.Error(t, (&PerfBaselineKey{Project: "project", Variant: "variant"}).Validate())
+}
+
+func TestPerfBaseline(t *testing.T) {
+	env := cedar.GetEnvironment()
+	conf, session, err := cedar.GetSessionWithConfig(env)
+	require.NoError(t, err)
+	defer session.Close()
+	defer func() {
+		assert.NoError(t, session.DB(conf.DatabaseName).C(perfBaselineCollection).DropCollection())
+	}()
+
+	key := PerfBaselineKey{Project: "project", Variant: "variant", TaskName: "task"}
+	baseline := CreatePerfBaseline(key)
+	baseline.Setup(env)
+	require.NoError(t, baseline.Find())
+	assert.True(t, baseline.IsNil())
+
+	assert.Error(t, baseline.Set(PerfBaselineChange{SetBy: "user"}))
+	assert.Error(t, baseline.Set(PerfBaselineChange{Version: "v1"}))
+	require.NoError(t, baseline.Set(PerfBaselineChange{Version: "v1", SetBy: "user"}))
+	require.NoError(t, baseline.Set(PerfBaselineChange{Version: "v2", PerfResultID: "perf", SetBy: "other"}))
+
+	found := CreatePerfBaseline(key)
+	found.Setup(env)
+	require.NoError(t, found.Find())
+	require.False(t, found.IsNil())
+	assert.Equal(t, key, found.Key)
+	assert.Equal(t, "v2", found.Version)
+	assert.Equal(t, "perf", found.PerfResultID)
+	assert.Equal(t, "other", found.SetBy)
+	assert.False(t, found.SetAt.IsZero())
+	require.Len(t, found.History, 2)
+	assert.Equal(t, "v1", found.History[0].Version)
+	assert.Equal(t, "user", found.History[0].SetBy)
+	assert.Equal(t, "v2", found.History[1].Version)
+
+	invalid := CreatePerfBaseline(PerfBaselineKey{Project: "project"})
+	invalid.Setup(env)
+	assert.Error(t, invalid.Set(PerfBaselineChange{Version: "v1", SetBy: "user"}))
+}
@@ -0,0 +1,175 @@
+package model
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/anser/db"
+	"github.com/mongodb/anser/model"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const perfChangePointCollection = "perf_change_points"
+
+// PerfChangePoint records a statistically significant shift in the
+// values of a rollup over the history of a series. The change point
+// belongs to the first result after the shift.
+type PerfChangePoint struct {
+	ID           string             `bson:"_id"`
+	SeriesID     string             `bson:"series_id"`
+	Series       PerfSeriesKey      `bson:"series"`
+	Measurement  string             `bson:"measurement"`
+	PerfResultID string             `bson:"perf_result_id"`
+	Version      string             `bson:"version"`
+	Before       float64            `bson:"before"`
+	After        float64            `bson:"after"`
+	Magnitude    float64            `bson:"magnitude"`
+	Confidence   float64            `bson:"confidence"`
+	Algorithm    ChangePointDetails `bson:"algorithm"`
+	CalculatedAt time.Time          `bson:"calculated_at"`
+}
+
+// ChangePointDetails identifies the algorithm that detected a change
+// point.
+type ChangePointDetails struct {
+	Name    string `bson:"name"`
+	Version int    `bson:"version"`
+}
+
+var (
+	perfChangePointIDKey           = bsonutil.MustHaveTag(PerfChangePoint{}, "ID")
+	perfChangePointSeriesIDKey     = bsonutil.MustHaveTag(PerfChangePoint{}, "SeriesID")
+	perfChangePointSeriesKey       = bsonutil.MustHaveTag(PerfChangePoint{}, "Series")
+	perfChangePointMeasurementKey  = bsonutil.MustHaveTag(PerfChangePoint{}, "Measurement")
+	perfChangePointPerfResultIDKey = bsonutil.MustHaveTag(PerfChangePoint{}, "PerfResultID")
+	perfChangePointCalculatedAtKey = bsonutil.MustHaveTag(PerfChangePoint{}, "CalculatedAt")
+)
+
+// CreatePerfChangePoint returns a change point for the given series,
+// measurement and result, with an id derived from all three.
+func CreatePerfChangePoint(series PerfSeriesKey, measurement, perfResultID string) *PerfChangePoint {
+	seriesID := series.ID()
+
+	hash := sha1.New()
+	_, _ = io.WriteString(hash, seriesID)
+	_, _ = io.WriteString(hash, measurement)
+	_, _ = io.WriteString(hash, perfResultID)
+
+	return &PerfChangePoint{
+		ID:           fmt.Sprintf("%x", hash.Sum(nil)),
+		SeriesID:     seriesID,
+		Series:       series,
+		Measurement:  measurement,
+		PerfResultID: perfResultID,
+	}
+}
+
+// PerfChangePoints is a collection of change points.
+type PerfChangePoints struct {
+	ChangePoints []PerfChangePoint `bson:"change_points"`
+	env          cedar.Environment
+	populated    bool
+}
+
+// PerfChangePointFindOptions filter change points. Empty fields match
+// all change points.
+type PerfChangePointFindOptions struct {
+	Project      string
+	Variant      string
+	TaskName     string
+	TestName     string
+	Measurement  string
+	PerfResultID string
+}
+
+func (c *PerfChangePoints) Setup(e cedar.Environment) { c.env = e }
+func (c *PerfChangePoints) IsNil() bool               { return !c.populated }
+
+// Find returns the change points matching the options, most recently
+// calculated first.
+func (c *PerfChangePoints) Find(opts PerfChangePointFindOptions) error {
+	conf, session, err := cedar.GetSessionWithConfig(c.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	search := bson.M{}
+	for key, val := range map[string]string{
+		bsonutil.GetDottedKeyName(perfChangePointSeriesKey, perfSeriesKeyProjectKey):  opts.Project,
+		bsonutil.GetDottedKeyName(perfChangePointSeriesKey, perfSeriesKeyVariantKey):  opts.Variant,
+		bsonutil.GetDottedKeyName(perfChangePointSeriesKey, perfSeriesKeyTaskNameKey): opts.TaskName,
+		bsonutil.GetDottedKeyName(perfChangePointSeriesKey, perfSeriesKeyTestNameKey): opts.TestName,
+		perfChangePointMeasurementKey:  opts.Measurement,
+		perfChangePointPerfResultIDKey: opts.PerfResultID,
+	} {
+		if val != "" {
+			search[key] = val
+		}
+	}
+
+	c.populated = false
+	err = session.DB(conf.DatabaseName).C(perfChangePointCollection).Find(search).
+		Sort("-"+perfChangePointCalculatedAtKey, perfChangePointIDKey).All(&c.ChangePoints)
+	if err != nil && !db.ResultsNotFound(err) {
+		return errors.Wrap(err, "problem finding change points")
+	}
+	c.populated = true
+
+	return nil
+}
+
+// ReplaceSeries replaces all change points of the measurement in the
+// series with the given change points, since detecting change points
+// again may move or remove existing ones.
+func (c *PerfChangePoints) ReplaceSeries(series PerfSeriesKey, measurement string, points []PerfChangePoint) error {
+	conf, session, err := cedar.GetSessionWithConfig(c.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	seriesID := series.ID()
+	docs := make([]interface{}, 0, len(points))
+	for _, point := range points {
+		if point.SeriesID != seriesID || point.Measurement != measurement {
+			return errors.Errorf("change point '%s' does not belong to series '%s' and measurement '%s'", point.ID, seriesID, measurement)
+		}
+		docs = append(docs, point)
+	}
+
+	coll := session.DB(conf.DatabaseName).C(perfChangePointCollection)
+	info, err := coll.RemoveAll(bson.M{
+		perfChangePointSeriesIDKey:    seriesID,
+		perfChangePointMeasurementKey: measurement,
+	})
+	if err != nil {
+		return errors.Wrapf(err, "problem removing change points for '%s'", measurement)
+	}
+
+	if len(docs) > 0 {
+		if err = coll.Insert(docs...); err != nil {
+			return errors.Wrapf(err, "problem saving change points for '%s'", measurement)
+		}
+	}
+
+	grip.Debug(message.Fields{
+		"ns":          model.Namespace{DB: conf.DatabaseName, Collection: perfChangePointCollection},
+		"series":      seriesID,
+		"measurement": measurement,
+		"removed":     info.Removed,
+		"inserted":    len(docs),
+		"op":          "replace perf change points",
+	})
+	c.ChangePoints = points
+	c.populated = true
+
+	return nil
+}
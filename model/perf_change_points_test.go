@@ -0,0 +1,116 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerfSeriesKey(t *testing.T) {
+	info := PerformanceResultInfo{
+		Project:   "project",
+		Version:   "version",
+		Variant:   "variant",
+		TaskName:  "task",
+		TaskID:    "task_id",
+		Execution: 1,
+		TestName:  "test",
+		Arguments: map[string]int32{"threads": 8, "size": 64},
+	}
+	key := info.SeriesKey()
+	assert.True(t, key.matches(info))
+
+	other := info
+	other.Version = "other"
+	other.TaskID = "other_task_id"
+	other.Execution = 2
+	other.Arguments = map[string]int32{"size": 64, "threads": 8}
+	otherKey := other.SeriesKey()
+	assert.True(t, key.matches(other))
+	assert.Equal(t, key.ID(), otherKey.ID())
+
+	other.Arguments = map[string]int32{"threads": 16, "size": 64}
+	otherKey = other.SeriesKey()
+	assert.False(t, key.matches(other))
+	assert.NotEqual(t, key.ID(), otherKey.ID())
+
+	other = info
+	other.Variant = "other"
+	otherKey = other.SeriesKey()
+	assert.False(t, key.matches(other))
+	assert.NotEqual(t, key.ID(), otherKey.ID())
+}
+
+func TestLatestPerVersion(t *testing.T) {
+	key := PerfSeriesKey{Project: "project", TestName: "test"}
+	result := func(version string, execution, trial int) PerformanceResult {
+		return PerformanceResult{
+			ID: version + string(rune('0'+execution)) + string(rune('0'+trial)),
+			Info: PerformanceResultInfo{
+				Project:   "project",
+				TestName:  "test",
+				Version:   version,
+				Execution: execution,
+				Trial:     trial,
+			},
+			Rollups: &PerfRollups{},
+		}
+	}
+
+	other := result("a", 0, 0)
+	other.Info.TestName = "other"
+	noRollups := result("c", 0, 0)
+	noRollups.Rollups = nil
+
+	filtered := latestPerVersion(key, []PerformanceResult{
+		result("a", 0, 1),
+		result("a", 0, 0),
+		other,
+		result("b", 0, 0),
+		result("a", 1, 0),
+		noRollups,
+	})
+	require.Len(t, filtered, 2)
+	assert.Equal(t, result("a", 1, 0).ID, filtered[0].ID)
+	assert.Equal(t, result("b", 0, 0).ID, filtered[1].ID)
+}
+
+func TestPerfChangePoints(t *testing.T) {
+	env := cedar.GetEnvironment()
+	conf, session, err := cedar.GetSessionWithConfig(env)
+	require.NoError(t, err)
+	defer session.Close()
+	defer func() {
+		assert.NoError(t, session.DB(conf.DatabaseName).C(perfChangePointCollection).DropCollection())
+	}()
+
+	series := PerfSeriesKey{Project: "project", Variant: "variant", TaskName: "task", TestName: "test"}
+	makePoint := func(measurement, perfID string) PerfChangePoint {
+		point := CreatePerfChangePoint(series, measurement, perfID)
+		point.CalculatedAt = time.Now()
+		return *point
+	}
+
+	points := &PerfChangePoints{}
+	points.Setup(env)
+	require.NoError(t, points.ReplaceSeries(series, "latency", []PerfChangePoint{
+		makePoint("latency", "a"),
+		makePoint("latency", "b"),
+	}))
+	require.NoError(t, points.ReplaceSeries(series, "ops", []PerfChangePoint{makePoint("ops", "a")}))
+	assert.Error(t, points.ReplaceSeries(series, "ops", []PerfChangePoint{makePoint("latency", "a")}))
+
+	require.NoError(t, points.Find(PerfChangePointFindOptions{Project: "project"}))
+	assert.Len(t, points.ChangePoints, 3)
+
+	require.NoError(t, points.ReplaceSeries(series, "latency", []PerfChangePoint{makePoint("latency", "c")}))
+	require.NoError(t, points.Find(PerfChangePointFindOptions{Project: "project", Measurement: "latency"}))
+	require.Len(t, points.ChangePoints, 1)
+	assert.Equal(t, "c", points.ChangePoints[0].PerfResultID)
+
+	require.NoError(t, points.Find(PerfChangePointFindOptions{Project: "other"}))
+	assert.Empty(t, points.ChangePoints)
+}
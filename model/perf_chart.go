@@ -0,0 +1,134 @@
+package model
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/db"
+	"github.com/mongodb/anser/model"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	perfChartCollection = "perf_charts"
+
+	// perfChartTTL is how long cached charts are kept before they are
+	// removed, and drawn again when they are requested.
+	perfChartTTL = 7 * 24 * time.Hour
+)
+
+// PerfChart caches the downsampled timeseries of the metrics of a
+// performance result, for charting, since downsampling requires
+// reading all of the data of the result. Charts expire some time after
+// they are created.
+type PerfChart struct {
+	ID        string            `bson:"_id"`
+	PerfID    string            `bson:"perf_id"`
+	Metric    string            `bson:"metric,omitempty"`
+	MaxPoints int               `bson:"max_points"`
+	Series    []PerfChartSeries `bson:"series"`
+	CreatedAt time.Time         `bson:"created_at"`
+
+	env       cedar.Environment
+	populated bool
+}
+
+// PerfChartSeries is the downsampled timeseries of a metric.
+type PerfChartSeries struct {
+	Metric string           `bson:"metric"`
+	Points []PerfChartPoint `bson:"points"`
+}
+
+// PerfChartPoint is a point of a chart, where X is the time of the
+// sample, in milliseconds since the epoch, or the index of the sample
+// when the data has no timestamps.
+type PerfChartPoint struct {
+	X float64 `bson:"x"`
+	Y float64 `bson:"y"`
+}
+
+// CreatePerfChart returns the chart of the given metric, or of every
+// metric when the metric is empty, of the performance result with the
+// given id, downsampled to at most the given number of points per
+// metric. The id of the chart is derived from the artifacts that the
+// chart is drawn from, so that the cached chart is not used once the
+// result has new data.
+func CreatePerfChart(perfID, metric string, maxPoints int, artifacts []ArtifactInfo) *PerfChart {
+	hash := sha1.New()
+	// every field is terminated, so that adjacent fields cannot run
+	// into each other.
+	write := func(field string) {
+		_, _ = io.WriteString(hash, field)
+		_, _ = io.WriteString(hash, "\x00")
+	}
+	write(perfID)
+	write(metric)
+	write(fmt.Sprint(maxPoints))
+	for _, artifact := range artifacts {
+		write(artifact.Bucket)
+		write(artifact.Path)
+	}
+
+	return &PerfChart{
+		ID:        fmt.Sprintf("%x", hash.Sum(nil)),
+		PerfID:    perfID,
+		Metric:    metric,
+		MaxPoints: maxPoints,
+	}
+}
+
+func (c *PerfChart) Setup(e cedar.Environment) { c.env = e }
+func (c *PerfChart) IsNil() bool               { return !c.populated }
+
+// Find loads the cached chart with the id of the chart.
+func (c *PerfChart) Find() error {
+	conf, session, err := cedar.GetSessionWithConfig(c.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	c.populated = false
+	err = session.DB(conf.DatabaseName).C(perfChartCollection).FindId(c.ID).One(c)
+	if db.ResultsNotFound(err) {
+		return errors.Errorf("could not find chart '%s' in the database", c.ID)
+	} else if err != nil {
+		return errors.Wrapf(err, "problem finding chart '%s'", c.ID)
+	}
+	c.populated = true
+
+	return nil
+}
+
+// Save caches the chart.
+func (c *PerfChart) Save() error {
+	if c.ID == "" {
+		return errors.New("cannot save a chart without an id")
+	}
+
+	conf, session, err := cedar.GetSessionWithConfig(c.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = time.Now()
+	}
+
+	changeInfo, err := session.DB(conf.DatabaseName).C(perfChartCollection).UpsertId(c.ID, c)
+	grip.DebugWhen(err == nil, message.Fields{
+		"ns":      model.Namespace{DB: conf.DatabaseName, Collection: perfChartCollection},
+		"id":      c.ID,
+		"perf_id": c.PerfID,
+		"change":  changeInfo,
+		"op":      "save perf chart",
+	})
+
+	return errors.Wrapf(err, "problem saving chart '%s'", c.ID)
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePerfChart(t *testing.T) {
+	artifacts := []ArtifactInfo{{Bucket: "bucket", Path: "path"}}
+
+	chart := CreatePerfChart("id", "ops", 10, artifacts)
+	assert.Equal(t, "id", chart.PerfID)
+	assert.Equal(t, "ops", chart.Metric)
+	assert.Equal(t, 10, chart.MaxPoints)
+	assert.Equal(t, chart.ID, CreatePerfChart("id", "ops", 10, artifacts).ID)
+
+	for name, other := range map[string]*PerfChart{
+		"MetricAndPoints": CreatePerfChart("id", "ops1", 0, artifacts),
+		"IDAndMetric":     CreatePerfChart("ido", "ps", 10, artifacts),
+		"Artifacts":       CreatePerfChart("id", "ops", 10, []ArtifactInfo{{Bucket: "bucketpath"}}),
+		"NoArtifacts":     CreatePerfChart("id", "ops", 10, nil),
+	} {
+		assert.NotEqual(t, chart.ID, other.ID, name)
+	}
+	assert.NotEqual(t, CreatePerfChart("id", "ops", 10, nil).ID, CreatePerfChart("id", "ops1", 0, nil).ID)
+}
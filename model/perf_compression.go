@@ -0,0 +1,146 @@
+package model
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	"github.com/ulikunitz/xz"
+)
+
+// Open opens the artifact for reading from its offline storage and
+// decompresses it according to its compression. Archives (tarballs
+// and zip files) are read as the concatenation of the regular files
+// that they contain.
+func (a *ArtifactInfo) Open(ctx context.Context, env cedar.Environment) (io.ReadCloser, error) {
+	r, err := a.Reader(ctx, env)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out, err := decompress(a.Compression, r)
+	if err != nil {
+		grip.Warning(r.Close())
+		return nil, errors.Wrapf(err, "problem decompressing '%s'", a.Path)
+	}
+
+	return out, nil
+}
+
+// decompress wraps the reader to decompress it, taking ownership of
+// the reader such that closing the result closes the reader.
+func decompress(compression FileCompression, r io.ReadCloser) (io.ReadCloser, error) {
+	switch compression {
+	case FileUncompressed, "":
+		return r, nil
+	case FileGz:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "problem reading gzip data")
+		}
+
+		return &multiArtifactReader{Reader: gz, closers: []io.Closer{gz, r}}, nil
+	case FileTarGz:
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "problem reading gzip data")
+		}
+
+		return &multiArtifactReader{
+			Reader:  &tarFilesReader{archive: tar.NewReader(gz)},
+			closers: []io.Closer{gz, r},
+		}, nil
+	case FileZip:
+		return newZipFilesReader(r)
+	case FileXz:
+		xr, err := xz.NewReader(r)
+		if err != nil {
+			return nil, errors.Wrap(err, "problem reading xz data")
+		}
+
+		return &multiArtifactReader{Reader: xr, closers: []io.Closer{r}}, nil
+	default:
+		return nil, errors.Errorf("'%s' is not a valid compression", compression)
+	}
+}
+
+// tarFilesReader reads the regular files of a tar archive in order.
+type tarFilesReader struct {
+	archive *tar.Reader
+	current bool
+}
+
+func (r *tarFilesReader) Read(p []byte) (int, error) {
+	for {
+		if r.current {
+			n, err := r.archive.Read(p)
+			if err != io.EOF {
+				return n, err
+			}
+			r.current = false
+			if n > 0 {
+				return n, nil
+			}
+		}
+
+		header, err := r.archive.Next()
+		if err != nil {
+			return 0, err
+		}
+		r.current = header.Typeflag == tar.TypeReg || header.Typeflag == tar.TypeRegA
+	}
+}
+
+// newZipFilesReader reads the files of a zip archive in order. Since
+// zip files cannot be read sequentially, the data is first copied to
+// a temporary file, which is removed when the reader is closed.
+func newZipFilesReader(r io.ReadCloser) (io.ReadCloser, error) {
+	defer r.Close()
+
+	tmp, err := ioutil.TempFile("", "cedar-artifact")
+	if err != nil {
+		return nil, errors.Wrap(err, "problem creating temporary file")
+	}
+	out := &multiArtifactReader{closers: []io.Closer{tmp, removeFile(tmp.Name())}}
+
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		grip.Warning(out.Close())
+		return nil, errors.Wrap(err, "problem reading zip data")
+	}
+
+	archive, err := zip.NewReader(tmp, size)
+	if err != nil {
+		grip.Warning(out.Close())
+		return nil, errors.Wrap(err, "problem reading zip data")
+	}
+
+	readers := []io.Reader{}
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+
+		fr, err := file.Open()
+		if err != nil {
+			grip.Warning(out.Close())
+			return nil, errors.Wrapf(err, "problem opening '%s' in zip data", file.Name)
+		}
+		out.closers = append([]io.Closer{fr}, out.closers...)
+		readers = append(readers, fr)
+	}
+	out.Reader = io.MultiReader(readers...)
+
+	return out, nil
+}
+
+type removeFile string
+
+func (f removeFile) Close() error { return os.Remove(string(f)) }
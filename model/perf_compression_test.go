@@ -0,0 +1,105 @@
+package model
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/ulikunitz/xz"
+)
+
+func TestArtifactDecompression(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tempDir, err := ioutil.TempDir("", "cedar-compression")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	files := []struct {
+		name string
+		data string
+	}{
+		{name: "first", data: "hello "},
+		{name: "second", data: "world"},
+	}
+
+	gzData := &bytes.Buffer{}
+	gw := gzip.NewWriter(gzData)
+	_, err = gw.Write([]byte("hello world"))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+
+	tarData := &bytes.Buffer{}
+	gw = gzip.NewWriter(tarData)
+	tw := tar.NewWriter(gw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "dir", Typeflag: tar.TypeDir, Mode: 0755}))
+	for _, f := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: f.name, Typeflag: tar.TypeReg, Mode: 0644, Size: int64(len(f.data))}))
+		_, err = tw.Write([]byte(f.data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+
+	zipData := &bytes.Buffer{}
+	zw := zip.NewWriter(zipData)
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		require.NoError(t, err)
+		_, err = fw.Write([]byte(f.data))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	xzData := &bytes.Buffer{}
+	xw, err := xz.NewWriter(xzData)
+	require.NoError(t, err)
+	_, err = xw.Write([]byte("hello world"))
+	require.NoError(t, err)
+	require.NoError(t, xw.Close())
+
+	for _, test := range []struct {
+		compression FileCompression
+		data        []byte
+		hasErr      bool
+	}{
+		{compression: FileUncompressed, data: []byte("hello world")},
+		{compression: FileGz, data: gzData.Bytes()},
+		{compression: FileTarGz, data: tarData.Bytes()},
+		{compression: FileZip, data: zipData.Bytes()},
+		{compression: FileXz, data: xzData.Bytes()},
+		{compression: FileXz, data: []byte("hello world"), hasErr: true},
+		{compression: FileGz, data: []byte("hello world"), hasErr: true},
+	} {
+		artifact := &ArtifactInfo{
+			Type:        PailLocal,
+			Bucket:      tempDir,
+			Path:        string(test.compression),
+			Compression: test.compression,
+		}
+		w, err := artifact.Writer(ctx, nil)
+		require.NoError(t, err)
+		_, err = w.Write(test.data)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		r, err := artifact.Open(ctx, nil)
+		if test.hasErr {
+			assert.Error(t, err, string(test.compression))
+			continue
+		}
+		require.NoError(t, err, string(test.compression))
+		data, err := ioutil.ReadAll(r)
+		assert.NoError(t, err, string(test.compression))
+		assert.NoError(t, r.Close(), string(test.compression))
+		assert.Equal(t, "hello world", string(data), string(test.compression))
+	}
+}
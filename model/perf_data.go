@@ -32,25 +32,33 @@ const (
 	MetricTypePercentile50            = "percentile-50th"
 	MetricTypeThroughput              = "throughput"
 	MetricTypeLatency                 = "latency"
+	MetricTypeLast                    = "last"
 )
 
 func (t MetricType) Validate() error {
 	switch t {
 	case MetricTypeMax, MetricTypeMean, MetricTypeMedian, MetricTypeMin, MetricTypeStdDev:
 		return nil
-	case MetricTypePercentile50, MetricTypePercentile80, MetricTypePercentile95, MetricTypePercentile99:
+	case MetricTypePercentile50, MetricTypePercentile80, MetricTypePercentile90, MetricTypePercentile95, MetricTypePercentile99:
+		return nil
+	case MetricTypeSum, MetricTypeThroughput, MetricTypeLatency, MetricTypeLast:
 		return nil
 	default:
 		return errors.Errorf("'%s' is not a valid metric type", t)
 	}
 }
 
+// PerfRollupValue is one computation of a named rollup. The
+// calculator and version identify the algorithm that produced the
+// value.
 type PerfRollupValue struct {
 	Name          string      `bson:"name"`
 	Value         interface{} `bson:"val"`
 	Version       int         `bson:"version"`
 	MetricType    MetricType  `bson:"type"`
 	UserSubmitted bool        `bson:"user"`
+	Calculator    string      `bson:"calculator,omitempty"`
+	CalculatedAt  time.Time   `bson:"calculated_at,omitempty"`
 }
 
 var (
@@ -59,8 +67,13 @@ var (
 	perfRollupValueVersionKey       = bsonutil.MustHaveTag(PerfRollupValue{}, "Version")
 	perfRollupValueMetricTypeKey    = bsonutil.MustHaveTag(PerfRollupValue{}, "MetricType")
 	perfRollupValueUserSubmittedKey = bsonutil.MustHaveTag(PerfRollupValue{}, "UserSubmitted")
+	perfRollupValueCalculatorKey    = bsonutil.MustHaveTag(PerfRollupValue{}, "Calculator")
+	perfRollupValueCalculatedAtKey  = bsonutil.MustHaveTag(PerfRollupValue{}, "CalculatedAt")
 )
 
+// PerfRollups holds the current value of each rollup of a result.
+// Every computation of the rollups is kept in the rollup history, see
+// FindHistory.
 type PerfRollups struct {
 	Stats       []PerfRollupValue `bson:"stats"`
 	ProcessedAt time.Time         `bson:"processed_at"`
@@ -97,11 +110,36 @@ func (v *PerfRollupValue) getFloat() (float64, error) {
 	return 0, errors.Errorf("mismatched type for name %s", v.Name)
 }
 
+// getNumber returns the value of any numeric rollup as a float64.
+func (v *PerfRollupValue) getNumber() (float64, error) {
+	if val, err := v.getFloat(); err == nil {
+		return val, nil
+	}
+
+	val, err := v.getIntLong()
+	return float64(val), err
+}
+
 func (r *PerfRollups) Setup(env cedar.Environment) {
 	r.env = env
 }
 
+// Add records a computation of the named rollup. See AddValue.
 func (r *PerfRollups) Add(name string, version int, userSubmitted bool, t MetricType, value interface{}) error {
+	return r.AddValue(PerfRollupValue{
+		Name:          name,
+		Value:         value,
+		Version:       version,
+		UserSubmitted: userSubmitted,
+		MetricType:    t,
+	})
+}
+
+// AddValue records a computation of a rollup, which is always added to
+// the history of the rollup. The computation replaces the current value
+// of the rollup unless the current value was calculated by a newer
+// version.
+func (r *PerfRollups) AddValue(rollup PerfRollupValue) error {
 	if r.id == "" {
 		return errors.New("rollups missing id")
 	}
@@ -111,15 +149,15 @@ func (r *PerfRollups) Add(name string, version int, userSubmitted bool, t Metric
 	}
 	defer session.Close()
 
-	c := session.DB(conf.DatabaseName).C(perfResultCollection)
-	rollup := PerfRollupValue{
-		Name:          name,
-		Value:         value,
-		Version:       version,
-		UserSubmitted: userSubmitted,
-		MetricType:    t,
+	if rollup.CalculatedAt.IsZero() {
+		rollup.CalculatedAt = time.Now()
 	}
+	// match the precision of the database, so that the history is the
+	// same in memory as it is once reloaded.
+	rollup.CalculatedAt = rollup.CalculatedAt.Round(time.Millisecond)
 
+	db := session.DB(conf.DatabaseName)
+	c := db.C(perfResultCollection)
 	err = tryUpdate(r.id, rollup, c)
 	if err == mgo.ErrNotFound {
 		search := bson.M{perfIDKey: r.id}
@@ -135,26 +173,153 @@ func (r *PerfRollups) Add(name string, version int, userSubmitted bool, t Metric
 		return errors.Wrap(err, "problem adding rollup")
 	}
 
+	entry := perfRollupHistoryEntry{
+		ID:     bson.NewObjectId(),
+		PerfID: r.id,
+		Rollup: rollup,
+	}
+	if err = db.C(perfRollupHistoryCollection).Insert(entry); err != nil {
+		return errors.Wrapf(err, "problem adding rollup '%s' to the history of '%s'", rollup.Name, r.id)
+	}
+
 	for i := range r.Stats {
-		if r.Stats[i].Name == name {
-			r.Stats[i].Version = version
-			r.Stats[i].Value = value
-			r.Stats[i].UserSubmitted = userSubmitted
-			r.Stats[i].MetricType = t
+		if r.Stats[i].Name == rollup.Name {
+			if r.Stats[i].Version <= rollup.Version {
+				r.Stats[i] = rollup
+			}
 			return nil
 		}
 	}
-	r.Stats = append(r.Stats, PerfRollupValue{
-		Name:          name,
-		Value:         value,
-		Version:       version,
-		UserSubmitted: userSubmitted,
-		MetricType:    t,
-	})
+	r.Stats = append(r.Stats, rollup)
 	r.Count++
 	return nil
 }
 
+// Revert restores the most recent computation of the named rollup by
+// the given version as its current value, regardless of the version
+// of the current value. The history is not modified, and a later
+// computation by the same or a newer version replaces the value again.
+func (r *PerfRollups) Revert(name string, version int) error {
+	if r.id == "" {
+		return errors.New("rollups missing id")
+	}
+
+	conf, session, err := cedar.GetSessionWithConfig(r.env)
+	if err != nil {
+		return errors.Wrap(err, "error connecting")
+	}
+	defer session.Close()
+
+	rollup, err := r.findLatestVersion(session.DB(conf.DatabaseName).C(perfRollupHistoryCollection), name, version)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if rollup == nil {
+		return errors.Errorf("rollup '%s' has no value calculated by version %d", name, version)
+	}
+
+	search := bson.M{
+		perfIDKey: r.id,
+		bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsStatsKey, perfRollupValueNameKey): name,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsStatsKey, "$"): *rollup,
+		},
+	}
+	if err = session.DB(conf.DatabaseName).C(perfResultCollection).Update(search, update); err != nil {
+		return errors.Wrapf(err, "problem reverting rollup '%s' of '%s'", name, r.id)
+	}
+
+	for i := range r.Stats {
+		if r.Stats[i].Name == name {
+			r.Stats[i] = *rollup
+			break
+		}
+	}
+
+	return nil
+}
+
+// MarkProcessed records that the rollups have been (re)calculated,
+// updating the processed time, count and validity of the rollups in
+// the database without modifying the individual stats.
+func (r *PerfRollups) MarkProcessed(valid bool) error {
+	if r.id == "" {
+		return errors.New("rollups missing id")
+	}
+	conf, session, err := cedar.GetSessionWithConfig(r.env)
+	if err != nil {
+		return errors.Wrap(err, "error connecting")
+	}
+	defer session.Close()
+
+	processedAt := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsProcessedAtKey): processedAt,
+			bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsCountKey):       len(r.Stats),
+			bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsValidKey):       valid,
+		},
+	}
+
+	if err = session.DB(conf.DatabaseName).C(perfResultCollection).UpdateId(r.id, update); err != nil {
+		return errors.Wrapf(err, "problem marking rollups for '%s' processed", r.id)
+	}
+
+	r.ProcessedAt = processedAt
+	r.Count = len(r.Stats)
+	r.Valid = valid
+
+	return nil
+}
+
+// RemoveStale removes the rollups calculated by an older version of a
+// calculator, where versions maps the names of the calculators to
+// their current versions. Once the rollups of a result are
+// recalculated, the stale rollups are those that the current versions
+// of the calculators no longer produce. The rollups remain in the
+// rollup history.
+func (r *PerfRollups) RemoveStale(versions map[string]int) error {
+	if r.id == "" {
+		return errors.New("rollups missing id")
+	}
+
+	stats := []PerfRollupValue{}
+	for _, rollup := range r.Stats {
+		if version, ok := versions[rollup.Calculator]; ok && rollup.Version < version {
+			continue
+		}
+		stats = append(stats, rollup)
+	}
+	if len(stats) == len(r.Stats) {
+		return nil
+	}
+
+	conf, session, err := cedar.GetSessionWithConfig(r.env)
+	if err != nil {
+		return errors.Wrap(err, "error connecting")
+	}
+	defer session.Close()
+
+	update := bson.M{
+		"$pull": bson.M{
+			bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsStatsKey): bson.M{"$or": staleRollupValues(versions)},
+		},
+		"$set": bson.M{
+			bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsCountKey): len(stats),
+		},
+	}
+	if err = session.DB(conf.DatabaseName).C(perfResultCollection).UpdateId(r.id, update); err != nil {
+		return errors.Wrapf(err, "problem removing stale rollups of '%s'", r.id)
+	}
+
+	r.Stats = stats
+	r.Count = len(stats)
+
+	return nil
+}
+
 func tryUpdate(id string, r PerfRollupValue, c *mgo.Collection) error {
 	query := bson.M{
 		perfIDKey: id,
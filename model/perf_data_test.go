@@ -200,6 +200,113 @@ func (s *perfRollupSuite) TestUpdateExistingEntry() {
 	s.Equal(false, out.Rollups.Stats[0].UserSubmitted)
 }
 
+func (s *perfRollupSuite) TestHistory() {
+	s.NoError(s.r.AddValue(PerfRollupValue{Name: "mean", Value: 1.0, Version: 1, Calculator: "first"}))
+	s.NoError(s.r.AddValue(PerfRollupValue{Name: "mean", Value: 2.0, Version: 2, Calculator: "second"}))
+	s.NoError(s.r.AddValue(PerfRollupValue{Name: "mean", Value: 3.0, Version: 1, Calculator: "first"}))
+
+	val, err := s.r.GetFloat("mean")
+	s.NoError(err)
+	s.Equal(2.0, val)
+
+	history, err := s.r.FindHistory("mean")
+	s.Require().NoError(err)
+	s.Equal("mean", history.Name)
+	s.Require().NotNil(history.Latest)
+	s.Equal(2.0, history.Latest.Value)
+	s.Require().Len(history.Values, 3)
+	for i, expected := range []float64{1.0, 2.0, 3.0} {
+		s.Equal(expected, history.Values[i].Value)
+		s.False(history.Values[i].CalculatedAt.IsZero())
+	}
+	s.Equal("second", history.Values[1].Calculator)
+
+	history, err = s.r.FindHistory("DNE")
+	s.Require().NoError(err)
+	s.Nil(history.Latest)
+	s.Empty(history.Values)
+
+	out := PerformanceResult{}
+	s.Require().NoError(s.c.FindId(s.r.id).One(&out))
+	s.Require().NotNil(out.Rollups)
+	val, err = out.Rollups.GetFloat("mean")
+	s.NoError(err)
+	s.Equal(2.0, val)
+
+	// reverting restores the latest value of the version
+	s.NoError(s.r.Revert("mean", 1))
+	val, err = s.r.GetFloat("mean")
+	s.NoError(err)
+	s.Equal(3.0, val)
+	history, err = s.r.FindHistory("mean")
+	s.Require().NoError(err)
+	s.Len(history.Values, 3)
+	out = PerformanceResult{}
+	s.Require().NoError(s.c.FindId(s.r.id).One(&out))
+	val, err = out.Rollups.GetFloat("mean")
+	s.NoError(err)
+	s.Equal(3.0, val)
+
+	s.Error(s.r.Revert("mean", 3))
+	s.Error(s.r.Revert("DNE", 1))
+}
+
+func (s *perfRollupSuite) TestHistoryIsComplete() {
+	const computations = 1000
+	for i := 0; i < computations; i++ {
+		s.Require().NoError(s.r.AddValue(PerfRollupValue{Name: "mean", Value: float64(i), Version: 1}))
+	}
+	s.Require().NoError(s.r.AddValue(PerfRollupValue{Name: "max", Value: 1.0, Version: 1}))
+
+	// the history of a rollup is neither truncated nor affected by
+	// the computations of the other rollups.
+	history, err := s.r.FindHistory("mean")
+	s.Require().NoError(err)
+	s.Require().Len(history.Values, computations)
+	s.Equal(0.0, history.Values[0].Value)
+	s.Equal(float64(computations-1), history.Values[computations-1].Value)
+
+	history, err = s.r.FindHistory("max")
+	s.Require().NoError(err)
+	s.Len(history.Values, 1)
+
+	s.NoError(s.r.Revert("mean", 1))
+	val, err := s.r.GetFloat("mean")
+	s.NoError(err)
+	s.Equal(float64(computations-1), val)
+}
+
+func (s *perfRollupSuite) TestRemoveStale() {
+	s.NoError(s.r.AddValue(PerfRollupValue{Name: "old", Value: 1.0, Version: 1, Calculator: "calculator"}))
+	s.NoError(s.r.AddValue(PerfRollupValue{Name: "current", Value: 2.0, Version: 2, Calculator: "calculator"}))
+	s.NoError(s.r.AddValue(PerfRollupValue{Name: "other", Value: 3.0, Version: 1, Calculator: "other"}))
+	s.Require().NoError(s.r.MarkProcessed(true))
+	count := s.r.Count
+
+	s.NoError(s.r.RemoveStale(map[string]int{"calculator": 2}))
+	s.Equal(count-1, s.r.Count)
+	_, err := s.r.GetFloat("old")
+	s.Error(err)
+
+	out := PerformanceResult{}
+	s.Require().NoError(s.c.FindId(s.r.id).One(&out))
+	s.Require().NotNil(out.Rollups)
+	s.Len(out.Rollups.Stats, count-1)
+	s.Equal(count-1, out.Rollups.Count)
+	for _, name := range []string{"current", "other", "float"} {
+		_, err = out.Rollups.GetFloat(name)
+		s.NoError(err, name)
+	}
+	_, err = out.Rollups.GetFloat("old")
+	s.Error(err)
+
+	// the removed rollups remain in the history.
+	history, err := s.r.FindHistory("old")
+	s.Require().NoError(err)
+	s.Nil(history.Latest)
+	s.Len(history.Values, 1)
+}
+
 func (s *perfRollupSuite) TearDownTest() {
 	conf, session, err := cedar.GetSessionWithConfig(s.r.env)
 	s.Require().NoError(err)
@@ -208,6 +315,8 @@ func (s *perfRollupSuite) TearDownTest() {
 	c := session.DB(conf.DatabaseName).C(perfResultCollection)
 	err = c.DropCollection()
 	s.NoError(err)
+	err = session.DB(conf.DatabaseName).C(perfRollupHistoryCollection).DropCollection()
+	s.NoError(err)
 }
 
 func (s *perfRollupSuite) TestValidate() {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/mongodb/ftdc"
 	"github.com/mongodb/ftdc/events"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 )
 
@@ -14,12 +15,45 @@ const defaultPointsPerChunk = 10 * 1000 // ten thousand
 // DumpPerformanceSeries takes a stream of PerformancePoints, converts
 // them to FTDC data and writes that data to the provided writer.
 //
-// FTDC makes it complicated to stream data directly from the input
-// channel to the writer, and indeed, this implementation will not
-// start writing to the output stream until the input stream is
-// exhausted, but future work should allow us to avoid that detail.
+// Chunks are written to the output as soon as they fill, so memory
+// use is bounded by the chunk size rather than the length of the
+// series. If the context is canceled or a point cannot be added, the
+// points collected so far are flushed before returning the error, so
+// the output always holds a readable (if partial) FTDC file.
 func DumpPerformanceSeries(ctx context.Context, stream <-chan events.Performance, metadata interface{}, output io.Writer) error {
-	collector := ftdc.NewBatchCollector(defaultPointsPerChunk)
+	return dumpSeries(metadata, output, func() (interface{}, bool, error) {
+		select {
+		case <-ctx.Done():
+			return nil, false, errors.New("operation canceled")
+		case point, ok := <-stream:
+			return point, ok, nil
+		}
+	})
+}
+
+// DumpCustomSeries takes a stream of custom points, with arbitrary
+// named metrics, converts them to FTDC data and writes that data to
+// the provided writer, in the same way as DumpPerformanceSeries.
+func DumpCustomSeries(ctx context.Context, stream <-chan events.Custom, metadata interface{}, output io.Writer) error {
+	return dumpSeries(metadata, output, func() (interface{}, bool, error) {
+		select {
+		case <-ctx.Done():
+			return nil, false, errors.New("operation canceled")
+		case point, ok := <-stream:
+			return point, ok, nil
+		}
+	})
+}
+
+// dumpSeries adds the points returned by next to an FTDC collector
+// until next reports that there are no more points or returns an
+// error.
+func dumpSeries(metadata interface{}, output io.Writer, next func() (interface{}, bool, error)) error {
+	if output == nil {
+		return errors.New("must specify an output writer")
+	}
+
+	collector := ftdc.NewStreamingCollector(defaultPointsPerChunk, output)
 
 	if metadata != nil {
 		if err := collector.SetMetadata(metadata); err != nil {
@@ -27,34 +61,25 @@ func DumpPerformanceSeries(ctx context.Context, stream <-chan events.Performance
 		}
 	}
 
-conversion:
-	for {
-		select {
-		case <-ctx.Done():
-			return errors.New("operation canceled")
-		case point, ok := <-stream:
-			if !ok {
-				break conversion
-			}
+	catcher := grip.NewBasicCatcher()
 
-			if err := collector.Add(point); err != nil {
-				return errors.Wrap(err, "problem adding document to ftdc")
-			}
+	for {
+		point, ok, err := next()
+		if err != nil {
+			catcher.Add(err)
+			break
+		}
+		if !ok {
+			break
 		}
-	}
 
-	payload, err := collector.Resolve()
-	if err != nil {
-		return errors.Wrap(err, "problem dumping ftdc data")
+		if err = collector.Add(point); err != nil {
+			catcher.Add(errors.Wrap(err, "problem adding document to ftdc"))
+			break
+		}
 	}
 
-	n, err := output.Write(payload)
-	if err != nil {
-		return errors.Wrap(err, "problem writing data")
-	}
-	if n != len(payload) {
-		return errors.New("data improperly flushed")
-	}
+	catcher.Add(errors.Wrap(ftdc.FlushCollector(collector, output), "problem flushing ftdc data"))
 
-	return nil
+	return catcher.Resolve()
 }
@@ -0,0 +1,154 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/ftdc/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lockedBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Len()
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Bytes()
+}
+
+func makePerformancePoint(i int) events.Performance {
+	point := events.Performance{
+		Timestamp: time.Now(),
+	}
+	point.Counters.Operations = int64(i)
+	point.Counters.Number = 1
+	point.Timers.Duration = time.Millisecond
+	point.Gauges.Workers = 2
+	return point
+}
+
+func countFTDCSamples(t *testing.T, data []byte) int {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	iter := ftdc.ReadChunks(ctx, bytes.NewReader(data))
+	defer iter.Close()
+
+	count := 0
+	for iter.Next() {
+		count += iter.Chunk().Size()
+	}
+	require.NoError(t, iter.Err())
+	return count
+}
+
+func TestDumpPerformanceSeries(t *testing.T) {
+	t.Run("NilWriter", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		assert.Error(t, DumpPerformanceSeries(ctx, make(chan events.Performance), nil, nil))
+	})
+	t.Run("WritesChunksBeforeStreamEnds", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		output := &lockedBuffer{}
+		stream := make(chan events.Performance)
+		errs := make(chan error, 1)
+		go func() {
+			errs <- DumpPerformanceSeries(ctx, stream, PerformanceResultInfo{Project: "test"}, output)
+		}()
+
+		total := defaultPointsPerChunk + 1
+		for i := 0; i < total; i++ {
+			stream <- makePerformancePoint(i)
+		}
+		assert.NotZero(t, output.Len(), "first chunk should be flushed before the stream closes")
+
+		close(stream)
+		require.NoError(t, <-errs)
+		assert.Equal(t, total, countFTDCSamples(t, output.Bytes()))
+	})
+	t.Run("CanceledStreamIsReadable", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		output := &lockedBuffer{}
+		stream := make(chan events.Performance)
+		errs := make(chan error, 1)
+		go func() {
+			errs <- DumpPerformanceSeries(ctx, stream, nil, output)
+		}()
+
+		total := 100
+		for i := 0; i < total; i++ {
+			stream <- makePerformancePoint(i)
+		}
+		cancel()
+
+		assert.Error(t, <-errs)
+		assert.Equal(t, total, countFTDCSamples(t, output.Bytes()))
+	})
+	t.Run("EmptyStream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		output := &lockedBuffer{}
+		stream := make(chan events.Performance)
+		close(stream)
+
+		assert.NoError(t, DumpPerformanceSeries(ctx, stream, nil, output))
+		assert.Zero(t, output.Len())
+	})
+}
+
+func TestDumpCustomSeries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	output := &bytes.Buffer{}
+	stream := make(chan events.Custom)
+	go func() {
+		defer close(stream)
+		for i := 0; i < 10; i++ {
+			point := events.MakeCustom(2)
+			assert.NoError(t, point.Add("engine.cache_hits", int64(i)))
+			assert.NoError(t, point.Add("engine.dirty_ratio", float64(i)/10))
+			stream <- point
+		}
+	}()
+
+	require.NoError(t, DumpCustomSeries(ctx, stream, PerformanceResultInfo{Project: "test"}, output))
+	assert.Equal(t, 10, countFTDCSamples(t, output.Bytes()))
+
+	iter := ftdc.ReadChunks(ctx, bytes.NewReader(output.Bytes()))
+	defer iter.Close()
+	require.True(t, iter.Next())
+	names := []string{}
+	for _, metric := range iter.Chunk().Metrics {
+		names = append(names, metric.Key())
+	}
+	assert.Contains(t, names, "engine.cache_hits")
+	assert.Contains(t, names, "engine.dirty_ratio")
+}
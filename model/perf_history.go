@@ -0,0 +1,128 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// PerfHistoryPoint is the value of a rollup for one version of a
+// test series, from the performance result with the id.
+type PerfHistoryPoint struct {
+	Version      string      `bson:"version"`
+	PerfResultID string      `bson:"perf_result_id"`
+	CreatedAt    time.Time   `bson:"created_ts"`
+	Value        interface{} `bson:"value"`
+}
+
+// PerfHistory is the history of one rollup of a test series, ordered
+// by the creation time of the results. Unlike PerfSeries, the history
+// is computed by the database and only holds the requested rollup.
+type PerfHistory struct {
+	Key    PerfSeriesKey
+	Metric string
+	Points []PerfHistoryPoint
+
+	env       cedar.Environment
+	populated bool
+}
+
+func (h *PerfHistory) Setup(e cedar.Environment) { h.env = e }
+func (h *PerfHistory) IsNil() bool               { return !h.populated }
+
+// Find loads the history for the key and metric. As with PerfSeries,
+// there is at most one point per version: when a test ran more than
+// once for a version, the latest execution and the first trial are
+// used.
+func (h *PerfHistory) Find() error {
+	if h.Metric == "" {
+		return errors.New("must specify a metric")
+	}
+
+	conf, session, err := cedar.GetSessionWithConfig(h.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	h.populated = false
+	h.Points = []PerfHistoryPoint{}
+	err = session.DB(conf.DatabaseName).C(perfResultCollection).Pipe(h.pipeline()).All(&h.Points)
+	if err != nil {
+		return errors.Wrapf(err, "problem finding history of '%s'", h.Metric)
+	}
+	h.populated = true
+
+	return nil
+}
+
+func (h *PerfHistory) pipeline() []bson.M {
+	infoKey := func(key string) string { return bsonutil.GetDottedKeyName(perfInfoKey, key) }
+	statsKey := bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsStatsKey)
+
+	match := bson.M{
+		infoKey(perfResultInfoProjectKey):                           h.Key.Project,
+		infoKey(perfResultInfoVariantKey):                           h.Key.Variant,
+		infoKey(perfResultInfoTaskNameKey):                          h.Key.TaskName,
+		infoKey(perfResultInfoTestNameKey):                          h.Key.TestName,
+		bsonutil.GetDottedKeyName(statsKey, perfRollupValueNameKey): h.Metric,
+	}
+	for name, val := range h.Key.Arguments {
+		match[bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoArgumentsKey, name)] = val
+	}
+
+	return []bson.M{
+		{"$match": match},
+		{"$project": bson.M{
+			"_id":            0,
+			"perf_result_id": "$_id",
+			"version":        "$" + infoKey(perfResultInfoVersionKey),
+			"execution":      "$" + infoKey(perfResultInfoExecutionKey),
+			"trial":          "$" + infoKey(perfResultInfoTrialKey),
+			"created_ts":     1,
+			// the series only includes results with exactly the
+			// arguments of the key, rather than a superset.
+			"num_args": bson.M{"$size": bson.M{"$objectToArray": bson.M{
+				"$ifNull": []interface{}{"$" + infoKey(perfResultInfoArgumentsKey), bson.M{}},
+			}}},
+			"value": bson.M{"$arrayElemAt": []interface{}{
+				bson.M{"$map": bson.M{
+					"input": bson.M{"$filter": bson.M{
+						"input": "$" + statsKey,
+						"as":    "stat",
+						"cond":  bson.M{"$eq": []interface{}{"$$stat." + perfRollupValueNameKey, h.Metric}},
+					}},
+					"as": "stat",
+					"in": "$$stat." + perfRollupValueValueKey,
+				}},
+				0,
+			}},
+		}},
+		{"$match": bson.M{"num_args": len(h.Key.Arguments)}},
+		{"$sort": bson.D{
+			{Name: "version", Value: 1},
+			{Name: "execution", Value: -1},
+			{Name: "trial", Value: 1},
+		}},
+		{"$group": bson.M{
+			"_id":            "$version",
+			"perf_result_id": bson.M{"$first": "$perf_result_id"},
+			"created_ts":     bson.M{"$first": "$created_ts"},
+			"value":          bson.M{"$first": "$value"},
+		}},
+		{"$sort": bson.D{
+			{Name: "created_ts", Value: 1},
+			{Name: "_id", Value: 1},
+		}},
+		{"$project": bson.M{
+			"_id":            0,
+			"version":        "$_id",
+			"perf_result_id": 1,
+			"created_ts":     1,
+			"value":          1,
+		}},
+	}
+}
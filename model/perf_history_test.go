@@ -0,0 +1,42 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestPerfHistoryPipeline(t *testing.T) {
+	history := PerfHistory{
+		Key: PerfSeriesKey{
+			Project:   "project",
+			Variant:   "variant",
+			TaskName:  "task",
+			TestName:  "test",
+			Arguments: map[string]int32{"threads": 4},
+		},
+		Metric: "latency",
+	}
+
+	pipeline := history.pipeline()
+	require.NotEmpty(t, pipeline)
+	assert.Equal(t, bson.M{
+		"info.project":       "project",
+		"info.variant":       "variant",
+		"info.task_name":     "task",
+		"info.test_name":     "test",
+		"info.args.threads":  int32(4),
+		"rollups.stats.name": "latency",
+	}, pipeline[0]["$match"])
+	assert.Equal(t, bson.M{"num_args": 1}, pipeline[2]["$match"])
+
+	final, ok := pipeline[len(pipeline)-1]["$project"].(bson.M)
+	require.True(t, ok)
+	for _, field := range []string{"version", "perf_result_id", "created_ts", "value"} {
+		assert.Contains(t, final, field)
+	}
+
+	assert.Error(t, (&PerfHistory{}).Find())
+}
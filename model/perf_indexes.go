@@ -0,0 +1,57 @@
+package model
+
+import (
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+)
+
+// perfResultIndexes support the queries on performance results: the
+// series and history of a test, and the (sorted) task, task name, and
+// version queries of the REST API.
+var perfResultIndexes = [][]string{
+	{
+		bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoProjectKey),
+		bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoVariantKey),
+		bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoTaskNameKey),
+		bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoTestNameKey),
+		"created_ts",
+	},
+	{bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoTaskIDKey), "created_ts"},
+	{bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoTaskNameKey), "created_ts"},
+	{bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoVersionKey), "created_ts"},
+}
+
+// EnsurePerfResultIndexes creates the indexes of the performance
+// results collection, the index of the history of their rollups, and
+// the index that expires the cached charts of the results, in the
+// background, if they do not exist.
+func EnsurePerfResultIndexes(env cedar.Environment) error {
+	conf, session, err := cedar.GetSessionWithConfig(env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	c := session.DB(conf.DatabaseName).C(perfResultCollection)
+	catcher := grip.NewBasicCatcher()
+	for _, key := range perfResultIndexes {
+		catcher.Add(errors.Wrapf(c.EnsureIndex(mgo.Index{Key: key, Background: true}),
+			"problem creating index %v on '%s'", key, perfResultCollection))
+	}
+
+	catcher.Add(errors.Wrapf(session.DB(conf.DatabaseName).C(perfRollupHistoryCollection).EnsureIndex(mgo.Index{
+		Key:        perfRollupHistoryIndex,
+		Background: true,
+	}), "problem creating index %v on '%s'", perfRollupHistoryIndex, perfRollupHistoryCollection))
+
+	catcher.Add(errors.Wrapf(session.DB(conf.DatabaseName).C(perfChartCollection).EnsureIndex(mgo.Index{
+		Key:         []string{"created_at"},
+		ExpireAfter: perfChartTTL,
+		Background:  true,
+	}), "problem creating expiration index on '%s'", perfChartCollection))
+
+	return catcher.Resolve()
+}
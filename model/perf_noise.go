@@ -0,0 +1,310 @@
+package model
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/anser/db"
+	"github.com/mongodb/anser/model"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const perfNoiseCollection = "perf_noise"
+
+// PerfNoise records how noisy a rollup of a series has been over its
+// recent versions. Series that are too noisy cannot be relied on to
+// detect regressions.
+type PerfNoise struct {
+	ID           string        `bson:"_id"`
+	SeriesID     string        `bson:"series_id"`
+	Series       PerfSeriesKey `bson:"series"`
+	Measurement  string        `bson:"measurement"`
+	Versions     int           `bson:"versions"`
+	Trials       int           `bson:"trials"`
+	TrialCV      float64       `bson:"trial_cv"`
+	HistoryCV    float64       `bson:"history_cv"`
+	Score        float64       `bson:"score"`
+	Threshold    float64       `bson:"threshold"`
+	Noisy        bool          `bson:"noisy"`
+	CalculatedAt time.Time     `bson:"calculated_at"`
+}
+
+var (
+	perfNoiseIDKey          = bsonutil.MustHaveTag(PerfNoise{}, "ID")
+	perfNoiseSeriesIDKey    = bsonutil.MustHaveTag(PerfNoise{}, "SeriesID")
+	perfNoiseSeriesKey      = bsonutil.MustHaveTag(PerfNoise{}, "Series")
+	perfNoiseMeasurementKey = bsonutil.MustHaveTag(PerfNoise{}, "Measurement")
+	perfNoiseScoreKey       = bsonutil.MustHaveTag(PerfNoise{}, "Score")
+	perfNoiseNoisyKey       = bsonutil.MustHaveTag(PerfNoise{}, "Noisy")
+)
+
+// CreatePerfNoise returns the noise of the given series and
+// measurement, with an id derived from both.
+func CreatePerfNoise(series PerfSeriesKey, measurement string) *PerfNoise {
+	seriesID := series.ID()
+
+	hash := sha1.New()
+	_, _ = io.WriteString(hash, seriesID)
+	_, _ = io.WriteString(hash, measurement)
+
+	return &PerfNoise{
+		ID:          fmt.Sprintf("%x", hash.Sum(nil)),
+		SeriesID:    seriesID,
+		Series:      series,
+		Measurement: measurement,
+	}
+}
+
+// PerfNoiseScores is a collection of noise scores.
+type PerfNoiseScores struct {
+	Scores    []PerfNoise `bson:"scores"`
+	env       cedar.Environment
+	populated bool
+}
+
+// PerfNoiseFindOptions filter noise scores. Empty fields match all
+// scores.
+type PerfNoiseFindOptions struct {
+	Project   string
+	SeriesID  string
+	NoisyOnly bool
+}
+
+func (n *PerfNoiseScores) Setup(e cedar.Environment) { n.env = e }
+func (n *PerfNoiseScores) IsNil() bool               { return !n.populated }
+
+// Find returns the noise scores matching the options, noisiest first.
+func (n *PerfNoiseScores) Find(opts PerfNoiseFindOptions) error {
+	conf, session, err := cedar.GetSessionWithConfig(n.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	search := bson.M{}
+	if opts.Project != "" {
+		search[bsonutil.GetDottedKeyName(perfNoiseSeriesKey, perfSeriesKeyProjectKey)] = opts.Project
+	}
+	if opts.SeriesID != "" {
+		search[perfNoiseSeriesIDKey] = opts.SeriesID
+	}
+	if opts.NoisyOnly {
+		search[perfNoiseNoisyKey] = true
+	}
+
+	n.populated = false
+	err = session.DB(conf.DatabaseName).C(perfNoiseCollection).Find(search).
+		Sort("-"+perfNoiseScoreKey, perfNoiseIDKey).All(&n.Scores)
+	if err != nil && !db.ResultsNotFound(err) {
+		return errors.Wrap(err, "problem finding noise scores")
+	}
+	n.populated = true
+
+	return nil
+}
+
+// ReplaceSeries replaces all noise scores of the series with the
+// given scores, so that measurements that are no longer reported do
+// not keep stale scores.
+func (n *PerfNoiseScores) ReplaceSeries(series PerfSeriesKey, scores []PerfNoise) error {
+	conf, session, err := cedar.GetSessionWithConfig(n.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	seriesID := series.ID()
+	docs := make([]interface{}, 0, len(scores))
+	for _, score := range scores {
+		if score.SeriesID != seriesID {
+			return errors.Errorf("noise score '%s' does not belong to series '%s'", score.ID, seriesID)
+		}
+		docs = append(docs, score)
+	}
+
+	coll := session.DB(conf.DatabaseName).C(perfNoiseCollection)
+	info, err := coll.RemoveAll(bson.M{perfNoiseSeriesIDKey: seriesID})
+	if err != nil {
+		return errors.Wrapf(err, "problem removing noise scores for '%s'", seriesID)
+	}
+
+	if len(docs) > 0 {
+		if err = coll.Insert(docs...); err != nil {
+			return errors.Wrapf(err, "problem saving noise scores for '%s'", seriesID)
+		}
+	}
+
+	grip.Debug(message.Fields{
+		"ns":       model.Namespace{DB: conf.DatabaseName, Collection: perfNoiseCollection},
+		"series":   seriesID,
+		"removed":  info.Removed,
+		"inserted": len(docs),
+		"op":       "replace perf noise scores",
+	})
+	n.Scores = scores
+	n.populated = true
+
+	return nil
+}
+
+// Map returns the scores keyed by measurement.
+func (n *PerfNoiseScores) Map() map[string]PerfNoise {
+	out := make(map[string]PerfNoise, len(n.Scores))
+	for _, score := range n.Scores {
+		out[score.Measurement] = score
+	}
+
+	return out
+}
+
+// FindRecentPerfSeries returns the keys of the series with rollups
+// from results created since the given time, sorted by series id.
+func FindRecentPerfSeries(env cedar.Environment, since time.Time) ([]PerfSeriesKey, error) {
+	conf, session, err := cedar.GetSessionWithConfig(env)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer session.Close()
+
+	search := bson.M{
+		"created_ts": bson.M{"$gte": since},
+		bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsStatsKey): bson.M{"$exists": true},
+	}
+
+	results := []PerformanceResult{}
+	err = session.DB(conf.DatabaseName).C(perfResultCollection).Find(search).
+		Select(bson.M{perfInfoKey: 1}).All(&results)
+	if err != nil && !db.ResultsNotFound(err) {
+		return nil, errors.Wrap(err, "problem finding recent results")
+	}
+
+	series := map[string]PerfSeriesKey{}
+	for _, result := range results {
+		key := result.Info.SeriesKey()
+		series[key.ID()] = key
+	}
+
+	ids := make([]string, 0, len(series))
+	for id := range series {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	keys := make([]PerfSeriesKey, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, series[id])
+	}
+
+	return keys, nil
+}
+
+// FindPerfSeriesTrials returns the values of every rollup of the
+// series in each trial of the latest execution of at most limit of
+// the most recent versions, keyed by rollup name. The versions are
+// ordered oldest first and a version is empty for rollups that none
+// of its trials reported. Non-numeric rollups are ignored.
+func FindPerfSeriesTrials(env cedar.Environment, key PerfSeriesKey, limit int) (map[string][][]float64, error) {
+	conf, session, err := cedar.GetSessionWithConfig(env)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer session.Close()
+
+	search := bson.M{
+		bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoProjectKey):  key.Project,
+		bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoVariantKey):  key.Variant,
+		bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoTaskNameKey): key.TaskName,
+		bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoTestNameKey): key.TestName,
+		bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsStatsKey):    bson.M{"$exists": true},
+	}
+
+	// read the results from the newest, and only as far as the oldest
+	// of the versions within the limit, since the number of results of
+	// each version depends on its trials and executions.
+	iter := session.DB(conf.DatabaseName).C(perfResultCollection).Find(search).
+		Select(bson.M{
+			perfInfoKey: 1,
+			bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsStatsKey): 1,
+		}).
+		Sort("-created_ts").Iter()
+	results := []PerformanceResult{}
+	seen := map[string]bool{}
+	result := PerformanceResult{}
+	for iter.Next(&result) {
+		if result.Rollups != nil && key.matches(result.Info) {
+			if !seen[result.Info.Version] {
+				if limit > 0 && len(seen) == limit {
+					break
+				}
+				seen[result.Info.Version] = true
+			}
+			results = append(results, result)
+		}
+		result = PerformanceResult{}
+	}
+	if err = iter.Close(); err != nil {
+		return nil, errors.Wrap(err, "problem finding results in series")
+	}
+	for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+		results[i], results[j] = results[j], results[i]
+	}
+
+	versions := trialsPerVersion(key, results)
+
+	out := map[string][][]float64{}
+	for idx, trials := range versions {
+		for _, result := range trials {
+			for _, rollup := range result.Rollups.Stats {
+				value, err := rollup.getNumber()
+				if err != nil {
+					continue
+				}
+
+				if _, ok := out[rollup.Name]; !ok {
+					out[rollup.Name] = make([][]float64, len(versions))
+				}
+				out[rollup.Name][idx] = append(out[rollup.Name][idx], value)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// trialsPerVersion groups the results, which must be sorted by
+// creation time, by version, keeping the trials of the latest
+// execution of each version. The versions are ordered by their first
+// result.
+func trialsPerVersion(key PerfSeriesKey, results []PerformanceResult) [][]PerformanceResult {
+	versions := map[string]int{}
+	out := [][]PerformanceResult{}
+	for _, result := range results {
+		if result.Rollups == nil || !key.matches(result.Info) {
+			continue
+		}
+
+		idx, ok := versions[result.Info.Version]
+		if !ok {
+			versions[result.Info.Version] = len(out)
+			out = append(out, []PerformanceResult{result})
+			continue
+		}
+
+		switch prev := out[idx][0].Info.Execution; {
+		case result.Info.Execution > prev:
+			out[idx] = []PerformanceResult{result}
+		case result.Info.Execution == prev:
+			out[idx] = append(out[idx], result)
+		}
+	}
+
+	return out
+}
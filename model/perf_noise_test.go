@@ -0,0 +1,135 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrialsPerVersion(t *testing.T) {
+	key := PerfSeriesKey{Project: "project", TestName: "test"}
+	result := func(version string, execution, trial int) PerformanceResult {
+		return PerformanceResult{
+			ID: version + string(rune('0'+execution)) + string(rune('0'+trial)),
+			Info: PerformanceResultInfo{
+				Project:   "project",
+				TestName:  "test",
+				Version:   version,
+				Execution: execution,
+				Trial:     trial,
+			},
+			Rollups: &PerfRollups{},
+		}
+	}
+
+	other := result("a", 0, 0)
+	other.Info.TestName = "other"
+	noRollups := result("b", 0, 1)
+	noRollups.Rollups = nil
+
+	versions := trialsPerVersion(key, []PerformanceResult{
+		result("a", 0, 0),
+		result("a", 0, 1),
+		other,
+		result("b", 0, 0),
+		noRollups,
+		result("a", 1, 0),
+		result("a", 1, 1),
+		result("a", 0, 2),
+	})
+	require.Len(t, versions, 2)
+	require.Len(t, versions[0], 2)
+	assert.Equal(t, result("a", 1, 0).ID, versions[0][0].ID)
+	assert.Equal(t, result("a", 1, 1).ID, versions[0][1].ID)
+	require.Len(t, versions[1], 1)
+	assert.Equal(t, result("b", 0, 0).ID, versions[1][0].ID)
+}
+
+func TestPerfNoiseScores(t *testing.T) {
+	env := cedar.GetEnvironment()
+	conf, session, err := cedar.GetSessionWithConfig(env)
+	require.NoError(t, err)
+	defer session.Close()
+	defer func() {
+		assert.NoError(t, session.DB(conf.DatabaseName).C(perfNoiseCollection).DropCollection())
+	}()
+
+	series := PerfSeriesKey{Project: "project", Variant: "variant", TaskName: "task", TestName: "test"}
+	makeScore := func(key PerfSeriesKey, measurement string, score float64) PerfNoise {
+		noise := CreatePerfNoise(key, measurement)
+		noise.Score = score
+		noise.Threshold = 0.1
+		noise.Noisy = score > noise.Threshold
+		noise.CalculatedAt = time.Now()
+		return *noise
+	}
+
+	scores := &PerfNoiseScores{}
+	scores.Setup(env)
+	require.NoError(t, scores.ReplaceSeries(series, []PerfNoise{
+		makeScore(series, "latency", 0.2),
+		makeScore(series, "ops", 0.05),
+	}))
+	other := series
+	other.Project = "other"
+	assert.Error(t, scores.ReplaceSeries(series, []PerfNoise{makeScore(other, "latency", 0.5)}))
+	require.NoError(t, scores.ReplaceSeries(other, []PerfNoise{makeScore(other, "latency", 0.5)}))
+
+	require.NoError(t, scores.Find(PerfNoiseFindOptions{Project: "project"}))
+	require.Len(t, scores.Scores, 2)
+	assert.Equal(t, "latency", scores.Scores[0].Measurement)
+	assert.Equal(t, "ops", scores.Scores[1].Measurement)
+	assert.Contains(t, scores.Map(), "ops")
+
+	require.NoError(t, scores.Find(PerfNoiseFindOptions{NoisyOnly: true}))
+	require.Len(t, scores.Scores, 2)
+	assert.Equal(t, "other", scores.Scores[0].Series.Project)
+
+	require.NoError(t, scores.ReplaceSeries(series, []PerfNoise{makeScore(series, "ops", 0.01)}))
+	require.NoError(t, scores.Find(PerfNoiseFindOptions{SeriesID: series.ID()}))
+	require.Len(t, scores.Scores, 1)
+	assert.Equal(t, "ops", scores.Scores[0].Measurement)
+}
+
+func TestFindPerfSeriesTrials(t *testing.T) {
+	env := cedar.GetEnvironment()
+	conf, session, err := cedar.GetSessionWithConfig(env)
+	require.NoError(t, err)
+	defer session.Close()
+	defer func() {
+		assert.NoError(t, session.DB(conf.DatabaseName).C(perfResultCollection).DropCollection())
+	}()
+
+	key := PerfSeriesKey{Project: "project", Variant: "variant", TaskName: "task", TestName: "test"}
+	createdAt := time.Now().Add(-time.Hour)
+	for idx, version := range []string{"v0", "v1", "v2", "v3"} {
+		for trial := 0; trial < 2; trial++ {
+			result := CreatePerformanceResult(PerformanceResultInfo{
+				Project:  key.Project,
+				Variant:  key.Variant,
+				TaskName: key.TaskName,
+				TestName: key.TestName,
+				Version:  version,
+				Trial:    trial,
+			}, nil)
+			result.CreatedAt = createdAt.Add(time.Duration(2*idx+trial) * time.Minute)
+			result.Rollups.Stats = []PerfRollupValue{
+				{Name: "latency", Value: float64(10*idx + trial)},
+				{Name: "name", Value: "not a number"},
+			}
+			result.Setup(env)
+			require.NoError(t, result.Save())
+		}
+	}
+
+	trials, err := FindPerfSeriesTrials(env, key, 2)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][][]float64{"latency": {{20, 21}, {30, 31}}}, trials)
+
+	trials, err = FindPerfSeriesTrials(env, key, 0)
+	require.NoError(t, err)
+	assert.Len(t, trials["latency"], 4)
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"encoding/base64"
+
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// PerfSortKey is the field by which performance results are sorted
+// when paging through them.
+type PerfSortKey string
+
+const (
+	PerfSortCreatedAt   PerfSortKey = "created_at"
+	PerfSortCompletedAt PerfSortKey = "completed_at"
+	PerfSortTestName    PerfSortKey = "test_name"
+)
+
+// Validate checks that the results can be sorted by the key.
+func (k PerfSortKey) Validate() error {
+	switch k {
+	case PerfSortCreatedAt, PerfSortCompletedAt, PerfSortTestName:
+		return nil
+	default:
+		return errors.Errorf("cannot sort performance results by '%s'", k)
+	}
+}
+
+func (k PerfSortKey) dbKey() string {
+	switch k {
+	case PerfSortCompletedAt:
+		return "completed_at"
+	case PerfSortTestName:
+		return bsonutil.GetDottedKeyName("info", "test_name")
+	default:
+		return "created_ts"
+	}
+}
+
+func (k PerfSortKey) value(result *PerformanceResult) interface{} {
+	switch k {
+	case PerfSortCompletedAt:
+		return result.CompletedAt
+	case PerfSortTestName:
+		return result.Info.TestName
+	default:
+		return result.CreatedAt
+	}
+}
+
+// PerfCursor is the position of a result in a sorted list of
+// results, such that the following page starts after it. Results are
+// ordered by the sort key and then by id, so that the order is total
+// even when results share a sort value.
+type PerfCursor struct {
+	Sort       PerfSortKey `bson:"s"`
+	Descending bool        `bson:"d,omitempty"`
+	Value      interface{} `bson:"v"`
+	ID         string      `bson:"id"`
+}
+
+// NewPerfCursor returns the cursor positioned after the given result.
+func NewPerfCursor(sort PerfSortKey, descending bool, value interface{}, id string) *PerfCursor {
+	return &PerfCursor{
+		Sort:       sort,
+		Descending: descending,
+		Value:      value,
+		ID:         id,
+	}
+}
+
+// String encodes the cursor as an opaque, URL-safe token.
+func (c *PerfCursor) String() string {
+	data, err := bson.Marshal(c)
+	if err != nil {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParsePerfCursor decodes a cursor from the token produced by
+// PerfCursor.String.
+func ParsePerfCursor(token string) (*PerfCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, errors.Wrap(err, "malformed cursor")
+	}
+
+	c := &PerfCursor{}
+	if err = bson.Unmarshal(data, c); err != nil {
+		return nil, errors.Wrap(err, "malformed cursor")
+	}
+	if err = c.Sort.Validate(); err != nil {
+		return nil, errors.Wrap(err, "malformed cursor")
+	}
+	if c.ID == "" {
+		return nil, errors.New("malformed cursor")
+	}
+
+	return c, nil
+}
+
+// Matches returns an error if the cursor was not produced by a query
+// with the given sort order.
+func (c *PerfCursor) Matches(sort PerfSortKey, descending bool) error {
+	if c.Sort != sort || c.Descending != descending {
+		return errors.New("cursor does not match the sort order of the query")
+	}
+
+	return nil
+}
+
+func (c *PerfCursor) query() bson.M {
+	op := "$gt"
+	if c.Descending {
+		op = "$lt"
+	}
+
+	key := c.Sort.dbKey()
+	return bson.M{"$or": []bson.M{
+		{key: bson.M{op: c.Value}},
+		{key: c.Value, "_id": bson.M{op: c.ID}},
+	}}
+}
+
+func perfSortFields(sort PerfSortKey, descending bool) []string {
+	prefix := ""
+	if descending {
+		prefix = "-"
+	}
+
+	return []string{prefix + sort.dbKey(), prefix + "_id"}
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestPerfCursor(t *testing.T) {
+	t.Run("RoundTrip", func(t *testing.T) {
+		ts := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+		cursor, err := ParsePerfCursor(NewPerfCursor(PerfSortCreatedAt, true, ts, "abc").String())
+		require.NoError(t, err)
+		assert.Equal(t, PerfSortCreatedAt, cursor.Sort)
+		assert.True(t, cursor.Descending)
+		assert.Equal(t, "abc", cursor.ID)
+		value, ok := cursor.Value.(time.Time)
+		require.True(t, ok)
+		assert.True(t, ts.Equal(value))
+
+		assert.NoError(t, cursor.Matches(PerfSortCreatedAt, true))
+		assert.Error(t, cursor.Matches(PerfSortCreatedAt, false))
+		assert.Error(t, cursor.Matches(PerfSortTestName, true))
+	})
+	t.Run("Query", func(t *testing.T) {
+		query := NewPerfCursor(PerfSortTestName, false, "test", "abc").query()
+		assert.Equal(t, bson.M{"$or": []bson.M{
+			{"info.test_name": bson.M{"$gt": "test"}},
+			{"info.test_name": "test", "_id": bson.M{"$gt": "abc"}},
+		}}, query)
+		assert.Equal(t, []string{"-completed_at", "-_id"}, perfSortFields(PerfSortCompletedAt, true))
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for _, token := range []string{
+			"",
+			"not a cursor",
+			NewPerfCursor("invalid", false, "test", "abc").String(),
+			NewPerfCursor(PerfSortTestName, false, "test", "").String(),
+		} {
+			_, err := ParsePerfCursor(token)
+			assert.Error(t, err, token)
+		}
+	})
+	t.Run("FindOptions", func(t *testing.T) {
+		opts := PerfFindOptions{}
+		require.NoError(t, opts.Validate())
+		assert.Equal(t, PerfSortCreatedAt, opts.Sort)
+
+		opts = PerfFindOptions{Limit: -1}
+		assert.Error(t, opts.Validate())
+
+		opts = PerfFindOptions{Sort: PerfSortTestName, Cursor: NewPerfCursor(PerfSortCreatedAt, false, time.Now(), "abc").String()}
+		assert.Error(t, opts.Validate())
+	})
+}
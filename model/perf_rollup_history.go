@@ -0,0 +1,112 @@
+package model
+
+import (
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	mgo "gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const perfRollupHistoryCollection = "perf_rollup_history"
+
+// perfRollupHistoryEntry is one computation of a rollup of a
+// performance result. The computations are kept in their own
+// collection, rather than in the result, so that the history of every
+// rollup is complete no matter how often the rollups are recalculated.
+type perfRollupHistoryEntry struct {
+	ID     bson.ObjectId   `bson:"_id"`
+	PerfID string          `bson:"perf_id"`
+	Rollup PerfRollupValue `bson:"rollup"`
+}
+
+var (
+	perfRollupHistoryEntryIDKey     = bsonutil.MustHaveTag(perfRollupHistoryEntry{}, "ID")
+	perfRollupHistoryEntryPerfIDKey = bsonutil.MustHaveTag(perfRollupHistoryEntry{}, "PerfID")
+	perfRollupHistoryEntryRollupKey = bsonutil.MustHaveTag(perfRollupHistoryEntry{}, "Rollup")
+)
+
+// perfRollupHistoryIndex supports finding the computations of a rollup
+// of a result in the order that they were calculated.
+var perfRollupHistoryIndex = []string{
+	perfRollupHistoryEntryPerfIDKey,
+	bsonutil.GetDottedKeyName(perfRollupHistoryEntryRollupKey, perfRollupValueNameKey),
+	bsonutil.GetDottedKeyName(perfRollupHistoryEntryRollupKey, perfRollupValueCalculatedAtKey),
+}
+
+// PerfRollupHistory is the current value of a rollup of a performance
+// result and every computation of the rollup, from the oldest to the
+// newest.
+type PerfRollupHistory struct {
+	Name   string
+	Latest *PerfRollupValue
+	Values []PerfRollupValue
+}
+
+// FindHistory returns the current value and every computation of the
+// named rollup. The history is empty, and the latest value nil, if the
+// rollup was never calculated.
+func (r *PerfRollups) FindHistory(name string) (*PerfRollupHistory, error) {
+	if r.id == "" {
+		return nil, errors.New("rollups missing id")
+	}
+	conf, session, err := cedar.GetSessionWithConfig(r.env)
+	if err != nil {
+		return nil, errors.Wrap(err, "error connecting")
+	}
+	defer session.Close()
+
+	entries := []perfRollupHistoryEntry{}
+	err = session.DB(conf.DatabaseName).C(perfRollupHistoryCollection).
+		Find(perfRollupHistoryQuery(r.id, name)).
+		Sort(bsonutil.GetDottedKeyName(perfRollupHistoryEntryRollupKey, perfRollupValueCalculatedAtKey), perfRollupHistoryEntryIDKey).
+		All(&entries)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding history of rollup '%s' of '%s'", name, r.id)
+	}
+
+	history := &PerfRollupHistory{
+		Name:   name,
+		Values: make([]PerfRollupValue, len(entries)),
+	}
+	for i := range entries {
+		history.Values[i] = entries[i].Rollup
+	}
+	for i := range r.Stats {
+		if r.Stats[i].Name == name {
+			latest := r.Stats[i]
+			history.Latest = &latest
+			break
+		}
+	}
+
+	return history, nil
+}
+
+// findLatestVersion returns the most recent computation of the named
+// rollup by the given version, or nil if the version never calculated
+// the rollup.
+func (r *PerfRollups) findLatestVersion(c *mgo.Collection, name string, version int) (*PerfRollupValue, error) {
+	query := perfRollupHistoryQuery(r.id, name)
+	query[bsonutil.GetDottedKeyName(perfRollupHistoryEntryRollupKey, perfRollupValueVersionKey)] = version
+
+	entry := perfRollupHistoryEntry{}
+	err := c.Find(query).
+		Sort("-"+bsonutil.GetDottedKeyName(perfRollupHistoryEntryRollupKey, perfRollupValueCalculatedAtKey), "-"+perfRollupHistoryEntryIDKey).
+		One(&entry)
+	if err == mgo.ErrNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem finding version %d of rollup '%s' of '%s'", version, name, r.id)
+	}
+
+	return &entry.Rollup, nil
+}
+
+func perfRollupHistoryQuery(id, name string) bson.M {
+	return bson.M{
+		perfRollupHistoryEntryPerfIDKey: id,
+		bsonutil.GetDottedKeyName(perfRollupHistoryEntryRollupKey, perfRollupValueNameKey): name,
+	}
+}
@@ -0,0 +1,184 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/anser/db"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+const perfRollupRecalculationID = "perf-rollup-recalculation"
+
+// PerfRollupRecalculationStatus tracks the progress of recalculating
+// the rollups of performance results that were calculated by older
+// versions of the rollup calculators. A pass starts whenever the
+// calculator versions change, and continues in batches, in order of
+// result id, until no results with stale rollups remain.
+type PerfRollupRecalculationStatus struct {
+	ID          string         `bson:"_id" json:"-" yaml:"-"`
+	Calculators map[string]int `bson:"calculators" json:"calculators" yaml:"calculators"`
+	LastID      string         `bson:"last_id" json:"last_id" yaml:"last_id"`
+	Processed   int            `bson:"processed" json:"processed" yaml:"processed"`
+	Failed      int            `bson:"failed" json:"failed" yaml:"failed"`
+	Skipped     int            `bson:"skipped" json:"skipped" yaml:"skipped"`
+	Remaining   int            `bson:"remaining" json:"remaining" yaml:"remaining"`
+	StartedAt   time.Time      `bson:"started_at" json:"started_at" yaml:"started_at"`
+	UpdatedAt   time.Time      `bson:"updated_at" json:"updated_at" yaml:"updated_at"`
+
+	populated bool
+	env       cedar.Environment
+}
+
+func (s *PerfRollupRecalculationStatus) Setup(e cedar.Environment) { s.env = e }
+func (s *PerfRollupRecalculationStatus) IsNil() bool               { return !s.populated }
+
+// Find loads the status from the database. A missing status, because
+// rollups have never been recalculated, is not an error.
+func (s *PerfRollupRecalculationStatus) Find() error {
+	conf, session, err := cedar.GetSessionWithConfig(s.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	s.populated = false
+	err = session.DB(conf.DatabaseName).C(configurationCollection).FindId(perfRollupRecalculationID).One(s)
+	if db.ResultsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Wrap(err, "problem finding rollup recalculation status")
+	}
+	s.populated = true
+
+	return nil
+}
+
+// Save upserts the status.
+func (s *PerfRollupRecalculationStatus) Save() error {
+	conf, session, err := cedar.GetSessionWithConfig(s.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	s.ID = perfRollupRecalculationID
+	if _, err = session.DB(conf.DatabaseName).C(configurationCollection).UpsertId(perfRollupRecalculationID, s); err != nil {
+		return errors.Wrap(err, "problem saving rollup recalculation status")
+	}
+	s.populated = true
+
+	return nil
+}
+
+// Matches returns true if the status is of a pass for the given
+// calculator versions.
+func (s *PerfRollupRecalculationStatus) Matches(versions map[string]int) bool {
+	if len(s.Calculators) != len(versions) {
+		return false
+	}
+	for name, version := range versions {
+		if v, ok := s.Calculators[name]; !ok || v != version {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Reset starts a new pass for the given calculator versions.
+func (s *PerfRollupRecalculationStatus) Reset(versions map[string]int) {
+	s.Calculators = versions
+	s.LastID = ""
+	s.Processed = 0
+	s.Failed = 0
+	s.Skipped = 0
+	s.Remaining = 0
+	s.StartedAt = time.Now()
+	s.UpdatedAt = s.StartedAt
+}
+
+// FindPerfResultsWithStaleRollups returns, in order, the ids of at
+// most limit performance results after the given id that have rollups
+// calculated by an older version of a calculator, where versions maps
+// the names of the calculators to their current versions. Rollups
+// without a calculator, such as user submitted rollups, are never
+// stale.
+func FindPerfResultsWithStaleRollups(env cedar.Environment, versions map[string]int, after string, limit int) ([]string, error) {
+	conf, session, err := cedar.GetSessionWithConfig(env)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer session.Close()
+
+	query := staleRollupsQuery(versions)
+	if after != "" {
+		query = bson.M{"$and": []bson.M{query, {perfIDKey: bson.M{"$gt": after}}}}
+	}
+
+	results := []PerformanceResult{}
+	err = session.DB(conf.DatabaseName).C(perfResultCollection).Find(query).
+		Select(bson.M{perfIDKey: 1}).Sort(perfIDKey).Limit(limit).All(&results)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem finding results with stale rollups")
+	}
+
+	ids := make([]string, len(results))
+	for i := range results {
+		ids[i] = results[i].ID
+	}
+
+	return ids, nil
+}
+
+// CountPerfResultsWithStaleRollups returns the number of performance
+// results with rollups calculated by an older version of a calculator.
+func CountPerfResultsWithStaleRollups(env cedar.Environment, versions map[string]int) (int, error) {
+	conf, session, err := cedar.GetSessionWithConfig(env)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	defer session.Close()
+
+	count, err := session.DB(conf.DatabaseName).C(perfResultCollection).Find(staleRollupsQuery(versions)).Count()
+	return count, errors.Wrap(err, "problem counting results with stale rollups")
+}
+
+func staleRollupsQuery(versions map[string]int) bson.M {
+	stale := []bson.M{}
+	for _, value := range staleRollupValues(versions) {
+		stale = append(stale, bson.M{
+			bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsStatsKey): bson.M{"$elemMatch": value},
+		})
+	}
+	if len(stale) == 0 {
+		// no calculators means no stale rollups
+		return bson.M{perfIDKey: bson.M{"$exists": false}}
+	}
+
+	return bson.M{"$or": stale}
+}
+
+// staleRollupValues returns, for each calculator in order of name, the
+// query that matches the rollup values calculated by an older version
+// of the calculator.
+func staleRollupValues(versions map[string]int) []bson.M {
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	stale := make([]bson.M, len(names))
+	for i, name := range names {
+		stale[i] = bson.M{
+			perfRollupValueCalculatorKey: name,
+			perfRollupValueVersionKey:    bson.M{"$lt": versions[name]},
+		}
+	}
+
+	return stale
+}
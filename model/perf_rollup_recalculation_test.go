@@ -0,0 +1,76 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/mgo.v2/bson"
+)
+
+func TestStaleRollupsQuery(t *testing.T) {
+	assert.Equal(t, bson.M{"$or": []bson.M{
+		{"rollups.stats": bson.M{"$elemMatch": bson.M{"calculator": "a", "version": bson.M{"$lt": 2}}}},
+		{"rollups.stats": bson.M{"$elemMatch": bson.M{"calculator": "b", "version": bson.M{"$lt": 1}}}},
+	}}, staleRollupsQuery(map[string]int{"b": 1, "a": 2}))
+
+	assert.Equal(t, bson.M{"_id": bson.M{"$exists": false}}, staleRollupsQuery(nil))
+}
+
+func TestPerfRollupRecalculationStatus(t *testing.T) {
+	status := &PerfRollupRecalculationStatus{}
+	assert.True(t, status.Matches(map[string]int{}))
+	assert.False(t, status.Matches(map[string]int{"a": 1}))
+
+	status.Processed = 10
+	status.LastID = "foo"
+	status.Reset(map[string]int{"a": 1})
+	assert.True(t, status.Matches(map[string]int{"a": 1}))
+	assert.False(t, status.Matches(map[string]int{"a": 2}))
+	assert.False(t, status.Matches(map[string]int{"a": 1, "b": 1}))
+	assert.Zero(t, status.Processed)
+	assert.Empty(t, status.LastID)
+	assert.False(t, status.StartedAt.IsZero())
+}
+
+func TestFindPerfResultsWithStaleRollups(t *testing.T) {
+	env := cedar.GetEnvironment()
+	conf, session, err := cedar.GetSessionWithConfig(env)
+	require.NoError(t, err)
+	defer session.Close()
+	c := session.DB(conf.DatabaseName).C(perfResultCollection)
+	defer func() {
+		assert.NoError(t, c.DropCollection())
+	}()
+
+	for id, stats := range map[string][]PerfRollupValue{
+		"current": {{Name: "mean", Calculator: "ftdc", Version: 2}},
+		"stale":   {{Name: "mean", Calculator: "ftdc", Version: 1}},
+		"mixed":   {{Name: "mean", Calculator: "ftdc", Version: 2}, {Name: "p50", Calculator: "ftdc", Version: 1}},
+		"user":    {{Name: "mean", Version: 1, UserSubmitted: true}},
+	} {
+		require.NoError(t, c.Insert(PerformanceResult{ID: id, Rollups: &PerfRollups{Stats: stats}}))
+	}
+	versions := map[string]int{"ftdc": 2}
+
+	ids, err := FindPerfResultsWithStaleRollups(env, versions, "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mixed", "stale"}, ids)
+
+	ids, err = FindPerfResultsWithStaleRollups(env, versions, "mixed", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stale"}, ids)
+
+	ids, err = FindPerfResultsWithStaleRollups(env, versions, "", 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mixed"}, ids)
+
+	count, err := CountPerfResultsWithStaleRollups(env, versions)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = CountPerfResultsWithStaleRollups(env, map[string]int{"ftdc": 1})
+	require.NoError(t, err)
+	assert.Zero(t, count)
+}
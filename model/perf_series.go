@@ -0,0 +1,177 @@
+package model
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// PerfSeriesKey identifies the results of the same test over time,
+// regardless of the version, task or execution that produced them.
+type PerfSeriesKey struct {
+	Project   string           `bson:"project"`
+	Variant   string           `bson:"variant"`
+	TaskName  string           `bson:"task_name"`
+	TestName  string           `bson:"test_name"`
+	Arguments map[string]int32 `bson:"args,omitempty"`
+}
+
+var (
+	perfSeriesKeyProjectKey  = bsonutil.MustHaveTag(PerfSeriesKey{}, "Project")
+	perfSeriesKeyVariantKey  = bsonutil.MustHaveTag(PerfSeriesKey{}, "Variant")
+	perfSeriesKeyTaskNameKey = bsonutil.MustHaveTag(PerfSeriesKey{}, "TaskName")
+	perfSeriesKeyTestNameKey = bsonutil.MustHaveTag(PerfSeriesKey{}, "TestName")
+)
+
+// SeriesKey returns the key of the series that the result belongs to.
+func (id *PerformanceResultInfo) SeriesKey() PerfSeriesKey {
+	return PerfSeriesKey{
+		Project:   id.Project,
+		Variant:   id.Variant,
+		TaskName:  id.TaskName,
+		TestName:  id.TestName,
+		Arguments: id.Arguments,
+	}
+}
+
+// ID returns a stable hash of the series key, which does not depend
+// on the order of the arguments.
+func (k *PerfSeriesKey) ID() string {
+	hash := sha1.New()
+	_, _ = io.WriteString(hash, k.Project)
+	_, _ = io.WriteString(hash, k.Variant)
+	_, _ = io.WriteString(hash, k.TaskName)
+	_, _ = io.WriteString(hash, k.TestName)
+
+	args := []string{}
+	for name, val := range k.Arguments {
+		args = append(args, fmt.Sprintf("%s=%d", name, val))
+	}
+	sort.Strings(args)
+	for _, str := range args {
+		_, _ = io.WriteString(hash, str)
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+func (k *PerfSeriesKey) matches(info PerformanceResultInfo) bool {
+	if k.Project != info.Project || k.Variant != info.Variant || k.TaskName != info.TaskName || k.TestName != info.TestName {
+		return false
+	}
+	if len(k.Arguments) != len(info.Arguments) {
+		return false
+	}
+	for key, val := range k.Arguments {
+		if other, ok := info.Arguments[key]; !ok || other != val {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PerfSeriesPoint is the value of a rollup for one version in a
+// series.
+type PerfSeriesPoint struct {
+	PerfResultID string
+	Version      string
+	CreatedAt    time.Time
+	Value        float64
+	MetricType   MetricType
+}
+
+// PerfSeries holds the rollups of all results in a series, keyed by
+// rollup name and ordered by the creation time of the results.
+type PerfSeries struct {
+	Key          PerfSeriesKey
+	Measurements map[string][]PerfSeriesPoint
+
+	env       cedar.Environment
+	populated bool
+}
+
+func (s *PerfSeries) Setup(e cedar.Environment) { s.env = e }
+func (s *PerfSeries) IsNil() bool               { return !s.populated }
+
+// Find loads the series for the key. There is at most one point per
+// version: when a test ran more than once for a version, the latest
+// execution and the first trial are used. Results without rollups
+// and non-numeric rollups are ignored.
+func (s *PerfSeries) Find() error {
+	conf, session, err := cedar.GetSessionWithConfig(s.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	search := bson.M{
+		bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoProjectKey):  s.Key.Project,
+		bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoVariantKey):  s.Key.Variant,
+		bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoTaskNameKey): s.Key.TaskName,
+		bsonutil.GetDottedKeyName(perfInfoKey, perfResultInfoTestNameKey): s.Key.TestName,
+		bsonutil.GetDottedKeyName(perfRollupsKey, perfRollupsStatsKey):    bson.M{"$exists": true},
+	}
+
+	results := []PerformanceResult{}
+	err = session.DB(conf.DatabaseName).C(perfResultCollection).Find(search).Sort("created_ts").All(&results)
+	if err != nil {
+		return errors.Wrap(err, "problem finding results in series")
+	}
+
+	s.populated = false
+	s.Measurements = map[string][]PerfSeriesPoint{}
+	for _, result := range latestPerVersion(s.Key, results) {
+		for _, rollup := range result.Rollups.Stats {
+			value, err := rollup.getNumber()
+			if err != nil {
+				continue
+			}
+
+			s.Measurements[rollup.Name] = append(s.Measurements[rollup.Name], PerfSeriesPoint{
+				PerfResultID: result.ID,
+				Version:      result.Info.Version,
+				CreatedAt:    result.CreatedAt,
+				Value:        value,
+				MetricType:   rollup.MetricType,
+			})
+		}
+	}
+	s.populated = true
+
+	return nil
+}
+
+// latestPerVersion filters the results, which must be sorted by
+// creation time, to those in the series with one result per version.
+func latestPerVersion(key PerfSeriesKey, results []PerformanceResult) []PerformanceResult {
+	versions := map[string]int{}
+	out := []PerformanceResult{}
+	for _, result := range results {
+		if result.Rollups == nil || !key.matches(result.Info) {
+			continue
+		}
+
+		idx, ok := versions[result.Info.Version]
+		if !ok {
+			versions[result.Info.Version] = len(out)
+			out = append(out, result)
+			continue
+		}
+
+		prev := out[idx].Info
+		if result.Info.Execution > prev.Execution ||
+			(result.Info.Execution == prev.Execution && result.Info.Trial < prev.Trial) {
+			out[idx] = result
+		}
+	}
+
+	return out
+}
@@ -1,11 +1,17 @@
 package model
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"os"
 	"time"
 
-	"github.com/evergreen-ci/pail"
+	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/pail"
 	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 )
 
@@ -14,12 +20,69 @@ type PailType string
 const (
 	PailS3           PailType = "s3"
 	PailLegacyGridFS          = "gridfs-legacy"
+	PailLocal                 = "local"
+
+	defaultS3Region = "us-east-1"
 )
 
+// GetPailType returns the storage backend that cedar uses for the
+// artifacts that it creates, as specified in the configuration. When
+// the configuration does not specify a backend, the legacy GridFS
+// backend is used, since it only requires the database.
+func GetPailType(conf *cedar.Configuration) PailType {
+	if conf == nil || conf.BucketType == "" {
+		return PailLegacyGridFS
+	}
+
+	return PailType(conf.BucketType)
+}
+
+func (t PailType) Validate() error {
+	switch t {
+	case PailS3, PailLegacyGridFS, PailLocal:
+		return nil
+	default:
+		return errors.Errorf("'%s' is not a valid pail type", t)
+	}
+}
+
+// Create returns the bucket implementation for the pail type. For
+// S3, the bucket is the name of the S3 bucket (defaulting to the
+// configured bucket name) and credentials are read from the
+// configuration. For the local filesystem, the bucket is the path to
+// a directory, which is created if it does not exist.
 func (t PailType) Create(env cedar.Environment, bucket string) (pail.Bucket, error) {
 	switch t {
 	case PailS3:
-		return nil, errors.New("not implemented")
+		if env == nil {
+			return nil, errors.New("env is nil")
+		}
+
+		conf, err := env.GetConf()
+		if err != nil {
+			return nil, errors.Wrap(err, "problem getting configuration")
+		}
+
+		opts := pail.S3Options{
+			Name:   bucket,
+			Region: conf.BucketRegion,
+		}
+		if opts.Name == "" {
+			opts.Name = conf.BucketName
+		}
+		if opts.Region == "" {
+			opts.Region = defaultS3Region
+		}
+		if conf.AWSKey != "" {
+			opts.Credentials = credentials.NewStaticCredentials(conf.AWSKey, conf.AWSSecret, "")
+		}
+
+		b, err := pail.NewS3Bucket(opts)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		return b, nil
 	case PailLegacyGridFS:
 		conf, session, err := cedar.GetSessionWithConfig(env)
 		if err != nil {
@@ -36,6 +99,21 @@ func (t PailType) Create(env cedar.Environment, bucket string) (pail.Bucket, err
 			return nil, errors.WithStack(err)
 		}
 
+		return b, nil
+	case PailLocal:
+		if bucket == "" {
+			return nil, errors.New("must specify a path for a local bucket")
+		}
+
+		if err := os.MkdirAll(bucket, 0700); err != nil {
+			return nil, errors.Wrapf(err, "problem creating local bucket '%s'", bucket)
+		}
+
+		b, err := pail.NewLocalBucket(bucket)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
 		return b, nil
 	default:
 		return nil, errors.New("not implemented")
@@ -88,11 +166,12 @@ const (
 	SchemaCollapsedEvents            = "collapsed-events"
 	SchemaIntervalSummary            = "interval-summarization"
 	SchemaHistogram                  = "histogram"
+	SchemaCustomEvents               = "custom-events"
 )
 
 func (fs FileSchema) Validate() error {
 	switch fs {
-	case SchemaRawEvents, SchemaIntervalSummary, SchemaCollapsedEvents, SchemaHistogram:
+	case SchemaRawEvents, SchemaIntervalSummary, SchemaCollapsedEvents, SchemaHistogram, SchemaCustomEvents:
 		return nil
 	default:
 		return errors.New("invalid schema specified")
@@ -116,6 +195,11 @@ type ArtifactInfo struct {
 	Schema      FileSchema      `bson:"schema"`
 	Tags        []string        `bson:"tags,omitempty"`
 	CreatedAt   time.Time       `bson:"created_at"`
+
+	// ConversionError is the reason that the artifact could not be
+	// converted to FTDC, if the conversion failed, in which case it
+	// is not converted again.
+	ConversionError string `bson:"conversion_error,omitempty"`
 }
 
 var (
@@ -126,4 +210,182 @@ var (
 	artifactInfoCompressionKey = bsonutil.MustHaveTag(ArtifactInfo{}, "Compression")
 	artifactInfoTagsKey        = bsonutil.MustHaveTag(ArtifactInfo{}, "Tags")
 	artifactInfoCreatedAtKey   = bsonutil.MustHaveTag(ArtifactInfo{}, "CreatedAt")
+
+	artifactInfoConversionErrorKey = bsonutil.MustHaveTag(ArtifactInfo{}, "ConversionError")
 )
+
+// Validate checks that the artifact has a path and that its type,
+// format, compression, and schema are all supported.
+func (a *ArtifactInfo) Validate() error {
+	catcher := grip.NewBasicCatcher()
+	if a.Path == "" {
+		catcher.Add(errors.New("artifact must have a path"))
+	}
+	catcher.Add(a.Type.Validate())
+	catcher.Add(a.Format.Validate())
+	catcher.Add(a.Compression.Validate())
+	catcher.Add(a.Schema.Validate())
+
+	return catcher.Resolve()
+}
+
+// NewRawEventsArtifact describes a new, uncompressed FTDC artifact
+// for the raw events data of the result with the given id, in the
+// bucket specified by the configuration.
+func NewRawEventsArtifact(conf *cedar.Configuration, id string) *ArtifactInfo {
+	return newFTDCArtifact(conf, id, SchemaRawEvents)
+}
+
+// NewCustomEventsArtifact describes a new, uncompressed FTDC artifact
+// for the custom events data of the result with the given id, in the
+// bucket specified by the configuration.
+func NewCustomEventsArtifact(conf *cedar.Configuration, id string) *ArtifactInfo {
+	return newFTDCArtifact(conf, id, SchemaCustomEvents)
+}
+
+func newFTDCArtifact(conf *cedar.Configuration, id string, schema FileSchema) *ArtifactInfo {
+	createdAt := time.Now()
+	return &ArtifactInfo{
+		Type:        GetPailType(conf),
+		Bucket:      conf.BucketName,
+		Path:        fmt.Sprintf("%s/%d.ftdc", id, createdAt.UnixNano()),
+		Format:      FileFTDC,
+		Compression: FileUncompressed,
+		Schema:      schema,
+		CreatedAt:   createdAt,
+	}
+}
+
+// CreateRawEventsArtifact creates a new raw events artifact for the
+// result with the given id in the configured bucket. Callers must
+// close the writer and then attach the artifact to the result.
+func CreateRawEventsArtifact(ctx context.Context, env cedar.Environment, id string) (*ArtifactInfo, io.WriteCloser, error) {
+	return createFTDCArtifact(ctx, env, id, SchemaRawEvents)
+}
+
+// CreateCustomEventsArtifact creates a new custom events artifact for
+// the result with the given id in the configured bucket. Callers must
+// close the writer and then attach the artifact to the result.
+func CreateCustomEventsArtifact(ctx context.Context, env cedar.Environment, id string) (*ArtifactInfo, io.WriteCloser, error) {
+	return createFTDCArtifact(ctx, env, id, SchemaCustomEvents)
+}
+
+// CreateIntervalSummaryArtifact creates a new interval summary
+// artifact, for the summary of the raw events of the result with the
+// given id over windows of the given interval, in the configured
+// bucket. The artifact is tagged with the interval. Callers must
+// close the writer and then attach the artifact to the result.
+func CreateIntervalSummaryArtifact(ctx context.Context, env cedar.Environment, id string, interval time.Duration) (*ArtifactInfo, io.WriteCloser, error) {
+	artifact, output, err := createFTDCArtifact(ctx, env, id, SchemaIntervalSummary)
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	artifact.Tags = []string{IntervalSummaryTag(interval)}
+
+	return artifact, output, nil
+}
+
+// IntervalSummaryTag returns the tag of the interval summary artifacts
+// for the given interval.
+func IntervalSummaryTag(interval time.Duration) string {
+	return fmt.Sprintf("interval:%s", interval)
+}
+
+// CreateCollapsedEventsArtifact creates a new collapsed events
+// artifact for the result with the given id in the configured bucket.
+// Callers must close the writer and then attach the artifact to the
+// result.
+func CreateCollapsedEventsArtifact(ctx context.Context, env cedar.Environment, id string) (*ArtifactInfo, io.WriteCloser, error) {
+	return createFTDCArtifact(ctx, env, id, SchemaCollapsedEvents)
+}
+
+func createFTDCArtifact(ctx context.Context, env cedar.Environment, id string, schema FileSchema) (*ArtifactInfo, io.WriteCloser, error) {
+	conf, err := env.GetConf()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "problem getting configuration")
+	}
+
+	artifact := newFTDCArtifact(conf, id, schema)
+	output, err := artifact.Writer(ctx, env)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "problem creating artifact for '%s'", id)
+	}
+
+	return artifact, output, nil
+}
+
+// Reader opens the artifact for reading from its offline storage.
+func (a *ArtifactInfo) Reader(ctx context.Context, env cedar.Environment) (io.ReadCloser, error) {
+	bucket, err := a.Type.Create(env, a.Bucket)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem resolving bucket for '%s'", a.Path)
+	}
+
+	r, err := bucket.Reader(ctx, a.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem opening '%s'", a.Path)
+	}
+
+	return r, nil
+}
+
+// OpenArtifacts returns a reader over the concatenated, decompressed
+// contents of the given artifacts, in order. Closing the reader closes
+// all of the underlying artifact readers.
+func OpenArtifacts(ctx context.Context, env cedar.Environment, artifacts []ArtifactInfo) (io.ReadCloser, error) {
+	out := &multiArtifactReader{}
+	readers := make([]io.Reader, 0, len(artifacts))
+	for idx := range artifacts {
+		r, err := artifacts[idx].Open(ctx, env)
+		if err != nil {
+			grip.Warning(out.Close())
+			return nil, errors.WithStack(err)
+		}
+
+		out.closers = append(out.closers, r)
+		readers = append(readers, r)
+	}
+	out.Reader = io.MultiReader(readers...)
+
+	return out, nil
+}
+
+type multiArtifactReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r *multiArtifactReader) Close() error {
+	catcher := grip.NewBasicCatcher()
+	for _, c := range r.closers {
+		catcher.Add(c.Close())
+	}
+
+	return catcher.Resolve()
+}
+
+// Writer creates the artifact in its offline storage. Callers must
+// close the writer to flush the data.
+func (a *ArtifactInfo) Writer(ctx context.Context, env cedar.Environment) (io.WriteCloser, error) {
+	bucket, err := a.Type.Create(env, a.Bucket)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem resolving bucket for '%s'", a.Path)
+	}
+
+	w, err := bucket.Writer(ctx, a.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem creating '%s'", a.Path)
+	}
+
+	return w, nil
+}
+
+// Remove deletes the artifact from its offline storage.
+func (a *ArtifactInfo) Remove(ctx context.Context, env cedar.Environment) error {
+	bucket, err := a.Type.Create(env, a.Bucket)
+	if err != nil {
+		return errors.Wrapf(err, "problem resolving bucket for '%s'", a.Path)
+	}
+
+	return errors.Wrapf(bucket.Remove(ctx, a.Path), "problem removing '%s'", a.Path)
+}
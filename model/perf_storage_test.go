@@ -0,0 +1,68 @@
+package model
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPailType(t *testing.T) {
+	t.Run("Validate", func(t *testing.T) {
+		assert.NoError(t, PailType(PailS3).Validate())
+		assert.NoError(t, PailType(PailLegacyGridFS).Validate())
+		assert.NoError(t, PailType(PailLocal).Validate())
+		assert.Error(t, PailType("ftp").Validate())
+		assert.Error(t, PailType("").Validate())
+	})
+	t.Run("DefaultsToLegacyGridFS", func(t *testing.T) {
+		assert.Equal(t, PailType(PailLegacyGridFS), GetPailType(nil))
+		assert.Equal(t, PailType(PailLegacyGridFS), GetPailType(&cedar.Configuration{}))
+		assert.Equal(t, PailS3, GetPailType(&cedar.Configuration{BucketType: string(PailS3)}))
+		assert.Equal(t, PailType(PailLocal), GetPailType(&cedar.Configuration{BucketType: PailLocal}))
+	})
+	t.Run("LocalRequiresPath", func(t *testing.T) {
+		_, err := PailType(PailLocal).Create(nil, "")
+		assert.Error(t, err)
+	})
+	t.Run("UnknownType", func(t *testing.T) {
+		_, err := PailType("ftp").Create(nil, "foo")
+		assert.Error(t, err)
+	})
+	t.Run("S3RequiresEnv", func(t *testing.T) {
+		_, err := PailS3.Create(nil, "foo")
+		assert.Error(t, err)
+	})
+	t.Run("LocalArtifactRoundTrip", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		tempDir, err := ioutil.TempDir("", "cedar-pail")
+		require.NoError(t, err)
+		defer os.RemoveAll(tempDir)
+
+		artifact := &ArtifactInfo{
+			Type:   PailLocal,
+			Bucket: filepath.Join(tempDir, "artifacts"),
+			Path:   "data.ftdc",
+		}
+
+		w, err := artifact.Writer(ctx, nil)
+		require.NoError(t, err)
+		_, err = w.Write([]byte("cedar"))
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+
+		r, err := artifact.Reader(ctx, nil)
+		require.NoError(t, err)
+		defer r.Close()
+		data, err := ioutil.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, "cedar", string(data))
+	})
+}
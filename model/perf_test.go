@@ -6,6 +6,7 @@ import (
 
 	"github.com/evergreen-ci/cedar"
 	"github.com/evergreen-ci/cedar/util"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -246,3 +247,55 @@ func (s *perfResultSuite) TearDownTest() {
 	err = c.DropCollection()
 	s.NoError(err)
 }
+
+func TestPerformanceResultInfoID(t *testing.T) {
+	info := func(schema int) *PerformanceResultInfo {
+		return &PerformanceResultInfo{
+			Project:   "project",
+			Version:   "version",
+			Variant:   "variant",
+			TaskName:  "task",
+			TestName:  "test",
+			Tags:      []string{"b", "a"},
+			Arguments: map[string]int32{"threads": 8, "size": 1},
+			Schema:    schema,
+		}
+	}
+
+	assert.Equal(t, []int{0, 1}, SupportedPerfResultIDSchemas())
+	assert.Equal(t, 1, LatestPerfResultIDSchema())
+
+	t.Run("Schema0", func(t *testing.T) {
+		id := info(0)
+		assert.NoError(t, id.ValidateSchema())
+		assert.Equal(t, "1207763cd7bfb3dadbd213719436f47676410a91", id.ID())
+	})
+	t.Run("Schema1", func(t *testing.T) {
+		id := info(1)
+		assert.NoError(t, id.ValidateSchema())
+		assert.Equal(t, "80825db506ec8bb4ff6656a6a4f4beb62d56ea85", id.ID())
+		assert.NotEqual(t, info(0).ID(), id.ID())
+
+		other := info(1)
+		other.Tags = []string{" a", "b", "a", ""}
+		assert.Equal(t, id.ID(), other.ID())
+		assert.Equal(t, []string{" a", "b", "a", ""}, other.Tags)
+
+		other.Variant = "other"
+		assert.NotEqual(t, id.ID(), other.ID())
+
+		// fields cannot run into each other
+		other = info(1)
+		other.Project, other.Version = "projectv", "ersion"
+		assert.NotEqual(t, id.ID(), other.ID())
+	})
+	t.Run("UnsupportedSchema", func(t *testing.T) {
+		for _, schema := range []int{-1, 2, 1000} {
+			id := info(schema)
+			assert.Error(t, id.ValidateSchema())
+			assert.NotPanics(t, func() {
+				assert.Empty(t, id.ID())
+			})
+		}
+	})
+}
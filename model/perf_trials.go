@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/mongodb/anser/bsonutil"
+	"github.com/mongodb/anser/db"
+	"github.com/mongodb/anser/model"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// PerfTrialSummary summarizes one rollup across the trials of a test,
+// which are the children of a result with the same test name and
+// arguments. The summaries are stored on the parent result.
+type PerfTrialSummary struct {
+	TestName  string           `bson:"test_name" json:"test_name" yaml:"test_name"`
+	Arguments map[string]int32 `bson:"args,omitempty" json:"args,omitempty" yaml:"args,omitempty"`
+	Rollup    string           `bson:"rollup" json:"rollup" yaml:"rollup"`
+	Trials    int              `bson:"trials" json:"trials" yaml:"trials"`
+	Mean      float64          `bson:"mean" json:"mean" yaml:"mean"`
+	Median    float64          `bson:"median" json:"median" yaml:"median"`
+	Min       float64          `bson:"min" json:"min" yaml:"min"`
+	Max       float64          `bson:"max" json:"max" yaml:"max"`
+	StdDev    float64          `bson:"std_dev" json:"std_dev" yaml:"std_dev"`
+
+	// CoefficientOfVariation is the standard deviation relative to
+	// the mean, which measures the noise of the test, and is zero
+	// when the mean is zero.
+	CoefficientOfVariation float64 `bson:"cv" json:"cv" yaml:"cv"`
+
+	// ConfidenceLower and ConfidenceUpper bound the confidence
+	// interval of the mean at the ConfidenceLevel.
+	ConfidenceLevel float64 `bson:"confidence_level" json:"confidence_level" yaml:"confidence_level"`
+	ConfidenceLower float64 `bson:"confidence_lower" json:"confidence_lower" yaml:"confidence_lower"`
+	ConfidenceUpper float64 `bson:"confidence_upper" json:"confidence_upper" yaml:"confidence_upper"`
+}
+
+var (
+	perfTrialSummariesKey          = bsonutil.MustHaveTag(PerformanceResult{}, "TrialSummaries")
+	perfTrialSummariesUpdatedAtKey = bsonutil.MustHaveTag(PerformanceResult{}, "TrialSummariesUpdatedAt")
+)
+
+// SetTrialSummaries replaces the trial summaries of the result.
+func (result *PerformanceResult) SetTrialSummaries(summaries []PerfTrialSummary) error {
+	if result.ID == "" {
+		return errors.New("cannot set trial summaries of a result without an id")
+	}
+
+	conf, session, err := cedar.GetSessionWithConfig(result.env)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer session.Close()
+
+	updatedAt := time.Now().Round(time.Millisecond)
+	update := bson.M{"$set": bson.M{
+		perfTrialSummariesKey:          summaries,
+		perfTrialSummariesUpdatedAtKey: updatedAt,
+	}}
+	err = session.DB(conf.DatabaseName).C(perfResultCollection).UpdateId(result.ID, update)
+	if db.ResultsNotFound(err) {
+		return errors.Errorf("could not find result record '%s' in the database", result.ID)
+	} else if err != nil {
+		return errors.Wrapf(err, "problem setting trial summaries of '%s'", result.ID)
+	}
+
+	result.TrialSummaries = summaries
+	result.TrialSummariesUpdatedAt = updatedAt
+	grip.Debug(message.Fields{
+		"ns":    model.Namespace{DB: conf.DatabaseName, Collection: perfResultCollection},
+		"id":    result.ID,
+		"count": len(summaries),
+		"op":    "set perf result trial summaries",
+	})
+
+	return nil
+}
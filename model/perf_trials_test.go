@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTrialSummaries(t *testing.T) {
+	env := cedar.GetEnvironment()
+	conf, session, err := cedar.GetSessionWithConfig(env)
+	require.NoError(t, err)
+	defer session.Close()
+	c := session.DB(conf.DatabaseName).C(perfResultCollection)
+	defer func() {
+		assert.NoError(t, c.DropCollection())
+	}()
+
+	result := &PerformanceResult{}
+	result.Setup(env)
+	assert.Error(t, result.SetTrialSummaries(nil))
+
+	result.ID = "parent"
+	assert.Error(t, result.SetTrialSummaries(nil))
+
+	require.NoError(t, c.Insert(PerformanceResult{ID: "parent"}))
+	summaries := []PerfTrialSummary{
+		{TestName: "test", Arguments: map[string]int32{"threads": 4}, Rollup: "latency", Trials: 5, Mean: 14, Median: 14, Min: 10, Max: 18},
+	}
+	require.NoError(t, result.SetTrialSummaries(summaries))
+	assert.Equal(t, summaries, result.TrialSummaries)
+
+	found := &PerformanceResult{ID: "parent"}
+	found.Setup(env)
+	require.NoError(t, found.Find())
+	assert.Equal(t, summaries, found.TrialSummaries)
+	assert.Equal(t, result.TrialSummariesUpdatedAt.UTC(), found.TrialSummariesUpdatedAt.UTC())
+}
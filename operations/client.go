@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/evergreen-ci/cedar/rest"
+	"github.com/evergreen-ci/cedar/rest/model"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
@@ -33,6 +34,7 @@ func Client() cli.Command {
 			getSystemStatusEvents(),
 			systemEvent(),
 			systemInfo(),
+			perfBaseline(),
 		},
 	}
 }
@@ -259,6 +261,90 @@ func systemEvent() cli.Command {
 
 }
 
+func perfBaseline() cli.Command {
+	const (
+		projectFlag = "project"
+		variantFlag = "variant"
+		taskFlag    = "task"
+		versionFlag = "version"
+		perfIDFlag  = "perf-id"
+		userFlag    = "user"
+	)
+
+	return cli.Command{
+		Name:  "perf-baseline",
+		Usage: "prints json for the performance baseline of a task, setting it when a version or result is specified",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  projectFlag,
+				Usage: "specify the project of the task",
+			},
+			cli.StringFlag{
+				Name:  variantFlag,
+				Usage: "specify the build variant of the task",
+			},
+			cli.StringFlag{
+				Name:  taskFlag,
+				Usage: "specify the name of the task",
+			},
+			cli.StringFlag{
+				Name:  versionFlag,
+				Usage: "set the baseline to the results of this version",
+			},
+			cli.StringFlag{
+				Name:  perfIDFlag,
+				Usage: "set the baseline to the task execution of this performance result",
+			},
+			cli.StringFlag{
+				Name:   userFlag,
+				Usage:  "specify the user setting the baseline",
+				EnvVar: "USER",
+			},
+		},
+		Before: mergeBeforeFuncs(
+			requireStringFlag(projectFlag),
+			requireStringFlag(variantFlag),
+			requireStringFlag(taskFlag),
+		),
+		Action: func(c *cli.Context) error {
+			ctx := context.Background()
+
+			client, err := rest.NewClient(c.Parent().String(clientHostFlag), c.Parent().Int(clientPortFlag), "")
+			if err != nil {
+				return errors.Wrap(err, "problem creating REST client")
+			}
+
+			project := c.String(projectFlag)
+			variant := c.String(variantFlag)
+			task := c.String(taskFlag)
+
+			var resp *model.APIPerfBaseline
+			if c.String(versionFlag) != "" || c.String(perfIDFlag) != "" {
+				resp, err = client.SetPerformanceBaseline(ctx, project, variant, task, model.APIPerfBaselineChange{
+					Version:      model.ToAPIString(c.String(versionFlag)),
+					PerfResultID: model.ToAPIString(c.String(perfIDFlag)),
+					SetBy:        model.ToAPIString(c.String(userFlag)),
+				})
+			} else {
+				resp, err = client.GetPerformanceBaseline(ctx, project, variant, task)
+			}
+
+			if err != nil {
+				return errors.Wrap(err, "problem with performance baseline request")
+			}
+
+			grip.Debug(resp)
+			out, err := pretyJSON(resp)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+
+			fmt.Println(out)
+			return nil
+		},
+	}
+}
+
 func systemInfo() cli.Command {
 	return cli.Command{
 		Name:  "sysinfo",
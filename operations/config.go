@@ -25,7 +25,7 @@ func dumpCedarConfig() cli.Command {
 			mongodbURI := c.String(dbURIFlag)
 			dbName := c.String(dbNameFlag)
 
-			if err := configure(env, 2, true, mongodbURI, "", dbName); err != nil {
+			if err := configure(env, 2, true, mongodbURI, bucketOptions{}, dbName); err != nil {
 				return errors.WithStack(err)
 			}
 
@@ -57,7 +57,7 @@ func loadCedarConfig() cli.Command {
 			mongodbURI := c.String(dbURIFlag)
 			dbName := c.String(dbNameFlag)
 
-			if err := configure(env, 2, true, mongodbURI, "", dbName); err != nil {
+			if err := configure(env, 2, true, mongodbURI, bucketOptions{}, dbName); err != nil {
 				return errors.WithStack(err)
 			}
 
@@ -54,7 +54,7 @@ func dumpCostConfig() cli.Command {
 			mongodbURI := c.String(dbURIFlag)
 			dbName := c.String(dbNameFlag)
 
-			if err := configure(env, 2, true, mongodbURI, "", dbName); err != nil {
+			if err := configure(env, 2, true, mongodbURI, bucketOptions{}, dbName); err != nil {
 				return errors.WithStack(err)
 			}
 
@@ -86,7 +86,7 @@ func loadCostConfig() cli.Command {
 			mongodbURI := c.String(dbURIFlag)
 			dbName := c.String(dbNameFlag)
 
-			if err := configure(env, 2, true, mongodbURI, "", dbName); err != nil {
+			if err := configure(env, 2, true, mongodbURI, bucketOptions{}, dbName); err != nil {
 				return errors.WithStack(err)
 			}
 
@@ -120,7 +120,7 @@ func collectLoop() cli.Command {
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 
-			if err := configure(env, 1, false, mongodbURI, "", dbName); err != nil {
+			if err := configure(env, 1, false, mongodbURI, bucketOptions{}, dbName); err != nil {
 				return errors.WithStack(err)
 			}
 
@@ -190,7 +190,7 @@ func summarize() cli.Command {
 			dbName := c.String(dbNameFlag)
 
 			env := cedar.GetEnvironment()
-			if err := configure(env, 1, false, mongodbURI, "", dbName); err != nil {
+			if err := configure(env, 1, false, mongodbURI, bucketOptions{}, dbName); err != nil {
 				return errors.WithStack(err)
 			}
 
@@ -345,7 +345,7 @@ func dump() cli.Command {
 			mongodbURI := c.String(dbURIFlag)
 			dbName := c.String(dbNameFlag)
 
-			if err := configure(env, 2, true, mongodbURI, "", dbName); err != nil {
+			if err := configure(env, 2, true, mongodbURI, bucketOptions{}, dbName); err != nil {
 				return errors.WithStack(err)
 			}
 
@@ -75,7 +75,7 @@ func loadGraphToDB() cli.Command {
 
 			env := cedar.GetEnvironment()
 
-			if err := configure(env, 2, true, mongodbURI, "", dbName); err != nil {
+			if err := configure(env, 2, true, mongodbURI, bucketOptions{}, dbName); err != nil {
 				return errors.WithStack(err)
 			}
 
@@ -140,7 +140,7 @@ func cleanDB() cli.Command {
 
 			env := cedar.GetEnvironment()
 
-			if err := configure(env, 2, true, mongodbURI, "", dbName); err != nil {
+			if err := configure(env, 2, true, mongodbURI, bucketOptions{}, dbName); err != nil {
 				return errors.WithStack(err)
 			}
 
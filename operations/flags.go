@@ -27,8 +27,12 @@ const (
 	pathFlagName   = "path"
 	outputFlagName = "output"
 
-	numWorkersFlag = "workers"
-	bucketNameFlag = "bucket"
+	numWorkersFlag   = "workers"
+	bucketNameFlag   = "bucket"
+	bucketTypeFlag   = "bucketType"
+	bucketRegionFlag = "bucketRegion"
+	awsKeyFlag       = "awsKey"
+	awsSecretFlag    = "awsSecret"
 
 	dbURIFlag  = "dbUri"
 	dbNameFlag = "dbName"
@@ -152,9 +156,32 @@ func baseFlags(flags ...cli.Flag) []cli.Flag {
 			Value: 2,
 		},
 		cli.StringFlag{
+			Name:   bucketNameFlag,
 			Usage:  "specify a bucket name to use for storing data in s3",
 			EnvVar: "CEDAR_BUCKET_NAME",
 			Value:  "build-test-curator",
+		},
+		cli.StringFlag{
+			Name:   bucketTypeFlag,
+			Usage:  "specify the storage backend for artifacts (s3, local, or gridfs-legacy)",
+			EnvVar: "CEDAR_BUCKET_TYPE",
+			Value:  "gridfs-legacy",
+		},
+		cli.StringFlag{
+			Name:   bucketRegionFlag,
+			Usage:  "specify the aws region of the s3 bucket",
+			EnvVar: "CEDAR_BUCKET_REGION",
+			Value:  "us-east-1",
+		},
+		cli.StringFlag{
+			Name:   awsKeyFlag,
+			Usage:  "specify the aws key for s3, otherwise the default credential chain is used",
+			EnvVar: "CEDAR_AWS_KEY",
+		},
+		cli.StringFlag{
+			Name:   awsSecretFlag,
+			Usage:  "specify the aws secret for s3",
+			EnvVar: "CEDAR_AWS_SECRET",
 		})
 }
 
@@ -16,7 +16,7 @@ func TestBaseFlags(t *testing.T) {
 		flagMap[f.GetName()] = f
 	}
 
-	expected := []string{"workers", "dbUri", "dbName", "bucket"}
+	expected := []string{"workers", "dbUri", "dbName", "bucket", "bucketType", "bucketRegion", "awsKey", "awsSecret"}
 	for _, n := range expected {
 		_, ok := flagMap[n]
 		assert.True(ok, n)
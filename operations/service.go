@@ -7,10 +7,14 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
 	"github.com/evergreen-ci/cedar/rest"
 	"github.com/evergreen-ci/cedar/rpc"
+	"github.com/evergreen-ci/cedar/units"
+	"github.com/mongodb/amboy"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/recovery"
 	"github.com/pkg/errors"
@@ -66,7 +70,7 @@ func Service() cli.Command {
 			workers := c.Int(numWorkersFlag)
 			mongodbURI := c.String(dbURIFlag)
 			runLocal := c.Bool(localQueueFlag)
-			bucket := c.String(bucketNameFlag)
+			bucket := newBucketOptions(c)
 			dbName := c.String(dbNameFlag)
 			port := c.Int(servicePortFlag)
 
@@ -82,6 +86,23 @@ func Service() cli.Command {
 			if err := configure(env, workers, runLocal, mongodbURI, bucket, dbName); err != nil {
 				return errors.WithStack(err)
 			}
+			grip.Warning(errors.Wrap(model.EnsurePerfResultIndexes(env), "problem creating indexes"))
+
+			q, err := env.GetQueue()
+			if err != nil {
+				return errors.Wrap(err, "problem getting queue")
+			}
+
+			///////////////////////////////////
+			//
+			// scheduling background jobs
+			//
+			amboy.IntervalQueueOperation(ctx, q, units.RollupRecalculationInterval, time.Now(), amboy.QueueOperationConfig{ContinueOnError: true}, func(queue amboy.Queue) error {
+				return queue.Put(units.MakeRollupRecalculationJob(env, time.Now().Truncate(units.RollupRecalculationInterval)))
+			})
+			amboy.IntervalQueueOperation(ctx, q, units.NoiseScoringInterval, time.Now(), amboy.QueueOperationConfig{ContinueOnError: true}, func(queue amboy.Queue) error {
+				return queue.Put(units.MakeNoiseScoringJob(env, time.Now().Truncate(units.NoiseScoringInterval)))
+			})
 
 			///////////////////////////////////
 			//
@@ -108,7 +129,7 @@ func Service() cli.Command {
 			//
 			// starting grpc
 			//
-			rpcSrv := grpc.NewServer()
+			rpcSrv := grpc.NewServer(rpc.ServerOptions()...)
 			rpc.AttachService(env, rpcSrv)
 
 			lis, err := net.Listen("tcp", rpcAddr)
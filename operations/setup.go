@@ -9,6 +9,7 @@ import (
 	"github.com/mongodb/grip/level"
 	"github.com/mongodb/grip/send"
 	"github.com/pkg/errors"
+	"github.com/urfave/cli"
 )
 
 const (
@@ -16,9 +17,38 @@ const (
 	loggingBufferDuration = 20 * time.Second
 )
 
-func configure(env cedar.Environment, numWorkers int, localQueue bool, mongodbURI, bucket, dbName string) error {
+// bucketOptions describes where cedar stores offline data.
+type bucketOptions struct {
+	name   string
+	kind   string
+	region string
+	key    string
+	secret string
+}
+
+func newBucketOptions(c *cli.Context) bucketOptions {
+	return bucketOptions{
+		name:   c.String(bucketNameFlag),
+		kind:   c.String(bucketTypeFlag),
+		region: c.String(bucketRegionFlag),
+		key:    c.String(awsKeyFlag),
+		secret: c.String(awsSecretFlag),
+	}
+}
+
+func configure(env cedar.Environment, numWorkers int, localQueue bool, mongodbURI string, bucket bucketOptions, dbName string) error {
+	if bucket.kind != "" {
+		if err := model.PailType(bucket.kind).Validate(); err != nil {
+			return errors.Wrap(err, "problem setting up configuration")
+		}
+	}
+
 	err := env.Configure(&cedar.Configuration{
-		BucketName:    bucket,
+		BucketName:    bucket.name,
+		BucketType:    bucket.kind,
+		BucketRegion:  bucket.region,
+		AWSKey:        bucket.key,
+		AWSSecret:     bucket.secret,
 		DatabaseName:  dbName,
 		MongoDBURI:    mongodbURI,
 		UseLocalQueue: localQueue,
@@ -14,25 +14,30 @@ func TestServiceConfiguration(t *testing.T) {
 		},
 		"PanicsWithNilEnv": func(t *testing.T, env cedar.Environment) {
 			assert.Panics(t, func() {
-				_ = configure(nil, 2, true, "foo", "bar", "baz")
+				_ = configure(nil, 2, true, "foo", bucketOptions{name: "bar"}, "baz")
 			})
 		},
 		"ErrorsWithInvalidConfigDatabase": func(t *testing.T, env cedar.Environment) {
-			err := configure(env, 2, true, "", "", "")
+			err := configure(env, 2, true, "", bucketOptions{}, "")
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "problem setting up config")
 			assert.Contains(t, err.Error(), "mongodb")
 		},
 		"ErrorsWithInvalidConfigWorkers": func(t *testing.T, env cedar.Environment) {
-			err := configure(env, -1, true, "mongodb://localhost:27017", "", "")
+			err := configure(env, -1, true, "mongodb://localhost:27017", bucketOptions{}, "")
 			assert.Error(t, err)
 			assert.Contains(t, err.Error(), "problem setting up config")
 			assert.Contains(t, err.Error(), "workers")
 		},
 		"ValidOptions": func(t *testing.T, env cedar.Environment) {
-			err := configure(env, 2, true, "mongodb://localhost:27017", "foo", "cedar_test")
+			err := configure(env, 2, true, "mongodb://localhost:27017", bucketOptions{name: "foo"}, "cedar_test")
 			assert.NoError(t, err)
 		},
+		"ErrorsWithInvalidBucketType": func(t *testing.T, env cedar.Environment) {
+			err := configure(env, 2, true, "mongodb://localhost:27017", bucketOptions{name: "foo", kind: "ftp"}, "cedar_test")
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "pail type")
+		},
 		"ConfigurationOfLogging": func(t *testing.T, env cedar.Environment) {
 			t.Skip("skipping because the code is improperly factored to support testing")
 		},
@@ -29,7 +29,7 @@ func Worker() cli.Command {
 
 			workers := c.Int(numWorkersFlag)
 			mongodbURI := c.String(dbURIFlag)
-			bucket := c.String(bucketNameFlag)
+			bucket := newBucketOptions(c)
 			dbName := c.String(dbNameFlag)
 
 			env := cedar.GetEnvironment()
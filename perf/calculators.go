@@ -0,0 +1,128 @@
+package perf
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/pkg/errors"
+)
+
+const (
+	// FTDCRollupCalculator, HistogramRollupCalculator and
+	// CustomRollupCalculator name the calculations that produce the
+	// rollups of raw events, of histogram data and of custom events,
+	// respectively.
+	FTDCRollupCalculator      = "ftdc"
+	HistogramRollupCalculator = "ftdc-histograms"
+	CustomRollupCalculator    = "ftdc-custom"
+)
+
+// RollupCalculator describes a calculation of rollups from the FTDC
+// artifacts of a performance result. The version must be incremented
+// whenever a change to the calculation changes the values that it
+// produces, so that rollups calculated by older versions are
+// recalculated.
+type RollupCalculator struct {
+	Name    string
+	Version int
+	Schema  model.FileSchema
+}
+
+// Validate checks that the calculator has a name, a positive version
+// and a valid schema.
+func (c RollupCalculator) Validate() error {
+	if c.Name == "" {
+		return errors.New("rollup calculator must have a name")
+	}
+	if c.Version <= 0 {
+		return errors.Errorf("rollup calculator '%s' must have a positive version", c.Name)
+	}
+
+	return errors.Wrapf(c.Schema.Validate(), "invalid schema for rollup calculator '%s'", c.Name)
+}
+
+var rollupCalculators = struct {
+	mu       sync.RWMutex
+	registry map[string]RollupCalculator
+}{
+	registry: map[string]RollupCalculator{},
+}
+
+func init() {
+	for _, c := range []RollupCalculator{
+		{Name: FTDCRollupCalculator, Version: 1, Schema: model.SchemaRawEvents},
+		{Name: HistogramRollupCalculator, Version: 1, Schema: model.SchemaHistogram},
+		{Name: CustomRollupCalculator, Version: 1, Schema: model.SchemaCustomEvents},
+	} {
+		if err := RegisterRollupCalculator(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterRollupCalculator adds the calculator to the registry,
+// replacing any calculator with the same name.
+func RegisterRollupCalculator(c RollupCalculator) error {
+	if err := c.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	rollupCalculators.mu.Lock()
+	defer rollupCalculators.mu.Unlock()
+
+	rollupCalculators.registry[c.Name] = c
+
+	return nil
+}
+
+// GetRollupCalculator returns the registered calculator with the
+// given name.
+func GetRollupCalculator(name string) (RollupCalculator, bool) {
+	rollupCalculators.mu.RLock()
+	defer rollupCalculators.mu.RUnlock()
+
+	c, ok := rollupCalculators.registry[name]
+	return c, ok
+}
+
+// RollupCalculators returns the registered calculators, sorted by
+// name.
+func RollupCalculators() []RollupCalculator {
+	rollupCalculators.mu.RLock()
+	defer rollupCalculators.mu.RUnlock()
+
+	out := make([]RollupCalculator, 0, len(rollupCalculators.registry))
+	for _, c := range rollupCalculators.registry {
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+
+	return out
+}
+
+// RollupCalculatorVersions returns the current version of every
+// registered calculator, by name.
+func RollupCalculatorVersions() map[string]int {
+	rollupCalculators.mu.RLock()
+	defer rollupCalculators.mu.RUnlock()
+
+	out := make(map[string]int, len(rollupCalculators.registry))
+	for name, c := range rollupCalculators.registry {
+		out[name] = c.Version
+	}
+
+	return out
+}
+
+// withCalculator records the name and the current version of the
+// calculator that produced the rollups.
+func withCalculator(rollups []model.PerfRollupValue, name string) []model.PerfRollupValue {
+	c, _ := GetRollupCalculator(name)
+	for i := range rollups {
+		rollups[i].Calculator = name
+		rollups[i].Version = c.Version
+	}
+
+	return rollups
+}
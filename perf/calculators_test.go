@@ -0,0 +1,48 @@
+package perf
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollupCalculators(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		versions := RollupCalculatorVersions()
+		assert.Contains(t, versions, FTDCRollupCalculator)
+		assert.Contains(t, versions, HistogramRollupCalculator)
+		assert.Contains(t, versions, CustomRollupCalculator)
+
+		calculators := RollupCalculators()
+		require.Len(t, calculators, len(versions))
+		for i, c := range calculators {
+			assert.Equal(t, versions[c.Name], c.Version)
+			if i > 0 {
+				assert.True(t, calculators[i-1].Name < c.Name)
+			}
+		}
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		for name, c := range map[string]RollupCalculator{
+			"NoName":        {Version: 1, Schema: model.SchemaRawEvents},
+			"NoVersion":     {Name: "test", Schema: model.SchemaRawEvents},
+			"InvalidSchema": {Name: "test", Version: 1, Schema: "foo"},
+		} {
+			assert.Error(t, RegisterRollupCalculator(c), name)
+		}
+		_, ok := GetRollupCalculator("test")
+		assert.False(t, ok)
+	})
+	t.Run("WithCalculator", func(t *testing.T) {
+		c, ok := GetRollupCalculator(FTDCRollupCalculator)
+		require.True(t, ok)
+
+		rollups := withCalculator([]model.PerfRollupValue{{Name: "a"}, {Name: "b"}}, FTDCRollupCalculator)
+		for _, r := range rollups {
+			assert.Equal(t, FTDCRollupCalculator, r.Calculator)
+			assert.Equal(t, c.Version, r.Version)
+		}
+	})
+}
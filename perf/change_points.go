@@ -0,0 +1,228 @@
+package perf
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	// ChangePointAlgorithm and ChangePointAlgorithmVersion identify
+	// the implementation of DetectChangePoints, so that stored change
+	// points can be recalculated when the algorithm changes.
+	ChangePointAlgorithm        = "e-divisive-means"
+	ChangePointAlgorithmVersion = 1
+
+	defaultChangePointPermutations = 100
+	defaultChangePointPValue       = 0.05
+	defaultChangePointMinSize      = 3
+)
+
+// ChangePointOptions configure DetectChangePoints. The zero value
+// uses the default options.
+type ChangePointOptions struct {
+	// Permutations is the number of random permutations used to
+	// estimate the significance of each candidate change point.
+	Permutations int
+	// PValue is the significance threshold for accepting a change
+	// point.
+	PValue float64
+	// MinSegmentSize is the smallest number of points on either side
+	// of a change point.
+	MinSegmentSize int
+	// Seed seeds the permutation test, which makes the results
+	// reproducible for a given series.
+	Seed int64
+	// MinMagnitude discards change points with a smaller absolute
+	// magnitude, such as shifts within the noise of the series. The
+	// means around the remaining change points are not recalculated.
+	MinMagnitude float64
+}
+
+func (opts *ChangePointOptions) setDefaults() {
+	if opts.Permutations <= 0 {
+		opts.Permutations = defaultChangePointPermutations
+	}
+	if opts.PValue <= 0 || opts.PValue >= 1 {
+		opts.PValue = defaultChangePointPValue
+	}
+	if opts.MinSegmentSize < 2 {
+		opts.MinSegmentSize = defaultChangePointMinSize
+	}
+}
+
+// ChangePoint describes a statistically significant shift in a
+// series. The point at Index is the first point after the shift.
+type ChangePoint struct {
+	Index int
+	// Before and After are the means of the series between the
+	// change point and its neighboring change points (or the ends
+	// of the series).
+	Before float64
+	After  float64
+	// Magnitude is the change of the mean relative to the mean
+	// before the change point, or the absolute change if the mean
+	// before the change point is zero.
+	Magnitude float64
+	// Confidence is one minus the estimated p-value of the change
+	// point.
+	Confidence float64
+}
+
+// DetectChangePoints finds the change points in the series using
+// E-Divisive means: the series is recursively divided at the point
+// that maximizes the energy distance between the two sides, for as
+// long as the division is significant according to a permutation
+// test. The change points are returned in series order.
+func DetectChangePoints(series []float64, opts ChangePointOptions) []ChangePoint {
+	opts.setDefaults()
+
+	d := &eDivisive{
+		series: series,
+		opts:   opts,
+		rand:   rand.New(rand.NewSource(opts.Seed)),
+	}
+
+	found := []ChangePoint{}
+	segments := [][2]int{{0, len(series)}}
+	for {
+		best := -1
+		bestIdx := 0
+		bestQ := 0.0
+		for i, seg := range segments {
+			idx, q, ok := d.bestSplit(series[seg[0]:seg[1]])
+			if ok && (best < 0 || q > bestQ) {
+				best, bestIdx, bestQ = i, seg[0]+idx, q
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		seg := segments[best]
+		pValue := d.significance(series[seg[0]:seg[1]], bestQ)
+		if pValue > opts.PValue {
+			break
+		}
+
+		found = append(found, ChangePoint{Index: bestIdx, Confidence: 1 - pValue})
+		segments = append(segments[:best], append([][2]int{{seg[0], bestIdx}, {bestIdx, seg[1]}}, segments[best+1:]...)...)
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].Index < found[j].Index })
+	for i := range found {
+		start := 0
+		if i > 0 {
+			start = found[i-1].Index
+		}
+		end := len(series)
+		if i < len(found)-1 {
+			end = found[i+1].Index
+		}
+
+		found[i].Before = mean(series[start:found[i].Index])
+		found[i].After = mean(series[found[i].Index:end])
+		if found[i].Before == 0 {
+			found[i].Magnitude = found[i].After
+		} else {
+			found[i].Magnitude = (found[i].After - found[i].Before) / math.Abs(found[i].Before)
+		}
+	}
+
+	if opts.MinMagnitude > 0 {
+		significant := found[:0]
+		for _, cp := range found {
+			if math.Abs(cp.Magnitude) >= opts.MinMagnitude {
+				significant = append(significant, cp)
+			}
+		}
+		found = significant
+	}
+
+	return found
+}
+
+type eDivisive struct {
+	series []float64
+	opts   ChangePointOptions
+	rand   *rand.Rand
+}
+
+// bestSplit returns the index in the segment that maximizes the
+// divergence between the points before and after it, and the value of
+// the divergence statistic at that index.
+func (d *eDivisive) bestSplit(segment []float64) (int, float64, bool) {
+	n := len(segment)
+	minSize := d.opts.MinSegmentSize
+	if n < 2*minSize {
+		return 0, 0, false
+	}
+
+	// within sums the distances between pairs of points on the same
+	// side of the split, and across sums the distances between
+	// points on opposite sides.
+	withinLeft, withinRight, across := 0.0, 0.0, 0.0
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			withinRight += math.Abs(segment[i] - segment[j])
+		}
+	}
+
+	bestIdx, bestQ := 0, math.Inf(-1)
+	for tau := 1; tau <= n-minSize; tau++ {
+		// move the point at tau-1 from the right to the left side.
+		moved := segment[tau-1]
+		for i := 0; i < tau-1; i++ {
+			dist := math.Abs(segment[i] - moved)
+			withinLeft += dist
+			across -= dist
+		}
+		for j := tau; j < n; j++ {
+			dist := math.Abs(moved - segment[j])
+			withinRight -= dist
+			across += dist
+		}
+
+		if tau < minSize {
+			continue
+		}
+
+		left, right := float64(tau), float64(n-tau)
+		q := (left * right / (left + right)) *
+			(2*across/(left*right) - withinLeft/(left*(left-1)/2) - withinRight/(right*(right-1)/2))
+		if q > bestQ {
+			bestIdx, bestQ = tau, q
+		}
+	}
+
+	return bestIdx, bestQ, true
+}
+
+// significance estimates the probability that a split at least as
+// good as q would be found in a random permutation of the segment.
+func (d *eDivisive) significance(segment []float64, q float64) float64 {
+	permuted := make([]float64, len(segment))
+	copy(permuted, segment)
+
+	exceeded := 0
+	for i := 0; i < d.opts.Permutations; i++ {
+		d.rand.Shuffle(len(permuted), func(a, b int) { permuted[a], permuted[b] = permuted[b], permuted[a] })
+		if _, pq, ok := d.bestSplit(permuted); ok && pq >= q {
+			exceeded++
+		}
+	}
+
+	return float64(exceeded+1) / float64(d.opts.Permutations+1)
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,58 @@
+package perf
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noisySeries(seed int64, levels ...float64) []float64 {
+	r := rand.New(rand.NewSource(seed))
+	series := []float64{}
+	for _, level := range levels {
+		for i := 0; i < 20; i++ {
+			series = append(series, level+r.Float64())
+		}
+	}
+	return series
+}
+
+func TestDetectChangePoints(t *testing.T) {
+	t.Run("NoChange", func(t *testing.T) {
+		assert.Empty(t, DetectChangePoints(noisySeries(1, 100, 100), ChangePointOptions{}))
+	})
+	t.Run("TooShort", func(t *testing.T) {
+		assert.Empty(t, DetectChangePoints([]float64{1, 100}, ChangePointOptions{}))
+		assert.Empty(t, DetectChangePoints(nil, ChangePointOptions{}))
+	})
+	t.Run("SingleShift", func(t *testing.T) {
+		points := DetectChangePoints(noisySeries(2, 100, 150), ChangePointOptions{})
+		require.Len(t, points, 1)
+		assert.Equal(t, 20, points[0].Index)
+		assert.InDelta(t, 100.5, points[0].Before, 1)
+		assert.InDelta(t, 150.5, points[0].After, 1)
+		assert.InDelta(t, 0.5, points[0].Magnitude, 0.02)
+		assert.True(t, points[0].Confidence > 0.95)
+	})
+	t.Run("MultipleShifts", func(t *testing.T) {
+		points := DetectChangePoints(noisySeries(3, 100, 50, 200), ChangePointOptions{})
+		require.Len(t, points, 2)
+		assert.Equal(t, 20, points[0].Index)
+		assert.Equal(t, 40, points[1].Index)
+		assert.True(t, points[0].Magnitude < 0)
+		assert.True(t, points[1].Magnitude > 0)
+		assert.InDelta(t, points[0].After, points[1].Before, 0.001)
+	})
+	t.Run("MinMagnitude", func(t *testing.T) {
+		points := DetectChangePoints(noisySeries(3, 100, 50, 200), ChangePointOptions{MinMagnitude: 1})
+		require.Len(t, points, 1)
+		assert.Equal(t, 40, points[0].Index)
+	})
+	t.Run("Reproducible", func(t *testing.T) {
+		series := noisySeries(4, 10, 11)
+		opts := ChangePointOptions{Seed: 42}
+		assert.Equal(t, DetectChangePoints(series, opts), DetectChangePoints(series, opts))
+	})
+}
@@ -0,0 +1,266 @@
+package perf
+
+import (
+	"context"
+	"io"
+	"math"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultChartPoints is the number of points per metric of a
+	// chart when the number is not specified.
+	DefaultChartPoints = 1000
+
+	// MaxChartPoints is the largest number of points per metric of a
+	// chart.
+	MaxChartPoints = 10000
+)
+
+// ChartOptions configure ChartTimeseries.
+type ChartOptions struct {
+	// Metric is the flattened FTDC key of the metric to chart, such
+	// as "counters.ops". When empty, every metric is charted.
+	Metric string
+	// MaxPoints is the largest number of points per metric, and
+	// must be at least 3.
+	MaxPoints int
+}
+
+// Validate checks that the number of points is supported.
+func (opts ChartOptions) Validate() error {
+	if opts.MaxPoints < 3 || opts.MaxPoints > MaxChartPoints {
+		return errors.Errorf("number of points must be between 3 and %d", MaxChartPoints)
+	}
+
+	return nil
+}
+
+// ChartTimeseries downsamples the metrics of the FTDC data to at most
+// the configured number of points per metric, using the largest
+// triangle three buckets algorithm, which keeps the points that
+// matter most to the shape of the chart. The X value of a point is
+// its timestamp, in milliseconds since the epoch, when the data has
+// timestamps, and its index otherwise.
+//
+// The data is read twice, once to count the samples of each metric
+// and once to downsample them, so that at most two buckets of samples
+// per metric are held in memory. Metrics are returned in the order
+// that they first appear.
+func ChartTimeseries(ctx context.Context, open func() (io.ReadCloser, error), opts ChartOptions) ([]model.PerfChartSeries, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	names := []string{}
+	counts := map[string]int{}
+	err := readChartChunks(ctx, open, opts.Metric, func(name string, ts, values []int64) {
+		if _, ok := counts[name]; !ok {
+			names = append(names, name)
+		}
+		counts[name] += len(values)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "problem counting samples")
+	}
+
+	samplers := make(map[string]*lttbSampler, len(names))
+	for _, name := range names {
+		samplers[name] = newLTTBSampler(counts[name], opts.MaxPoints)
+	}
+	err = readChartChunks(ctx, open, opts.Metric, func(name string, ts, values []int64) {
+		sampler, ok := samplers[name]
+		if !ok {
+			return
+		}
+
+		for i, val := range values {
+			x := float64(sampler.seen)
+			if len(ts) == len(values) {
+				x = float64(ts[i])
+			}
+			sampler.add(model.PerfChartPoint{X: x, Y: float64(val)})
+		}
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "problem downsampling samples")
+	}
+
+	series := make([]model.PerfChartSeries, len(names))
+	for i, name := range names {
+		series[i] = model.PerfChartSeries{
+			Metric: name,
+			Points: samplers[name].finish(),
+		}
+	}
+
+	return series, nil
+}
+
+// readChartChunks calls add with the values of every metric, other
+// than the timestamp, of each chunk of the data, along with the
+// timestamps of the chunk, if any. When metric is not empty, only the
+// values of that metric are added.
+func readChartChunks(ctx context.Context, open func() (io.ReadCloser, error), metric string, add func(name string, ts, values []int64)) error {
+	r, err := open()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	iter := ftdc.ReadChunks(ctx, r)
+	for iter.Next() {
+		chunk := iter.Chunk()
+
+		var ts []int64
+		for _, m := range chunk.Metrics {
+			if m.Key() == timeseriesTimestampKey {
+				ts = m.Values
+				break
+			}
+		}
+
+		for _, m := range chunk.Metrics {
+			name := m.Key()
+			if name == timeseriesTimestampKey || (metric != "" && name != metric) {
+				continue
+			}
+			add(name, ts, m.Values)
+		}
+	}
+	catcher.Add(iter.Err())
+	iter.Close()
+	catcher.Add(r.Close())
+
+	return catcher.Resolve()
+}
+
+// lttbSampler selects the points of the largest triangle three
+// buckets downsampling of a series as the points are added, given the
+// length of the series in advance. The first and last points are
+// always selected, and the points in between are divided into buckets
+// of equal size. From each bucket, the sampler selects the point that
+// forms the largest triangle with the point selected from the
+// previous bucket and the average of the next bucket, which is why
+// the sampler holds two buckets at a time.
+type lttbSampler struct {
+	total   int
+	every   float64
+	buckets int
+	seen    int
+
+	bucket  int
+	current []model.PerfChartPoint
+	next    []model.PerfChartPoint
+	last    *model.PerfChartPoint
+	points  []model.PerfChartPoint
+}
+
+func newLTTBSampler(total, threshold int) *lttbSampler {
+	s := &lttbSampler{total: total}
+	if threshold >= 3 && total > threshold {
+		s.buckets = threshold - 2
+		s.every = float64(total-2) / float64(s.buckets)
+	}
+
+	return s
+}
+
+func (s *lttbSampler) add(point model.PerfChartPoint) {
+	idx := s.seen
+	s.seen++
+
+	switch {
+	case s.buckets == 0 || idx == 0:
+		s.points = append(s.points, point)
+		return
+	case idx >= s.total-1:
+		s.last = &point
+		return
+	}
+
+	bucket := int(float64(idx-1) / s.every)
+	if bucket >= s.buckets {
+		bucket = s.buckets - 1
+	}
+	for bucket > s.bucket+1 {
+		s.selectPoint(s.current, average(s.next))
+		s.current, s.next = s.next, s.current[:0]
+		s.bucket++
+	}
+
+	if bucket == s.bucket {
+		s.current = append(s.current, point)
+	} else {
+		s.next = append(s.next, point)
+	}
+}
+
+func (s *lttbSampler) finish() []model.PerfChartPoint {
+	if s.buckets == 0 {
+		return s.points
+	}
+
+	last := s.last
+	if last == nil {
+		// the series was shorter than expected, so the last
+		// point added stands in for the last point.
+		switch {
+		case len(s.next) > 0:
+			last = &s.next[len(s.next)-1]
+			s.next = s.next[:len(s.next)-1]
+		case len(s.current) > 0:
+			last = &s.current[len(s.current)-1]
+			s.current = s.current[:len(s.current)-1]
+		default:
+			return s.points
+		}
+	}
+
+	if len(s.next) > 0 {
+		s.selectPoint(s.current, average(s.next))
+		s.selectPoint(s.next, *last)
+	} else {
+		s.selectPoint(s.current, *last)
+	}
+
+	return append(s.points, *last)
+}
+
+// selectPoint selects the point of the bucket that forms the largest
+// triangle with the last selected point and the given point.
+func (s *lttbSampler) selectPoint(bucket []model.PerfChartPoint, next model.PerfChartPoint) {
+	if len(bucket) == 0 {
+		return
+	}
+
+	prev := s.points[len(s.points)-1]
+	selected, maxArea := 0, -1.0
+	for i, point := range bucket {
+		area := math.Abs((prev.X-next.X)*(point.Y-prev.Y) - (prev.X-point.X)*(next.Y-prev.Y))
+		if area > maxArea {
+			selected, maxArea = i, area
+		}
+	}
+	s.points = append(s.points, bucket[selected])
+}
+
+func average(points []model.PerfChartPoint) model.PerfChartPoint {
+	avg := model.PerfChartPoint{}
+	if len(points) == 0 {
+		return avg
+	}
+
+	for _, point := range points {
+		avg.X += point.X
+		avg.Y += point.Y
+	}
+	avg.X /= float64(len(points))
+	avg.Y /= float64(len(points))
+
+	return avg
+}
@@ -0,0 +1,170 @@
+package perf
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/ftdc/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lttb is the reference implementation of the downsampling, which
+// holds the whole series in memory.
+func lttb(series []model.PerfChartPoint, threshold int) []model.PerfChartPoint {
+	if threshold < 3 || len(series) <= threshold {
+		return series
+	}
+
+	every := float64(len(series)-2) / float64(threshold-2)
+	buckets := make([][]model.PerfChartPoint, threshold-2)
+	for idx := 1; idx < len(series)-1; idx++ {
+		bucket := int(float64(idx-1) / every)
+		if bucket >= len(buckets) {
+			bucket = len(buckets) - 1
+		}
+		buckets[bucket] = append(buckets[bucket], series[idx])
+	}
+
+	out := []model.PerfChartPoint{series[0]}
+	for i, bucket := range buckets {
+		next := series[len(series)-1]
+		if i+1 < len(buckets) {
+			next = average(buckets[i+1])
+		}
+
+		prev := out[len(out)-1]
+		selected, maxArea := 0, -1.0
+		for j, point := range bucket {
+			area := math.Abs((prev.X-next.X)*(point.Y-prev.Y) - (prev.X-point.X)*(next.Y-prev.Y))
+			if area > maxArea {
+				selected, maxArea = j, area
+			}
+		}
+		out = append(out, bucket[selected])
+	}
+
+	return append(out, series[len(series)-1])
+}
+
+func TestChartTimeseries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	collector := ftdc.NewDynamicCollector(100)
+	workers := []model.PerfChartPoint{}
+	for i := 0; i < 1000; i++ {
+		point := events.Performance{Timestamp: start.Add(time.Duration(i) * time.Second)}
+		point.Counters.Operations = int64(10 * i)
+		point.Gauges.Workers = int64(4 + i%7)
+		if i == 555 {
+			point.Gauges.Workers = 100
+		}
+		require.NoError(t, collector.Add(point))
+		workers = append(workers, model.PerfChartPoint{
+			X: float64(point.Timestamp.UnixNano() / int64(time.Millisecond)),
+			Y: float64(point.Gauges.Workers),
+		})
+	}
+	data, err := collector.Resolve()
+	require.NoError(t, err)
+
+	opened := 0
+	open := func() (io.ReadCloser, error) {
+		opened++
+		return ioutil.NopCloser(bytes.NewReader(data)), nil
+	}
+
+	t.Run("Downsample", func(t *testing.T) {
+		series, err := ChartTimeseries(ctx, open, ChartOptions{Metric: "gauges.workers", MaxPoints: 50})
+		require.NoError(t, err)
+		require.Len(t, series, 1)
+		assert.Equal(t, "gauges.workers", series[0].Metric)
+
+		points := series[0].Points
+		require.Len(t, points, 50)
+		assert.Equal(t, lttb(workers, 50), points)
+		assert.Equal(t, workers[0], points[0])
+		assert.Equal(t, workers[len(workers)-1], points[len(points)-1])
+		assert.Contains(t, points, workers[555])
+		for i := 1; i < len(points); i++ {
+			assert.True(t, points[i].X > points[i-1].X)
+		}
+	})
+	t.Run("AllMetrics", func(t *testing.T) {
+		series, err := ChartTimeseries(ctx, open, ChartOptions{MaxPoints: 10})
+		require.NoError(t, err)
+
+		names := []string{}
+		for _, s := range series {
+			names = append(names, s.Metric)
+			assert.Len(t, s.Points, 10)
+		}
+		assert.NotContains(t, names, "ts")
+		assert.Contains(t, names, "counters.ops")
+		assert.Contains(t, names, "gauges.workers")
+	})
+	t.Run("FewerSamplesThanPoints", func(t *testing.T) {
+		series, err := ChartTimeseries(ctx, open, ChartOptions{Metric: "gauges.workers", MaxPoints: MaxChartPoints})
+		require.NoError(t, err)
+		require.Len(t, series, 1)
+		assert.Equal(t, workers, series[0].Points)
+	})
+	t.Run("MissingMetric", func(t *testing.T) {
+		series, err := ChartTimeseries(ctx, open, ChartOptions{Metric: "counters.foo", MaxPoints: 10})
+		require.NoError(t, err)
+		assert.Empty(t, series)
+	})
+	t.Run("InvalidPoints", func(t *testing.T) {
+		opened = 0
+		for _, points := range []int{0, 2, MaxChartPoints + 1} {
+			_, err := ChartTimeseries(ctx, open, ChartOptions{MaxPoints: points})
+			assert.Error(t, err)
+		}
+		assert.Zero(t, opened)
+	})
+}
+
+func TestLTTBSampler(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		total     int
+		threshold int
+	}{
+		{name: "EvenBuckets", total: 102, threshold: 12},
+		{name: "UnevenBuckets", total: 1000, threshold: 7},
+		{name: "SmallBuckets", total: 11, threshold: 10},
+		{name: "MinimumThreshold", total: 50, threshold: 3},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			series := make([]model.PerfChartPoint, test.total)
+			for i := range series {
+				series[i] = model.PerfChartPoint{X: float64(i), Y: math.Sin(float64(i) / 3)}
+			}
+
+			sampler := newLTTBSampler(test.total, test.threshold)
+			for _, point := range series {
+				sampler.add(point)
+			}
+			assert.Equal(t, lttb(series, test.threshold), sampler.finish())
+		})
+	}
+	t.Run("ShorterThanExpected", func(t *testing.T) {
+		sampler := newLTTBSampler(100, 10)
+		for i := 0; i < 50; i++ {
+			sampler.add(model.PerfChartPoint{X: float64(i), Y: float64(i % 5)})
+		}
+
+		points := sampler.finish()
+		assert.True(t, len(points) <= 10)
+		assert.Equal(t, model.PerfChartPoint{X: 49, Y: 4}, points[len(points)-1])
+	})
+}
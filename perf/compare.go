@@ -0,0 +1,200 @@
+package perf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/evergreen-ci/cedar/model"
+)
+
+// ComparisonKey identifies the results that are compared with each
+// other. Results with the same key are trials of the same test.
+type ComparisonKey struct {
+	Variant   string
+	TaskName  string
+	TestName  string
+	Arguments map[string]int32
+	Tags      []string
+}
+
+func (k ComparisonKey) String() string {
+	args := make([]string, 0, len(k.Arguments))
+	for name, val := range k.Arguments {
+		args = append(args, fmt.Sprintf("%s=%d", name, val))
+	}
+	sort.Strings(args)
+
+	tags := append([]string{}, k.Tags...)
+	sort.Strings(tags)
+
+	return strings.Join([]string{
+		k.Variant,
+		k.TaskName,
+		k.TestName,
+		strings.Join(args, ","),
+		strings.Join(tags, ","),
+	}, "|")
+}
+
+// CompareOptions configure CompareResults.
+type CompareOptions struct {
+	// MatchTask includes the variant and task name of the results
+	// in the comparison key, which is necessary when comparing
+	// versions, but prevents comparing results from different tasks.
+	MatchTask bool
+}
+
+// ResultComparison compares the rollups of a test between the base
+// and comparison results.
+type ResultComparison struct {
+	Key           ComparisonKey
+	BaseIDs       []string
+	ComparisonIDs []string
+	Rollups       []RollupComparison
+}
+
+// RollupComparison compares the values of a rollup across the trials
+// of a test in the base and comparison results.
+type RollupComparison struct {
+	Name string
+	// Base and Comparison are the means of the rollup across trials.
+	Base       float64
+	Comparison float64
+	// BaseTrials and ComparisonTrials are the number of values of
+	// the rollup in each set of results.
+	BaseTrials       int
+	ComparisonTrials int
+	AbsoluteDelta    float64
+	// PercentDelta is the change relative to the base value, which
+	// is NaN if the base value is zero or the change is not finite.
+	PercentDelta float64
+	// UStatistic and PValue are the results of a Mann-Whitney U test
+	// between the trials of the two sets of results.
+	UStatistic float64
+	PValue     float64
+}
+
+// CompareResults joins the base and comparison results by test name,
+// arguments and tags, and compares every rollup that is present in
+// both. Non-finite values of the rollups, such as the latency of a
+// trial without operations, are ignored. The trials of each test are used as the samples for the
+// significance test. The comparisons are sorted by key and rollup
+// name.
+func CompareResults(base, comparison []model.PerformanceResult, opts CompareOptions) []ResultComparison {
+	type trials struct {
+		key           ComparisonKey
+		baseIDs       []string
+		comparisonIDs []string
+		base          map[string][]float64
+		comparison    map[string][]float64
+	}
+
+	groups := map[string]*trials{}
+	add := func(result model.PerformanceResult, isBase bool) {
+		if result.Rollups == nil {
+			return
+		}
+
+		key := ComparisonKey{
+			TestName:  result.Info.TestName,
+			Arguments: result.Info.Arguments,
+			Tags:      result.Info.Tags,
+		}
+		if opts.MatchTask {
+			key.Variant = result.Info.Variant
+			key.TaskName = result.Info.TaskName
+		}
+
+		group, ok := groups[key.String()]
+		if !ok {
+			group = &trials{
+				key:        key,
+				base:       map[string][]float64{},
+				comparison: map[string][]float64{},
+			}
+			groups[key.String()] = group
+		}
+
+		values := group.comparison
+		if isBase {
+			values = group.base
+			group.baseIDs = append(group.baseIDs, result.ID)
+		} else {
+			group.comparisonIDs = append(group.comparisonIDs, result.ID)
+		}
+		for name, val := range result.Rollups.MapFloat() {
+			if isFinite(val) {
+				values[name] = append(values[name], val)
+			}
+		}
+	}
+
+	for _, result := range base {
+		add(result, true)
+	}
+	for _, result := range comparison {
+		add(result, false)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key, group := range groups {
+		if len(group.baseIDs) > 0 && len(group.comparisonIDs) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	out := make([]ResultComparison, 0, len(keys))
+	for _, key := range keys {
+		group := groups[key]
+
+		names := []string{}
+		for name := range group.base {
+			if _, ok := group.comparison[name]; ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		rollups := make([]RollupComparison, 0, len(names))
+		for _, name := range names {
+			rollups = append(rollups, compareRollup(name, group.base[name], group.comparison[name]))
+		}
+
+		out = append(out, ResultComparison{
+			Key:           group.key,
+			BaseIDs:       group.baseIDs,
+			ComparisonIDs: group.comparisonIDs,
+			Rollups:       rollups,
+		})
+	}
+
+	return out
+}
+
+func compareRollup(name string, base, comparison []float64) RollupComparison {
+	c := RollupComparison{
+		Name:             name,
+		Base:             mean(base),
+		Comparison:       mean(comparison),
+		BaseTrials:       len(base),
+		ComparisonTrials: len(comparison),
+	}
+
+	c.AbsoluteDelta = c.Comparison - c.Base
+	if c.Base == 0 || !isFinite(c.AbsoluteDelta) {
+		c.PercentDelta = math.NaN()
+	} else {
+		c.PercentDelta = 100 * c.AbsoluteDelta / math.Abs(c.Base)
+	}
+	c.UStatistic, c.PValue = MannWhitneyU(base, comparison)
+
+	return c
+}
+
+// isFinite returns false for NaN and infinite values.
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
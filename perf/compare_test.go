@@ -0,0 +1,103 @@
+package perf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func comparisonResult(id, variant, test string, trial int, rollups map[string]interface{}) model.PerformanceResult {
+	result := model.PerformanceResult{
+		ID: id,
+		Info: model.PerformanceResultInfo{
+			Variant:   variant,
+			TaskName:  "task",
+			TestName:  test,
+			Trial:     trial,
+			Arguments: map[string]int32{"threads": 4},
+			Tags:      []string{"b", "a"},
+		},
+		Rollups: &model.PerfRollups{},
+	}
+	for name, val := range rollups {
+		result.Rollups.Stats = append(result.Rollups.Stats, model.PerfRollupValue{Name: name, Value: val})
+	}
+	return result
+}
+
+func TestCompareResults(t *testing.T) {
+	base := []model.PerformanceResult{}
+	comparison := []model.PerformanceResult{}
+	for trial := 0; trial < 5; trial++ {
+		base = append(base, comparisonResult("base", "variant", "insert", trial, map[string]interface{}{
+			"latency": 100.0 + float64(trial),
+			"ops":     int64(1000),
+			"zero":    0.0,
+			"base":    1.0,
+		}))
+		comparison = append(comparison, comparisonResult("cmp", "variant", "insert", trial, map[string]interface{}{
+			"latency": 200.0 + float64(trial),
+			"ops":     int64(1000),
+			"zero":    1.0,
+		}))
+	}
+	base = append(base, comparisonResult("other", "variant", "update", 0, map[string]interface{}{"latency": 1.0}))
+	comparison = append(comparison, comparisonResult("other-variant", "other", "insert", 0, map[string]interface{}{"latency": 1.0}))
+
+	t.Run("MatchTask", func(t *testing.T) {
+		comparisons := CompareResults(base, comparison, CompareOptions{MatchTask: true})
+		require.Len(t, comparisons, 1)
+		assert.Equal(t, "insert", comparisons[0].Key.TestName)
+		assert.Len(t, comparisons[0].BaseIDs, 5)
+		assert.Len(t, comparisons[0].ComparisonIDs, 5)
+
+		rollups := comparisons[0].Rollups
+		require.Len(t, rollups, 3)
+		assert.Equal(t, "latency", rollups[0].Name)
+		assert.Equal(t, 102.0, rollups[0].Base)
+		assert.Equal(t, 202.0, rollups[0].Comparison)
+		assert.Equal(t, 5, rollups[0].BaseTrials)
+		assert.Equal(t, 100.0, rollups[0].AbsoluteDelta)
+		assert.InDelta(t, 98.04, rollups[0].PercentDelta, 0.01)
+		assert.True(t, rollups[0].PValue < 0.05)
+
+		assert.Equal(t, "ops", rollups[1].Name)
+		assert.Zero(t, rollups[1].AbsoluteDelta)
+		assert.Equal(t, 1.0, rollups[1].PValue)
+
+		assert.Equal(t, "zero", rollups[2].Name)
+		assert.True(t, math.IsNaN(rollups[2].PercentDelta))
+	})
+	t.Run("IgnoreTask", func(t *testing.T) {
+		comparisons := CompareResults(base, comparison, CompareOptions{})
+		require.Len(t, comparisons, 1)
+		assert.Len(t, comparisons[0].ComparisonIDs, 6)
+		assert.Empty(t, comparisons[0].Key.Variant)
+	})
+	t.Run("NonFinite", func(t *testing.T) {
+		base := []model.PerformanceResult{
+			comparisonResult("base0", "variant", "insert", 0, map[string]interface{}{"latency": 100.0, "inf": 1.0}),
+			comparisonResult("base1", "variant", "insert", 1, map[string]interface{}{"latency": math.Inf(1), "inf": 1.0}),
+		}
+		comparison := []model.PerformanceResult{
+			comparisonResult("cmp0", "variant", "insert", 0, map[string]interface{}{"latency": 200.0, "inf": math.Inf(1)}),
+			comparisonResult("cmp1", "variant", "insert", 1, map[string]interface{}{"latency": math.NaN(), "inf": math.Inf(-1)}),
+		}
+
+		comparisons := CompareResults(base, comparison, CompareOptions{})
+		require.Len(t, comparisons, 1)
+		rollups := comparisons[0].Rollups
+		require.Len(t, rollups, 1)
+		assert.Equal(t, "latency", rollups[0].Name)
+		assert.Equal(t, 1, rollups[0].BaseTrials)
+		assert.Equal(t, 1, rollups[0].ComparisonTrials)
+		assert.Equal(t, 100.0, rollups[0].AbsoluteDelta)
+		assert.Equal(t, 100.0, rollups[0].PercentDelta)
+	})
+	t.Run("NoOverlap", func(t *testing.T) {
+		assert.Empty(t, CompareResults(base[:1], nil, CompareOptions{}))
+	})
+}
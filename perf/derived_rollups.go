@@ -0,0 +1,236 @@
+package perf
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DerivedRollupCalculator names the calculation of the rollups
+	// that are defined by expressions in the configuration. The
+	// calculator is not registered, since derived rollups are not
+	// calculated from artifacts, and they are recalculated with the
+	// rollups that they are derived from instead.
+	DerivedRollupCalculator = "derived"
+
+	// DerivedRollupVersion is the version of the expression
+	// evaluation.
+	DerivedRollupVersion = 1
+)
+
+// RollupExpression is a parsed arithmetic expression over named
+// rollups. Expressions support numbers, rollup names, parentheses,
+// and the +, -, * and / operators. Names may contain dots, for the
+// rollups of FTDC metrics such as "counters.hits.max".
+type RollupExpression struct {
+	source string
+	expr   ast.Expr
+	names  []string
+}
+
+// ParseRollupExpression parses and validates the expression.
+func ParseRollupExpression(source string) (*RollupExpression, error) {
+	expr, err := parser.ParseExpr(source)
+	if err != nil {
+		return nil, errors.Wrapf(err, "problem parsing expression '%s'", source)
+	}
+
+	e := &RollupExpression{source: source, expr: expr}
+	if err = e.validate(expr); err != nil {
+		return nil, errors.Wrapf(err, "invalid expression '%s'", source)
+	}
+
+	return e, nil
+}
+
+// String returns the source of the expression.
+func (e *RollupExpression) String() string { return e.source }
+
+// Names returns the names that the expression refers to, in the order
+// that they first appear.
+func (e *RollupExpression) Names() []string { return e.names }
+
+func (e *RollupExpression) validate(expr ast.Expr) error {
+	switch n := expr.(type) {
+	case *ast.BasicLit:
+		if n.Kind != token.INT && n.Kind != token.FLOAT {
+			return errors.Errorf("unsupported literal %s", n.Value)
+		}
+		return nil
+	case *ast.Ident, *ast.SelectorExpr:
+		name, err := rollupExpressionName(n)
+		if err != nil {
+			return err
+		}
+		for _, existing := range e.names {
+			if existing == name {
+				return nil
+			}
+		}
+		e.names = append(e.names, name)
+		return nil
+	case *ast.ParenExpr:
+		return e.validate(n.X)
+	case *ast.UnaryExpr:
+		if n.Op != token.ADD && n.Op != token.SUB {
+			return errors.Errorf("unsupported operator %s", n.Op)
+		}
+		return e.validate(n.X)
+	case *ast.BinaryExpr:
+		switch n.Op {
+		case token.ADD, token.SUB, token.MUL, token.QUO:
+		default:
+			return errors.Errorf("unsupported operator %s", n.Op)
+		}
+		catcher := grip.NewBasicCatcher()
+		catcher.Add(e.validate(n.X))
+		catcher.Add(e.validate(n.Y))
+		return catcher.Resolve()
+	default:
+		return errors.Errorf("unsupported expression of type %T", expr)
+	}
+}
+
+// rollupExpressionName returns the dotted name of an identifier or of
+// a chain of selectors.
+func rollupExpressionName(expr ast.Expr) (string, error) {
+	switch n := expr.(type) {
+	case *ast.Ident:
+		return n.Name, nil
+	case *ast.SelectorExpr:
+		prefix, err := rollupExpressionName(n.X)
+		if err != nil {
+			return "", err
+		}
+		return prefix + "." + n.Sel.Name, nil
+	default:
+		return "", errors.Errorf("unsupported name of type %T", expr)
+	}
+}
+
+// Evaluate calculates the value of the expression from the values of
+// the rollups, by name. A name without a value falls back to the mean
+// of the FTDC metric with that name. It is an error for a name to
+// have no value, or for the result not to be a finite number.
+func (e *RollupExpression) Evaluate(values map[string]float64) (float64, error) {
+	val, err := evaluateRollupExpression(e.expr, values)
+	if err != nil {
+		return 0, errors.Wrapf(err, "problem evaluating '%s'", e.source)
+	}
+	if math.IsNaN(val) || math.IsInf(val, 0) {
+		return 0, errors.Errorf("expression '%s' is not a finite number", e.source)
+	}
+
+	return val, nil
+}
+
+func evaluateRollupExpression(expr ast.Expr, values map[string]float64) (float64, error) {
+	switch n := expr.(type) {
+	case *ast.BasicLit:
+		return strconv.ParseFloat(n.Value, 64)
+	case *ast.Ident, *ast.SelectorExpr:
+		name, err := rollupExpressionName(n)
+		if err != nil {
+			return 0, err
+		}
+		if val, ok := values[name]; ok {
+			return val, nil
+		}
+		if val, ok := values[name+".mean"]; ok {
+			return val, nil
+		}
+		return 0, errors.Errorf("no value for '%s'", name)
+	case *ast.ParenExpr:
+		return evaluateRollupExpression(n.X, values)
+	case *ast.UnaryExpr:
+		val, err := evaluateRollupExpression(n.X, values)
+		if n.Op == token.SUB {
+			val = -val
+		}
+		return val, err
+	case *ast.BinaryExpr:
+		x, err := evaluateRollupExpression(n.X, values)
+		if err != nil {
+			return 0, err
+		}
+		y, err := evaluateRollupExpression(n.Y, values)
+		if err != nil {
+			return 0, err
+		}
+
+		switch n.Op {
+		case token.ADD:
+			return x + y, nil
+		case token.SUB:
+			return x - y, nil
+		case token.MUL:
+			return x * y, nil
+		case token.QUO:
+			if y == 0 {
+				return 0, errors.New("division by zero")
+			}
+			return x / y, nil
+		}
+	}
+
+	return 0, errors.Errorf("unsupported expression of type %T", expr)
+}
+
+// CalculateDerivedRollups evaluates the derived rollups, in order,
+// over the values of the other rollups of a result, by name. Each
+// derived rollup may refer to the rollups derived before it. Derived
+// rollups whose expressions cannot be evaluated for the result, for
+// example because a rollup that they refer to is missing, are
+// skipped, and the errors are returned with the rollups that could be
+// derived.
+func CalculateDerivedRollups(values map[string]float64, derived []model.PerfDerivedRollup) ([]model.PerfRollupValue, error) {
+	known := make(map[string]float64, len(values)+len(derived))
+	for name, val := range values {
+		known[name] = val
+	}
+
+	catcher := grip.NewBasicCatcher()
+	rollups := []model.PerfRollupValue{}
+	for _, d := range derived {
+		if strings.TrimSpace(d.Name) == "" {
+			catcher.Add(errors.Errorf("derived rollup '%s' must have a name", d.Expression))
+			continue
+		}
+
+		expr, err := ParseRollupExpression(d.Expression)
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "invalid derived rollup '%s'", d.Name))
+			continue
+		}
+
+		val, err := expr.Evaluate(known)
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem deriving rollup '%s'", d.Name))
+			continue
+		}
+		known[d.Name] = val
+
+		metricType := d.MetricType
+		if metricType == "" {
+			metricType = model.MetricTypeMean
+		}
+		rollups = append(rollups, model.PerfRollupValue{
+			Name:          d.Name,
+			Value:         val,
+			Version:       DerivedRollupVersion,
+			MetricType:    metricType,
+			UserSubmitted: false,
+			Calculator:    DerivedRollupCalculator,
+		})
+	}
+
+	return rollups, catcher.Resolve()
+}
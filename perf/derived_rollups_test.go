@@ -0,0 +1,93 @@
+package perf
+
+import (
+	"testing"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollupExpression(t *testing.T) {
+	values := map[string]float64{
+		"totalOperations":    1000,
+		"avgWorkers":         4,
+		"counters.hits.mean": 25,
+		"counters.hits.max":  40,
+		"zero":               0,
+	}
+
+	for _, test := range []struct {
+		name     string
+		source   string
+		names    []string
+		expected float64
+		invalid  bool
+		fails    bool
+	}{
+		{name: "Division", source: "totalOperations / avgWorkers", names: []string{"totalOperations", "avgWorkers"}, expected: 250},
+		{name: "Precedence", source: "1 + 2 * avgWorkers", names: []string{"avgWorkers"}, expected: 9},
+		{name: "Parentheses", source: "(1 + 2) * -avgWorkers", names: []string{"avgWorkers"}, expected: -12},
+		{name: "Float", source: "avgWorkers * 0.5e1", names: []string{"avgWorkers"}, expected: 20},
+		{name: "MetricRollup", source: "counters.hits.max - counters.hits", names: []string{"counters.hits.max", "counters.hits"}, expected: 15},
+		{name: "RepeatedName", source: "avgWorkers * avgWorkers", names: []string{"avgWorkers"}, expected: 16},
+		{name: "MissingName", source: "totalOperations / workers", fails: true},
+		{name: "DivisionByZero", source: "totalOperations / zero", fails: true},
+		{name: "Syntax", source: "totalOperations /", invalid: true},
+		{name: "Call", source: "max(avgWorkers, 1)", invalid: true},
+		{name: "Index", source: "avgWorkers[0]", invalid: true},
+		{name: "String", source: `"avgWorkers"`, invalid: true},
+		{name: "Operator", source: "totalOperations % avgWorkers", invalid: true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := ParseRollupExpression(test.source)
+			if test.invalid {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.source, expr.String())
+
+			val, err := expr.Evaluate(values)
+			if test.fails {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.names, expr.Names())
+			assert.Equal(t, test.expected, val)
+		})
+	}
+}
+
+func TestCalculateDerivedRollups(t *testing.T) {
+	values := map[string]float64{
+		"totalOperations": 1000,
+		"totalErrors":     10,
+		"avgWorkers":      4,
+	}
+
+	rollups, err := CalculateDerivedRollups(values, []model.PerfDerivedRollup{
+		{Name: "opsPerWorker", Expression: "totalOperations / avgWorkers"},
+		{Name: "missing", Expression: "totalOperations / workers"},
+		{Name: "errorsPerWorker", Expression: "opsPerWorker * totalErrors / totalOperations", MetricType: model.MetricTypeMax},
+		{Name: "invalid", Expression: "totalOperations +"},
+	})
+	assert.Error(t, err)
+	require.Len(t, rollups, 2)
+
+	assert.Equal(t, "opsPerWorker", rollups[0].Name)
+	assert.Equal(t, 250.0, rollups[0].Value)
+	assert.Equal(t, model.MetricTypeMean, rollups[0].MetricType)
+	assert.False(t, rollups[0].UserSubmitted)
+	assert.Equal(t, DerivedRollupCalculator, rollups[0].Calculator)
+	assert.Equal(t, DerivedRollupVersion, rollups[0].Version)
+
+	assert.Equal(t, "errorsPerWorker", rollups[1].Name)
+	assert.Equal(t, 2.5, rollups[1].Value)
+	assert.Equal(t, model.MetricType(model.MetricTypeMax), rollups[1].MetricType)
+
+	rollups, err = CalculateDerivedRollups(values, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, rollups)
+}
@@ -0,0 +1,127 @@
+package perf
+
+import (
+	"math"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/ftdc/hdrhist"
+)
+
+const (
+	distributionSigFigs  = 3
+	distributionMaxValue = int64(time.Hour)
+
+	// throughputs are recorded in thousandths of an op per second.
+	throughputScale = 1000
+)
+
+// distribution accumulates a stream of samples in constant memory.
+// The minimum, maximum, mean and standard deviation are exact, while
+// the median and percentiles are estimated from a histogram with
+// three significant figures of precision.
+//
+// Samples are multiplied by scale before they are recorded in the
+// histogram so that fractional values (e.g. throughputs of less than
+// one op per second) retain their precision.
+type distribution struct {
+	hist  *hdrhist.Histogram
+	scale float64
+
+	count int64
+	mean  float64
+	m2    float64
+	min   float64
+	max   float64
+}
+
+func newDistribution(scale float64) *distribution {
+	return &distribution{
+		hist:  hdrhist.New(1, distributionMaxValue, distributionSigFigs),
+		scale: scale,
+		min:   math.Inf(1),
+		max:   math.Inf(-1),
+	}
+}
+
+func (d *distribution) add(value float64) {
+	d.count++
+	delta := value - d.mean
+	d.mean += delta / float64(d.count)
+	d.m2 += delta * (value - d.mean)
+
+	d.min = math.Min(d.min, value)
+	d.max = math.Max(d.max, value)
+
+	scaled := value * d.scale
+	if scaled > float64(distributionMaxValue) {
+		scaled = float64(distributionMaxValue)
+	} else if scaled < 0 {
+		scaled = 0
+	}
+	// the value is clamped to the histogram's range, so this
+	// cannot fail.
+	_ = d.hist.RecordValue(int64(scaled))
+}
+
+func (d *distribution) stdDev() float64 {
+	if d.count < 2 {
+		return 0
+	}
+
+	return math.Sqrt(d.m2 / float64(d.count-1))
+}
+
+func (d *distribution) quantile(q float64) float64 {
+	return float64(d.hist.ValueAtQuantile(q)) / d.scale
+}
+
+func (d *distribution) minimum() float64 { return d.min }
+func (d *distribution) maximum() float64 { return d.max }
+
+// rollups returns the summary statistics of the distribution, with
+// names derived from prefix, or no rollups if there are no samples.
+func (d *distribution) rollups(prefix string) []model.PerfRollupValue {
+	if d.count == 0 {
+		return []model.PerfRollupValue{}
+	}
+
+	return summaryRollups(prefix, d)
+}
+
+// quantileSummary describes a distribution of values that can be
+// summarized by summaryRollups.
+type quantileSummary interface {
+	minimum() float64
+	maximum() float64
+	stdDev() float64
+	quantile(float64) float64
+}
+
+func summaryRollups(prefix string, s quantileSummary) []model.PerfRollupValue {
+	rollups := []model.PerfRollupValue{}
+
+	for _, r := range []struct {
+		suffix string
+		value  float64
+		t      model.MetricType
+	}{
+		{suffix: "Min", value: s.minimum(), t: model.MetricTypeMin},
+		{suffix: "Max", value: s.maximum(), t: model.MetricTypeMax},
+		{suffix: "Median", value: s.quantile(50), t: model.MetricTypeMedian},
+		{suffix: "StdDev", value: s.stdDev(), t: model.MetricTypeStdDev},
+		{suffix: "Percentile80", value: s.quantile(80), t: model.MetricTypePercentile80},
+		{suffix: "Percentile90", value: s.quantile(90), t: model.MetricTypePercentile90},
+		{suffix: "Percentile95", value: s.quantile(95), t: model.MetricTypePercentile95},
+		{suffix: "Percentile99", value: s.quantile(99), t: model.MetricTypePercentile99},
+	} {
+		rollups = append(rollups, model.PerfRollupValue{
+			Name:          prefix + r.suffix,
+			Value:         r.value,
+			MetricType:    r.t,
+			UserSubmitted: false,
+		})
+	}
+
+	return rollups
+}
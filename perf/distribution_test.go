@@ -0,0 +1,85 @@
+package perf
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistribution(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		d := newDistribution(1)
+		assert.Equal(t, []model.PerfRollupValue{}, d.rollups("latency"))
+	})
+	t.Run("UniformValues", func(t *testing.T) {
+		d := newDistribution(1)
+		for i := 1; i <= 1000; i++ {
+			d.add(float64(i))
+		}
+
+		rollups := map[string]model.PerfRollupValue{}
+		for _, r := range d.rollups("latency") {
+			rollups[r.Name] = r
+		}
+		require.Len(t, rollups, 8)
+
+		assert.Equal(t, float64(1), rollups["latencyMin"].Value)
+		assert.Equal(t, float64(1000), rollups["latencyMax"].Value)
+		assert.InDelta(t, 288.8, rollups["latencyStdDev"].Value, 0.1)
+		for name, expected := range map[string]float64{
+			"latencyMedian":       500,
+			"latencyPercentile80": 800,
+			"latencyPercentile90": 900,
+			"latencyPercentile95": 950,
+			"latencyPercentile99": 990,
+		} {
+			assert.InEpsilon(t, expected, rollups[name].Value, 0.01, name)
+		}
+		assert.Equal(t, model.MetricType(model.MetricTypePercentile95), rollups["latencyPercentile95"].MetricType)
+	})
+	t.Run("FractionalValuesWithScale", func(t *testing.T) {
+		d := newDistribution(throughputScale)
+		for i := 0; i < 100; i++ {
+			d.add(0.5)
+		}
+		assert.InEpsilon(t, 0.5, d.quantile(50), 0.01)
+		assert.Zero(t, d.stdDev())
+	})
+	t.Run("OutOfRangeValues", func(t *testing.T) {
+		d := newDistribution(1)
+		assert.NotPanics(t, func() {
+			d.add(-1)
+			d.add(math.MaxFloat64)
+		})
+		assert.Equal(t, int64(2), d.count)
+	})
+}
+
+func TestPerformanceSamples(t *testing.T) {
+	s := &performanceStatistics{
+		latency:    newDistribution(1),
+		throughput: newDistribution(throughputScale),
+	}
+
+	// the counters are cumulative and the third sample starts a
+	// new series.
+	s.addSamples([]int64{10, 20, 5}, []int64{
+		int64(10 * time.Millisecond),
+		int64(30 * time.Millisecond),
+		int64(5 * time.Millisecond),
+	})
+
+	assert.Equal(t, int64(3), s.latency.count)
+	assert.Equal(t, float64(time.Millisecond), s.latency.min)
+	assert.Equal(t, float64(2*time.Millisecond), s.latency.max)
+	assert.Equal(t, int64(3), s.throughput.count)
+	assert.InDelta(t, 500, s.throughput.min, 0.001)
+	assert.InDelta(t, 1000, s.throughput.max, 0.001)
+
+	s.addSamples([]int64{1}, nil)
+	assert.Equal(t, int64(3), s.latency.count)
+}
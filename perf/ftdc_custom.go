@@ -0,0 +1,35 @@
+package perf
+
+import (
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/ftdc"
+	"github.com/pkg/errors"
+)
+
+// CalculateCustomRollups calculates the sum, mean, min, max and last
+// value of every metric in custom events data, such as the data
+// recorded with events.Custom points. The rollups are named after the
+// metrics, or after their names in the options, as the rollups of
+// additional metrics in raw events data are.
+func CalculateCustomRollups(dx *ftdc.ChunkIterator, opts RollupOptions) ([]model.PerfRollupValue, error) {
+	stats := performanceStatistics{
+		other: map[string]*metricStatistics{},
+	}
+
+	defer dx.Close()
+	for dx.Next() {
+		chunk := dx.Chunk()
+		doubles := doubleMetrics(chunk)
+		for _, metric := range chunk.Metrics {
+			if metric.Key() == "ts" {
+				continue
+			}
+			stats.addOther(metric.Key(), metric.Values, doubles[metric.Key()])
+		}
+	}
+	if err := dx.Err(); err != nil {
+		return []model.PerfRollupValue{}, errors.Wrap(err, "problem calculating custom rollups")
+	}
+
+	return withCalculator(stats.genericRollups(opts.MetricNames), CustomRollupCalculator), nil
+}
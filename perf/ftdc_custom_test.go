@@ -0,0 +1,62 @@
+package perf
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/ftdc/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculateCustomRollups(t *testing.T) {
+	collector := ftdc.NewDynamicCollector(5)
+	for i := int64(1); i <= 10; i++ {
+		point := events.MakeCustom(2)
+		require.NoError(t, point.Add("engine.cache_hits", i))
+		require.NoError(t, point.Add("engine.evictions", 2*i))
+		require.NoError(t, point.Add("engine.ratio", 0.5))
+		require.NoError(t, collector.Add(point))
+	}
+	data, err := collector.Resolve()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	actual, err := CalculateCustomRollups(ftdc.ReadChunks(ctx, bytes.NewReader(data)), RollupOptions{
+		MetricNames: map[string]string{"engine.evictions": "evictions"},
+	})
+	require.NoError(t, err)
+	assert.Len(t, actual, 15)
+
+	rollups := map[string]model.PerfRollupValue{}
+	for _, r := range actual {
+		rollups[r.Name] = r
+	}
+
+	for name, expected := range map[string]interface{}{
+		"engine.cache_hits.sum":  int64(55),
+		"engine.cache_hits.mean": 5.5,
+		"engine.cache_hits.max":  int64(10),
+		"evictions.min":          int64(2),
+		"evictions.last":         int64(20),
+		"engine.ratio.sum":       5.0,
+		"engine.ratio.mean":      0.5,
+		"engine.ratio.min":       0.5,
+		"engine.ratio.last":      0.5,
+	} {
+		require.Contains(t, rollups, name)
+		assert.Equal(t, expected, rollups[name].Value, name)
+	}
+	assert.NotContains(t, rollups, "engine.evictions.sum")
+	assert.Equal(t, CustomRollupCalculator, rollups["evictions.last"].Calculator)
+	assert.False(t, rollups["evictions.last"].UserSubmitted)
+
+	actual, err = CalculateCustomRollups(ftdc.ReadChunks(ctx, bytes.NewReader(nil)), RollupOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, actual)
+}
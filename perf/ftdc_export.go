@@ -0,0 +1,247 @@
+package perf
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/ftdc/bsonx"
+	"github.com/pkg/errors"
+)
+
+const (
+	timeseriesTimestampKey     = "ts"
+	timeseriesPointsPerChunk   = 1000
+	timeseriesCSVFlushInterval = 1000
+)
+
+// TimeseriesExportOptions configure ExportTimeseries. Only samples with
+// a timestamp between Start and End (inclusive) are exported, and a
+// zero Start or End leaves that side of the range open. Samples
+// without a timestamp are always exported.
+type TimeseriesExportOptions struct {
+	Format model.FileDataFormat
+	Start  time.Time
+	End    time.Time
+}
+
+// Validate checks that the format is supported and that the range is
+// not inverted.
+func (opts TimeseriesExportOptions) Validate() error {
+	switch opts.Format {
+	case model.FileFTDC, model.FileJSON, model.FileCSV:
+	default:
+		return errors.Errorf("cannot export timeseries as '%s'", opts.Format)
+	}
+
+	if !opts.Start.IsZero() && !opts.End.IsZero() && opts.End.Before(opts.Start) {
+		return errors.New("end of range must not be before the start")
+	}
+
+	return nil
+}
+
+func (opts TimeseriesExportOptions) hasRange() bool {
+	return !opts.Start.IsZero() || !opts.End.IsZero()
+}
+
+func (opts TimeseriesExportOptions) includes(doc *bsonx.Document) bool {
+	if !opts.hasRange() {
+		return true
+	}
+
+	ts, ok := doc.Lookup(timeseriesTimestampKey).TimeOK()
+	if !ok {
+		return true
+	}
+
+	if !opts.Start.IsZero() && ts.Before(opts.Start) {
+		return false
+	}
+	return opts.End.IsZero() || !ts.After(opts.End)
+}
+
+// ExportTimeseries decodes the FTDC data from the reader and writes the
+// samples to the writer in the requested format, as it reads them, so
+// that large files are never held in memory:
+//
+//   - FTDC data is copied as is, or re-encoded when filtering samples.
+//   - JSON is an array of the samples as (nested) objects.
+//   - CSV has a header of the flattened metric names followed by one
+//     row per sample, and the metric names must not change.
+func ExportTimeseries(ctx context.Context, r io.Reader, w io.Writer, opts TimeseriesExportOptions) error {
+	if err := opts.Validate(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	switch opts.Format {
+	case model.FileFTDC:
+		return errors.WithStack(exportTimeseriesFTDC(ctx, r, w, opts))
+	case model.FileJSON:
+		return errors.WithStack(exportTimeseriesJSON(ctx, r, w, opts))
+	default:
+		return errors.WithStack(exportTimeseriesCSV(ctx, r, w, opts))
+	}
+}
+
+func exportTimeseriesFTDC(ctx context.Context, r io.Reader, w io.Writer, opts TimeseriesExportOptions) error {
+	if !opts.hasRange() {
+		_, err := io.Copy(w, r)
+		return errors.Wrap(err, "problem copying ftdc data")
+	}
+
+	iter := ftdc.ReadStructuredMetrics(ctx, r)
+	defer iter.Close()
+
+	collector := ftdc.NewStreamingCollector(timeseriesPointsPerChunk, w)
+	for iter.Next() {
+		doc := iter.Document()
+		if !opts.includes(doc) {
+			continue
+		}
+
+		if err := collector.Add(doc); err != nil {
+			return errors.Wrap(err, "problem adding sample to ftdc")
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return errors.Wrap(err, "problem reading ftdc data")
+	}
+
+	return errors.Wrap(ftdc.FlushCollector(collector, w), "problem flushing ftdc data")
+}
+
+func exportTimeseriesJSON(ctx context.Context, r io.Reader, w io.Writer, opts TimeseriesExportOptions) error {
+	iter := ftdc.ReadStructuredMetrics(ctx, r)
+	defer iter.Close()
+
+	if _, err := io.WriteString(w, "["); err != nil {
+		return errors.WithStack(err)
+	}
+
+	count := 0
+	for iter.Next() {
+		doc := iter.Document()
+		if !opts.includes(doc) {
+			continue
+		}
+
+		out, err := json.Marshal(documentToMap(doc))
+		if err != nil {
+			return errors.Wrap(err, "problem marshaling sample")
+		}
+		if count > 0 {
+			if _, err = io.WriteString(w, ","); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+		if _, err = w.Write(out); err != nil {
+			return errors.WithStack(err)
+		}
+		count++
+	}
+	if err := iter.Err(); err != nil {
+		return errors.Wrap(err, "problem reading ftdc data")
+	}
+
+	_, err := io.WriteString(w, "]\n")
+	return errors.WithStack(err)
+}
+
+func exportTimeseriesCSV(ctx context.Context, r io.Reader, w io.Writer, opts TimeseriesExportOptions) error {
+	iter := ftdc.ReadMetrics(ctx, r)
+	defer iter.Close()
+
+	csvw := csv.NewWriter(w)
+	var fields []string
+	count := 0
+	for iter.Next() {
+		doc := iter.Document()
+		if !opts.includes(doc) {
+			continue
+		}
+
+		if fields == nil {
+			fields = make([]string, 0, doc.Len())
+			elems := doc.Iterator()
+			for elems.Next() {
+				fields = append(fields, elems.Element().Key())
+			}
+			if err := csvw.Write(fields); err != nil {
+				return errors.Wrap(err, "problem writing field names")
+			}
+		} else if doc.Len() != len(fields) {
+			return errors.New("unexpected schema change detected")
+		}
+
+		record := make([]string, 0, len(fields))
+		elems := doc.Iterator()
+		for elems.Next() {
+			record = append(record, formatCSVValue(elems.Element().Value()))
+		}
+		if err := csvw.Write(record); err != nil {
+			return errors.Wrapf(err, "problem writing csv record %d", count)
+		}
+
+		count++
+		if count%timeseriesCSVFlushInterval == 0 {
+			csvw.Flush()
+			if err := csvw.Error(); err != nil {
+				return errors.Wrap(err, "problem flushing csv data")
+			}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return errors.Wrap(err, "problem reading ftdc data")
+	}
+
+	csvw.Flush()
+	return errors.Wrap(csvw.Error(), "problem flushing csv data")
+}
+
+func formatCSVValue(v *bsonx.Value) string {
+	switch v.Type() {
+	case bsonx.TypeDateTime:
+		return v.Time().UTC().Format(time.RFC3339Nano)
+	case bsonx.TypeDouble:
+		return strconv.FormatFloat(v.Double(), 'g', -1, 64)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
+
+// documentToMap converts a document into a map that can be marshaled
+// as JSON, since bsonx documents do not support JSON directly.
+func documentToMap(doc *bsonx.Document) map[string]interface{} {
+	out := make(map[string]interface{}, doc.Len())
+	elems := doc.Iterator()
+	for elems.Next() {
+		out[elems.Element().Key()] = valueToInterface(elems.Element().Value())
+	}
+
+	return out
+}
+
+func valueToInterface(v *bsonx.Value) interface{} {
+	switch v.Type() {
+	case bsonx.TypeEmbeddedDocument:
+		return documentToMap(v.MutableDocument())
+	case bsonx.TypeArray:
+		out := []interface{}{}
+		values := v.MutableArray().Iterator()
+		for values.Next() {
+			out = append(out, valueToInterface(values.Value()))
+		}
+		return out
+	case bsonx.TypeDateTime:
+		return v.Time().UTC()
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,91 @@
+package perf
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/ftdc/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportTimeseries(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+	stream := make(chan events.Performance, 10)
+	for i := 0; i < 10; i++ {
+		stream <- events.Performance{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Counters:  events.PerformanceCounters{Number: int64(i), Operations: int64(2 * i)},
+		}
+	}
+	close(stream)
+	data := &bytes.Buffer{}
+	require.NoError(t, model.DumpPerformanceSeries(ctx, stream, nil, data))
+
+	t.Run("FTDC", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, ExportTimeseries(ctx, bytes.NewReader(data.Bytes()), out, TimeseriesExportOptions{Format: model.FileFTDC}))
+		assert.Equal(t, data.Bytes(), out.Bytes())
+	})
+	t.Run("FTDCRange", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, ExportTimeseries(ctx, bytes.NewReader(data.Bytes()), out, TimeseriesExportOptions{
+			Format: model.FileFTDC,
+			Start:  start.Add(2 * time.Second),
+			End:    start.Add(5 * time.Second),
+		}))
+
+		count := 0
+		iter := ftdc.ReadChunks(ctx, out)
+		for iter.Next() {
+			count += iter.Chunk().Size()
+		}
+		require.NoError(t, iter.Err())
+		assert.Equal(t, 4, count)
+	})
+	t.Run("JSON", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, ExportTimeseries(ctx, bytes.NewReader(data.Bytes()), out, TimeseriesExportOptions{
+			Format: model.FileJSON,
+			Start:  start.Add(8 * time.Second),
+		}))
+
+		samples := []events.Performance{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &samples))
+		require.Len(t, samples, 2)
+		assert.Equal(t, int64(8), samples[0].Counters.Number)
+		assert.Equal(t, int64(18), samples[1].Counters.Operations)
+		assert.True(t, samples[1].Timestamp.Equal(start.Add(9*time.Second)))
+	})
+	t.Run("CSV", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		require.NoError(t, ExportTimeseries(ctx, bytes.NewReader(data.Bytes()), out, TimeseriesExportOptions{
+			Format: model.FileCSV,
+			End:    start.Add(time.Second),
+		}))
+
+		records, err := csv.NewReader(out).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 3)
+		assert.Equal(t, "ts", records[0][0])
+		assert.Contains(t, records[0], "counters.ops")
+		assert.Equal(t, start.Add(time.Second).Format(time.RFC3339Nano), records[2][0])
+	})
+	t.Run("InvalidOptions", func(t *testing.T) {
+		for _, opts := range []TimeseriesExportOptions{
+			{Format: model.FileText},
+			{Format: model.FileJSON, Start: start, End: start.Add(-time.Second)},
+		} {
+			assert.Error(t, ExportTimeseries(ctx, bytes.NewReader(data.Bytes()), &bytes.Buffer{}, opts))
+		}
+	})
+}
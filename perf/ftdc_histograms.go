@@ -0,0 +1,353 @@
+package perf
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/ftdc/bsonx"
+	"github.com/mongodb/ftdc/events"
+	"github.com/mongodb/ftdc/hdrhist"
+	"github.com/pkg/errors"
+)
+
+// Histogram-schema FTDC files store each histogram as a sub-document
+// with the same layout as an hdrhist.Snapshot (i.e. "lowest",
+// "highest", "figures" and a "counts" array), so that a histogram
+// with the key "timers.duration" produces the metrics
+// "timers.duration.lowest", "timers.duration.counts.0" and so on.
+//
+// Each sample may either hold the values recorded since the previous
+// sample, or all values recorded so far, as the histogram recorders
+// in the ftdc/events package do until they are flushed. A sample is
+// treated as cumulative when none of its bucket counts are lower
+// than in the previous sample.
+//
+// The ftdc package drops the histograms of an events.PerformanceHDR
+// when flattening it, so the data written by the recorder from
+// events.NewHistogramRecorder only holds the timestamps and gauges.
+// Clients should record histograms with NewHistogramRecorder instead,
+// which wraps that recorder and writes this layout, or add the result
+// of MarshalHistogramPoint to a collector.
+
+const (
+	histogramLowestKey  = "lowest"
+	histogramHighestKey = "highest"
+	histogramFiguresKey = "figures"
+	histogramCountsKey  = "counts"
+
+	// histogramEncodingFigures is the largest number of significant
+	// figures of an encoded histogram. Every bucket of a histogram is
+	// a metric in each sample, and the histograms of the recorders in
+	// the ftdc/events package, with 5 significant figures, have over
+	// a million buckets, so they are encoded with a precision of 1%
+	// instead.
+	histogramEncodingFigures = 2
+)
+
+// histogramRollupNames maps the keys of the histograms in an
+// events.PerformanceHDR to the prefixes of their rollup names.
+var histogramRollupNames = map[string]string{
+	"timers.duration":     "latency",
+	"timers.total":        "totalTime",
+	"counters.number":     "iterations",
+	"counters.operations": "ops",
+	"counters.size":       "size",
+	"counters.errors":     "errors",
+}
+
+// MarshalHistogramPoint converts a histogram event into the document
+// layout of the histogram schema. The collectors in the ftdc package
+// cannot encode the histograms in an events.PerformanceHDR directly,
+// so clients should add the result of this function to the
+// collector instead.
+func MarshalHistogramPoint(point *events.PerformanceHDR) *bsonx.Document {
+	doc := bsonx.NewDocument(bsonx.EC.Time("ts", point.Timestamp))
+
+	// the histograms are top level documents keyed by their full
+	// name, because the ftdc package drops the names of intermediate
+	// documents when flattening more than one level of nesting.
+	for _, h := range []struct {
+		key  string
+		hist *hdrhist.Histogram
+	}{
+		{key: "counters.number", hist: point.Counters.Number},
+		{key: "counters.operations", hist: point.Counters.Operations},
+		{key: "counters.size", hist: point.Counters.Size},
+		{key: "counters.errors", hist: point.Counters.Errors},
+		{key: "timers.duration", hist: point.Timers.Duration},
+		{key: "timers.total", hist: point.Timers.Total},
+	} {
+		if h.hist != nil {
+			doc.Append(bsonx.EC.SubDocument(h.key, marshalHistogram(h.hist)))
+		}
+	}
+
+	// the gauges key matches the bson tag of events.PerformanceHDR.
+	return doc.Append(bsonx.EC.SubDocument("guages", bsonx.NewDocument(
+		bsonx.EC.Int64("state", point.Gauges.State),
+		bsonx.EC.Int64("workers", point.Gauges.Workers),
+		bsonx.EC.Boolean("failed", point.Gauges.Failed),
+	)))
+}
+
+func marshalHistogram(h *hdrhist.Histogram) *bsonx.Document {
+	if h.SignificantFigures() > histogramEncodingFigures {
+		coarse := hdrhist.New(h.LowestTrackableValue(), h.HighestTrackableValue(), histogramEncodingFigures)
+		coarse.Merge(h)
+		h = coarse
+	}
+	snapshot := h.Export()
+
+	counts := make([]*bsonx.Value, len(snapshot.Counts))
+	for idx := range snapshot.Counts {
+		counts[idx] = bsonx.VC.Int64(snapshot.Counts[idx])
+	}
+
+	return bsonx.NewDocument(
+		bsonx.EC.Int64(histogramLowestKey, snapshot.LowestTrackableValue),
+		bsonx.EC.Int64(histogramHighestKey, snapshot.HighestTrackableValue),
+		bsonx.EC.Int64(histogramFiguresKey, snapshot.SignificantFigures),
+		bsonx.EC.Array(histogramCountsKey, bsonx.NewArray(counts...)),
+	)
+}
+
+// NewHistogramRecorder returns the histogram recorder of the
+// ftdc/events package, which writes each point to the collector in
+// the layout of the histogram schema.
+func NewHistogramRecorder(collector ftdc.Collector) events.Recorder {
+	return events.NewHistogramRecorder(&histogramCollector{Collector: collector})
+}
+
+// histogramCollector converts the histogram events added to the
+// collector with MarshalHistogramPoint.
+type histogramCollector struct {
+	ftdc.Collector
+}
+
+func (c *histogramCollector) Add(in interface{}) error {
+	switch point := in.(type) {
+	case events.PerformanceHDR:
+		return c.Collector.Add(MarshalHistogramPoint(&point))
+	case *events.PerformanceHDR:
+		return c.Collector.Add(MarshalHistogramPoint(point))
+	default:
+		return c.Collector.Add(in)
+	}
+}
+
+// histogramSeries accumulates the samples of a single histogram.
+type histogramSeries struct {
+	lowest  int64
+	highest int64
+	figures int64
+
+	merged []int64
+	prev   []int64
+	cur    []int64
+}
+
+// histogramChunk holds the metrics of a single histogram in a chunk.
+type histogramChunk struct {
+	lowest  []int64
+	highest []int64
+	figures []int64
+	counts  map[int][]int64
+}
+
+// MergeHistograms reads histogram-schema FTDC data and returns the
+// histograms of all samples merged together, keyed by the key of the
+// histogram in the source documents (e.g. "timers.duration").
+func MergeHistograms(dx *ftdc.ChunkIterator) (map[string]*hdrhist.Histogram, error) {
+	defer dx.Close()
+
+	series := map[string]*histogramSeries{}
+	for dx.Next() {
+		chunk := dx.Chunk()
+
+		for key, hc := range groupHistogramMetrics(chunk) {
+			s, ok := series[key]
+			if !ok {
+				s = &histogramSeries{}
+				series[key] = s
+			}
+
+			if err := s.add(hc, chunk.Size()); err != nil {
+				return nil, errors.Wrapf(err, "problem reading histogram '%s'", key)
+			}
+		}
+	}
+	if err := dx.Err(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	out := make(map[string]*hdrhist.Histogram, len(series))
+	for key, s := range series {
+		h, err := s.histogram()
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem building histogram '%s'", key)
+		}
+		out[key] = h
+	}
+
+	return out, nil
+}
+
+// CalculateHistogramRollups calculates the distribution rollups
+// (min, max, median, standard deviation and percentiles) of every
+// histogram in histogram-schema FTDC data.
+func CalculateHistogramRollups(dx *ftdc.ChunkIterator) ([]model.PerfRollupValue, error) {
+	rollups := []model.PerfRollupValue{}
+
+	histograms, err := MergeHistograms(dx)
+	if err != nil {
+		return rollups, errors.Wrap(err, "problem calculating histogram rollups")
+	}
+
+	keys := make([]string, 0, len(histograms))
+	for key := range histograms {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		h := histograms[key]
+		if h.TotalCount() == 0 {
+			continue
+		}
+
+		name, ok := histogramRollupNames[key]
+		if !ok {
+			name = key
+		}
+
+		rollups = append(rollups, summaryRollups(name, histogramSummary{h})...)
+	}
+
+	return withCalculator(rollups, HistogramRollupCalculator), nil
+}
+
+func groupHistogramMetrics(chunk *ftdc.Chunk) map[string]*histogramChunk {
+	out := map[string]*histogramChunk{}
+	get := func(key string) *histogramChunk {
+		hc, ok := out[key]
+		if !ok {
+			hc = &histogramChunk{counts: map[int][]int64{}}
+			out[key] = hc
+		}
+		return hc
+	}
+
+	for _, metric := range chunk.Metrics {
+		name := metric.Key()
+
+		if idx := strings.LastIndex(name, "."+histogramCountsKey+"."); idx >= 0 {
+			bucket, err := strconv.Atoi(name[idx+len(histogramCountsKey)+2:])
+			if err != nil {
+				continue
+			}
+			get(name[:idx]).counts[bucket] = metric.Values
+			continue
+		}
+
+		idx := strings.LastIndex(name, ".")
+		if idx < 0 {
+			continue
+		}
+
+		switch name[idx+1:] {
+		case histogramLowestKey:
+			get(name[:idx]).lowest = metric.Values
+		case histogramHighestKey:
+			get(name[:idx]).highest = metric.Values
+		case histogramFiguresKey:
+			get(name[:idx]).figures = metric.Values
+		}
+	}
+
+	for key, hc := range out {
+		if len(hc.counts) == 0 || hc.lowest == nil || hc.highest == nil || hc.figures == nil {
+			delete(out, key)
+		}
+	}
+
+	return out
+}
+
+func (s *histogramSeries) add(hc *histogramChunk, size int) error {
+	numBuckets := len(hc.counts)
+	for bucket := range hc.counts {
+		if bucket >= numBuckets {
+			return errors.Errorf("histogram is missing buckets below %d", bucket)
+		}
+	}
+
+	for sample := 0; sample < size; sample++ {
+		if s.merged == nil {
+			s.lowest = hc.lowest[sample]
+			s.highest = hc.highest[sample]
+			s.figures = hc.figures[sample]
+			s.merged = make([]int64, numBuckets)
+		} else if s.lowest != hc.lowest[sample] || s.highest != hc.highest[sample] ||
+			s.figures != hc.figures[sample] || len(s.merged) != numBuckets {
+			return errors.New("histogram layout changed between samples")
+		}
+
+		if cap(s.cur) < numBuckets {
+			s.cur = make([]int64, numBuckets)
+		}
+		s.cur = s.cur[:numBuckets]
+		for bucket, values := range hc.counts {
+			s.cur[bucket] = values[sample]
+		}
+
+		cumulative := s.prev != nil
+		for bucket := range s.cur {
+			if cumulative && s.cur[bucket] < s.prev[bucket] {
+				cumulative = false
+				break
+			}
+		}
+
+		for bucket := range s.cur {
+			if cumulative {
+				s.merged[bucket] += s.cur[bucket] - s.prev[bucket]
+			} else {
+				s.merged[bucket] += s.cur[bucket]
+			}
+		}
+
+		s.prev, s.cur = s.cur, s.prev
+	}
+
+	return nil
+}
+
+func (s *histogramSeries) histogram() (*hdrhist.Histogram, error) {
+	if s.figures < 1 || s.figures > 5 {
+		return nil, errors.Errorf("invalid number of significant figures %d", s.figures)
+	}
+	if s.lowest < 0 || s.highest <= s.lowest {
+		return nil, errors.Errorf("invalid histogram range [%d, %d]", s.lowest, s.highest)
+	}
+
+	layout := hdrhist.New(s.lowest, s.highest, int(s.figures)).Export()
+	if len(layout.Counts) != len(s.merged) {
+		return nil, errors.Errorf("histogram has %d buckets, expected %d", len(s.merged), len(layout.Counts))
+	}
+	layout.Counts = s.merged
+
+	return hdrhist.Import(layout), nil
+}
+
+// histogramSummary adapts an hdrhist.Histogram to summaryRollups.
+type histogramSummary struct {
+	h *hdrhist.Histogram
+}
+
+func (s histogramSummary) minimum() float64           { return float64(s.h.Min()) }
+func (s histogramSummary) maximum() float64           { return float64(s.h.Max()) }
+func (s histogramSummary) stdDev() float64            { return s.h.StdDev() }
+func (s histogramSummary) quantile(q float64) float64 { return float64(s.h.ValueAtQuantile(q)) }
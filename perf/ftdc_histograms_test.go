@@ -0,0 +1,155 @@
+package perf
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/ftdc/events"
+	"github.com/mongodb/ftdc/hdrhist"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHistogram() *hdrhist.Histogram {
+	return hdrhist.New(1, int64(time.Second), 2)
+}
+
+// createHistogramFTDC records 100 durations of 1..100ms across 10
+// samples, either accumulating all values in one histogram or
+// starting a new histogram for every sample.
+func createHistogramFTDC(t *testing.T, cumulative bool) []byte {
+	collector := ftdc.NewDynamicCollector(5)
+
+	point := &events.PerformanceHDR{}
+	point.Timers.Duration = newTestHistogram()
+	for i := 0; i < 10; i++ {
+		if !cumulative {
+			point.Timers.Duration = newTestHistogram()
+		}
+		for j := 1; j <= 10; j++ {
+			require.NoError(t, point.Timers.Duration.RecordValue(int64(time.Duration(i*10+j)*time.Millisecond)))
+		}
+		point.Timestamp = time.Now()
+		require.NoError(t, collector.Add(MarshalHistogramPoint(point)))
+	}
+
+	data, err := collector.Resolve()
+	require.NoError(t, err)
+	return data
+}
+
+func TestMergeHistograms(t *testing.T) {
+	for name, cumulative := range map[string]bool{
+		"Cumulative": true,
+		"Interval":   false,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			data := createHistogramFTDC(t, cumulative)
+			histograms, err := MergeHistograms(ftdc.ReadChunks(ctx, bytes.NewReader(data)))
+			require.NoError(t, err)
+			require.Len(t, histograms, 1)
+			require.Contains(t, histograms, "timers.duration")
+
+			h := histograms["timers.duration"]
+			assert.Equal(t, int64(100), h.TotalCount())
+			assert.InEpsilon(t, float64(50*time.Millisecond), float64(h.ValueAtQuantile(50)), 0.02)
+			assert.InEpsilon(t, float64(95*time.Millisecond), float64(h.ValueAtQuantile(95)), 0.02)
+		})
+	}
+	t.Run("ChangedLayout", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		collector := ftdc.NewDynamicCollector(5)
+		point := &events.PerformanceHDR{}
+		point.Timers.Duration = newTestHistogram()
+		require.NoError(t, collector.Add(MarshalHistogramPoint(point)))
+		point.Timers.Duration = hdrhist.New(2, int64(time.Second), 2)
+		require.NoError(t, collector.Add(MarshalHistogramPoint(point)))
+		data, err := collector.Resolve()
+		require.NoError(t, err)
+
+		_, err = MergeHistograms(ftdc.ReadChunks(ctx, bytes.NewReader(data)))
+		assert.Error(t, err)
+	})
+}
+
+func TestCalculateHistogramRollups(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	data := createHistogramFTDC(t, true)
+	actual, err := CalculateHistogramRollups(ftdc.ReadChunks(ctx, bytes.NewReader(data)))
+	require.NoError(t, err)
+	require.Len(t, actual, 8)
+
+	rollups := map[string]model.PerfRollupValue{}
+	for _, r := range actual {
+		rollups[r.Name] = r
+	}
+	require.Contains(t, rollups, "latencyPercentile99")
+	assert.InEpsilon(t, float64(99*time.Millisecond), rollups["latencyPercentile99"].Value, 0.02)
+	assert.InEpsilon(t, float64(time.Millisecond), rollups["latencyMin"].Value, 0.02)
+	assert.Equal(t, model.MetricType(model.MetricTypeMedian), rollups["latencyMedian"].MetricType)
+	assert.Equal(t, HistogramRollupCalculator, rollups["latencyMedian"].Calculator)
+}
+
+func TestHistogramRecorder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	record := func(recorder events.Recorder) {
+		for i := 1; i <= 100; i++ {
+			recorder.Begin()
+			recorder.IncOps(3)
+			recorder.End(time.Duration(i) * time.Millisecond)
+		}
+		require.NoError(t, recorder.Flush())
+	}
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		collector := ftdc.NewDynamicCollector(10)
+		record(NewHistogramRecorder(collector))
+		data, err := collector.Resolve()
+		require.NoError(t, err)
+
+		histograms, err := MergeHistograms(ftdc.ReadChunks(ctx, bytes.NewReader(data)))
+		require.NoError(t, err)
+		require.Contains(t, histograms, "timers.duration")
+		require.Contains(t, histograms, "counters.operations")
+
+		duration := histograms["timers.duration"]
+		assert.Equal(t, int64(100), duration.TotalCount())
+		assert.Equal(t, int64(histogramEncodingFigures), duration.SignificantFigures())
+		assert.InEpsilon(t, float64(50*time.Millisecond), float64(duration.ValueAtQuantile(50)), 0.02)
+		assert.InEpsilon(t, float64(99*time.Millisecond), float64(duration.ValueAtQuantile(99)), 0.02)
+		assert.Equal(t, int64(100), histograms["counters.operations"].TotalCount())
+		assert.Equal(t, int64(3), histograms["counters.operations"].Max())
+
+		rollups, err := CalculateHistogramRollups(ftdc.ReadChunks(ctx, bytes.NewReader(data)))
+		require.NoError(t, err)
+		names := map[string]bool{}
+		for _, r := range rollups {
+			names[r.Name] = true
+		}
+		assert.True(t, names["latencyPercentile95"])
+		assert.True(t, names["opsMax"])
+	})
+	t.Run("UnwrappedRecorderDropsHistograms", func(t *testing.T) {
+		collector := ftdc.NewDynamicCollector(10)
+		record(events.NewHistogramRecorder(collector))
+		data, err := collector.Resolve()
+		require.NoError(t, err)
+
+		histograms, err := MergeHistograms(ftdc.ReadChunks(ctx, bytes.NewReader(data)))
+		require.NoError(t, err)
+		assert.Empty(t, histograms)
+	})
+}
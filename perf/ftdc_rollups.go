@@ -10,10 +10,6 @@ import (
 	"github.com/pkg/errors"
 )
 
-const (
-	defaultVer = 1
-)
-
 type performanceStatistics struct {
 	counters struct {
 		operations int64
@@ -33,9 +29,40 @@ type performanceStatistics struct {
 	}
 
 	numSamples int
+
+	// the counters and timers are cumulative, so the per-sample
+	// distributions are computed from the difference between
+	// consecutive samples.
+	latency    *distribution
+	throughput *distribution
+
+	// other tracks metrics without a default calculation, in the
+	// order that they first appear.
+	other      map[string]*metricStatistics
+	otherNames []string
+	last       struct {
+		operations int64
+		duration   int64
+	}
 }
 
+// RollupOptions configures the rollup calculation.
+type RollupOptions struct {
+	// MetricNames maps the FTDC keys of metrics that do not have a
+	// default calculation to the names used for their rollups.
+	MetricNames map[string]string
+}
+
+// CalculateDefaultRollups calculates the default rollups, using the
+// FTDC keys to name the rollups of any additional metrics.
 func CalculateDefaultRollups(dx *ftdc.ChunkIterator) ([]model.PerfRollupValue, error) {
+	return CalculateRollups(dx, RollupOptions{})
+}
+
+// CalculateRollups calculates the default rollups for the performance
+// metrics, and the sum, mean, min, max and last value of every other
+// metric in the data.
+func CalculateRollups(dx *ftdc.ChunkIterator, opts RollupOptions) ([]model.PerfRollupValue, error) {
 	rollups := []model.PerfRollupValue{}
 
 	perfStats, err := createPerformanceStats(dx)
@@ -47,26 +74,36 @@ func CalculateDefaultRollups(dx *ftdc.ChunkIterator) ([]model.PerfRollupValue, e
 	rollups = append(rollups, perfStats.perfThroughputs()...)
 	rollups = append(rollups, perfStats.perfLatencies()...)
 	rollups = append(rollups, perfStats.perfTotals()...)
-	return rollups, nil
+	rollups = append(rollups, perfStats.perfDistributions()...)
+	rollups = append(rollups, perfStats.genericRollups(opts.MetricNames)...)
+	return withCalculator(rollups, FTDCRollupCalculator), nil
 }
 
 func createPerformanceStats(dx *ftdc.ChunkIterator) (performanceStatistics, error) {
-	perfStats := performanceStatistics{}
+	perfStats := performanceStatistics{
+		latency:    newDistribution(1),
+		throughput: newDistribution(throughputScale),
+		other:      map[string]*metricStatistics{},
+	}
 
 	defer dx.Close()
 	for i := 0; dx.Next(); i++ {
 		chunk := dx.Chunk()
 		perfStats.numSamples += chunk.Size()
 
+		var ops, dur []int64
+		var doubles map[string]bool
 		for _, metric := range chunk.Metrics {
 			switch name := metric.Key(); name {
 			case "counters.ops":
+				ops = metric.Values
 				perfStats.counters.operations = metric.Values[len(metric.Values)-1]
 			case "counters.size":
 				perfStats.counters.size = metric.Values[len(metric.Values)-1]
 			case "counters.errors":
 				perfStats.counters.errors = metric.Values[len(metric.Values)-1]
 			case "timers.dur":
+				dur = metric.Values
 				perfStats.timers.durationTotal += time.Duration(util.SumInt64(metric.Values))
 			case "timers.total":
 				perfStats.timers.total = time.Duration(metric.Values[len(metric.Values)-1])
@@ -79,13 +116,67 @@ func createPerformanceStats(dx *ftdc.ChunkIterator) (performanceStatistics, erro
 			case "ts", "counters.n":
 				continue
 			default:
-				return performanceStatistics{}, errors.Errorf("unknown field name %s", name)
+				if doubles == nil {
+					doubles = doubleMetrics(chunk)
+				}
+				perfStats.addOther(name, metric.Values, doubles[name])
 			}
 		}
+
+		perfStats.addSamples(ops, dur)
 	}
 	return perfStats, errors.WithStack(dx.Err())
 }
 
+// addOther records the values of a metric without a default
+// calculation, decoding the values of metrics recorded as doubles.
+func (s *performanceStatistics) addOther(name string, values []int64, double bool) {
+	stats, ok := s.other[name]
+	if !ok {
+		stats = &metricStatistics{}
+		s.other[name] = stats
+		s.otherNames = append(s.otherNames, name)
+	}
+
+	if double {
+		stats.addDoubles(values)
+		return
+	}
+	stats.add(values)
+}
+
+// addSamples records the latency and throughput of each sample in a
+// chunk, given the cumulative operation counts and durations.
+func (s *performanceStatistics) addSamples(ops, dur []int64) {
+	if len(ops) != len(dur) {
+		return
+	}
+
+	for i := range ops {
+		opsDelta := counterDelta(s.last.operations, ops[i])
+		durDelta := counterDelta(s.last.duration, dur[i])
+		s.last.operations = ops[i]
+		s.last.duration = dur[i]
+
+		if opsDelta > 0 {
+			s.latency.add(float64(durDelta) / float64(opsDelta))
+		}
+		if durDelta > 0 {
+			s.throughput.add(float64(opsDelta) / time.Duration(durDelta).Seconds())
+		}
+	}
+}
+
+// counterDelta returns the change in a cumulative counter, treating a
+// decrease as the start of a new series.
+func counterDelta(prev, cur int64) int64 {
+	if cur < prev {
+		return cur
+	}
+
+	return cur - prev
+}
+
 func (s *performanceStatistics) perfMeans() []model.PerfRollupValue {
 	rollups := []model.PerfRollupValue{}
 
@@ -95,21 +186,18 @@ func (s *performanceStatistics) perfMeans() []model.PerfRollupValue {
 			model.PerfRollupValue{
 				Name:          "avgDuration",
 				Value:         float64(s.timers.durationTotal) / float64(s.numSamples),
-				Version:       defaultVer,
 				MetricType:    model.MetricTypeMean,
 				UserSubmitted: false,
 			},
 			model.PerfRollupValue{
 				Name:          "avgState",
 				Value:         float64(s.gauges.stateTotal) / float64(s.numSamples),
-				Version:       defaultVer,
 				MetricType:    model.MetricTypeMean,
 				UserSubmitted: false,
 			},
 			model.PerfRollupValue{
 				Name:          "avgWorkers",
 				Value:         float64(s.gauges.workersTotal) / float64(s.numSamples),
-				Version:       defaultVer,
 				MetricType:    model.MetricTypeMean,
 				UserSubmitted: false,
 			},
@@ -127,21 +215,18 @@ func (s *performanceStatistics) perfThroughputs() []model.PerfRollupValue {
 			model.PerfRollupValue{
 				Name:          "throughputOps",
 				Value:         float64(s.counters.operations) / s.timers.durationTotal.Seconds(),
-				Version:       defaultVer,
 				MetricType:    model.MetricTypeThroughput,
 				UserSubmitted: false,
 			},
 			model.PerfRollupValue{
 				Name:          "throughputSize",
 				Value:         float64(s.counters.size) / s.timers.durationTotal.Seconds(),
-				Version:       defaultVer,
 				MetricType:    model.MetricTypeThroughput,
 				UserSubmitted: false,
 			},
 			model.PerfRollupValue{
 				Name:          "errorRate",
 				Value:         float64(s.counters.errors) / s.timers.durationTotal.Seconds(),
-				Version:       defaultVer,
 				MetricType:    model.MetricTypeThroughput,
 				UserSubmitted: false,
 			},
@@ -162,7 +247,6 @@ func (s *performanceStatistics) perfLatencies() []model.PerfRollupValue {
 	return append(rollups, model.PerfRollupValue{
 		Name:          "latency",
 		Value:         value,
-		Version:       defaultVer,
 		MetricType:    model.MetricTypeLatency,
 		UserSubmitted: false,
 	})
@@ -176,44 +260,66 @@ func (s *performanceStatistics) perfTotals() []model.PerfRollupValue {
 		model.PerfRollupValue{
 			Name:          "totalTime",
 			Value:         s.timers.durationTotal,
-			Version:       defaultVer,
 			MetricType:    model.MetricTypeSum,
 			UserSubmitted: false,
 		},
 		model.PerfRollupValue{
 			Name:          "totalFailures",
 			Value:         s.gauges.failedTotal,
-			Version:       defaultVer,
 			MetricType:    model.MetricTypeSum,
 			UserSubmitted: false,
 		},
 		model.PerfRollupValue{
 			Name:          "totalErrors",
 			Value:         s.counters.errors,
-			Version:       defaultVer,
 			MetricType:    model.MetricTypeSum,
 			UserSubmitted: false,
 		},
 		model.PerfRollupValue{
 			Name:          "totalOperations",
 			Value:         s.counters.operations,
-			Version:       defaultVer,
 			MetricType:    model.MetricTypeSum,
 			UserSubmitted: false,
 		},
 		model.PerfRollupValue{
 			Name:          "totalSize",
 			Value:         s.counters.size,
-			Version:       defaultVer,
 			MetricType:    model.MetricTypeSum,
 			UserSubmitted: false,
 		},
 		model.PerfRollupValue{
 			Name:          "totalSamples",
 			Value:         s.numSamples,
-			Version:       defaultVer,
 			MetricType:    model.MetricTypeSum,
 			UserSubmitted: false,
 		},
 	)
 }
+
+func (s *performanceStatistics) perfDistributions() []model.PerfRollupValue {
+	rollups := []model.PerfRollupValue{}
+
+	if s.latency != nil {
+		rollups = append(rollups, s.latency.rollups("latency")...)
+	}
+	if s.throughput != nil {
+		rollups = append(rollups, s.throughput.rollups("throughputOps")...)
+	}
+
+	return rollups
+}
+
+func (s *performanceStatistics) genericRollups(names map[string]string) []model.PerfRollupValue {
+	rollups := []model.PerfRollupValue{}
+
+	for _, key := range s.otherNames {
+		name, ok := names[key]
+		if !ok {
+			name = key
+		}
+
+		rollups = append(rollups, s.other[key].rollups(name)...)
+	}
+
+	return rollups
+}
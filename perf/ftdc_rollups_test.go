@@ -21,37 +21,84 @@ func TestCalculateDefaultRollups(t *testing.T) {
 	for _, test := range []struct {
 		name    string
 		samples int
-		err     bool
+		other   bool
 	}{
 		{
 			name:    "TestExpectedInput",
 			samples: 100,
 		},
 		{
-			name:    "TestInvalidInput",
+			name:    "TestArbitraryMetrics",
 			samples: 10,
-			err:     true,
+			other:   true,
 		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
-			data, err := createFTDC(!test.err, test.samples)
+			data, err := createFTDC(!test.other, test.samples)
 			require.NoError(t, err)
 			ctx, cancel := context.WithCancel(context.Background())
 			defer cancel()
 			ci := ftdc.ReadChunks(ctx, bytes.NewReader(data))
 
 			actual, err := CalculateDefaultRollups(ci)
-			if test.err {
-				assert.Equal(t, []model.PerfRollupValue{}, actual)
-				assert.Error(t, err)
+			require.NoError(t, err)
+			if test.other {
+				assert.Equal(t, 20, len(actual))
 			} else {
-				assert.Equal(t, 13, len(actual))
-				assert.NoError(t, err)
+				assert.Equal(t, 29, len(actual))
 			}
 		})
 	}
 }
 
+func TestCalculateRollupsForArbitraryMetrics(t *testing.T) {
+	collector := ftdc.NewDynamicCollector(5)
+	for i := int64(1); i <= 10; i++ {
+		require.NoError(t, collector.Add(bsonx.NewDocument(
+			bsonx.EC.Int64("hits", i),
+			bsonx.EC.Int64("bytes", 10*i),
+			bsonx.EC.Double("ratio", float64(i)/4),
+		)))
+	}
+	data, err := collector.Resolve()
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ci := ftdc.ReadChunks(ctx, bytes.NewReader(data))
+
+	actual, err := CalculateRollups(ci, RollupOptions{
+		MetricNames: map[string]string{"bytes": "bytesReplicated"},
+	})
+	require.NoError(t, err)
+
+	rollups := map[string]model.PerfRollupValue{}
+	for _, r := range actual {
+		rollups[r.Name] = r
+	}
+
+	for name, expected := range map[string]interface{}{
+		"hits.sum":             int64(55),
+		"hits.mean":            5.5,
+		"hits.min":             int64(1),
+		"hits.max":             int64(10),
+		"hits.last":            int64(10),
+		"bytesReplicated.sum":  int64(550),
+		"bytesReplicated.last": int64(100),
+		"ratio.sum":            13.75,
+		"ratio.mean":           1.375,
+		"ratio.min":            0.25,
+		"ratio.max":            2.5,
+		"ratio.last":           2.5,
+	} {
+		require.Contains(t, rollups, name)
+		assert.Equal(t, expected, rollups[name].Value, name)
+	}
+	assert.NotContains(t, rollups, "bytes.sum")
+	assert.Equal(t, model.MetricType(model.MetricTypeLast), rollups["hits.last"].MetricType)
+	assert.Equal(t, FTDCRollupCalculator, rollups["hits.last"].Calculator)
+}
+
 func TestCalcFunctions(t *testing.T) {
 	s := &performanceStatistics{
 		counters: struct {
@@ -0,0 +1,103 @@
+package perf
+
+import (
+	"time"
+
+	"github.com/mongodb/ftdc/events"
+)
+
+// SeriesReducer reduces a series of raw performance events, in
+// order, to a shorter series of points for the derived artifact
+// schemas.
+//
+// The counters and timers of raw events are cumulative, so the
+// reduced series keeps the values of the last event that each point
+// replaces, which means that the reduced series can be read in the
+// same way as the raw series.
+type SeriesReducer interface {
+	// Add adds the next event of the series, and returns a point of
+	// the reduced series when the event completes one.
+	Add(events.Performance) (events.Performance, bool)
+	// Flush returns the last point of the reduced series, if any
+	// events were added since the last point was returned.
+	Flush() (events.Performance, bool)
+}
+
+// NewIntervalSummary returns a reducer that buckets the events into
+// fixed windows of the given interval, aligned to the interval, and
+// produces one point per window that has events. Each point holds
+// the last event of its window, except that the point is marked as
+// failed if any event in the window failed.
+func NewIntervalSummary(interval time.Duration) SeriesReducer {
+	return &intervalSummary{interval: interval}
+}
+
+type intervalSummary struct {
+	interval time.Duration
+	window   time.Time
+	point    events.Performance
+	failed   bool
+	pending  bool
+}
+
+func (s *intervalSummary) Add(event events.Performance) (events.Performance, bool) {
+	window := event.Timestamp.Truncate(s.interval)
+
+	out, ok := events.Performance{}, false
+	if s.pending && !window.Equal(s.window) {
+		out, ok = s.Flush()
+	}
+
+	s.window = window
+	s.point = event
+	s.failed = s.failed || event.Gauges.Failed
+	s.pending = true
+
+	return out, ok
+}
+
+func (s *intervalSummary) Flush() (events.Performance, bool) {
+	if !s.pending {
+		return events.Performance{}, false
+	}
+
+	out := s.point
+	out.Gauges.Failed = s.failed
+	s.failed = false
+	s.pending = false
+
+	return out, true
+}
+
+// NewCollapsedEvents returns a reducer that merges consecutive
+// equivalent events, that is events with the same gauges, and
+// produces one point per run of equivalent events. Each point holds
+// the last event of its run, so the points mark the changes in the
+// state of the test.
+func NewCollapsedEvents() SeriesReducer { return &collapsedEvents{} }
+
+type collapsedEvents struct {
+	point   events.Performance
+	pending bool
+}
+
+func (s *collapsedEvents) Add(event events.Performance) (events.Performance, bool) {
+	out, ok := events.Performance{}, false
+	if s.pending && event.Gauges != s.point.Gauges {
+		out, ok = s.Flush()
+	}
+
+	s.point = event
+	s.pending = true
+
+	return out, ok
+}
+
+func (s *collapsedEvents) Flush() (events.Performance, bool) {
+	if !s.pending {
+		return events.Performance{}, false
+	}
+	s.pending = false
+
+	return s.point, true
+}
@@ -0,0 +1,67 @@
+package perf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mongodb/ftdc/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func reduceSeries(reducer SeriesReducer, series []events.Performance) []events.Performance {
+	out := []events.Performance{}
+	for _, event := range series {
+		if point, ok := reducer.Add(event); ok {
+			out = append(out, point)
+		}
+	}
+	if point, ok := reducer.Flush(); ok {
+		out = append(out, point)
+	}
+
+	return out
+}
+
+func TestSeriesReducers(t *testing.T) {
+	start := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	series := []events.Performance{}
+	for i := 0; i < 25; i++ {
+		event := events.Performance{Timestamp: start.Add(time.Duration(i) * 500 * time.Millisecond)}
+		event.Counters.Operations = int64(10 * (i + 1))
+		event.Timers.Duration = time.Duration(i+1) * time.Millisecond
+		event.Gauges.Workers = 2
+		if i >= 10 {
+			event.Gauges.Workers = 4
+		}
+		event.Gauges.Failed = i == 7
+		series = append(series, event)
+	}
+
+	t.Run("IntervalSummary", func(t *testing.T) {
+		points := reduceSeries(NewIntervalSummary(5*time.Second), series)
+		require.Len(t, points, 3)
+
+		// a failure anywhere in the window marks the window as failed.
+		expected := series[9]
+		expected.Gauges.Failed = true
+		assert.Equal(t, expected, points[0])
+		assert.Equal(t, series[19], points[1])
+		assert.Equal(t, series[24], points[2])
+
+		points = reduceSeries(NewIntervalSummary(time.Second), series)
+		assert.Len(t, points, 13)
+	})
+	t.Run("CollapsedEvents", func(t *testing.T) {
+		points := reduceSeries(NewCollapsedEvents(), series)
+		require.Len(t, points, 4)
+		assert.Equal(t, series[6], points[0])
+		assert.Equal(t, series[7], points[1])
+		assert.Equal(t, series[9], points[2])
+		assert.Equal(t, series[24], points[3])
+	})
+	t.Run("Empty", func(t *testing.T) {
+		assert.Empty(t, reduceSeries(NewIntervalSummary(time.Second), nil))
+		assert.Empty(t, reduceSeries(NewCollapsedEvents(), nil))
+	})
+}
@@ -0,0 +1,123 @@
+package perf
+
+import (
+	"math"
+	"sort"
+)
+
+// exactMannWhitneyLimit is the largest sample size for which the exact
+// distribution of the U statistic is used.
+const exactMannWhitneyLimit = 20
+
+// MannWhitneyU performs a two-sided Mann-Whitney U test of whether the
+// two samples come from the same distribution. It returns the U
+// statistic of the first sample and the p-value of the test. The
+// p-value is exact for small samples without ties, and otherwise uses
+// the normal approximation with tie and continuity corrections. Empty
+// samples have a p-value of one.
+func MannWhitneyU(a, b []float64) (float64, float64) {
+	n1, n2 := len(a), len(b)
+	if n1 == 0 || n2 == 0 {
+		return 0, 1
+	}
+
+	type sample struct {
+		value float64
+		first bool
+	}
+	samples := make([]sample, 0, n1+n2)
+	for _, v := range a {
+		samples = append(samples, sample{value: v, first: true})
+	}
+	for _, v := range b {
+		samples = append(samples, sample{value: v})
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i].value < samples[j].value })
+
+	// assign average ranks to ties and accumulate the tie correction.
+	rankSum := 0.0
+	tieCorrection := 0.0
+	for i := 0; i < len(samples); {
+		j := i + 1
+		for j < len(samples) && samples[j].value == samples[i].value {
+			j++
+		}
+
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if samples[k].first {
+				rankSum += rank
+			}
+		}
+		if ties := float64(j - i); ties > 1 {
+			tieCorrection += ties*ties*ties - ties
+		}
+		i = j
+	}
+
+	u := rankSum - float64(n1*(n1+1))/2
+	if tieCorrection == 0 && n1 <= exactMannWhitneyLimit && n2 <= exactMannWhitneyLimit {
+		return u, exactMannWhitneyPValue(u, n1, n2)
+	}
+
+	n := float64(n1 + n2)
+	mean := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+	if variance <= 0 {
+		return u, 1
+	}
+
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		z = 0
+	}
+
+	return u, math.Min(1, math.Erfc(z/math.Sqrt2))
+}
+
+// exactMannWhitneyPValue returns the two-sided p-value of u using the
+// exact distribution of the U statistic for samples of size n1 and n2
+// without ties.
+func exactMannWhitneyPValue(u float64, n1, n2 int) float64 {
+	maxU := n1 * n2
+
+	// counts[i][u] is the number of arrangements of i values from the
+	// first sample and j values from the second with statistic u,
+	// built up one value of j at a time.
+	counts := make([][]float64, n1+1)
+	for i := range counts {
+		counts[i] = make([]float64, maxU+1)
+		counts[i][0] = 1
+	}
+	for j := 1; j <= n2; j++ {
+		next := make([][]float64, n1+1)
+		next[0] = make([]float64, maxU+1)
+		next[0][0] = 1
+		for i := 1; i <= n1; i++ {
+			next[i] = make([]float64, maxU+1)
+			for v := 0; v <= maxU; v++ {
+				next[i][v] = counts[i][v]
+				if v >= j {
+					next[i][v] += next[i-1][v-j]
+				}
+			}
+		}
+		counts = next
+	}
+
+	dist := counts[n1]
+	total := 0.0
+	for _, c := range dist {
+		total += c
+	}
+
+	// the distribution is symmetric, so the two-sided p-value is
+	// twice the probability of the smaller tail.
+	tail := math.Min(u, float64(maxU)-u)
+	p := 0.0
+	for v := 0; v <= maxU && float64(v) <= tail; v++ {
+		p += dist[v]
+	}
+
+	return math.Min(1, 2*p/total)
+}
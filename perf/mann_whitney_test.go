@@ -0,0 +1,72 @@
+package perf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		a    []float64
+		b    []float64
+		u    float64
+		p    float64
+	}{
+		{
+			name: "Empty",
+			a:    []float64{1, 2, 3},
+			u:    0,
+			p:    1,
+		},
+		{
+			name: "SingleSamples",
+			a:    []float64{1},
+			b:    []float64{2},
+			u:    0,
+			p:    1,
+		},
+		{
+			name: "SeparatedSmall",
+			a:    []float64{1, 2, 3},
+			b:    []float64{4, 5, 6},
+			u:    0,
+			p:    0.1,
+		},
+		{
+			name: "SeparatedReversed",
+			a:    []float64{6, 7, 8, 9, 10},
+			b:    []float64{1, 2, 3, 4, 5},
+			u:    25,
+			p:    2.0 / 252,
+		},
+		{
+			name: "Interleaved",
+			a:    []float64{1, 3, 5, 7},
+			b:    []float64{2, 4, 6, 8},
+			u:    6,
+			p:    0.6857,
+		},
+		{
+			name: "Identical",
+			a:    []float64{5, 5, 5},
+			b:    []float64{5, 5, 5},
+			u:    4.5,
+			p:    1,
+		},
+		{
+			name: "TiesUseNormalApproximation",
+			a:    []float64{1, 2, 2, 3, 3, 4},
+			b:    []float64{3, 4, 4, 5, 5, 6},
+			u:    3,
+			p:    0.0181,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			u, p := MannWhitneyU(test.a, test.b)
+			assert.Equal(t, test.u, u)
+			assert.InDelta(t, test.p, p, 0.0005)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package perf
+
+import (
+	"context"
+	"math"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/ftdc/bsonx"
+)
+
+// metricStatistics accumulates the summary of a metric that does not
+// have a default rollup calculation.
+type metricStatistics struct {
+	count int64
+	sum   int64
+	min   int64
+	max   int64
+	last  int64
+
+	// double is set for metrics recorded as doubles, which FTDC
+	// stores as the bits of each value, and which are summarized in
+	// the float fields instead.
+	double bool
+	fsum   float64
+	fmin   float64
+	fmax   float64
+	flast  float64
+}
+
+func (s *metricStatistics) add(values []int64) {
+	for _, v := range values {
+		if s.count == 0 || v < s.min {
+			s.min = v
+		}
+		if s.count == 0 || v > s.max {
+			s.max = v
+		}
+
+		s.sum += v
+		s.last = v
+		s.count++
+	}
+}
+
+// addDoubles records the values of a chunk of a metric recorded as
+// doubles. FTDC stores the first value of the chunk as the bits of the
+// value, and every following value as the bits of its difference from
+// the previous value, which the FTDC reader does not undo.
+func (s *metricStatistics) addDoubles(values []int64) {
+	s.double = true
+
+	var v float64
+	for idx, bits := range values {
+		if idx == 0 {
+			v = math.Float64frombits(uint64(bits))
+		} else {
+			v += math.Float64frombits(uint64(bits))
+		}
+
+		if s.count == 0 || v < s.fmin {
+			s.fmin = v
+		}
+		if s.count == 0 || v > s.fmax {
+			s.fmax = v
+		}
+
+		s.fsum += v
+		s.flast = v
+		s.count++
+	}
+}
+
+func (s *metricStatistics) rollups(name string) []model.PerfRollupValue {
+	rollups := []model.PerfRollupValue{}
+	if s.count == 0 {
+		return rollups
+	}
+
+	type rollup struct {
+		suffix string
+		value  interface{}
+		t      model.MetricType
+	}
+	values := []rollup{
+		{suffix: "sum", value: s.sum, t: model.MetricTypeSum},
+		{suffix: "mean", value: float64(s.sum) / float64(s.count), t: model.MetricTypeMean},
+		{suffix: "min", value: s.min, t: model.MetricTypeMin},
+		{suffix: "max", value: s.max, t: model.MetricTypeMax},
+		{suffix: "last", value: s.last, t: model.MetricTypeLast},
+	}
+	if s.double {
+		values = []rollup{
+			{suffix: "sum", value: s.fsum, t: model.MetricTypeSum},
+			{suffix: "mean", value: s.fsum / float64(s.count), t: model.MetricTypeMean},
+			{suffix: "min", value: s.fmin, t: model.MetricTypeMin},
+			{suffix: "max", value: s.fmax, t: model.MetricTypeMax},
+			{suffix: "last", value: s.flast, t: model.MetricTypeLast},
+		}
+	}
+
+	for _, r := range values {
+		rollups = append(rollups, model.PerfRollupValue{
+			Name:          name + "." + r.suffix,
+			Value:         r.value,
+			MetricType:    r.t,
+			UserSubmitted: false,
+		})
+	}
+
+	return rollups
+}
+
+// doubleMetrics returns the keys of the metrics in the chunk that were
+// recorded as doubles. The metrics of a chunk share the types of the
+// first sample, so only the first sample is decoded.
+func doubleMetrics(chunk *ftdc.Chunk) map[string]bool {
+	doubles := map[string]bool{}
+
+	iter := chunk.Iterator(context.Background())
+	defer iter.Close()
+	if !iter.Next() {
+		return doubles
+	}
+
+	elems := iter.Document().Iterator()
+	for elems.Next() {
+		elem := elems.Element()
+		if elem.Value().Type() == bsonx.TypeDouble {
+			doubles[elem.Key()] = true
+		}
+	}
+
+	return doubles
+}
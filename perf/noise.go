@@ -0,0 +1,73 @@
+package perf
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultNoiseThreshold is the noise score above which a rollup is
+// too noisy for regressions to be detected reliably.
+const DefaultNoiseThreshold = 0.1
+
+// NoiseScore measures the noise of a rollup of a series, relative to
+// its mean.
+type NoiseScore struct {
+	// Versions and Trials are the number of versions and the total
+	// number of trials that were scored.
+	Versions int
+	Trials   int
+	// TrialCV is the mean coefficient of variation across the
+	// trials of each version with more than one trial.
+	TrialCV float64
+	// HistoryCV is the coefficient of variation of the means of the
+	// versions. The standard deviation is estimated from the median
+	// of the differences between successive versions, so that a few
+	// real shifts in the series are not counted as noise.
+	HistoryCV float64
+	// Score is the larger of TrialCV and HistoryCV.
+	Score float64
+}
+
+// ScoreNoise scores the noise of a rollup from its values in the
+// trials of each version, oldest version first. Versions without
+// values are ignored.
+func ScoreNoise(versions [][]float64) NoiseScore {
+	s := NoiseScore{}
+
+	means := []float64{}
+	trialCVs := []float64{}
+	for _, trials := range versions {
+		if len(trials) == 0 {
+			continue
+		}
+
+		s.Versions++
+		s.Trials += len(trials)
+		means = append(means, mean(trials))
+		if len(trials) > 1 {
+			trialCVs = append(trialCVs, summarizeTrials(trials).CoefficientOfVariation)
+		}
+	}
+
+	s.TrialCV = mean(trialCVs)
+
+	if len(means) > 1 {
+		diffs := make([]float64, 0, len(means)-1)
+		for i := 1; i < len(means); i++ {
+			diffs = append(diffs, math.Abs(means[i]-means[i-1]))
+		}
+		sort.Float64s(diffs)
+
+		// for normally distributed values, the median absolute
+		// difference of two values is 0.6745*sqrt(2) standard
+		// deviations.
+		stdDev := summarizeTrials(diffs).Median / (0.6745 * math.Sqrt2)
+		if center := math.Abs(mean(means)); center != 0 {
+			s.HistoryCV = stdDev / center
+		}
+	}
+
+	s.Score = math.Max(s.TrialCV, s.HistoryCV)
+
+	return s
+}
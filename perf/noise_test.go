@@ -0,0 +1,43 @@
+package perf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScoreNoise(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		assert.Equal(t, NoiseScore{}, ScoreNoise(nil))
+		assert.Equal(t, NoiseScore{}, ScoreNoise([][]float64{{}, {}}))
+	})
+	t.Run("Stable", func(t *testing.T) {
+		score := ScoreNoise([][]float64{{100, 100}, {100}, {100, 100, 100}})
+		assert.Equal(t, 3, score.Versions)
+		assert.Equal(t, 6, score.Trials)
+		assert.Zero(t, score.TrialCV)
+		assert.Zero(t, score.HistoryCV)
+		assert.Zero(t, score.Score)
+	})
+	t.Run("NoisyTrials", func(t *testing.T) {
+		score := ScoreNoise([][]float64{{80, 120}, {80, 120}, {80, 120}})
+		assert.InDelta(t, 0.2828, score.TrialCV, 0.0001)
+		assert.Zero(t, score.HistoryCV)
+		assert.Equal(t, score.TrialCV, score.Score)
+		assert.True(t, score.Score > DefaultNoiseThreshold)
+	})
+	t.Run("NoisyHistory", func(t *testing.T) {
+		score := ScoreNoise([][]float64{{100}, {120}, {100}, {120}, {100}})
+		assert.Zero(t, score.TrialCV)
+		// successive differences of 20 around a mean of 108
+		assert.InDelta(t, 20/(0.6745*1.4142)/108, score.HistoryCV, 0.0001)
+		assert.Equal(t, score.HistoryCV, score.Score)
+	})
+	t.Run("ShiftIsNotNoise", func(t *testing.T) {
+		score := ScoreNoise([][]float64{{100}, {100}, {100}, {100}, {200}, {200}, {200}, {200}})
+		assert.Zero(t, score.HistoryCV)
+
+		score = ScoreNoise([][]float64{{100}, {200}, {100}, {200}, {100}, {200}, {100}, {200}})
+		assert.True(t, score.HistoryCV > DefaultNoiseThreshold)
+	})
+}
@@ -0,0 +1,108 @@
+package perf
+
+import (
+	"math"
+	"strings"
+
+	"github.com/evergreen-ci/cedar/model"
+)
+
+// RegressionCheck holds the values of a rollup that a regression rule
+// is checked against.
+type RegressionCheck struct {
+	MetricType model.MetricType
+	Baseline   float64
+	Value      float64
+	// ChangePoint is the change point of the rollup that was
+	// detected at the new version, if any.
+	ChangePoint *model.PerfChangePoint
+	// Noise is the change of the rollup, relative to the baseline,
+	// that is within the noise of its series. The threshold of the
+	// rule is raised by the noise, so that noisy rollups are not
+	// reported every time they vary.
+	Noise float64
+}
+
+// Regression describes a change of a rollup that matches a
+// regression rule.
+type Regression struct {
+	Baseline float64
+	Value    float64
+	// PercentChange is the change relative to the baseline, which
+	// is NaN if the baseline is zero or either value is not finite.
+	PercentChange float64
+	// Confidence is the confidence of the change point at the new
+	// version, or zero if there is none.
+	Confidence float64
+	// ThresholdPercent is the threshold of the rule, raised by the
+	// noise of the rollup.
+	ThresholdPercent float64
+}
+
+// CheckRegression returns the regression when the rollup matches the
+// threshold, raised by the noise of the rollup, or the change point
+// confidence of the rule, in the direction that is worse for the
+// rollup. Change points are detected beyond the noise of the series
+// already, so the noise does not affect the confidence.
+func CheckRegression(rule model.PerfRegressionRule, check RegressionCheck) (Regression, bool) {
+	r := Regression{
+		Baseline:      check.Baseline,
+		Value:         check.Value,
+		PercentChange: math.NaN(),
+	}
+	if check.Baseline != 0 && isFinite(check.Baseline) && isFinite(check.Value) {
+		r.PercentChange = 100 * (check.Value - check.Baseline) / math.Abs(check.Baseline)
+	}
+	if check.ChangePoint != nil {
+		r.Confidence = check.ChangePoint.Confidence
+	}
+	if rule.ThresholdPercent > 0 {
+		r.ThresholdPercent = rule.ThresholdPercent
+		if check.Noise > 0 {
+			r.ThresholdPercent += 100 * check.Noise
+		}
+	}
+
+	direction := regressionDirection(rule.Metric, check.MetricType)
+	if r.ThresholdPercent > 0 && !math.IsNaN(r.PercentChange) &&
+		math.Abs(r.PercentChange) >= r.ThresholdPercent && isWorse(direction, r.PercentChange) {
+		return r, true
+	}
+
+	cp := check.ChangePoint
+	if rule.Confidence > 0 && cp != nil && cp.Confidence >= rule.Confidence && isWorse(direction, cp.After-cp.Before) {
+		return r, true
+	}
+
+	return r, false
+}
+
+// regressionDirection returns 1 when higher values of the rollup are
+// worse, -1 when lower values are worse, and 0 when either may be. The
+// distribution rollups of latency and throughput, such as their
+// percentiles, have the direction of the metric that their name is
+// prefixed with, except for their standard deviation, where a higher
+// spread is worse.
+func regressionDirection(metric string, t model.MetricType) float64 {
+	var direction float64
+	switch {
+	case t == model.MetricTypeThroughput, strings.HasPrefix(metric, "throughput"):
+		direction = -1
+	case t == model.MetricTypeLatency, strings.HasPrefix(metric, "latency"):
+		direction = 1
+	}
+
+	if direction != 0 && t == model.MetricTypeStdDev {
+		return 1
+	}
+
+	return direction
+}
+
+func isWorse(direction, delta float64) bool {
+	if direction == 0 {
+		return delta != 0
+	}
+
+	return direction*delta > 0
+}
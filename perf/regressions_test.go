@@ -0,0 +1,92 @@
+package perf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRegression(t *testing.T) {
+	threshold := model.PerfRegressionRule{Metric: "m", ThresholdPercent: 10}
+	confidence := model.PerfRegressionRule{Metric: "m", Confidence: 0.9}
+	latencyP99 := model.PerfRegressionRule{Metric: "latencyPercentile99", ThresholdPercent: 10}
+	throughputP95 := model.PerfRegressionRule{Metric: "throughputOpsPercentile95", ThresholdPercent: 10}
+
+	for _, test := range []struct {
+		name      string
+		rule      model.PerfRegressionRule
+		check     RegressionCheck
+		regressed bool
+	}{
+		{name: "ThroughputDrop", rule: threshold, check: RegressionCheck{MetricType: model.MetricTypeThroughput, Baseline: 100, Value: 85}, regressed: true},
+		{name: "ThroughputGain", rule: threshold, check: RegressionCheck{MetricType: model.MetricTypeThroughput, Baseline: 100, Value: 120}},
+		{name: "SmallThroughputDrop", rule: threshold, check: RegressionCheck{MetricType: model.MetricTypeThroughput, Baseline: 100, Value: 95}},
+		{name: "LatencyIncrease", rule: threshold, check: RegressionCheck{MetricType: model.MetricTypeLatency, Baseline: 10, Value: 11}, regressed: true},
+		{name: "LatencyDecrease", rule: threshold, check: RegressionCheck{MetricType: model.MetricTypeLatency, Baseline: 10, Value: 5}},
+		{name: "UntypedChange", rule: threshold, check: RegressionCheck{MetricType: model.MetricTypeMean, Baseline: -10, Value: -5}, regressed: true},
+		{name: "ZeroBaseline", rule: threshold, check: RegressionCheck{MetricType: model.MetricTypeLatency, Value: 5}},
+		{name: "InfiniteValue", rule: threshold, check: RegressionCheck{MetricType: model.MetricTypeLatency, Baseline: 10, Value: math.Inf(1)}},
+		{name: "LatencyPercentileIncrease", rule: latencyP99, check: RegressionCheck{MetricType: model.MetricTypePercentile99, Baseline: 10, Value: 12}, regressed: true},
+		{name: "LatencyPercentileDecrease", rule: latencyP99, check: RegressionCheck{MetricType: model.MetricTypePercentile99, Baseline: 10, Value: 5}},
+		{name: "LatencyMedianDecrease", rule: model.PerfRegressionRule{Metric: "latencyMedian", ThresholdPercent: 10}, check: RegressionCheck{MetricType: model.MetricTypeMedian, Baseline: 10, Value: 5}},
+		{name: "LatencyStdDevIncrease", rule: model.PerfRegressionRule{Metric: "latencyStdDev", ThresholdPercent: 10}, check: RegressionCheck{MetricType: model.MetricTypeStdDev, Baseline: 10, Value: 15}, regressed: true},
+		{name: "ThroughputPercentileDrop", rule: throughputP95, check: RegressionCheck{MetricType: model.MetricTypePercentile95, Baseline: 100, Value: 80}, regressed: true},
+		{name: "ThroughputPercentileGain", rule: throughputP95, check: RegressionCheck{MetricType: model.MetricTypePercentile95, Baseline: 100, Value: 120}},
+		{name: "ThroughputMinGain", rule: model.PerfRegressionRule{Metric: "throughputOpsMin", ThresholdPercent: 10}, check: RegressionCheck{MetricType: model.MetricTypeMin, Baseline: 100, Value: 120}},
+		{name: "ThroughputStdDevIncrease", rule: model.PerfRegressionRule{Metric: "throughputOpsStdDev", ThresholdPercent: 10}, check: RegressionCheck{MetricType: model.MetricTypeStdDev, Baseline: 10, Value: 15}, regressed: true},
+		{
+			name:  "ImprovingPercentileChangePoint",
+			rule:  model.PerfRegressionRule{Metric: "latencyPercentile99", Confidence: 0.9},
+			check: RegressionCheck{MetricType: model.MetricTypePercentile99, Baseline: 10, Value: 9, ChangePoint: &model.PerfChangePoint{Before: 10, After: 8, Confidence: 0.99}},
+		},
+		{
+			name:      "ConfidentChangePoint",
+			rule:      confidence,
+			check:     RegressionCheck{MetricType: model.MetricTypeThroughput, Baseline: 100, Value: 99, ChangePoint: &model.PerfChangePoint{Before: 100, After: 90, Confidence: 0.95}},
+			regressed: true,
+		},
+		{
+			name:  "UnconfidentChangePoint",
+			rule:  confidence,
+			check: RegressionCheck{MetricType: model.MetricTypeThroughput, Baseline: 100, Value: 50, ChangePoint: &model.PerfChangePoint{Before: 100, After: 50, Confidence: 0.5}},
+		},
+		{name: "LatencyIncreaseWithinNoise", rule: threshold, check: RegressionCheck{MetricType: model.MetricTypeLatency, Baseline: 10, Value: 12, Noise: 0.2}},
+		{name: "LatencyIncreaseBeyondNoise", rule: threshold, check: RegressionCheck{MetricType: model.MetricTypeLatency, Baseline: 10, Value: 14, Noise: 0.2}, regressed: true},
+		{
+			name:      "ConfidentChangePointWithinNoise",
+			rule:      confidence,
+			check:     RegressionCheck{MetricType: model.MetricTypeThroughput, Baseline: 100, Value: 99, Noise: 0.5, ChangePoint: &model.PerfChangePoint{Before: 100, After: 90, Confidence: 0.95}},
+			regressed: true,
+		},
+		{
+			name:  "ImprovingChangePoint",
+			rule:  confidence,
+			check: RegressionCheck{MetricType: model.MetricTypeThroughput, Baseline: 100, Value: 110, ChangePoint: &model.PerfChangePoint{Before: 100, After: 110, Confidence: 0.99}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r, regressed := CheckRegression(test.rule, test.check)
+			assert.Equal(t, test.regressed, regressed)
+			assert.Equal(t, test.check.Baseline, r.Baseline)
+			assert.Equal(t, test.check.Value, r.Value)
+			if test.check.Baseline == 0 {
+				assert.True(t, math.IsNaN(r.PercentChange))
+			}
+			if test.check.ChangePoint != nil {
+				assert.Equal(t, test.check.ChangePoint.Confidence, r.Confidence)
+			}
+		})
+	}
+
+	r, _ := CheckRegression(threshold, RegressionCheck{Baseline: 100, Value: 85})
+	assert.Equal(t, -15.0, r.PercentChange)
+	assert.Equal(t, 10.0, r.ThresholdPercent)
+
+	r, _ = CheckRegression(threshold, RegressionCheck{Baseline: 100, Value: 85, Noise: 0.05})
+	assert.InDelta(t, 15.0, r.ThresholdPercent, 1e-9)
+
+	r, _ = CheckRegression(confidence, RegressionCheck{Baseline: 100, Value: 85, Noise: 0.05})
+	assert.Zero(t, r.ThresholdPercent)
+}
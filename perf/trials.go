@@ -0,0 +1,120 @@
+package perf
+
+import (
+	"math"
+	"sort"
+
+	"github.com/evergreen-ci/cedar/model"
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// TrialConfidenceLevel is the confidence level of the confidence
+// intervals of trial summaries.
+const TrialConfidenceLevel = 0.95
+
+// SummarizeTrials groups the results by test name and arguments, so
+// that each group holds the trials of one test, and summarizes every
+// rollup across the trials of each group. Rollups that are missing
+// from some trials, or whose values are not finite in some trials, are
+// summarized over the trials that have finite values.
+// The summaries are sorted by test, arguments and rollup name.
+func SummarizeTrials(results []model.PerformanceResult) []model.PerfTrialSummary {
+	type trials struct {
+		key    ComparisonKey
+		values map[string][]float64
+	}
+
+	groups := map[string]*trials{}
+	for _, result := range results {
+		if result.Rollups == nil {
+			continue
+		}
+
+		key := ComparisonKey{
+			TestName:  result.Info.TestName,
+			Arguments: result.Info.Arguments,
+		}
+		group, ok := groups[key.String()]
+		if !ok {
+			group = &trials{key: key, values: map[string][]float64{}}
+			groups[key.String()] = group
+		}
+
+		for name, val := range result.Rollups.MapFloat() {
+			if isFinite(val) {
+				group.values[name] = append(group.values[name], val)
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	summaries := []model.PerfTrialSummary{}
+	for _, key := range keys {
+		group := groups[key]
+
+		names := make([]string, 0, len(group.values))
+		for name := range group.values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			summary := summarizeTrials(group.values[name])
+			summary.TestName = group.key.TestName
+			summary.Arguments = group.key.Arguments
+			summary.Rollup = name
+			summaries = append(summaries, summary)
+		}
+	}
+
+	return summaries
+}
+
+func summarizeTrials(values []float64) model.PerfTrialSummary {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	n := len(sorted)
+	s := model.PerfTrialSummary{
+		Trials:          n,
+		Mean:            mean(sorted),
+		Min:             sorted[0],
+		Max:             sorted[n-1],
+		ConfidenceLevel: TrialConfidenceLevel,
+	}
+
+	if n%2 == 1 {
+		s.Median = sorted[n/2]
+	} else {
+		s.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	if n > 1 {
+		sumSquares := 0.0
+		for _, v := range sorted {
+			sumSquares += (v - s.Mean) * (v - s.Mean)
+		}
+		s.StdDev = math.Sqrt(sumSquares / float64(n-1))
+	}
+
+	if s.Mean != 0 {
+		s.CoefficientOfVariation = s.StdDev / math.Abs(s.Mean)
+	}
+
+	// the interval uses the t-distribution, since there are usually
+	// only a handful of trials, and is empty for a single trial.
+	margin := 0.0
+	if n > 1 {
+		t := distuv.StudentsT{Mu: 0, Sigma: 1, Nu: float64(n - 1)}
+		margin = t.Quantile(1-(1-TrialConfidenceLevel)/2) * s.StdDev / math.Sqrt(float64(n))
+	}
+	s.ConfidenceLower = s.Mean - margin
+	s.ConfidenceUpper = s.Mean + margin
+
+	return s
+}
@@ -0,0 +1,74 @@
+package perf
+
+import (
+	"math"
+	"testing"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizeTrials(t *testing.T) {
+	results := []model.PerformanceResult{}
+	for trial, latency := range []float64{10, 12, 14, 16, 18} {
+		results = append(results, comparisonResult("insert", "variant", "insert", trial, map[string]interface{}{
+			"latency": latency,
+			"ops":     int64(100),
+		}))
+	}
+	results = append(results,
+		comparisonResult("update", "variant", "update", 0, map[string]interface{}{"latency": 5.0}),
+		comparisonResult("update", "variant", "update", 1, map[string]interface{}{"latency": 7.0}),
+		comparisonResult("update", "variant", "update", 2, map[string]interface{}{"latency": math.Inf(1), "nan": math.NaN()}),
+		comparisonResult("zero", "variant", "zero", 0, map[string]interface{}{"latency": 0.0}),
+		model.PerformanceResult{Info: model.PerformanceResultInfo{TestName: "no rollups"}},
+	)
+	other := comparisonResult("insert", "variant", "insert", 5, map[string]interface{}{"latency": 1000.0})
+	other.Info.Arguments = map[string]int32{"threads": 8}
+	results = append(results, other)
+
+	summaries := SummarizeTrials(results)
+	require.Len(t, summaries, 5)
+
+	latency := summaries[0]
+	assert.Equal(t, "insert", latency.TestName)
+	assert.Equal(t, map[string]int32{"threads": 4}, latency.Arguments)
+	assert.Equal(t, "latency", latency.Rollup)
+	assert.Equal(t, 5, latency.Trials)
+	assert.Equal(t, 14.0, latency.Mean)
+	assert.Equal(t, 14.0, latency.Median)
+	assert.Equal(t, 10.0, latency.Min)
+	assert.Equal(t, 18.0, latency.Max)
+	assert.InDelta(t, 3.1623, latency.StdDev, 0.0001)
+	assert.InDelta(t, 0.2259, latency.CoefficientOfVariation, 0.0001)
+	assert.Equal(t, TrialConfidenceLevel, latency.ConfidenceLevel)
+	// t(0.975, 4) = 2.7764
+	assert.InDelta(t, 14-2.7764*3.1623/2.2361, latency.ConfidenceLower, 0.001)
+	assert.InDelta(t, 14+2.7764*3.1623/2.2361, latency.ConfidenceUpper, 0.001)
+
+	ops := summaries[1]
+	assert.Equal(t, "ops", ops.Rollup)
+	assert.Equal(t, 100.0, ops.Mean)
+	assert.Zero(t, ops.StdDev)
+	assert.Zero(t, ops.CoefficientOfVariation)
+	assert.Equal(t, 100.0, ops.ConfidenceLower)
+	assert.Equal(t, 100.0, ops.ConfidenceUpper)
+
+	single := summaries[2]
+	assert.Equal(t, map[string]int32{"threads": 8}, single.Arguments)
+	assert.Equal(t, 1, single.Trials)
+	assert.Equal(t, 1000.0, single.Median)
+	assert.Zero(t, single.StdDev)
+	assert.Equal(t, 1000.0, single.ConfidenceLower)
+	assert.Equal(t, 1000.0, single.ConfidenceUpper)
+
+	update := summaries[3]
+	assert.Equal(t, "update", update.TestName)
+	assert.Equal(t, 2, update.Trials)
+	assert.Equal(t, 6.0, update.Median)
+
+	zero := summaries[4]
+	assert.Equal(t, "zero", zero.TestName)
+	assert.Zero(t, zero.CoefficientOfVariation)
+}
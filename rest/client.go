@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
+	"github.com/evergreen-ci/cedar/rest/model"
 	"github.com/evergreen-ci/gimlet"
 	"github.com/mongodb/grip"
 	"github.com/mongodb/grip/message"
@@ -466,3 +468,109 @@ func (c *Client) DisableFeatureFlag(ctx context.Context, name string) (bool, err
 	}
 	return out.State, nil
 }
+
+///////////////////////////////////
+//
+// Performance Results
+
+// ComparePerformanceVersions compares the performance results of the
+// comparison version to those of the base version, optionally limited
+// to results with the given tags.
+func (c *Client) ComparePerformanceVersions(ctx context.Context, base, comparison string, tags ...string) ([]model.APIPerformanceComparison, error) {
+	return c.comparePerformance(ctx, fmt.Sprintf("version/%s/%s", url.PathEscape(base), url.PathEscape(comparison)), tags)
+}
+
+// ComparePerformanceTasks compares the performance results of the
+// comparison task to those of the base task, optionally limited to
+// results with the given tags.
+func (c *Client) ComparePerformanceTasks(ctx context.Context, base, comparison string, tags ...string) ([]model.APIPerformanceComparison, error) {
+	return c.comparePerformance(ctx, fmt.Sprintf("task_id/%s/%s", url.PathEscape(base), url.PathEscape(comparison)), tags)
+}
+
+// ComparePerformanceToBaseline compares the performance results of
+// each task of the comparison version to those of the baseline of the
+// task, optionally limited to results with the given tags.
+func (c *Client) ComparePerformanceToBaseline(ctx context.Context, comparison string, tags ...string) ([]model.APIPerformanceComparison, error) {
+	return c.comparePerformance(ctx, fmt.Sprintf("baseline/%s", url.PathEscape(comparison)), tags)
+}
+
+func (c *Client) comparePerformance(ctx context.Context, sides string, tags []string) ([]model.APIPerformanceComparison, error) {
+	path := "/v1/perf/compare/" + sides
+	if len(tags) > 0 {
+		path += "?" + url.Values{"tags": tags}.Encode()
+	}
+
+	req, err := c.makeRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errResp := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &errResp); err != nil {
+			return nil, errors.Wrapf(err, "problem reading error response with status %d", resp.StatusCode)
+		}
+		return nil, errors.WithStack(errResp)
+	}
+
+	out := []model.APIPerformanceComparison{}
+	if err = gimlet.GetJSON(resp.Body, &out); err != nil {
+		return nil, errors.Wrap(err, "problem reading comparison result")
+	}
+
+	return out, nil
+}
+
+// GetPerformanceBaseline returns the baseline of the task of the
+// project and variant.
+func (c *Client) GetPerformanceBaseline(ctx context.Context, project, variant, task string) (*model.APIPerfBaseline, error) {
+	return c.doPerformanceBaseline(ctx, http.MethodGet, project, variant, task, nil)
+}
+
+// SetPerformanceBaseline sets the baseline of the task of the project
+// and variant to the version or performance result of the change.
+func (c *Client) SetPerformanceBaseline(ctx context.Context, project, variant, task string, change model.APIPerfBaselineChange) (*model.APIPerfBaseline, error) {
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem building payload")
+	}
+
+	return c.doPerformanceBaseline(ctx, http.MethodPut, project, variant, task, bytes.NewBuffer(payload))
+}
+
+func (c *Client) doPerformanceBaseline(ctx context.Context, method, project, variant, task string, body io.Reader) (*model.APIPerfBaseline, error) {
+	path := fmt.Sprintf("/v1/perf/project/%s/baseline?%s", url.PathEscape(project),
+		url.Values{"variant": []string{variant}, "task": []string{task}}.Encode())
+
+	req, err := c.makeRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem with request")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errResp := gimlet.ErrorResponse{}
+		if err = gimlet.GetJSON(resp.Body, &errResp); err != nil {
+			return nil, errors.Wrapf(err, "problem reading error response with status %d", resp.StatusCode)
+		}
+		return nil, errors.WithStack(errResp)
+	}
+
+	out := &model.APIPerfBaseline{}
+	if err = gimlet.GetJSON(resp.Body, out); err != nil {
+		return nil, errors.Wrap(err, "problem reading baseline result")
+	}
+
+	return out, nil
+}
@@ -3,6 +3,7 @@
 package rest
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -10,8 +11,13 @@ import (
 	"testing"
 
 	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
 	"github.com/mongodb/amboy/queue"
 	"github.com/mongodb/grip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"context"
 )
@@ -252,3 +258,104 @@ func (s *ClientSuite) TestURLGenerationWithEmptyPrefix() {
 // Client/Service Interaction: Public Methods
 //
 ////////////////////////////////////////////////////////////////////////
+
+func TestClientComparePerformance(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sc := &data.MockConnector{
+		CachedPerformanceResults: map[string]model.APIPerformanceResult{},
+	}
+	for version, latency := range map[string]float64{"base": 100, "patch": 150} {
+		for trial := 0; trial < 3; trial++ {
+			id := fmt.Sprintf("%s-%d", version, trial)
+			sc.CachedPerformanceResults[id] = model.APIPerformanceResult{
+				Name: model.ToAPIString(id),
+				Info: model.APIPerformanceResultInfo{
+					Project:  model.ToAPIString("project"),
+					Version:  model.ToAPIString(version),
+					Variant:  model.ToAPIString("variant"),
+					TaskID:   model.ToAPIString(version + "-task"),
+					TaskName: model.ToAPIString("task"),
+					TestName: model.ToAPIString("test"),
+					Trial:    trial,
+				},
+				Rollups: &model.APIPerfRollups{
+					Stats: []model.APIPerfRollupValue{
+						{Name: model.ToAPIString("latency"), Value: latency + float64(trial)},
+					},
+				},
+			}
+		}
+	}
+
+	app := gimlet.NewApp()
+	app.AddRoute("/perf/compare/version/{base}/{comparison}").Version(1).Get().RouteHandler(makeComparePerfByVersion(sc))
+	app.AddRoute("/perf/compare/task_id/{base}/{comparison}").Version(1).Get().RouteHandler(makeComparePerfByTaskId(sc))
+	app.AddRoute("/perf/compare/baseline/{comparison}").Version(1).Get().RouteHandler(makeComparePerfToBaseline(sc))
+	app.AddRoute("/perf/project/{project}/baseline").Version(1).Get().RouteHandler(makeGetPerfBaseline(sc))
+	app.AddRoute("/perf/project/{project}/baseline").Version(1).Put().RouteHandler(makeSetPerfBaseline(sc))
+	require.NoError(t, app.Resolve())
+	router, err := app.Router()
+	require.NoError(t, err)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	portStart := strings.LastIndex(server.URL, ":")
+	port, err := strconv.Atoi(server.URL[portStart+1:])
+	require.NoError(t, err)
+	client, err := NewClient(server.URL[:portStart], port, "")
+	require.NoError(t, err)
+
+	for name, compare := range map[string]func() ([]model.APIPerformanceComparison, error){
+		"Versions": func() ([]model.APIPerformanceComparison, error) {
+			return client.ComparePerformanceVersions(ctx, "base", "patch")
+		},
+		"Tasks": func() ([]model.APIPerformanceComparison, error) {
+			return client.ComparePerformanceTasks(ctx, "base-task", "patch-task")
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			comparisons, err := compare()
+			require.NoError(t, err)
+			require.Len(t, comparisons, 1)
+			assert.Equal(t, "test", model.FromAPIString(comparisons[0].TestName))
+			require.Len(t, comparisons[0].Rollups, 1)
+
+			rollup := comparisons[0].Rollups[0]
+			assert.Equal(t, 50.0, rollup.AbsoluteDelta)
+			require.NotNil(t, rollup.PercentDelta)
+			assert.InDelta(t, 49.5, *rollup.PercentDelta, 0.01)
+			assert.Equal(t, 3, rollup.BaseTrials)
+			assert.InDelta(t, 0.1, rollup.PValue, 0.001)
+		})
+	}
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := client.ComparePerformanceVersions(ctx, "base", "DNE")
+		assert.Error(t, err)
+	})
+	t.Run("Baseline", func(t *testing.T) {
+		_, err := client.GetPerformanceBaseline(ctx, "project", "variant", "task")
+		assert.Error(t, err)
+		_, err = client.ComparePerformanceToBaseline(ctx, "patch")
+		assert.Error(t, err)
+
+		baseline, err := client.SetPerformanceBaseline(ctx, "project", "variant", "task", model.APIPerfBaselineChange{
+			Version: model.ToAPIString("base"),
+			SetBy:   model.ToAPIString("user"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "base", model.FromAPIString(baseline.Version))
+
+		baseline, err = client.GetPerformanceBaseline(ctx, "project", "variant", "task")
+		require.NoError(t, err)
+		assert.Equal(t, "user", model.FromAPIString(baseline.SetBy))
+		assert.Len(t, baseline.History, 1)
+
+		comparisons, err := client.ComparePerformanceToBaseline(ctx, "patch")
+		require.NoError(t, err)
+		require.Len(t, comparisons, 1)
+		require.Len(t, comparisons[0].Rollups, 1)
+		assert.Equal(t, 50.0, comparisons[0].Rollups[0].AbsoluteDelta)
+	})
+}
@@ -0,0 +1,66 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/cedar/model"
+	dataModel "github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+)
+
+// FindChangePointsByProject queries the database to find all change
+// points detected in the given project, narrowed by the filter.
+func (dbc *DBConnector) FindChangePointsByProject(project string, filter ChangePointFilter) ([]dataModel.APIChangePoint, error) {
+	changePoints := model.PerfChangePoints{}
+	changePoints.Setup(dbc.env)
+
+	options := model.PerfChangePointFindOptions{
+		Project:     project,
+		Variant:     filter.Variant,
+		TaskName:    filter.TaskName,
+		TestName:    filter.TestName,
+		Measurement: filter.Measurement,
+	}
+	if err := changePoints.Find(options); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("database error"),
+		}
+	}
+
+	apiChangePoints := make([]dataModel.APIChangePoint, len(changePoints.ChangePoints))
+	for i, changePoint := range changePoints.ChangePoints {
+		if err := apiChangePoints[i].Import(changePoint); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    fmt.Sprintf("corrupt data"),
+			}
+		}
+	}
+	return apiChangePoints, nil
+}
+
+// MockConnector Implementation
+
+func (mc *MockConnector) FindChangePointsByProject(project string, filter ChangePointFilter) ([]dataModel.APIChangePoint, error) {
+	changePoints := []dataModel.APIChangePoint{}
+	for _, changePoint := range mc.CachedChangePoints {
+		if dataModel.FromAPIString(changePoint.Project) != project {
+			continue
+		}
+		if !matchesFilter(dataModel.FromAPIString(changePoint.Variant), filter.Variant) ||
+			!matchesFilter(dataModel.FromAPIString(changePoint.TaskName), filter.TaskName) ||
+			!matchesFilter(dataModel.FromAPIString(changePoint.TestName), filter.TestName) ||
+			!matchesFilter(dataModel.FromAPIString(changePoint.Measurement), filter.Measurement) {
+			continue
+		}
+		changePoints = append(changePoints, changePoint)
+	}
+
+	return changePoints, nil
+}
+
+func matchesFilter(value, filter string) bool {
+	return filter == "" || value == filter
+}
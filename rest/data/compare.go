@@ -0,0 +1,344 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
+	dataModel "github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/cedar/util"
+	"github.com/evergreen-ci/gimlet"
+)
+
+// ComparePerformanceResultsByVersion queries the database for the
+// performance results of the two versions, filtered by the optional
+// tags, and compares the rollups of the tests that ran in both
+// versions. Tests are matched by variant, task name, test name,
+// arguments and tags.
+func (dbc *DBConnector) ComparePerformanceResultsByVersion(base, comparison string, tags ...string) ([]dataModel.APIPerformanceComparison, error) {
+	baseResults, err := dbc.findComparisonResults(model.PerformanceResultInfo{Version: base, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+	comparisonResults, err := dbc.findComparisonResults(model.PerformanceResultInfo{Version: comparison, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+
+	return importComparisons(perf.CompareResults(baseResults, comparisonResults, perf.CompareOptions{MatchTask: true}))
+}
+
+// ComparePerformanceResultsByTaskId queries the database for the
+// performance results of the two tasks, filtered by the optional tags,
+// and compares the rollups of the tests that ran in both tasks. Tests
+// are matched by test name, arguments and tags.
+func (dbc *DBConnector) ComparePerformanceResultsByTaskId(base, comparison string, tags ...string) ([]dataModel.APIPerformanceComparison, error) {
+	baseResults, err := dbc.findComparisonResults(model.PerformanceResultInfo{TaskID: base, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+	comparisonResults, err := dbc.findComparisonResults(model.PerformanceResultInfo{TaskID: comparison, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+
+	return importComparisons(perf.CompareResults(baseResults, comparisonResults, perf.CompareOptions{}))
+}
+
+// ComparePerformanceResultsToBaseline queries the database for the
+// performance results of the version, filtered by the optional tags,
+// and compares the rollups of the tests of each task to those of the
+// baseline of the task. Tasks without a baseline are not compared.
+func (dbc *DBConnector) ComparePerformanceResultsToBaseline(comparison string, tags ...string) ([]dataModel.APIPerformanceComparison, error) {
+	comparisonResults, err := dbc.findComparisonResults(model.PerformanceResultInfo{Version: comparison, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+
+	return compareToBaselines(comparison, comparisonResults, func(key model.PerfBaselineKey) ([]model.PerformanceResult, bool, error) {
+		baseline, err := dbc.findBaseline(key)
+		if err != nil || baseline.IsNil() {
+			return nil, false, err
+		}
+
+		info := model.PerformanceResultInfo{
+			Project:  key.Project,
+			Version:  baseline.Version,
+			TaskName: key.TaskName,
+			Tags:     tags,
+		}
+		execution := -1
+		if baseline.PerfResultID != "" {
+			result, err := dbc.findPerformanceResult(baseline.PerfResultID)
+			if err != nil {
+				return nil, true, err
+			}
+			info = model.PerformanceResultInfo{TaskID: result.Info.TaskID, Tags: tags}
+			execution = result.Info.Execution
+		}
+
+		results, err := dbc.findResults(info)
+		if err != nil {
+			return nil, true, err
+		}
+		return filterBaselineResults(key, execution, results), true, nil
+	})
+}
+
+func (dbc *DBConnector) findComparisonResults(info model.PerformanceResultInfo) ([]model.PerformanceResult, error) {
+	results, err := dbc.findResults(info)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("performance results for '%s' not found", comparisonSide(info)),
+		}
+	}
+
+	return results, nil
+}
+
+// findResults returns the performance results that match the info,
+// without their children.
+func (dbc *DBConnector) findResults(info model.PerformanceResultInfo) ([]model.PerformanceResult, error) {
+	results := model.PerformanceResults{}
+	results.Setup(dbc.env)
+
+	options := model.PerfFindOptions{
+		Interval: util.TimeRange{EndAt: time.Now()},
+		Info:     info,
+		// set MaxDepth to -1 to avoid recursive child search
+		MaxDepth: -1,
+	}
+
+	if err := results.Find(options); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("database error"),
+		}
+	}
+
+	return results.Results, nil
+}
+
+// MockConnector Implementation
+
+func (mc *MockConnector) ComparePerformanceResultsByVersion(base, comparison string, tags ...string) ([]dataModel.APIPerformanceComparison, error) {
+	baseResults, err := mc.findComparisonResults(model.PerformanceResultInfo{Version: base, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+	comparisonResults, err := mc.findComparisonResults(model.PerformanceResultInfo{Version: comparison, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+
+	return importComparisons(perf.CompareResults(baseResults, comparisonResults, perf.CompareOptions{MatchTask: true}))
+}
+
+func (mc *MockConnector) ComparePerformanceResultsByTaskId(base, comparison string, tags ...string) ([]dataModel.APIPerformanceComparison, error) {
+	baseResults, err := mc.findComparisonResults(model.PerformanceResultInfo{TaskID: base, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+	comparisonResults, err := mc.findComparisonResults(model.PerformanceResultInfo{TaskID: comparison, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+
+	return importComparisons(perf.CompareResults(baseResults, comparisonResults, perf.CompareOptions{}))
+}
+
+func (mc *MockConnector) ComparePerformanceResultsToBaseline(comparison string, tags ...string) ([]dataModel.APIPerformanceComparison, error) {
+	comparisonResults, err := mc.findComparisonResults(model.PerformanceResultInfo{Version: comparison, Tags: tags})
+	if err != nil {
+		return nil, err
+	}
+
+	return compareToBaselines(comparison, comparisonResults, func(key model.PerfBaselineKey) ([]model.PerformanceResult, bool, error) {
+		idx := mc.findBaseline(key)
+		if idx < 0 {
+			return nil, false, nil
+		}
+		baseline := mc.CachedPerfBaselines[idx]
+
+		version := dataModel.FromAPIString(baseline.Version)
+		taskID := ""
+		execution := -1
+		if perfID := dataModel.FromAPIString(baseline.PerfResultID); perfID != "" {
+			result, ok := mc.CachedPerformanceResults[perfID]
+			if !ok {
+				return nil, true, gimlet.ErrorResponse{
+					StatusCode: http.StatusNotFound,
+					Message:    fmt.Sprintf("performance result with id '%s' not found", perfID),
+				}
+			}
+			version = ""
+			taskID = dataModel.FromAPIString(result.Info.TaskID)
+			execution = result.Info.Execution
+		}
+
+		results := []model.PerformanceResult{}
+		for id, result := range mc.CachedPerformanceResults {
+			if version != "" && dataModel.FromAPIString(result.Info.Version) != version {
+				continue
+			}
+			if taskID != "" && dataModel.FromAPIString(result.Info.TaskID) != taskID {
+				continue
+			}
+			if !mc.checkTags(id, tags) {
+				continue
+			}
+			results = append(results, exportPerformanceResult(result))
+		}
+		return filterBaselineResults(key, execution, results), true, nil
+	})
+}
+
+func (mc *MockConnector) findComparisonResults(info model.PerformanceResultInfo) ([]model.PerformanceResult, error) {
+	results := []model.PerformanceResult{}
+	for id, result := range mc.CachedPerformanceResults {
+		if info.Version != "" && dataModel.FromAPIString(result.Info.Version) != info.Version {
+			continue
+		}
+		if info.TaskID != "" && dataModel.FromAPIString(result.Info.TaskID) != info.TaskID {
+			continue
+		}
+		if !mc.checkTags(id, info.Tags) {
+			continue
+		}
+
+		results = append(results, exportPerformanceResult(result))
+	}
+
+	if len(results) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("performance results for '%s' not found", comparisonSide(info)),
+		}
+	}
+	return results, nil
+}
+
+// exportPerformanceResult converts the fields of a cached result that
+// are needed to compare results back into the database model.
+func exportPerformanceResult(result dataModel.APIPerformanceResult) model.PerformanceResult {
+	out := model.PerformanceResult{
+		ID: dataModel.FromAPIString(result.Name),
+		Info: model.PerformanceResultInfo{
+			Project:   dataModel.FromAPIString(result.Info.Project),
+			Version:   dataModel.FromAPIString(result.Info.Version),
+			Variant:   dataModel.FromAPIString(result.Info.Variant),
+			TaskName:  dataModel.FromAPIString(result.Info.TaskName),
+			TaskID:    dataModel.FromAPIString(result.Info.TaskID),
+			Execution: result.Info.Execution,
+			TestName:  dataModel.FromAPIString(result.Info.TestName),
+			Trial:     result.Info.Trial,
+			Parent:    dataModel.FromAPIString(result.Info.Parent),
+			Tags:      result.Info.Tags,
+			Arguments: result.Info.Arguments,
+			Schema:    result.Info.Schema,
+		},
+	}
+	if result.Rollups != nil {
+		out.Rollups = &model.PerfRollups{}
+		for _, stat := range result.Rollups.Stats {
+			out.Rollups.Stats = append(out.Rollups.Stats, model.PerfRollupValue{
+				Name:          dataModel.FromAPIString(stat.Name),
+				Value:         stat.Value,
+				Version:       stat.Version,
+				UserSubmitted: stat.UserSubmitted,
+			})
+		}
+	}
+
+	return out
+}
+
+func comparisonSide(info model.PerformanceResultInfo) string {
+	if info.Version != "" {
+		return info.Version
+	}
+	return info.TaskID
+}
+
+// compareToBaselines compares the results of each task of the
+// comparison version to the results of the baseline of the task,
+// which findBaseline returns along with whether the task has a
+// baseline. It is an error for none of the tasks to have a baseline.
+func compareToBaselines(version string, comparison []model.PerformanceResult, findBaseline func(model.PerfBaselineKey) ([]model.PerformanceResult, bool, error)) ([]dataModel.APIPerformanceComparison, error) {
+	keys := []model.PerfBaselineKey{}
+	tasks := map[model.PerfBaselineKey][]model.PerformanceResult{}
+	for _, result := range comparison {
+		key := model.PerfBaselineKey{
+			Project:  result.Info.Project,
+			Variant:  result.Info.Variant,
+			TaskName: result.Info.TaskName,
+		}
+		if _, ok := tasks[key]; !ok {
+			keys = append(keys, key)
+		}
+		tasks[key] = append(tasks[key], result)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return baselineDescription(keys[i]) < baselineDescription(keys[j])
+	})
+
+	comparisons := []perf.ResultComparison{}
+	found := false
+	for _, key := range keys {
+		base, ok, err := findBaseline(key)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		found = true
+
+		comparisons = append(comparisons, perf.CompareResults(base, tasks[key], perf.CompareOptions{MatchTask: true})...)
+	}
+	if !found {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("baselines for the tasks of '%s' not found", version),
+		}
+	}
+
+	return importComparisons(comparisons)
+}
+
+// filterBaselineResults returns the results of the task of the
+// baseline and, unless the execution is negative, of the execution.
+func filterBaselineResults(key model.PerfBaselineKey, execution int, results []model.PerformanceResult) []model.PerformanceResult {
+	out := []model.PerformanceResult{}
+	for _, result := range results {
+		if result.Info.Project != key.Project || result.Info.Variant != key.Variant || result.Info.TaskName != key.TaskName {
+			continue
+		}
+		if execution >= 0 && result.Info.Execution != execution {
+			continue
+		}
+		out = append(out, result)
+	}
+
+	return out
+}
+
+func importComparisons(comparisons []perf.ResultComparison) ([]dataModel.APIPerformanceComparison, error) {
+	apiComparisons := make([]dataModel.APIPerformanceComparison, len(comparisons))
+	for i, comparison := range comparisons {
+		if err := apiComparisons[i].Import(comparison); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    fmt.Sprintf("corrupt data"),
+			}
+		}
+	}
+	return apiComparisons, nil
+}
@@ -21,5 +21,13 @@ func CreateNewDBConnector(env cedar.Environment) Connector {
 
 type MockConnector struct {
 	CachedPerformanceResults map[string]model.APIPerformanceResult
+	CachedPerfRollupHistory  map[string][]model.APIPerfRollupValue
 	ChildMap                 map[string][]string
+	CachedChangePoints       []model.APIChangePoint
+	CachedPerfNoise          []model.APIPerfNoise
+	CachedPerfBaselines      []model.APIPerfBaseline
+
+	// Bucket is the local directory that uploaded timeseries data
+	// is written to.
+	Bucket string
 }
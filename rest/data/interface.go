@@ -1,6 +1,10 @@
 package data
 
 import (
+	"context"
+	"io"
+
+	"github.com/evergreen-ci/cedar/perf"
 	"github.com/evergreen-ci/cedar/rest/model"
 	"github.com/evergreen-ci/cedar/util"
 )
@@ -8,8 +12,64 @@ import (
 type Connector interface {
 	// PerformanceResult
 	FindPerformanceResultById(string) (*model.APIPerformanceResult, error)
-	FindPerformanceResultsByTaskId(string, util.TimeRange, ...string) ([]model.APIPerformanceResult, error)
-	FindPerformanceResultsByTaskName(string, util.TimeRange, ...string) ([]model.APIPerformanceResult, error)
-	FindPerformanceResultsByVersion(string, util.TimeRange, ...string) ([]model.APIPerformanceResult, error)
+	FindPerformanceResultsByTaskId(string, util.TimeRange, PerfPagination, ...string) ([]model.APIPerformanceResult, string, error)
+	FindPerformanceResultsByTaskName(string, util.TimeRange, PerfPagination, ...string) ([]model.APIPerformanceResult, string, error)
+	FindPerformanceResultsByVersion(string, util.TimeRange, PerfPagination, ...string) ([]model.APIPerformanceResult, string, error)
 	FindPerformanceResultWithChildren(string, int, ...string) ([]model.APIPerformanceResult, error)
+	FindPerformanceResultHistograms(context.Context, string) ([]model.APIPerformanceHistogram, error)
+	FindPerformanceResultTimeseries(context.Context, string) (io.ReadCloser, error)
+	FindPerformanceResultChart(context.Context, string, perf.ChartOptions) (*model.APIPerfChart, error)
+	FindPerformanceResultRollupHistory(string, string) (*model.APIPerfRollupHistory, error)
+	ComparePerformanceResultsByVersion(string, string, ...string) ([]model.APIPerformanceComparison, error)
+	ComparePerformanceResultsByTaskId(string, string, ...string) ([]model.APIPerformanceComparison, error)
+	ComparePerformanceResultsToBaseline(string, ...string) ([]model.APIPerformanceComparison, error)
+	CreatePerformanceResult(model.APIPerformanceResultData) (*model.APIPerformanceResult, error)
+	AddPerformanceResultArtifacts(string, []model.APIArtifactInfo) (*model.APIPerformanceResult, error)
+	AddPerformanceResultRollups(string, []model.APIPerfRollupValue) (*model.APIPerformanceResult, error)
+	RevertPerformanceResultRollup(string, string, int) (*model.APIPerformanceResult, error)
+	AddPerformanceResultTimeseries(context.Context, string, string, io.Reader) (*model.APIPerformanceResult, error)
+	ClosePerformanceResult(string) (*model.APIPerformanceResult, error)
+
+	FindPerformanceHistory(string, PerfHistoryFilter) ([]model.APIPerfHistoryPoint, error)
+
+	// Baselines
+	FindPerformanceBaseline(string, string, string) (*model.APIPerfBaseline, error)
+	SetPerformanceBaseline(string, string, string, model.APIPerfBaselineChange) (*model.APIPerfBaseline, error)
+
+	// ChangePoints
+	FindChangePointsByProject(string, ChangePointFilter) ([]model.APIChangePoint, error)
+
+	// Noise
+	FindNoisyPerfSeries(string) ([]model.APIPerfNoise, error)
+}
+
+// PerfPagination describes a page of performance results, sorted by
+// Sort (which defaults to the creation time) and starting after the
+// opaque Cursor returned with the previous page. A Limit of 0 returns
+// all of the results.
+type PerfPagination struct {
+	Limit      int
+	Sort       string
+	Descending bool
+	Cursor     string
+}
+
+// PerfHistoryFilter identifies a test series of a project, by its
+// variant, task, test, and arguments, and the rollup whose history to
+// return. All fields but the arguments are required.
+type PerfHistoryFilter struct {
+	Variant   string
+	TaskName  string
+	TestName  string
+	Arguments map[string]int32
+	Metric    string
+}
+
+// ChangePointFilter narrows the change points of a project. Empty
+// fields match all change points.
+type ChangePointFilter struct {
+	Variant     string
+	TaskName    string
+	TestName    string
+	Measurement string
 }
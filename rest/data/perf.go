@@ -1,14 +1,23 @@
 package data
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/evergreen-ci/cedar"
 	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
 	dataModel "github.com/evergreen-ci/cedar/rest/model"
 	"github.com/evergreen-ci/cedar/util"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
 )
 
 // FindPerformanceResultById queries the database to find a given performance
@@ -36,32 +45,73 @@ func (dbc *DBConnector) FindPerformanceResultById(id string) (*dataModel.APIPerf
 	return &apiResult, nil
 }
 
-// FindPerformanceResultsByTaskId queries the database to find all performance
-// results with the given taskId, time inteval, and optional tags.
-func (dbc *DBConnector) FindPerformanceResultsByTaskId(taskId string, interval util.TimeRange, tags ...string) ([]dataModel.APIPerformanceResult, error) {
+// FindPerformanceResultsByTaskId queries the database to find a page of the
+// performance results with the given taskId, time inteval, and optional tags,
+// and returns the cursor of the next page.
+func (dbc *DBConnector) FindPerformanceResultsByTaskId(taskId string, interval util.TimeRange, page PerfPagination, tags ...string) ([]dataModel.APIPerformanceResult, string, error) {
+	info := model.PerformanceResultInfo{
+		TaskID: taskId,
+		Tags:   tags,
+	}
+
+	return dbc.findPerformanceResultsPage(info, interval, page, fmt.Sprintf("task_id '%s'", taskId))
+}
+
+// FindPerformanceResultsByTaskName queries the database to find a page of the
+// performance results with the given taskName, time inteval, and optional
+// tags, and returns the cursor of the next page.
+func (dbc *DBConnector) FindPerformanceResultsByTaskName(taskName string, interval util.TimeRange, page PerfPagination, tags ...string) ([]dataModel.APIPerformanceResult, string, error) {
+	info := model.PerformanceResultInfo{
+		TaskName: taskName,
+		Tags:     tags,
+	}
+
+	return dbc.findPerformanceResultsPage(info, interval, page, fmt.Sprintf("task_name '%s'", taskName))
+}
+
+// FindPerformanceResultsByVersion queries the database to find a page of the
+// performance results with the given version, time inteval, and optional
+// tags, and returns the cursor of the next page.
+func (dbc *DBConnector) FindPerformanceResultsByVersion(version string, interval util.TimeRange, page PerfPagination, tags ...string) ([]dataModel.APIPerformanceResult, string, error) {
+	info := model.PerformanceResultInfo{
+		Version: version,
+		Tags:    tags,
+	}
+
+	return dbc.findPerformanceResultsPage(info, interval, page, fmt.Sprintf("version '%s'", version))
+}
+
+func (dbc *DBConnector) findPerformanceResultsPage(info model.PerformanceResultInfo, interval util.TimeRange, page PerfPagination, description string) ([]dataModel.APIPerformanceResult, string, error) {
 	results := model.PerformanceResults{}
 	results.Setup(dbc.env)
 
 	options := model.PerfFindOptions{
 		Interval: interval,
-		Info: model.PerformanceResultInfo{
-			TaskID: taskId,
-			Tags:   tags,
-		},
+		Info:     info,
 		// set MaxDepth to -1 to avoid recursive child search
-		MaxDepth: -1,
+		MaxDepth:   -1,
+		Limit:      page.Limit,
+		Sort:       model.PerfSortKey(page.Sort),
+		Descending: page.Descending,
+		Cursor:     page.Cursor,
+	}
+	if err := options.Validate(); err != nil {
+		return nil, "", gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
 	}
 
 	if err := results.Find(options); err != nil {
-		return nil, gimlet.ErrorResponse{
+		return nil, "", gimlet.ErrorResponse{
 			StatusCode: http.StatusInternalServerError,
 			Message:    fmt.Sprintf("database error"),
 		}
 	}
 	if results.IsNil() {
-		return nil, gimlet.ErrorResponse{
+		return nil, "", gimlet.ErrorResponse{
 			StatusCode: http.StatusNotFound,
-			Message:    fmt.Sprintf("performance results with task_id '%s' not found", taskId),
+			Message:    fmt.Sprintf("performance results with %s not found", description),
 		}
 	}
 
@@ -69,29 +119,29 @@ func (dbc *DBConnector) FindPerformanceResultsByTaskId(taskId string, interval u
 	for i, result := range results.Results {
 		err := apiResults[i].Import(result)
 		if err != nil {
-			return nil, gimlet.ErrorResponse{
+			return nil, "", gimlet.ErrorResponse{
 				StatusCode: http.StatusInternalServerError,
 				Message:    fmt.Sprintf("corrupt data"),
 			}
 		}
 	}
-	return apiResults, nil
+	return apiResults, results.NextCursor, nil
 }
 
-// FindPerformanceResultsByTaskName queries the database to find all performance
-// results with the given taskName, time inteval, and optional tags.
-func (dbc *DBConnector) FindPerformanceResultsByTaskName(taskName string, interval util.TimeRange, tags ...string) ([]dataModel.APIPerformanceResult, error) {
+// FindPerformanceResultsByTaskId queries the database to find a performance
+// result, based on its id, and its children up to maxDepth and filtered by the
+// optional tags.
+func (dbc *DBConnector) FindPerformanceResultWithChildren(id string, maxDepth int, tags ...string) ([]dataModel.APIPerformanceResult, error) {
 	results := model.PerformanceResults{}
 	results.Setup(dbc.env)
 
 	options := model.PerfFindOptions{
-		Interval: interval,
 		Info: model.PerformanceResultInfo{
-			TaskName: taskName,
-			Tags:     tags,
+			Parent: id,
+			Tags:   tags,
 		},
-		// set MaxDepth to -1 to avoid recursive child search
-		MaxDepth: -1,
+		MaxDepth:    maxDepth,
+		GraphLookup: true,
 	}
 
 	if err := results.Find(options); err != nil {
@@ -103,7 +153,7 @@ func (dbc *DBConnector) FindPerformanceResultsByTaskName(taskName string, interv
 	if results.IsNil() {
 		return nil, gimlet.ErrorResponse{
 			StatusCode: http.StatusNotFound,
-			Message:    fmt.Sprintf("performance results with task_name '%s' not found", taskName),
+			Message:    fmt.Sprintf("performance result with id '%s' not found", id),
 		}
 	}
 
@@ -120,88 +170,115 @@ func (dbc *DBConnector) FindPerformanceResultsByTaskName(taskName string, interv
 	return apiResults, nil
 }
 
-// FindPerformanceResultsByTaskId queries the database to find all performance
-// results with the given version, time inteval, and optional tags.
-func (dbc *DBConnector) FindPerformanceResultsByVersion(version string, interval util.TimeRange, tags ...string) ([]dataModel.APIPerformanceResult, error) {
-	results := model.PerformanceResults{}
-	results.Setup(dbc.env)
+// FindPerformanceResultHistograms merges the histogram-schema FTDC
+// artifacts of the performance result with the given id and returns
+// the resulting histograms.
+func (dbc *DBConnector) FindPerformanceResultHistograms(ctx context.Context, id string) ([]dataModel.APIPerformanceHistogram, error) {
+	result := model.PerformanceResult{}
+	result.Setup(dbc.env)
+	result.ID = id
 
-	options := model.PerfFindOptions{
-		Interval: interval,
-		Info: model.PerformanceResultInfo{
-			Version: version,
-			Tags:    tags,
-		},
-		// set MaxDepth to -1 to avoid recursive child search
-		MaxDepth: -1,
+	if err := result.Find(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("performance result with id '%s' not found", id),
+		}
 	}
 
-	if err := results.Find(options); err != nil {
+	return mergeHistogramArtifacts(ctx, dbc.env, id, result.Artifacts)
+}
+
+// FindPerformanceResultTimeseries opens the decompressed raw events
+// FTDC artifacts of the performance result with the given id as a
+// single stream. Callers must close the reader.
+func (dbc *DBConnector) FindPerformanceResultTimeseries(ctx context.Context, id string) (io.ReadCloser, error) {
+	result := model.PerformanceResult{}
+	result.Setup(dbc.env)
+	result.ID = id
+
+	if err := result.Find(); err != nil {
 		return nil, gimlet.ErrorResponse{
-			StatusCode: http.StatusInternalServerError,
-			Message:    fmt.Sprintf("database error"),
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("performance result with id '%s' not found", id),
 		}
 	}
-	if results.IsNil() {
+
+	return openTimeseriesArtifacts(ctx, dbc.env, id, result.Artifacts)
+}
+
+// FindPerformanceResultChart returns the downsampled timeseries of
+// the raw events FTDC artifacts of the performance result with the
+// given id. Charts are cached in the database once computed.
+func (dbc *DBConnector) FindPerformanceResultChart(ctx context.Context, id string, opts perf.ChartOptions) (*dataModel.APIPerfChart, error) {
+	result := model.PerformanceResult{}
+	result.Setup(dbc.env)
+	result.ID = id
+
+	if err := result.Find(); err != nil {
 		return nil, gimlet.ErrorResponse{
 			StatusCode: http.StatusNotFound,
-			Message:    fmt.Sprintf("performance results with version '%s' not found", version),
+			Message:    fmt.Sprintf("performance result with id '%s' not found", id),
 		}
 	}
 
-	apiResults := make([]dataModel.APIPerformanceResult, len(results.Results))
-	for i, result := range results.Results {
-		err := apiResults[i].Import(result)
+	artifacts, err := timeseriesArtifacts(id, result.Artifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	chart := model.CreatePerfChart(id, opts.Metric, opts.MaxPoints, artifacts)
+	chart.Setup(dbc.env)
+	if err = chart.Find(); err != nil {
+		chart.Series, err = chartTimeseriesArtifacts(ctx, dbc.env, id, artifacts, opts)
 		if err != nil {
-			return nil, gimlet.ErrorResponse{
-				StatusCode: http.StatusInternalServerError,
-				Message:    fmt.Sprintf("corrupt data"),
-			}
+			return nil, err
 		}
+		grip.Warning(message.WrapError(chart.Save(), message.Fields{
+			"perf_id": id,
+			"metric":  opts.Metric,
+			"points":  opts.MaxPoints,
+			"message": "problem caching chart",
+		}))
 	}
-	return apiResults, nil
+
+	return importPerfChart(chart, opts.Metric)
 }
 
-// FindPerformanceResultsByTaskId queries the database to find a performance
-// result, based on its id, and its children up to maxDepth and filtered by the
-// optional tags.
-func (dbc *DBConnector) FindPerformanceResultWithChildren(id string, maxDepth int, tags ...string) ([]dataModel.APIPerformanceResult, error) {
-	results := model.PerformanceResults{}
-	results.Setup(dbc.env)
+// FindPerformanceResultRollupHistory returns the current value and
+// every computation of the named rollup of the performance result with
+// the given id.
+func (dbc *DBConnector) FindPerformanceResultRollupHistory(id, name string) (*dataModel.APIPerfRollupHistory, error) {
+	result, err := dbc.findPerformanceResult(id)
+	if err != nil {
+		return nil, err
+	}
 
-	options := model.PerfFindOptions{
-		Info: model.PerformanceResultInfo{
-			Parent: id,
-			Tags:   tags,
-		},
-		MaxDepth:    maxDepth,
-		GraphLookup: true,
+	history, err := dbc.findRollupHistory(result, name)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := results.Find(options); err != nil {
-		return nil, gimlet.ErrorResponse{
-			StatusCode: http.StatusInternalServerError,
-			Message:    fmt.Sprintf("database error"),
-		}
+	return importRollupHistory(history, id)
+}
+
+// findRollupHistory returns the history of the named rollup of the
+// performance result.
+func (dbc *DBConnector) findRollupHistory(result *model.PerformanceResult, name string) (*model.PerfRollupHistory, error) {
+	if result.Rollups == nil {
+		return &model.PerfRollupHistory{Name: name}, nil
 	}
-	if results.IsNil() {
+
+	result.Rollups.Setup(dbc.env)
+	history, err := result.Rollups.FindHistory(name)
+	if err != nil {
+		grip.Warning(err)
 		return nil, gimlet.ErrorResponse{
-			StatusCode: http.StatusNotFound,
-			Message:    fmt.Sprintf("performance result with id '%s' not found", id),
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem finding history of rollup '%s' of '%s'", name, result.ID),
 		}
 	}
 
-	apiResults := make([]dataModel.APIPerformanceResult, len(results.Results))
-	for i, result := range results.Results {
-		err := apiResults[i].Import(result)
-		if err != nil {
-			return nil, gimlet.ErrorResponse{
-				StatusCode: http.StatusInternalServerError,
-				Message:    fmt.Sprintf("corrupt data"),
-			}
-		}
-	}
-	return apiResults, nil
+	return history, nil
 }
 
 // MockConnector Implementation
@@ -217,7 +294,7 @@ func (mc *MockConnector) FindPerformanceResultById(id string) (*dataModel.APIPer
 	return &result, nil
 }
 
-func (mc *MockConnector) FindPerformanceResultsByTaskId(taskId string, interval util.TimeRange, tags ...string) ([]dataModel.APIPerformanceResult, error) {
+func (mc *MockConnector) FindPerformanceResultsByTaskId(taskId string, interval util.TimeRange, page PerfPagination, tags ...string) ([]dataModel.APIPerformanceResult, string, error) {
 	results := []dataModel.APIPerformanceResult{}
 	for _, result := range mc.CachedPerformanceResults {
 		if *result.Info.TaskID == taskId && mc.checkInterval(*result.Name, interval) && mc.checkTags(*result.Name, tags) {
@@ -225,16 +302,10 @@ func (mc *MockConnector) FindPerformanceResultsByTaskId(taskId string, interval
 		}
 	}
 
-	if len(results) == 0 {
-		return nil, gimlet.ErrorResponse{
-			StatusCode: http.StatusNotFound,
-			Message:    fmt.Sprintf("performance result with task_id '%s' not found", taskId),
-		}
-	}
-	return results, nil
+	return pagePerformanceResults(results, page, fmt.Sprintf("task_id '%s'", taskId))
 }
 
-func (mc *MockConnector) FindPerformanceResultsByTaskName(taskName string, interval util.TimeRange, tags ...string) ([]dataModel.APIPerformanceResult, error) {
+func (mc *MockConnector) FindPerformanceResultsByTaskName(taskName string, interval util.TimeRange, page PerfPagination, tags ...string) ([]dataModel.APIPerformanceResult, string, error) {
 	results := []dataModel.APIPerformanceResult{}
 	for _, result := range mc.CachedPerformanceResults {
 		if *result.Info.TaskName == taskName && mc.checkInterval(*result.Name, interval) && mc.checkTags(*result.Name, tags) {
@@ -242,17 +313,10 @@ func (mc *MockConnector) FindPerformanceResultsByTaskName(taskName string, inter
 		}
 	}
 
-	if len(results) == 0 {
-		return nil, gimlet.ErrorResponse{
-			StatusCode: http.StatusNotFound,
-			Message:    fmt.Sprintf("performance result with task_name '%s' not found", taskName),
-		}
-	}
-	return results, nil
-
+	return pagePerformanceResults(results, page, fmt.Sprintf("task_name '%s'", taskName))
 }
 
-func (mc *MockConnector) FindPerformanceResultsByVersion(version string, interval util.TimeRange, tags ...string) ([]dataModel.APIPerformanceResult, error) {
+func (mc *MockConnector) FindPerformanceResultsByVersion(version string, interval util.TimeRange, page PerfPagination, tags ...string) ([]dataModel.APIPerformanceResult, string, error) {
 	results := []dataModel.APIPerformanceResult{}
 	for _, result := range mc.CachedPerformanceResults {
 		if *result.Info.Version == version && mc.checkInterval(*result.Name, interval) && mc.checkTags(*result.Name, tags) {
@@ -260,13 +324,96 @@ func (mc *MockConnector) FindPerformanceResultsByVersion(version string, interva
 		}
 	}
 
+	return pagePerformanceResults(results, page, fmt.Sprintf("version '%s'", version))
+}
+
+// pagePerformanceResults sorts the results and returns the requested
+// page, in the same way as the database implementation.
+func pagePerformanceResults(results []dataModel.APIPerformanceResult, page PerfPagination, description string) ([]dataModel.APIPerformanceResult, string, error) {
+	options := model.PerfFindOptions{
+		Limit:      page.Limit,
+		Sort:       model.PerfSortKey(page.Sort),
+		Descending: page.Descending,
+		Cursor:     page.Cursor,
+	}
+	if err := options.Validate(); err != nil {
+		return nil, "", gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	// less orders the results by sort value and then by name,
+	// reversed for a descending sort.
+	less := func(value interface{}, name string, otherValue interface{}, otherName string) bool {
+		cmp := compareSortValues(value, otherValue)
+		if cmp == 0 {
+			cmp = strings.Compare(name, otherName)
+		}
+		if options.Descending {
+			return cmp > 0
+		}
+		return cmp < 0
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return less(mockSortValue(results[i], options.Sort), *results[i].Name, mockSortValue(results[j], options.Sort), *results[j].Name)
+	})
+
+	if options.Cursor != "" {
+		cursor, _ := model.ParsePerfCursor(options.Cursor)
+		start := sort.Search(len(results), func(i int) bool {
+			return less(cursor.Value, cursor.ID, mockSortValue(results[i], options.Sort), *results[i].Name)
+		})
+		results = results[start:]
+	}
+
 	if len(results) == 0 {
-		return nil, gimlet.ErrorResponse{
+		return nil, "", gimlet.ErrorResponse{
 			StatusCode: http.StatusNotFound,
-			Message:    fmt.Sprintf("performance result with version '%s' not found", version),
+			Message:    fmt.Sprintf("performance result with %s not found", description),
 		}
 	}
-	return results, nil
+
+	next := ""
+	if options.Limit > 0 && len(results) > options.Limit {
+		results = results[:options.Limit]
+		last := results[len(results)-1]
+		next = model.NewPerfCursor(options.Sort, options.Descending, mockSortValue(last, options.Sort), *last.Name).String()
+	}
+
+	return results, next, nil
+}
+
+// mockSortValue returns the value by which the result is sorted.
+// Times are truncated to milliseconds, as they are in the database and
+// in cursors.
+func mockSortValue(result dataModel.APIPerformanceResult, key model.PerfSortKey) interface{} {
+	switch key {
+	case model.PerfSortCompletedAt:
+		return time.Time(result.CompletedAt).Truncate(time.Millisecond)
+	case model.PerfSortTestName:
+		return dataModel.FromAPIString(result.Info.TestName)
+	default:
+		return time.Time(result.CreatedAt).Truncate(time.Millisecond)
+	}
+}
+
+func compareSortValues(a, b interface{}) int {
+	switch av := a.(type) {
+	case time.Time:
+		bv, _ := b.(time.Time)
+		switch {
+		case av.Before(bv):
+			return -1
+		case av.After(bv):
+			return 1
+		}
+	case string:
+		bv, _ := b.(string)
+		return strings.Compare(av, bv)
+	}
+
+	return 0
 }
 
 func (mc *MockConnector) FindPerformanceResultWithChildren(id string, maxDepth int, tags ...string) ([]dataModel.APIPerformanceResult, error) {
@@ -281,6 +428,252 @@ func (mc *MockConnector) FindPerformanceResultWithChildren(id string, maxDepth i
 	return append(results, mc.findChildren(id, maxDepth, tags)...), nil
 }
 
+func (mc *MockConnector) FindPerformanceResultHistograms(ctx context.Context, id string) ([]dataModel.APIPerformanceHistogram, error) {
+	result, err := mc.FindPerformanceResultById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeHistogramArtifacts(ctx, nil, id, exportMockArtifacts(result.Artifacts))
+}
+
+func (mc *MockConnector) FindPerformanceResultTimeseries(ctx context.Context, id string) (io.ReadCloser, error) {
+	result, err := mc.FindPerformanceResultById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return openTimeseriesArtifacts(ctx, nil, id, exportMockArtifacts(result.Artifacts))
+}
+
+func (mc *MockConnector) FindPerformanceResultChart(ctx context.Context, id string, opts perf.ChartOptions) (*dataModel.APIPerfChart, error) {
+	result, err := mc.FindPerformanceResultById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	artifacts, err := timeseriesArtifacts(id, exportMockArtifacts(result.Artifacts))
+	if err != nil {
+		return nil, err
+	}
+
+	chart := model.CreatePerfChart(id, opts.Metric, opts.MaxPoints, artifacts)
+	chart.Series, err = chartTimeseriesArtifacts(ctx, nil, id, artifacts, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return importPerfChart(chart, opts.Metric)
+}
+
+func (mc *MockConnector) FindPerformanceResultRollupHistory(id, name string) (*dataModel.APIPerfRollupHistory, error) {
+	result, err := mc.FindPerformanceResultById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &model.PerfRollupHistory{Name: name, Values: []model.PerfRollupValue{}}
+	for _, rollup := range exportMockRollups(result.Rollups).Stats {
+		if rollup.Name == name {
+			latest := rollup
+			history.Latest = &latest
+			break
+		}
+	}
+	for _, rollup := range exportMockRollupValues(mc.CachedPerfRollupHistory[id]) {
+		if rollup.Name == name {
+			history.Values = append(history.Values, rollup)
+		}
+	}
+
+	return importRollupHistory(history, id)
+}
+
+// exportMockArtifacts converts the artifacts of a cached result back
+// into the database model, without validating them.
+func exportMockArtifacts(apiArtifacts []dataModel.APIArtifactInfo) []model.ArtifactInfo {
+	artifacts := make([]model.ArtifactInfo, len(apiArtifacts))
+	for i, artifact := range apiArtifacts {
+		artifacts[i] = model.ArtifactInfo{
+			Type:        model.PailType(dataModel.FromAPIString(artifact.Type)),
+			Bucket:      dataModel.FromAPIString(artifact.Bucket),
+			Path:        dataModel.FromAPIString(artifact.Path),
+			Format:      model.FileDataFormat(dataModel.FromAPIString(artifact.Format)),
+			Compression: model.FileCompression(dataModel.FromAPIString(artifact.Compression)),
+			Schema:      model.FileSchema(dataModel.FromAPIString(artifact.Schema)),
+		}
+	}
+
+	return artifacts
+}
+
+func exportMockRollups(apiRollups *dataModel.APIPerfRollups) *model.PerfRollups {
+	rollups := &model.PerfRollups{}
+	if apiRollups == nil {
+		return rollups
+	}
+
+	rollups.Stats = exportMockRollupValues(apiRollups.Stats)
+	rollups.ProcessedAt = time.Time(apiRollups.ProcessedAt)
+	rollups.Count = apiRollups.Count
+	rollups.Valid = apiRollups.Valid
+
+	return rollups
+}
+
+func exportMockRollupValues(apiRollups []dataModel.APIPerfRollupValue) []model.PerfRollupValue {
+	var rollups []model.PerfRollupValue
+	for _, apiRollup := range apiRollups {
+		rollups = append(rollups, model.PerfRollupValue{
+			Name:          dataModel.FromAPIString(apiRollup.Name),
+			Value:         apiRollup.Value,
+			Version:       apiRollup.Version,
+			MetricType:    model.MetricType(dataModel.FromAPIString(apiRollup.MetricType)),
+			UserSubmitted: apiRollup.UserSubmitted,
+			Calculator:    dataModel.FromAPIString(apiRollup.Calculator),
+			CalculatedAt:  time.Time(apiRollup.CalculatedAt),
+		})
+	}
+
+	return rollups
+}
+
+// importRollupHistory returns the history of the rollup, or a not found
+// error if the result does not have the rollup.
+func importRollupHistory(rollupHistory *model.PerfRollupHistory, id string) (*dataModel.APIPerfRollupHistory, error) {
+	history := &dataModel.APIPerfRollupHistory{}
+	if err := history.Import(rollupHistory); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("corrupt data"),
+		}
+	}
+	if history.Latest == nil && len(history.History) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("rollup '%s' of performance result '%s' not found", rollupHistory.Name, id),
+		}
+	}
+
+	return history, nil
+}
+
+// timeseriesArtifacts returns the raw events FTDC artifacts of the
+// result, or a not found error if it has none.
+func timeseriesArtifacts(id string, artifacts []model.ArtifactInfo) ([]model.ArtifactInfo, error) {
+	out := []model.ArtifactInfo{}
+	for _, artifact := range artifacts {
+		if artifact.Format == model.FileFTDC && artifact.Schema == model.SchemaRawEvents {
+			out = append(out, artifact)
+		}
+	}
+	if len(out) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("performance result with id '%s' has no timeseries data", id),
+		}
+	}
+
+	return out, nil
+}
+
+func openTimeseriesArtifacts(ctx context.Context, env cedar.Environment, id string, artifacts []model.ArtifactInfo) (io.ReadCloser, error) {
+	timeseriesArtifacts, err := timeseriesArtifacts(id, artifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := model.OpenArtifacts(ctx, env, timeseriesArtifacts)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem reading timeseries data for '%s'", id),
+		}
+	}
+
+	return r, nil
+}
+
+func chartTimeseriesArtifacts(ctx context.Context, env cedar.Environment, id string, artifacts []model.ArtifactInfo, opts perf.ChartOptions) ([]model.PerfChartSeries, error) {
+	series, err := perf.ChartTimeseries(ctx, func() (io.ReadCloser, error) {
+		return model.OpenArtifacts(ctx, env, artifacts)
+	}, opts)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem charting timeseries data for '%s'", id),
+		}
+	}
+
+	return series, nil
+}
+
+// importPerfChart converts the chart into its API model, or returns a
+// not found error if the chart is of a metric that the result does
+// not have.
+func importPerfChart(chart *model.PerfChart, metric string) (*dataModel.APIPerfChart, error) {
+	if metric != "" && len(chart.Series) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("metric '%s' of performance result '%s' not found", metric, chart.PerfID),
+		}
+	}
+
+	apiChart := &dataModel.APIPerfChart{}
+	if err := apiChart.Import(*chart); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("corrupt data"),
+		}
+	}
+
+	return apiChart, nil
+}
+
+func mergeHistogramArtifacts(ctx context.Context, env cedar.Environment, id string, artifacts []model.ArtifactInfo) ([]dataModel.APIPerformanceHistogram, error) {
+	histogramArtifacts := []model.ArtifactInfo{}
+	for _, artifact := range artifacts {
+		if artifact.Format == model.FileFTDC && artifact.Schema == model.SchemaHistogram {
+			histogramArtifacts = append(histogramArtifacts, artifact)
+		}
+	}
+	if len(histogramArtifacts) == 0 {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("performance result with id '%s' has no histogram data", id),
+		}
+	}
+
+	r, err := model.OpenArtifacts(ctx, env, histogramArtifacts)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem reading histogram data for '%s': %s", id, err.Error()),
+		}
+	}
+	defer r.Close()
+
+	histograms, err := perf.MergeHistograms(ftdc.ReadChunks(ctx, r))
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("corrupt histogram data for '%s': %s", id, err.Error()),
+		}
+	}
+
+	names := make([]string, 0, len(histograms))
+	for name := range histograms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	apiHistograms := make([]dataModel.APIPerformanceHistogram, len(names))
+	for i, name := range names {
+		apiHistograms[i] = dataModel.NewAPIPerformanceHistogram(name, histograms[name])
+	}
+	return apiHistograms, nil
+}
+
 func (mc *MockConnector) checkInterval(id string, interval util.TimeRange) bool {
 	result, _ := mc.CachedPerformanceResults[id]
 	createdAt := time.Time(result.CreatedAt)
@@ -0,0 +1,277 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	dataModel "github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+)
+
+// FindPerformanceBaseline queries the database for the baseline of the
+// given project, variant and task.
+func (dbc *DBConnector) FindPerformanceBaseline(project, variant, task string) (*dataModel.APIPerfBaseline, error) {
+	key, err := exportBaselineKey(project, variant, task)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline, err := dbc.findBaseline(key)
+	if err != nil {
+		return nil, err
+	}
+	if baseline.IsNil() {
+		return nil, baselineNotFound(key)
+	}
+
+	return importBaseline(*baseline)
+}
+
+// SetPerformanceBaseline sets the baseline of the given project,
+// variant and task to a version, or to a specific performance result
+// of the task, in which case the version of the result is recorded
+// as the version of the baseline.
+func (dbc *DBConnector) SetPerformanceBaseline(project, variant, task string, apiChange dataModel.APIPerfBaselineChange) (*dataModel.APIPerfBaseline, error) {
+	key, err := exportBaselineKey(project, variant, task)
+	if err != nil {
+		return nil, err
+	}
+	change, err := exportBaselineChange(apiChange)
+	if err != nil {
+		return nil, err
+	}
+
+	if change.PerfResultID != "" {
+		result, err := dbc.findPerformanceResult(change.PerfResultID)
+		if err != nil {
+			return nil, err
+		}
+		if err = checkBaselineResult(key, &change, *result); err != nil {
+			return nil, err
+		}
+	} else {
+		results, err := dbc.findResults(model.PerformanceResultInfo{
+			Project:  key.Project,
+			Version:  change.Version,
+			TaskName: key.TaskName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if !hasBaselineResults(key, results) {
+			return nil, baselineResultsNotFound(key, change.Version)
+		}
+	}
+
+	// find the baseline first, so that the whole history of the
+	// baseline is returned.
+	baseline, err := dbc.findBaseline(key)
+	if err != nil {
+		return nil, err
+	}
+	if err = baseline.Set(change); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem setting baseline: %s", err.Error()),
+		}
+	}
+
+	return importBaseline(*baseline)
+}
+
+func (dbc *DBConnector) findBaseline(key model.PerfBaselineKey) (*model.PerfBaseline, error) {
+	baseline := model.CreatePerfBaseline(key)
+	baseline.Setup(dbc.env)
+	if err := baseline.Find(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("database error"),
+		}
+	}
+
+	return baseline, nil
+}
+
+// MockConnector Implementation
+
+func (mc *MockConnector) FindPerformanceBaseline(project, variant, task string) (*dataModel.APIPerfBaseline, error) {
+	key, err := exportBaselineKey(project, variant, task)
+	if err != nil {
+		return nil, err
+	}
+
+	idx := mc.findBaseline(key)
+	if idx < 0 {
+		return nil, baselineNotFound(key)
+	}
+	baseline := mc.CachedPerfBaselines[idx]
+
+	return &baseline, nil
+}
+
+func (mc *MockConnector) SetPerformanceBaseline(project, variant, task string, apiChange dataModel.APIPerfBaselineChange) (*dataModel.APIPerfBaseline, error) {
+	key, err := exportBaselineKey(project, variant, task)
+	if err != nil {
+		return nil, err
+	}
+	change, err := exportBaselineChange(apiChange)
+	if err != nil {
+		return nil, err
+	}
+
+	if change.PerfResultID != "" {
+		result, ok := mc.CachedPerformanceResults[change.PerfResultID]
+		if !ok {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusNotFound,
+				Message:    fmt.Sprintf("performance result with id '%s' not found", change.PerfResultID),
+			}
+		}
+		if err = checkBaselineResult(key, &change, exportPerformanceResult(result)); err != nil {
+			return nil, err
+		}
+	} else {
+		results := []model.PerformanceResult{}
+		for _, result := range mc.CachedPerformanceResults {
+			if dataModel.FromAPIString(result.Info.Version) == change.Version {
+				results = append(results, exportPerformanceResult(result))
+			}
+		}
+		if !hasBaselineResults(key, results) {
+			return nil, baselineResultsNotFound(key, change.Version)
+		}
+	}
+	change.SetAt = time.Now()
+
+	apiChange = dataModel.APIPerfBaselineChange{}
+	if err = apiChange.Import(change); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("corrupt data"),
+		}
+	}
+
+	idx := mc.findBaseline(key)
+	if idx < 0 {
+		mc.CachedPerfBaselines = append(mc.CachedPerfBaselines, dataModel.APIPerfBaseline{
+			Project:  dataModel.ToAPIString(key.Project),
+			Variant:  dataModel.ToAPIString(key.Variant),
+			TaskName: dataModel.ToAPIString(key.TaskName),
+		})
+		idx = len(mc.CachedPerfBaselines) - 1
+	}
+	baseline := &mc.CachedPerfBaselines[idx]
+	baseline.Version = apiChange.Version
+	baseline.PerfResultID = apiChange.PerfResultID
+	baseline.SetBy = apiChange.SetBy
+	baseline.SetAt = apiChange.SetAt
+	baseline.History = append(baseline.History, apiChange)
+
+	out := *baseline
+	return &out, nil
+}
+
+// findBaseline returns the index of the cached baseline of the key, or
+// -1 if none is cached.
+func (mc *MockConnector) findBaseline(key model.PerfBaselineKey) int {
+	for idx, baseline := range mc.CachedPerfBaselines {
+		if dataModel.FromAPIString(baseline.Project) == key.Project &&
+			dataModel.FromAPIString(baseline.Variant) == key.Variant &&
+			dataModel.FromAPIString(baseline.TaskName) == key.TaskName {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+func exportBaselineKey(project, variant, task string) (model.PerfBaselineKey, error) {
+	key := model.PerfBaselineKey{
+		Project:  project,
+		Variant:  variant,
+		TaskName: task,
+	}
+	if err := key.Validate(); err != nil {
+		return key, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid baseline: %s", err.Error()),
+		}
+	}
+
+	return key, nil
+}
+
+func exportBaselineChange(apiChange dataModel.APIPerfBaselineChange) (model.PerfBaselineChange, error) {
+	change, err := apiChange.Export()
+	if err != nil {
+		return change, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid baseline: %s", err.Error()),
+		}
+	}
+
+	return change, nil
+}
+
+// checkBaselineResult checks that the result belongs to the task of
+// the baseline and to the version of the change, if any, and sets the
+// version of the change to the version of the result.
+func checkBaselineResult(key model.PerfBaselineKey, change *model.PerfBaselineChange, result model.PerformanceResult) error {
+	if result.Info.Project != key.Project || result.Info.Variant != key.Variant || result.Info.TaskName != key.TaskName {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("performance result '%s' does not belong to %s", result.ID, baselineDescription(key)),
+		}
+	}
+	if change.Version != "" && change.Version != result.Info.Version {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("performance result '%s' does not belong to version '%s'", result.ID, change.Version),
+		}
+	}
+	change.Version = result.Info.Version
+
+	return nil
+}
+
+func hasBaselineResults(key model.PerfBaselineKey, results []model.PerformanceResult) bool {
+	for _, result := range results {
+		if result.Info.Project == key.Project && result.Info.Variant == key.Variant && result.Info.TaskName == key.TaskName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func importBaseline(baseline model.PerfBaseline) (*dataModel.APIPerfBaseline, error) {
+	apiBaseline := &dataModel.APIPerfBaseline{}
+	if err := apiBaseline.Import(baseline); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("corrupt data"),
+		}
+	}
+
+	return apiBaseline, nil
+}
+
+func baselineNotFound(key model.PerfBaselineKey) error {
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("baseline for %s not found", baselineDescription(key)),
+	}
+}
+
+func baselineResultsNotFound(key model.PerfBaselineKey, version string) error {
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("performance results of version '%s' for %s not found", version, baselineDescription(key)),
+	}
+}
+
+func baselineDescription(key model.PerfBaselineKey) string {
+	return fmt.Sprintf("project '%s', variant '%s' and task '%s'", key.Project, key.Variant, key.TaskName)
+}
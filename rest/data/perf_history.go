@@ -0,0 +1,193 @@
+package data
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	dataModel "github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+)
+
+// FindPerformanceHistory queries the database for the history of a
+// rollup of a test series in the given project, in chronological
+// order.
+func (dbc *DBConnector) FindPerformanceHistory(project string, filter PerfHistoryFilter) ([]dataModel.APIPerfHistoryPoint, error) {
+	history := model.PerfHistory{
+		Key: model.PerfSeriesKey{
+			Project:   project,
+			Variant:   filter.Variant,
+			TaskName:  filter.TaskName,
+			TestName:  filter.TestName,
+			Arguments: filter.Arguments,
+		},
+		Metric: filter.Metric,
+	}
+	history.Setup(dbc.env)
+
+	if err := history.Find(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("database error"),
+		}
+	}
+
+	apiPoints := make([]dataModel.APIPerfHistoryPoint, len(history.Points))
+	for i, point := range history.Points {
+		if err := apiPoints[i].Import(point); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    fmt.Sprintf("corrupt data"),
+			}
+		}
+	}
+
+	baseline, err := dbc.findBaseline(history.Key.BaselineKey())
+	if err != nil {
+		return nil, err
+	}
+	if !baseline.IsNil() {
+		compareHistoryToBaseline(apiPoints, baseline.Version, baseline.PerfResultID)
+	}
+
+	return apiPoints, nil
+}
+
+// MockConnector Implementation
+
+func (mc *MockConnector) FindPerformanceHistory(project string, filter PerfHistoryFilter) ([]dataModel.APIPerfHistoryPoint, error) {
+	type candidate struct {
+		result dataModel.APIPerformanceResult
+		value  interface{}
+	}
+
+	// keep the latest execution and first trial of each version
+	versions := map[string]candidate{}
+	for _, result := range mc.CachedPerformanceResults {
+		info := result.Info
+		if dataModel.FromAPIString(info.Project) != project ||
+			dataModel.FromAPIString(info.Variant) != filter.Variant ||
+			dataModel.FromAPIString(info.TaskName) != filter.TaskName ||
+			dataModel.FromAPIString(info.TestName) != filter.TestName ||
+			!sameArguments(info.Arguments, filter.Arguments) ||
+			result.Rollups == nil {
+			continue
+		}
+
+		found := false
+		var value interface{}
+		for _, stat := range result.Rollups.Stats {
+			if dataModel.FromAPIString(stat.Name) == filter.Metric {
+				found = true
+				value = stat.Value
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+
+		version := dataModel.FromAPIString(info.Version)
+		prev, ok := versions[version]
+		if !ok || info.Execution > prev.result.Info.Execution ||
+			(info.Execution == prev.result.Info.Execution && info.Trial < prev.result.Info.Trial) {
+			versions[version] = candidate{result: result, value: value}
+		}
+	}
+
+	apiPoints := []dataModel.APIPerfHistoryPoint{}
+	for version, c := range versions {
+		apiPoints = append(apiPoints, dataModel.APIPerfHistoryPoint{
+			Version:      dataModel.ToAPIString(version),
+			PerfResultID: c.result.Name,
+			CreatedAt:    c.result.CreatedAt,
+			Value:        c.value,
+		})
+	}
+	sort.Slice(apiPoints, func(i, j int) bool {
+		ti, tj := time.Time(apiPoints[i].CreatedAt), time.Time(apiPoints[j].CreatedAt)
+		if ti.Equal(tj) {
+			return *apiPoints[i].Version < *apiPoints[j].Version
+		}
+		return ti.Before(tj)
+	})
+
+	key := model.PerfBaselineKey{Project: project, Variant: filter.Variant, TaskName: filter.TaskName}
+	if idx := mc.findBaseline(key); idx >= 0 {
+		baseline := mc.CachedPerfBaselines[idx]
+		compareHistoryToBaseline(apiPoints, dataModel.FromAPIString(baseline.Version), dataModel.FromAPIString(baseline.PerfResultID))
+	}
+
+	return apiPoints, nil
+}
+
+// compareHistoryToBaseline flags the point of the baseline and sets the
+// change of every numeric point relative to it. The point of the
+// result of the baseline is preferred, and otherwise the latest point
+// of the version of the baseline is used. There is no change relative
+// to a baseline of zero.
+func compareHistoryToBaseline(points []dataModel.APIPerfHistoryPoint, version, perfID string) {
+	baseIdx := -1
+	if perfID != "" {
+		for i := range points {
+			if dataModel.FromAPIString(points[i].PerfResultID) == perfID {
+				baseIdx = i
+				break
+			}
+		}
+	}
+	for i := len(points) - 1; i >= 0 && baseIdx < 0; i-- {
+		if dataModel.FromAPIString(points[i].Version) == version {
+			baseIdx = i
+		}
+	}
+	if baseIdx < 0 {
+		return
+	}
+
+	points[baseIdx].Baseline = true
+	base, ok := historyValue(points[baseIdx].Value)
+	if !ok || base == 0 {
+		return
+	}
+
+	for i := range points {
+		if val, ok := historyValue(points[i].Value); ok {
+			delta := 100 * (val - base) / math.Abs(base)
+			points[i].BaselinePercentDelta = &delta
+		}
+	}
+}
+
+func historyValue(val interface{}) (float64, bool) {
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func sameArguments(a, b map[string]int32) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for key, val := range a {
+		if other, ok := b[key]; !ok || other != val {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,50 @@
+package data
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/evergreen-ci/cedar/model"
+	dataModel "github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/gimlet"
+)
+
+// FindNoisyPerfSeries queries the database to find the measurements
+// of the series in the given project that are too noisy for
+// regressions to be detected reliably, noisiest first.
+func (dbc *DBConnector) FindNoisyPerfSeries(project string) ([]dataModel.APIPerfNoise, error) {
+	scores := model.PerfNoiseScores{}
+	scores.Setup(dbc.env)
+
+	if err := scores.Find(model.PerfNoiseFindOptions{Project: project, NoisyOnly: true}); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("database error"),
+		}
+	}
+
+	apiScores := make([]dataModel.APIPerfNoise, len(scores.Scores))
+	for i, score := range scores.Scores {
+		if err := apiScores[i].Import(score); err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusInternalServerError,
+				Message:    fmt.Sprintf("corrupt data"),
+			}
+		}
+	}
+	return apiScores, nil
+}
+
+// MockConnector Implementation
+
+func (mc *MockConnector) FindNoisyPerfSeries(project string) ([]dataModel.APIPerfNoise, error) {
+	scores := []dataModel.APIPerfNoise{}
+	for _, score := range mc.CachedPerfNoise {
+		if dataModel.FromAPIString(score.Project) != project || !score.Noisy {
+			continue
+		}
+		scores = append(scores, score)
+	}
+
+	return scores, nil
+}
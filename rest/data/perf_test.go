@@ -9,6 +9,8 @@ import (
 	dataModel "github.com/evergreen-ci/cedar/rest/model"
 	"github.com/evergreen-ci/cedar/util"
 	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -214,7 +216,7 @@ func (s *PerfConnectorSuite) TestFindPerformanceResultsByTaskId() {
 	s.Require().NoError(err)
 	tr := util.GetTimeRange(time.Time{}, dur)
 
-	actualResult, err := s.sc.FindPerformanceResultsByTaskId(expectedTaskID, tr)
+	actualResult, _, err := s.sc.FindPerformanceResultsByTaskId(expectedTaskID, tr, PerfPagination{})
 	s.Equal(expectedCount, len(actualResult))
 	for _, result := range actualResult {
 		s.Equal(expectedTaskID, *result.Info.TaskID)
@@ -222,7 +224,7 @@ func (s *PerfConnectorSuite) TestFindPerformanceResultsByTaskId() {
 	s.NoError(err)
 
 	// Now with tags
-	actualResult, err = s.sc.FindPerformanceResultsByTaskId(expectedTaskID, tr, "tag1", "tag2")
+	actualResult, _, err = s.sc.FindPerformanceResultsByTaskId(expectedTaskID, tr, PerfPagination{}, "tag1", "tag2")
 	s.True(len(actualResult) < expectedCount)
 	for _, result := range actualResult {
 		s.Equal(expectedTaskID, *result.Info.TaskID)
@@ -238,13 +240,68 @@ func (s *PerfConnectorSuite) TestFindPerformanceResultsByTaskId() {
 	s.NoError(err)
 }
 
+func (s *PerfConnectorSuite) TestFindPerformanceResultsByTaskIdPaginated() {
+	taskID := s.results[0].info.TaskID
+	expected := map[string]bool{}
+	for _, result := range s.results {
+		if result.info.TaskID == taskID {
+			expected[result.info.ID()] = true
+		}
+	}
+	s.Require().True(len(expected) > 2)
+	dur, err := time.ParseDuration("100h")
+	s.Require().NoError(err)
+	tr := util.GetTimeRange(time.Time{}, dur)
+
+	for _, descending := range []bool{false, true} {
+		page := PerfPagination{Limit: 2, Sort: string(model.PerfSortCreatedAt), Descending: descending}
+		seen := map[string]bool{}
+		var last time.Time
+		for {
+			results, next, err := s.sc.FindPerformanceResultsByTaskId(taskID, tr, page)
+			s.Require().NoError(err)
+			s.True(len(results) <= page.Limit)
+			for _, result := range results {
+				s.False(seen[*result.Name], "duplicate result %s", *result.Name)
+				seen[*result.Name] = true
+
+				createdAt := time.Time(result.CreatedAt).Truncate(time.Millisecond)
+				if !last.IsZero() {
+					if descending {
+						s.False(createdAt.After(last))
+					} else {
+						s.False(createdAt.Before(last))
+					}
+				}
+				last = createdAt
+			}
+			if next == "" {
+				break
+			}
+			page.Cursor = next
+		}
+		s.Equal(expected, seen)
+	}
+
+	_, _, err = s.sc.FindPerformanceResultsByTaskId(taskID, tr, PerfPagination{Cursor: "invalid"})
+	s.Error(err)
+	_, _, err = s.sc.FindPerformanceResultsByTaskId(taskID, tr, PerfPagination{Sort: "invalid"})
+	s.Error(err)
+
+	results, next, err := s.sc.FindPerformanceResultsByTaskId(taskID, tr, PerfPagination{Limit: 1})
+	s.Require().NoError(err)
+	s.Len(results, 1)
+	_, _, err = s.sc.FindPerformanceResultsByTaskId(taskID, tr, PerfPagination{Limit: 1, Cursor: next, Descending: true})
+	s.Error(err)
+}
+
 func (s *PerfConnectorSuite) TestFindPerformanceResultsByTaskIdDoesNotExist() {
 	dur, err := time.ParseDuration("100h")
 	s.Require().NoError(err)
 	tr := util.GetTimeRange(time.Time{}, dur)
 
 	var expectedResult []dataModel.APIPerformanceResult
-	actualResult, err := s.sc.FindPerformanceResultsByTaskId("doesNotExist", tr)
+	actualResult, _, err := s.sc.FindPerformanceResultsByTaskId("doesNotExist", tr, PerfPagination{})
 	s.Equal(expectedResult, actualResult)
 	s.Error(err)
 }
@@ -255,7 +312,7 @@ func (s *PerfConnectorSuite) TestFindPerformanceResultsByTaskIdNarrowInterval()
 	tr := util.GetTimeRange(time.Time{}, dur)
 
 	var expectedResult []dataModel.APIPerformanceResult
-	actualResult, err := s.sc.FindPerformanceResultsByTaskId(s.results[0].info.TaskID, tr)
+	actualResult, _, err := s.sc.FindPerformanceResultsByTaskId(s.results[0].info.TaskID, tr, PerfPagination{})
 	s.Equal(expectedResult, actualResult)
 	s.Error(err)
 }
@@ -272,7 +329,7 @@ func (s *PerfConnectorSuite) TestFindPerformanceResultsByTaskName() {
 	s.Require().NoError(err)
 	tr := util.GetTimeRange(time.Time{}, dur)
 
-	actualResult, err := s.sc.FindPerformanceResultsByTaskName(expectedTaskName, tr)
+	actualResult, _, err := s.sc.FindPerformanceResultsByTaskName(expectedTaskName, tr, PerfPagination{})
 	s.Equal(expectedCount, len(actualResult))
 	for _, result := range actualResult {
 		s.Equal(expectedTaskName, *result.Info.TaskName)
@@ -280,7 +337,7 @@ func (s *PerfConnectorSuite) TestFindPerformanceResultsByTaskName() {
 	s.NoError(err)
 
 	// Now with tags
-	actualResult, err = s.sc.FindPerformanceResultsByTaskName(expectedTaskName, tr, "tag1", "tag2")
+	actualResult, _, err = s.sc.FindPerformanceResultsByTaskName(expectedTaskName, tr, PerfPagination{}, "tag1", "tag2")
 	s.True(len(actualResult) < expectedCount)
 	for _, result := range actualResult {
 		s.Equal(expectedTaskName, *result.Info.TaskName)
@@ -302,7 +359,7 @@ func (s *PerfConnectorSuite) TestFindPerformanceResultsByTaskNameDoesNotExist()
 	tr := util.GetTimeRange(time.Time{}, dur)
 
 	var expectedResult []dataModel.APIPerformanceResult
-	actualResult, err := s.sc.FindPerformanceResultsByTaskName("doesNotExist", tr)
+	actualResult, _, err := s.sc.FindPerformanceResultsByTaskName("doesNotExist", tr, PerfPagination{})
 	s.Equal(expectedResult, actualResult)
 	s.Error(err)
 }
@@ -313,7 +370,7 @@ func (s *PerfConnectorSuite) TestFindPerformanceResultsByTaskNameNarrowInterval(
 	tr := util.GetTimeRange(time.Time{}, dur)
 
 	var expectedResult []dataModel.APIPerformanceResult
-	actualResult, err := s.sc.FindPerformanceResultsByTaskName(s.results[0].info.TaskName, tr)
+	actualResult, _, err := s.sc.FindPerformanceResultsByTaskName(s.results[0].info.TaskName, tr, PerfPagination{})
 	s.Equal(expectedResult, actualResult)
 	s.Error(err)
 }
@@ -329,7 +386,7 @@ func (s *PerfConnectorSuite) TestFindPerformanceResultsByVersion() {
 	s.Require().NoError(err)
 	tr := util.GetTimeRange(time.Time{}, dur)
 
-	actualResult, err := s.sc.FindPerformanceResultsByVersion(expectedVersion, tr)
+	actualResult, _, err := s.sc.FindPerformanceResultsByVersion(expectedVersion, tr, PerfPagination{})
 	s.Equal(expectedCount, len(actualResult))
 	for _, result := range actualResult {
 		s.Equal(expectedVersion, *result.Info.Version)
@@ -337,7 +394,7 @@ func (s *PerfConnectorSuite) TestFindPerformanceResultsByVersion() {
 	s.NoError(err)
 
 	// Now with tags
-	actualResult, err = s.sc.FindPerformanceResultsByVersion(expectedVersion, tr, "tag1")
+	actualResult, _, err = s.sc.FindPerformanceResultsByVersion(expectedVersion, tr, PerfPagination{}, "tag1")
 	s.True(len(actualResult) < expectedCount)
 	for _, result := range actualResult {
 		s.Equal(expectedVersion, *result.Info.Version)
@@ -359,7 +416,7 @@ func (s *PerfConnectorSuite) TestFindPerformanceResultsByVersionDoesNotExist() {
 	tr := util.GetTimeRange(time.Time{}, dur)
 
 	var expectedResult []dataModel.APIPerformanceResult
-	actualResult, err := s.sc.FindPerformanceResultsByVersion("doesNotExist", tr)
+	actualResult, _, err := s.sc.FindPerformanceResultsByVersion("doesNotExist", tr, PerfPagination{})
 	s.Equal(expectedResult, actualResult)
 	s.Error(err)
 }
@@ -370,7 +427,7 @@ func (s *PerfConnectorSuite) TestFindPerformanceResultsByVersionNarrowInterval()
 	tr := util.GetTimeRange(time.Time{}, dur)
 
 	var expectedResult []dataModel.APIPerformanceResult
-	actualResult, err := s.sc.FindPerformanceResultsByVersion(s.results[0].info.Version, tr)
+	actualResult, _, err := s.sc.FindPerformanceResultsByVersion(s.results[0].info.Version, tr, PerfPagination{})
 	s.Equal(expectedResult, actualResult)
 	s.Error(err)
 }
@@ -432,3 +489,50 @@ func (s *PerfConnectorSuite) TestFindPerformanceResultWithChildrenDepth() {
 		delete(expectedIds, *result.Name)
 	}
 }
+
+func TestCompareHistoryToBaseline(t *testing.T) {
+	makePoints := func() []dataModel.APIPerfHistoryPoint {
+		points := []dataModel.APIPerfHistoryPoint{}
+		for _, p := range []struct {
+			version string
+			id      string
+			value   interface{}
+		}{
+			{version: "v0", id: "first", value: 100.0},
+			{version: "v1", id: "base", value: 200.0},
+			{version: "v1", id: "base-retry", value: 400.0},
+			{version: "v2", id: "next", value: "text"},
+		} {
+			points = append(points, dataModel.APIPerfHistoryPoint{
+				Version:      dataModel.ToAPIString(p.version),
+				PerfResultID: dataModel.ToAPIString(p.id),
+				Value:        p.value,
+			})
+		}
+		return points
+	}
+
+	points := makePoints()
+	compareHistoryToBaseline(points, "v1", "base")
+	assert.True(t, points[1].Baseline)
+	assert.False(t, points[2].Baseline)
+	require.NotNil(t, points[0].BaselinePercentDelta)
+	assert.Equal(t, -50.0, *points[0].BaselinePercentDelta)
+	require.NotNil(t, points[2].BaselinePercentDelta)
+	assert.Equal(t, 100.0, *points[2].BaselinePercentDelta)
+	assert.Nil(t, points[3].BaselinePercentDelta)
+
+	points = makePoints()
+	compareHistoryToBaseline(points, "v1", "")
+	assert.False(t, points[1].Baseline)
+	assert.True(t, points[2].Baseline)
+	require.NotNil(t, points[1].BaselinePercentDelta)
+	assert.Equal(t, -50.0, *points[1].BaselinePercentDelta)
+
+	points = makePoints()
+	compareHistoryToBaseline(points, "DNE", "DNE")
+	for _, point := range points {
+		assert.False(t, point.Baseline)
+		assert.Nil(t, point.BaselinePercentDelta)
+	}
+}
@@ -0,0 +1,620 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	dataModel "github.com/evergreen-ci/cedar/rest/model"
+	"github.com/evergreen-ci/cedar/units"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/ftdc/events"
+	"github.com/mongodb/grip"
+	"github.com/pkg/errors"
+)
+
+// CreatePerformanceResult creates (or replaces) the performance result
+// described by the info, with its initial artifacts and rollups, and
+// queues a job to calculate rollups from the artifacts.
+func (dbc *DBConnector) CreatePerformanceResult(data dataModel.APIPerformanceResultData) (*dataModel.APIPerformanceResult, error) {
+	info, err := exportPerformanceResultInfo(data.Info)
+	if err != nil {
+		return nil, err
+	}
+	artifacts, err := exportArtifacts(data.Artifacts)
+	if err != nil {
+		return nil, err
+	}
+	rollups, err := exportRollups(data.Rollups)
+	if err != nil {
+		return nil, err
+	}
+
+	result := model.CreatePerformanceResult(info, artifacts)
+	result.Setup(dbc.env)
+	result.CreatedAt = time.Now()
+	if err = result.Save(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem saving performance result '%s'", result.ID),
+		}
+	}
+
+	if err = dbc.addRollups(result, rollups); err != nil {
+		return nil, err
+	}
+
+	if len(artifacts) > 0 {
+		if err = dbc.addFTDCRollupsJob(result); err != nil {
+			return nil, err
+		}
+	}
+
+	return importPerformanceResult(*result)
+}
+
+// AddPerformanceResultArtifacts attaches the artifacts to the
+// performance result with the given id and queues a job to
+// recalculate its rollups.
+func (dbc *DBConnector) AddPerformanceResultArtifacts(id string, apiArtifacts []dataModel.APIArtifactInfo) (*dataModel.APIPerformanceResult, error) {
+	artifacts, err := exportArtifacts(apiArtifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := dbc.findPerformanceResult(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = result.AppendArtifacts(artifacts...); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem attaching artifacts to '%s'", id),
+		}
+	}
+
+	if len(artifacts) > 0 {
+		if err = dbc.addFTDCRollupsJob(result); err != nil {
+			return nil, err
+		}
+	}
+
+	return importPerformanceResult(*result)
+}
+
+// AddPerformanceResultRollups adds the rollups to the performance
+// result with the given id. Each rollup is added to the history of the
+// rollups, and replaces the current value of the rollup with the same
+// name unless that value was calculated by a newer version.
+func (dbc *DBConnector) AddPerformanceResultRollups(id string, apiRollups []dataModel.APIPerfRollupValue) (*dataModel.APIPerformanceResult, error) {
+	rollups, err := exportRollups(apiRollups)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := dbc.findPerformanceResult(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = dbc.addRollups(result, rollups); err != nil {
+		return nil, err
+	}
+
+	return importPerformanceResult(*result)
+}
+
+// RevertPerformanceResultRollup restores the most recent computation
+// of the named rollup by the given version as the current value of the
+// rollup of the performance result with the given id.
+func (dbc *DBConnector) RevertPerformanceResultRollup(id, name string, version int) (*dataModel.APIPerformanceResult, error) {
+	result, err := dbc.findPerformanceResult(id)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := dbc.findRollupHistory(result, name)
+	if err != nil {
+		return nil, err
+	}
+	if !hasRollupVersion(history.Values, version) {
+		return nil, rollupVersionNotFound(id, name, version)
+	}
+
+	result.Rollups.Setup(dbc.env)
+	if err = result.Rollups.Revert(name, version); err != nil {
+		grip.Warning(err)
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem reverting rollup '%s' of '%s'", name, id),
+		}
+	}
+
+	return importPerformanceResult(*result)
+}
+
+// AddPerformanceResultTimeseries stores the timeseries data in the body
+// as a new raw events FTDC artifact of the performance result with the
+// given id, and queues a job to recalculate its rollups. The body is
+// either FTDC data or JSON performance events, depending on the
+// format.
+func (dbc *DBConnector) AddPerformanceResultTimeseries(ctx context.Context, id, format string, body io.Reader) (*dataModel.APIPerformanceResult, error) {
+	if err := validateTimeseriesFormat(format); err != nil {
+		return nil, err
+	}
+
+	result, err := dbc.findPerformanceResult(id)
+	if err != nil {
+		return nil, err
+	}
+
+	artifact, output, err := model.CreateRawEventsArtifact(ctx, dbc.env, id)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem creating timeseries artifact for '%s'", id),
+		}
+	}
+
+	if err = writeTimeseries(ctx, id, model.FileDataFormat(format), body, result, output); err != nil {
+		// the data is written as it is validated, so invalid data
+		// must be removed from the bucket.
+		grip.Warning(errors.Wrapf(artifact.Remove(ctx, dbc.env), "problem removing invalid timeseries data for '%s'", id))
+		return nil, err
+	}
+
+	if err = result.AppendArtifacts(*artifact); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem attaching timeseries artifact to '%s'", id),
+		}
+	}
+
+	if err = dbc.addFTDCRollupsJob(result); err != nil {
+		return nil, err
+	}
+
+	return importPerformanceResult(*result)
+}
+
+// ClosePerformanceResult marks the performance result with the given
+// id as completed and queues a job to calculate its rollups, if one is
+// not already queued for its artifacts.
+func (dbc *DBConnector) ClosePerformanceResult(id string) (*dataModel.APIPerformanceResult, error) {
+	result, err := dbc.findPerformanceResult(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result.CompletedAt = time.Now()
+	if err = result.Save(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem saving performance result '%s'", id),
+		}
+	}
+
+	if err = dbc.addFTDCRollupsJob(result); err != nil {
+		return nil, err
+	}
+
+	return importPerformanceResult(*result)
+}
+
+func (dbc *DBConnector) findPerformanceResult(id string) (*model.PerformanceResult, error) {
+	result := &model.PerformanceResult{}
+	result.Setup(dbc.env)
+	result.ID = id
+
+	if err := result.Find(); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusNotFound,
+			Message:    fmt.Sprintf("performance result with id '%s' not found", id),
+		}
+	}
+
+	return result, nil
+}
+
+func (dbc *DBConnector) addRollups(result *model.PerformanceResult, rollups []model.PerfRollupValue) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	catcher := grip.NewBasicCatcher()
+	result.Rollups.Setup(dbc.env)
+	for _, r := range rollups {
+		catcher.Add(result.Rollups.AddValue(r))
+	}
+	catcher.Add(result.Rollups.MarkProcessed(!catcher.HasErrors()))
+
+	if catcher.HasErrors() {
+		grip.Warning(catcher.Resolve())
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem adding rollups to '%s'", result.ID),
+		}
+	}
+
+	return nil
+}
+
+// addFTDCRollupsJob queues a job to (re)calculate the rollups for
+// the result from its FTDC artifacts, unless one is already queued for
+// the current artifacts of the result.
+func (dbc *DBConnector) addFTDCRollupsJob(result *model.PerformanceResult) error {
+	q, err := dbc.env.GetQueue()
+	if err == nil {
+		err = units.PutJobOnce(q, units.MakeFTDCRollupsJob(dbc.env, result))
+	}
+	if err != nil {
+		grip.Warning(errors.Wrapf(err, "problem queuing rollups job for '%s'", result.ID))
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem queuing rollups job for '%s'", result.ID),
+		}
+	}
+
+	return nil
+}
+
+// MockConnector Implementation
+
+func (mc *MockConnector) CreatePerformanceResult(data dataModel.APIPerformanceResultData) (*dataModel.APIPerformanceResult, error) {
+	info, err := exportPerformanceResultInfo(data.Info)
+	if err != nil {
+		return nil, err
+	}
+	artifacts, err := exportArtifacts(data.Artifacts)
+	if err != nil {
+		return nil, err
+	}
+	rollups, err := exportRollups(data.Rollups)
+	if err != nil {
+		return nil, err
+	}
+
+	result := model.CreatePerformanceResult(info, artifacts)
+	result.CreatedAt = time.Now()
+	for i := range rollups {
+		if rollups[i].CalculatedAt.IsZero() {
+			rollups[i].CalculatedAt = result.CreatedAt
+		}
+	}
+	result.Rollups.Stats = rollups
+	result.Rollups.Count = len(rollups)
+	result.Rollups.Valid = true
+
+	apiResult, err := importPerformanceResult(*result)
+	if err != nil {
+		return nil, err
+	}
+
+	if mc.CachedPerformanceResults == nil {
+		mc.CachedPerformanceResults = map[string]dataModel.APIPerformanceResult{}
+	}
+	mc.CachedPerformanceResults[result.ID] = *apiResult
+	if mc.CachedPerfRollupHistory == nil {
+		mc.CachedPerfRollupHistory = map[string][]dataModel.APIPerfRollupValue{}
+	}
+	mc.CachedPerfRollupHistory[result.ID] = apiResult.Rollups.Stats
+
+	return apiResult, nil
+}
+
+func (mc *MockConnector) AddPerformanceResultArtifacts(id string, apiArtifacts []dataModel.APIArtifactInfo) (*dataModel.APIPerformanceResult, error) {
+	artifacts, err := exportArtifacts(apiArtifacts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := mc.FindPerformanceResultById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	imported, err := importPerformanceResult(model.PerformanceResult{Artifacts: artifacts})
+	if err != nil {
+		return nil, err
+	}
+	result.Artifacts = append(result.Artifacts, imported.Artifacts...)
+	mc.CachedPerformanceResults[id] = *result
+
+	return result, nil
+}
+
+func (mc *MockConnector) AddPerformanceResultRollups(id string, apiRollups []dataModel.APIPerfRollupValue) (*dataModel.APIPerformanceResult, error) {
+	rollups, err := exportRollups(apiRollups)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := mc.FindPerformanceResultById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range rollups {
+		if rollups[i].CalculatedAt.IsZero() {
+			rollups[i].CalculatedAt = now
+		}
+	}
+	imported, err := importPerformanceResult(model.PerformanceResult{Rollups: &model.PerfRollups{Stats: rollups}})
+	if err != nil {
+		return nil, err
+	}
+	if result.Rollups == nil {
+		result.Rollups = &dataModel.APIPerfRollups{}
+	}
+
+	// as in the database, every rollup is added to the history but only
+	// replaces a current value calculated by the same or an older
+	// version.
+	if mc.CachedPerfRollupHistory == nil {
+		mc.CachedPerfRollupHistory = map[string][]dataModel.APIPerfRollupValue{}
+	}
+	for _, rollup := range imported.Rollups.Stats {
+		mc.CachedPerfRollupHistory[id] = append(mc.CachedPerfRollupHistory[id], rollup)
+
+		replaced := false
+		for i, stat := range result.Rollups.Stats {
+			if dataModel.FromAPIString(stat.Name) == dataModel.FromAPIString(rollup.Name) {
+				if stat.Version <= rollup.Version {
+					result.Rollups.Stats[i] = rollup
+				}
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			result.Rollups.Stats = append(result.Rollups.Stats, rollup)
+		}
+	}
+	result.Rollups.Count = len(result.Rollups.Stats)
+	result.Rollups.Valid = true
+	result.Rollups.ProcessedAt = dataModel.NewTime(now)
+	mc.CachedPerformanceResults[id] = *result
+
+	return result, nil
+}
+
+func (mc *MockConnector) RevertPerformanceResultRollup(id, name string, version int) (*dataModel.APIPerformanceResult, error) {
+	result, err := mc.FindPerformanceResultById(id)
+	if err != nil {
+		return nil, err
+	}
+	if result.Rollups == nil {
+		return nil, rollupVersionNotFound(id, name, version)
+	}
+
+	var rollup *dataModel.APIPerfRollupValue
+	history := mc.CachedPerfRollupHistory[id]
+	for i := len(history) - 1; i >= 0; i-- {
+		if dataModel.FromAPIString(history[i].Name) == name && history[i].Version == version {
+			rollup = &history[i]
+			break
+		}
+	}
+	if rollup == nil {
+		return nil, rollupVersionNotFound(id, name, version)
+	}
+
+	for i, stat := range result.Rollups.Stats {
+		if dataModel.FromAPIString(stat.Name) == name {
+			result.Rollups.Stats[i] = *rollup
+			break
+		}
+	}
+	mc.CachedPerformanceResults[id] = *result
+
+	return result, nil
+}
+
+// AddPerformanceResultTimeseries writes the timeseries data to the
+// local directory of the mock's Bucket.
+func (mc *MockConnector) AddPerformanceResultTimeseries(ctx context.Context, id, format string, body io.Reader) (*dataModel.APIPerformanceResult, error) {
+	if err := validateTimeseriesFormat(format); err != nil {
+		return nil, err
+	}
+
+	result, err := mc.FindPerformanceResultById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	artifact := model.NewRawEventsArtifact(&cedar.Configuration{BucketType: model.PailLocal, BucketName: mc.Bucket}, id)
+	output, err := artifact.Writer(ctx, nil)
+	if err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem creating timeseries artifact for '%s'", id),
+		}
+	}
+
+	if err = writeTimeseries(ctx, id, model.FileDataFormat(format), body, nil, output); err != nil {
+		grip.Warning(artifact.Remove(ctx, nil))
+		return nil, err
+	}
+
+	imported, err := importPerformanceResult(model.PerformanceResult{Artifacts: []model.ArtifactInfo{*artifact}})
+	if err != nil {
+		return nil, err
+	}
+	result.Artifacts = append(result.Artifacts, imported.Artifacts...)
+	mc.CachedPerformanceResults[id] = *result
+
+	return result, nil
+}
+
+func (mc *MockConnector) ClosePerformanceResult(id string) (*dataModel.APIPerformanceResult, error) {
+	result, err := mc.FindPerformanceResultById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result.CompletedAt = dataModel.NewTime(time.Now())
+	mc.CachedPerformanceResults[id] = *result
+
+	return result, nil
+}
+
+func importPerformanceResult(result model.PerformanceResult) (*dataModel.APIPerformanceResult, error) {
+	apiResult := &dataModel.APIPerformanceResult{}
+	if err := apiResult.Import(result); err != nil {
+		return nil, gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("corrupt data"),
+		}
+	}
+
+	return apiResult, nil
+}
+
+func exportPerformanceResultInfo(apiInfo dataModel.APIPerformanceResultInfo) (model.PerformanceResultInfo, error) {
+	info := apiInfo.Export()
+	if err := info.ValidateSchema(); err != nil {
+		return info, gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid info: %s", err.Error()),
+		}
+	}
+
+	return info, nil
+}
+
+func exportArtifacts(apiArtifacts []dataModel.APIArtifactInfo) ([]model.ArtifactInfo, error) {
+	artifacts := make([]model.ArtifactInfo, 0, len(apiArtifacts))
+	for i := range apiArtifacts {
+		artifact, err := apiArtifacts[i].Export()
+		if err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid artifact '%s': %s", dataModel.FromAPIString(apiArtifacts[i].Path), err.Error()),
+			}
+		}
+		artifacts = append(artifacts, artifact)
+	}
+
+	return artifacts, nil
+}
+
+func exportRollups(apiRollups []dataModel.APIPerfRollupValue) ([]model.PerfRollupValue, error) {
+	rollups := make([]model.PerfRollupValue, 0, len(apiRollups))
+	for i := range apiRollups {
+		rollup, err := apiRollups[i].Export()
+		if err != nil {
+			return nil, gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid rollup: %s", err.Error()),
+			}
+		}
+		rollups = append(rollups, rollup)
+	}
+
+	return rollups, nil
+}
+
+func hasRollupVersion(history []model.PerfRollupValue, version int) bool {
+	for _, rollup := range history {
+		if rollup.Version == version {
+			return true
+		}
+	}
+
+	return false
+}
+
+func rollupVersionNotFound(id, name string, version int) error {
+	return gimlet.ErrorResponse{
+		StatusCode: http.StatusNotFound,
+		Message:    fmt.Sprintf("rollup '%s' of performance result '%s' has no value calculated by version %d", name, id, version),
+	}
+}
+
+func validateTimeseriesFormat(format string) error {
+	switch model.FileDataFormat(format) {
+	case model.FileFTDC, model.FileJSON:
+		return nil
+	default:
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("timeseries format '%s' is not supported", format),
+		}
+	}
+}
+
+// writeTimeseries writes the timeseries data in the body to the
+// output as FTDC, closing the output when done. FTDC data is copied as
+// it is checked to be readable, while JSON data is either an array or
+// a stream of performance events. Since the data is validated as it is
+// written, callers must remove the output when the data is invalid.
+func writeTimeseries(ctx context.Context, id string, format model.FileDataFormat, body io.Reader, metadata interface{}, output io.WriteCloser) error {
+	catcher := grip.NewBasicCatcher()
+	count := 0
+
+	switch format {
+	case model.FileFTDC:
+		iter := ftdc.ReadChunks(ctx, io.TeeReader(body, output))
+		for iter.Next() {
+			count += iter.Chunk().Size()
+		}
+		catcher.Add(iter.Err())
+		iter.Close()
+	case model.FileJSON:
+		ctx, cancel := context.WithCancel(ctx)
+		pipe := make(chan events.Performance)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			defer close(pipe)
+
+			iter := model.ReadPerformanceEvents(ctx, model.FileJSON, ioutil.NopCloser(body))
+			defer iter.Close()
+			for iter.Next() {
+				select {
+				case pipe <- *iter.Event():
+					count++
+				case <-ctx.Done():
+					catcher.Add(errors.New("operation canceled"))
+					return
+				}
+			}
+			catcher.Add(iter.Err())
+		}()
+
+		catcher.Add(model.DumpPerformanceSeries(ctx, pipe, metadata, output))
+		cancel()
+		<-done
+	}
+
+	if err := output.Close(); err != nil {
+		grip.Warning(errors.Wrapf(err, "problem flushing timeseries data for '%s'", id))
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusInternalServerError,
+			Message:    fmt.Sprintf("problem writing timeseries data for '%s'", id),
+		}
+	}
+
+	if catcher.HasErrors() {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    fmt.Sprintf("invalid %s timeseries data: %s", format, catcher.Resolve().Error()),
+		}
+	}
+	if count == 0 {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "timeseries data is empty",
+		}
+	}
+
+	return nil
+}
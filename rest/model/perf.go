@@ -1,8 +1,13 @@
 package model
 
 import (
+	"math"
+	"time"
+
 	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
 	"github.com/mongodb/ftdc/events"
+	"github.com/mongodb/ftdc/hdrhist"
 	"github.com/pkg/errors"
 )
 
@@ -15,6 +20,9 @@ type APIPerformanceResult struct {
 	Artifacts   []APIArtifactInfo        `json:"artifacts"`
 	Total       *APIPerformanceEvent     `json:"total"`
 	Rollups     *APIPerfRollups          `json:"rollups"`
+
+	TrialSummaries          []APIPerfTrialSummary `json:"trial_summaries,omitempty"`
+	TrialSummariesUpdatedAt APITime               `json:"trial_summaries_updated_at"`
 }
 
 func (apiResult *APIPerformanceResult) Import(i interface{}) error {
@@ -40,6 +48,14 @@ func (apiResult *APIPerformanceResult) Import(i interface{}) error {
 			apiArtifacts = append(apiArtifacts, getArtifactInfo(artifactInfo))
 		}
 		apiResult.Artifacts = apiArtifacts
+
+		if len(r.TrialSummaries) > 0 {
+			apiResult.TrialSummaries = make([]APIPerfTrialSummary, 0, len(r.TrialSummaries))
+			for _, summary := range r.TrialSummaries {
+				apiResult.TrialSummaries = append(apiResult.TrialSummaries, getPerfTrialSummary(summary))
+			}
+			apiResult.TrialSummariesUpdatedAt = NewTime(r.TrialSummariesUpdatedAt)
+		}
 	default:
 		return errors.New("incorrect type when fetching converting PerformanceResult type")
 	}
@@ -53,6 +69,7 @@ func (apiResult *APIPerformanceResult) Export(i interface{}) (interface{}, error
 type APIPerformanceResultInfo struct {
 	Project   APIString        `json:"project"`
 	Version   APIString        `json:"version"`
+	Variant   APIString        `json:"variant"`
 	TaskName  APIString        `json:"task_name"`
 	TaskID    APIString        `json:"task_id"`
 	Execution int              `json:"execution"`
@@ -68,6 +85,7 @@ func getPerformanceResultInfo(r dbmodel.PerformanceResultInfo) APIPerformanceRes
 	return APIPerformanceResultInfo{
 		Project:   ToAPIString(r.Project),
 		Version:   ToAPIString(r.Version),
+		Variant:   ToAPIString(r.Variant),
 		TaskName:  ToAPIString(r.TaskName),
 		TaskID:    ToAPIString(r.TaskID),
 		Execution: r.Execution,
@@ -80,19 +98,75 @@ func getPerformanceResultInfo(r dbmodel.PerformanceResultInfo) APIPerformanceRes
 	}
 }
 
+// Export converts the info into the database model.
+func (apiInfo *APIPerformanceResultInfo) Export() dbmodel.PerformanceResultInfo {
+	return dbmodel.PerformanceResultInfo{
+		Project:   FromAPIString(apiInfo.Project),
+		Version:   FromAPIString(apiInfo.Version),
+		Variant:   FromAPIString(apiInfo.Variant),
+		TaskName:  FromAPIString(apiInfo.TaskName),
+		TaskID:    FromAPIString(apiInfo.TaskID),
+		Execution: apiInfo.Execution,
+		TestName:  FromAPIString(apiInfo.TestName),
+		Trial:     apiInfo.Trial,
+		Parent:    FromAPIString(apiInfo.Parent),
+		Tags:      apiInfo.Tags,
+		Arguments: apiInfo.Arguments,
+		Schema:    apiInfo.Schema,
+	}
+}
+
+// APIPerfTrialSummary summarizes one rollup across the trials of a
+// test that are children of a result.
+type APIPerfTrialSummary struct {
+	TestName               APIString        `json:"test_name"`
+	Arguments              map[string]int32 `json:"args"`
+	Rollup                 APIString        `json:"rollup"`
+	Trials                 int              `json:"trials"`
+	Mean                   float64          `json:"mean"`
+	Median                 float64          `json:"median"`
+	Min                    float64          `json:"min"`
+	Max                    float64          `json:"max"`
+	StdDev                 float64          `json:"std_dev"`
+	CoefficientOfVariation float64          `json:"cv"`
+	ConfidenceLevel        float64          `json:"confidence_level"`
+	ConfidenceLower        float64          `json:"confidence_lower"`
+	ConfidenceUpper        float64          `json:"confidence_upper"`
+}
+
+func getPerfTrialSummary(s dbmodel.PerfTrialSummary) APIPerfTrialSummary {
+	return APIPerfTrialSummary{
+		TestName:               ToAPIString(s.TestName),
+		Arguments:              s.Arguments,
+		Rollup:                 ToAPIString(s.Rollup),
+		Trials:                 s.Trials,
+		Mean:                   s.Mean,
+		Median:                 s.Median,
+		Min:                    s.Min,
+		Max:                    s.Max,
+		StdDev:                 s.StdDev,
+		CoefficientOfVariation: s.CoefficientOfVariation,
+		ConfidenceLevel:        s.ConfidenceLevel,
+		ConfidenceLower:        s.ConfidenceLower,
+		ConfidenceUpper:        s.ConfidenceUpper,
+	}
+}
+
 type APIArtifactInfo struct {
 	Type        APIString `json:"type"`
 	Bucket      APIString `json:"bucket"`
 	Path        APIString `json:"path"`
 	Format      APIString `json:"format"`
 	Compression APIString `json:"compression"`
-	Schema      APIString `bson:"schema"`
+	Schema      APIString `json:"schema"`
 	Tags        []string  `json:"tags"`
-	CreatedAt   APITime   `bson:"created_at"`
+	CreatedAt   APITime   `json:"created_at"`
+
+	ConversionError APIString `json:"conversion_error,omitempty"`
 }
 
 func getArtifactInfo(r dbmodel.ArtifactInfo) APIArtifactInfo {
-	return APIArtifactInfo{
+	artifact := APIArtifactInfo{
 		Type:        ToAPIString(string(r.Type)),
 		Bucket:      ToAPIString(r.Bucket),
 		Path:        ToAPIString(r.Path),
@@ -102,6 +176,39 @@ func getArtifactInfo(r dbmodel.ArtifactInfo) APIArtifactInfo {
 		Tags:        r.Tags,
 		CreatedAt:   NewTime(r.CreatedAt),
 	}
+	if r.ConversionError != "" {
+		artifact.ConversionError = ToAPIString(r.ConversionError)
+	}
+
+	return artifact
+}
+
+// Export converts the artifact into the database model, returning an
+// error if the artifact is not valid. The compression defaults to
+// uncompressed and the creation time defaults to the current time.
+func (apiArtifact *APIArtifactInfo) Export() (dbmodel.ArtifactInfo, error) {
+	artifact := dbmodel.ArtifactInfo{
+		Type:        dbmodel.PailType(FromAPIString(apiArtifact.Type)),
+		Bucket:      FromAPIString(apiArtifact.Bucket),
+		Path:        FromAPIString(apiArtifact.Path),
+		Format:      dbmodel.FileDataFormat(FromAPIString(apiArtifact.Format)),
+		Compression: dbmodel.FileCompression(FromAPIString(apiArtifact.Compression)),
+		Schema:      dbmodel.FileSchema(FromAPIString(apiArtifact.Schema)),
+		Tags:        apiArtifact.Tags,
+		CreatedAt:   time.Time(apiArtifact.CreatedAt),
+	}
+	if artifact.Compression == "" {
+		artifact.Compression = dbmodel.FileUncompressed
+	}
+	if artifact.CreatedAt.IsZero() {
+		artifact.CreatedAt = time.Now()
+	}
+
+	if err := artifact.Validate(); err != nil {
+		return dbmodel.ArtifactInfo{}, errors.WithStack(err)
+	}
+
+	return artifact, nil
 }
 
 type APIPerformanceEvent struct {
@@ -161,7 +268,45 @@ type APIPerfRollupValue struct {
 	Name          APIString   `json:"name"`
 	Value         interface{} `json:"val"`
 	Version       int         `json:"version"`
+	MetricType    APIString   `json:"type"`
 	UserSubmitted bool        `json:"user"`
+	Calculator    APIString   `json:"calculator"`
+	CalculatedAt  APITime     `json:"calculated_at"`
+}
+
+// APIPerfRollupHistory is the current value of a rollup of a
+// performance result and every computation of the rollup, from the
+// oldest to the newest.
+type APIPerfRollupHistory struct {
+	Name    APIString            `json:"name"`
+	Latest  *APIPerfRollupValue  `json:"latest"`
+	History []APIPerfRollupValue `json:"history"`
+}
+
+// Import transforms a PerfRollupHistory object into an
+// APIPerfRollupHistory object.
+func (apiHistory *APIPerfRollupHistory) Import(i interface{}) error {
+	switch h := i.(type) {
+	case *dbmodel.PerfRollupHistory:
+		apiHistory.Name = ToAPIString(h.Name)
+		apiHistory.Latest = nil
+		if h.Latest != nil {
+			latest := getPerfRollupValue(*h.Latest)
+			apiHistory.Latest = &latest
+		}
+
+		apiHistory.History = []APIPerfRollupValue{}
+		for _, rollup := range h.Values {
+			apiHistory.History = append(apiHistory.History, getPerfRollupValue(rollup))
+		}
+	default:
+		return errors.New("incorrect type when converting PerfRollupHistory type")
+	}
+	return nil
+}
+
+func (apiHistory *APIPerfRollupHistory) Export(i interface{}) (interface{}, error) {
+	return nil, errors.Errorf("Export is not implemented for APIPerfRollupHistory")
 }
 
 func getPerfRollups(r *dbmodel.PerfRollups) APIPerfRollups {
@@ -183,8 +328,414 @@ func getPerfRollups(r *dbmodel.PerfRollups) APIPerfRollups {
 func getPerfRollupValue(r dbmodel.PerfRollupValue) APIPerfRollupValue {
 	return APIPerfRollupValue{
 		Name:          ToAPIString(r.Name),
-		Value:         r.Value,
+		Value:         finiteValue(r.Value),
 		Version:       r.Version,
+		MetricType:    ToAPIString(string(r.MetricType)),
 		UserSubmitted: r.UserSubmitted,
+		Calculator:    ToAPIString(r.Calculator),
+		CalculatedAt:  NewTime(r.CalculatedAt),
+	}
+}
+
+// finiteValue replaces non-finite floating point values, such as the
+// latency of a result without operations, with nil, since they cannot
+// be encoded as json.
+func finiteValue(val interface{}) interface{} {
+	if v, ok := val.(float64); ok && (math.IsNaN(v) || math.IsInf(v, 0)) {
+		return nil
+	}
+
+	return val
+}
+
+// Export converts the rollup into the database model, returning an
+// error if the rollup does not have a name or value, or has an unknown
+// metric type.
+func (apiRollup *APIPerfRollupValue) Export() (dbmodel.PerfRollupValue, error) {
+	rollup := dbmodel.PerfRollupValue{
+		Name:          FromAPIString(apiRollup.Name),
+		Value:         apiRollup.Value,
+		Version:       apiRollup.Version,
+		MetricType:    dbmodel.MetricType(FromAPIString(apiRollup.MetricType)),
+		UserSubmitted: apiRollup.UserSubmitted,
+		Calculator:    FromAPIString(apiRollup.Calculator),
+		CalculatedAt:  time.Time(apiRollup.CalculatedAt),
+	}
+	if rollup.Name == "" {
+		return dbmodel.PerfRollupValue{}, errors.New("rollup must have a name")
+	}
+	if rollup.Value == nil {
+		return dbmodel.PerfRollupValue{}, errors.Errorf("rollup '%s' must have a value", rollup.Name)
+	}
+	if rollup.MetricType != "" {
+		if err := rollup.MetricType.Validate(); err != nil {
+			return dbmodel.PerfRollupValue{}, errors.Wrapf(err, "invalid rollup '%s'", rollup.Name)
+		}
+	}
+
+	return rollup, nil
+}
+
+// APIPerformanceResultData describes a new performance result, in the
+// same way as the ResultData message of the gRPC service.
+type APIPerformanceResultData struct {
+	Info      APIPerformanceResultInfo `json:"info"`
+	Artifacts []APIArtifactInfo        `json:"artifacts"`
+	Rollups   []APIPerfRollupValue     `json:"rollups"`
+}
+
+type APIPerformanceHistogram struct {
+	Name    APIString            `json:"name"`
+	Lowest  int64                `json:"lowest"`
+	Highest int64                `json:"highest"`
+	Figures int64                `json:"figures"`
+	Count   int64                `json:"count"`
+	Min     int64                `json:"min"`
+	Max     int64                `json:"max"`
+	Mean    float64              `json:"mean"`
+	StdDev  float64              `json:"std_dev"`
+	Buckets []APIHistogramBucket `json:"buckets"`
+}
+
+type APIHistogramBucket struct {
+	From  int64 `json:"from"`
+	To    int64 `json:"to"`
+	Count int64 `json:"count"`
+}
+
+// NewAPIPerformanceHistogram converts a merged histogram into its API
+// model, omitting empty buckets.
+func NewAPIPerformanceHistogram(name string, h *hdrhist.Histogram) APIPerformanceHistogram {
+	snapshot := h.Export()
+	apiHistogram := APIPerformanceHistogram{
+		Name:    ToAPIString(name),
+		Lowest:  snapshot.LowestTrackableValue,
+		Highest: snapshot.HighestTrackableValue,
+		Figures: snapshot.SignificantFigures,
+		Count:   h.TotalCount(),
+		Buckets: []APIHistogramBucket{},
+	}
+	if apiHistogram.Count == 0 {
+		return apiHistogram
+	}
+
+	apiHistogram.Min = h.Min()
+	apiHistogram.Max = h.Max()
+	apiHistogram.Mean = h.Mean()
+	apiHistogram.StdDev = h.StdDev()
+	for _, bar := range h.Distribution() {
+		if bar.Count == 0 {
+			continue
+		}
+		apiHistogram.Buckets = append(apiHistogram.Buckets, APIHistogramBucket{
+			From:  bar.From,
+			To:    bar.To,
+			Count: bar.Count,
+		})
+	}
+
+	return apiHistogram
+}
+
+type APIPerfHistoryPoint struct {
+	Version      APIString   `json:"version"`
+	PerfResultID APIString   `json:"perf_result_id"`
+	CreatedAt    APITime     `json:"created_ts"`
+	Value        interface{} `json:"value"`
+	// Baseline is set for the point of the version of the baseline
+	// of the task, and BaselinePercentDelta is the change of the
+	// value relative to the value of the baseline.
+	Baseline             bool     `json:"baseline,omitempty"`
+	BaselinePercentDelta *float64 `json:"baseline_percent_delta,omitempty"`
+}
+
+func (apiPoint *APIPerfHistoryPoint) Import(i interface{}) error {
+	switch p := i.(type) {
+	case dbmodel.PerfHistoryPoint:
+		apiPoint.Version = ToAPIString(p.Version)
+		apiPoint.PerfResultID = ToAPIString(p.PerfResultID)
+		apiPoint.CreatedAt = NewTime(p.CreatedAt)
+		apiPoint.Value = finiteValue(p.Value)
+	default:
+		return errors.New("incorrect type when converting PerfHistoryPoint type")
+	}
+	return nil
+}
+
+func (apiPoint *APIPerfHistoryPoint) Export(i interface{}) (interface{}, error) {
+	return nil, errors.Errorf("Export is not implemented for APIPerfHistoryPoint")
+}
+
+type APIChangePoint struct {
+	ID               APIString        `json:"id"`
+	Project          APIString        `json:"project"`
+	Variant          APIString        `json:"variant"`
+	TaskName         APIString        `json:"task_name"`
+	TestName         APIString        `json:"test_name"`
+	Arguments        map[string]int32 `json:"args"`
+	Measurement      APIString        `json:"measurement"`
+	PerfResultID     APIString        `json:"perf_result_id"`
+	Version          APIString        `json:"version"`
+	Before           float64          `json:"before"`
+	After            float64          `json:"after"`
+	Magnitude        float64          `json:"magnitude"`
+	Confidence       float64          `json:"confidence"`
+	Algorithm        APIString        `json:"algorithm"`
+	AlgorithmVersion int              `json:"algorithm_version"`
+	CalculatedAt     APITime          `json:"calculated_at"`
+}
+
+func (apiChangePoint *APIChangePoint) Import(i interface{}) error {
+	switch cp := i.(type) {
+	case dbmodel.PerfChangePoint:
+		apiChangePoint.ID = ToAPIString(cp.ID)
+		apiChangePoint.Project = ToAPIString(cp.Series.Project)
+		apiChangePoint.Variant = ToAPIString(cp.Series.Variant)
+		apiChangePoint.TaskName = ToAPIString(cp.Series.TaskName)
+		apiChangePoint.TestName = ToAPIString(cp.Series.TestName)
+		apiChangePoint.Arguments = cp.Series.Arguments
+		apiChangePoint.Measurement = ToAPIString(cp.Measurement)
+		apiChangePoint.PerfResultID = ToAPIString(cp.PerfResultID)
+		apiChangePoint.Version = ToAPIString(cp.Version)
+		apiChangePoint.Before = cp.Before
+		apiChangePoint.After = cp.After
+		apiChangePoint.Magnitude = cp.Magnitude
+		apiChangePoint.Confidence = cp.Confidence
+		apiChangePoint.Algorithm = ToAPIString(cp.Algorithm.Name)
+		apiChangePoint.AlgorithmVersion = cp.Algorithm.Version
+		apiChangePoint.CalculatedAt = NewTime(cp.CalculatedAt)
+	default:
+		return errors.New("incorrect type when converting PerfChangePoint type")
+	}
+	return nil
+}
+
+func (apiChangePoint *APIChangePoint) Export(i interface{}) (interface{}, error) {
+	return nil, errors.Errorf("Export is not implemented for APIChangePoint")
+}
+
+type APIPerfNoise struct {
+	ID           APIString        `json:"id"`
+	SeriesID     APIString        `json:"series_id"`
+	Project      APIString        `json:"project"`
+	Variant      APIString        `json:"variant"`
+	TaskName     APIString        `json:"task_name"`
+	TestName     APIString        `json:"test_name"`
+	Arguments    map[string]int32 `json:"args"`
+	Measurement  APIString        `json:"measurement"`
+	Versions     int              `json:"versions"`
+	Trials       int              `json:"trials"`
+	TrialCV      float64          `json:"trial_cv"`
+	HistoryCV    float64          `json:"history_cv"`
+	Score        float64          `json:"score"`
+	Threshold    float64          `json:"threshold"`
+	Noisy        bool             `json:"noisy"`
+	CalculatedAt APITime          `json:"calculated_at"`
+}
+
+func (apiNoise *APIPerfNoise) Import(i interface{}) error {
+	switch n := i.(type) {
+	case dbmodel.PerfNoise:
+		apiNoise.ID = ToAPIString(n.ID)
+		apiNoise.SeriesID = ToAPIString(n.SeriesID)
+		apiNoise.Project = ToAPIString(n.Series.Project)
+		apiNoise.Variant = ToAPIString(n.Series.Variant)
+		apiNoise.TaskName = ToAPIString(n.Series.TaskName)
+		apiNoise.TestName = ToAPIString(n.Series.TestName)
+		apiNoise.Arguments = n.Series.Arguments
+		apiNoise.Measurement = ToAPIString(n.Measurement)
+		apiNoise.Versions = n.Versions
+		apiNoise.Trials = n.Trials
+		apiNoise.TrialCV = n.TrialCV
+		apiNoise.HistoryCV = n.HistoryCV
+		apiNoise.Score = n.Score
+		apiNoise.Threshold = n.Threshold
+		apiNoise.Noisy = n.Noisy
+		apiNoise.CalculatedAt = NewTime(n.CalculatedAt)
+	default:
+		return errors.New("incorrect type when converting PerfNoise type")
 	}
+	return nil
+}
+
+func (apiNoise *APIPerfNoise) Export(i interface{}) (interface{}, error) {
+	return nil, errors.Errorf("Export is not implemented for APIPerfNoise")
+}
+
+type APIPerfChart struct {
+	PerfID    APIString            `json:"perf_id"`
+	MaxPoints int                  `json:"max_points"`
+	Series    []APIPerfChartSeries `json:"series"`
+}
+
+type APIPerfChartSeries struct {
+	Metric APIString           `json:"metric"`
+	Points []APIPerfChartPoint `json:"points"`
+}
+
+type APIPerfChartPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+func (apiChart *APIPerfChart) Import(i interface{}) error {
+	switch c := i.(type) {
+	case dbmodel.PerfChart:
+		apiChart.PerfID = ToAPIString(c.PerfID)
+		apiChart.MaxPoints = c.MaxPoints
+		apiChart.Series = make([]APIPerfChartSeries, len(c.Series))
+		for i, series := range c.Series {
+			apiChart.Series[i].Metric = ToAPIString(series.Metric)
+			apiChart.Series[i].Points = make([]APIPerfChartPoint, len(series.Points))
+			for j, point := range series.Points {
+				apiChart.Series[i].Points[j] = APIPerfChartPoint{X: point.X, Y: point.Y}
+			}
+		}
+	default:
+		return errors.New("incorrect type when converting PerfChart type")
+	}
+	return nil
+}
+
+func (apiChart *APIPerfChart) Export(i interface{}) (interface{}, error) {
+	return nil, errors.Errorf("Export is not implemented for APIPerfChart")
+}
+
+type APIPerfBaseline struct {
+	Project      APIString               `json:"project"`
+	Variant      APIString               `json:"variant"`
+	TaskName     APIString               `json:"task_name"`
+	Version      APIString               `json:"version"`
+	PerfResultID APIString               `json:"perf_result_id"`
+	SetBy        APIString               `json:"set_by"`
+	SetAt        APITime                 `json:"set_at"`
+	History      []APIPerfBaselineChange `json:"history"`
+}
+
+type APIPerfBaselineChange struct {
+	Version      APIString `json:"version"`
+	PerfResultID APIString `json:"perf_result_id"`
+	SetBy        APIString `json:"set_by"`
+	SetAt        APITime   `json:"set_at"`
+}
+
+func (apiBaseline *APIPerfBaseline) Import(i interface{}) error {
+	switch b := i.(type) {
+	case dbmodel.PerfBaseline:
+		apiBaseline.Project = ToAPIString(b.Key.Project)
+		apiBaseline.Variant = ToAPIString(b.Key.Variant)
+		apiBaseline.TaskName = ToAPIString(b.Key.TaskName)
+		apiBaseline.Version = ToAPIString(b.Version)
+		apiBaseline.PerfResultID = ToAPIString(b.PerfResultID)
+		apiBaseline.SetBy = ToAPIString(b.SetBy)
+		apiBaseline.SetAt = NewTime(b.SetAt)
+		apiBaseline.History = make([]APIPerfBaselineChange, len(b.History))
+		for idx, change := range b.History {
+			if err := apiBaseline.History[idx].Import(change); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	default:
+		return errors.New("incorrect type when converting PerfBaseline type")
+	}
+	return nil
+}
+
+func (apiBaseline *APIPerfBaseline) Export(i interface{}) (interface{}, error) {
+	return nil, errors.Errorf("Export is not implemented for APIPerfBaseline")
+}
+
+func (apiChange *APIPerfBaselineChange) Import(i interface{}) error {
+	switch c := i.(type) {
+	case dbmodel.PerfBaselineChange:
+		apiChange.Version = ToAPIString(c.Version)
+		apiChange.PerfResultID = ToAPIString(c.PerfResultID)
+		apiChange.SetBy = ToAPIString(c.SetBy)
+		apiChange.SetAt = NewTime(c.SetAt)
+	default:
+		return errors.New("incorrect type when converting PerfBaselineChange type")
+	}
+	return nil
+}
+
+// Export returns the change of a baseline to set, which must name a
+// version or a performance result, and the user who set it. The time
+// of the change is set when it is saved.
+func (apiChange *APIPerfBaselineChange) Export() (dbmodel.PerfBaselineChange, error) {
+	change := dbmodel.PerfBaselineChange{
+		Version:      FromAPIString(apiChange.Version),
+		PerfResultID: FromAPIString(apiChange.PerfResultID),
+		SetBy:        FromAPIString(apiChange.SetBy),
+	}
+	if change.Version == "" && change.PerfResultID == "" {
+		return dbmodel.PerfBaselineChange{}, errors.New("baseline must have a version or a performance result")
+	}
+	if change.SetBy == "" {
+		return dbmodel.PerfBaselineChange{}, errors.New("baseline must have the user who set it")
+	}
+
+	return change, nil
+}
+
+type APIPerformanceComparison struct {
+	Variant       APIString             `json:"variant"`
+	TaskName      APIString             `json:"task_name"`
+	TestName      APIString             `json:"test_name"`
+	Arguments     map[string]int32      `json:"args"`
+	Tags          []string              `json:"tags"`
+	BaseIDs       []string              `json:"base_ids"`
+	ComparisonIDs []string              `json:"comparison_ids"`
+	Rollups       []APIRollupComparison `json:"rollups"`
+}
+
+type APIRollupComparison struct {
+	Name             APIString `json:"name"`
+	Base             float64   `json:"base"`
+	Comparison       float64   `json:"comparison"`
+	BaseTrials       int       `json:"base_trials"`
+	ComparisonTrials int       `json:"comparison_trials"`
+	AbsoluteDelta    float64   `json:"absolute_delta"`
+	PercentDelta     *float64  `json:"percent_delta"`
+	UStatistic       float64   `json:"u_statistic"`
+	PValue           float64   `json:"p_value"`
+}
+
+func (apiComparison *APIPerformanceComparison) Import(i interface{}) error {
+	switch c := i.(type) {
+	case perf.ResultComparison:
+		apiComparison.Variant = ToAPIString(c.Key.Variant)
+		apiComparison.TaskName = ToAPIString(c.Key.TaskName)
+		apiComparison.TestName = ToAPIString(c.Key.TestName)
+		apiComparison.Arguments = c.Key.Arguments
+		apiComparison.Tags = c.Key.Tags
+		apiComparison.BaseIDs = c.BaseIDs
+		apiComparison.ComparisonIDs = c.ComparisonIDs
+
+		apiComparison.Rollups = make([]APIRollupComparison, len(c.Rollups))
+		for idx, r := range c.Rollups {
+			apiComparison.Rollups[idx] = APIRollupComparison{
+				Name:             ToAPIString(r.Name),
+				Base:             r.Base,
+				Comparison:       r.Comparison,
+				BaseTrials:       r.BaseTrials,
+				ComparisonTrials: r.ComparisonTrials,
+				AbsoluteDelta:    r.AbsoluteDelta,
+				UStatistic:       r.UStatistic,
+				PValue:           r.PValue,
+			}
+			// the percent change is undefined when the base is
+			// zero, and non-finite values cannot be encoded as json.
+			if !math.IsNaN(r.PercentDelta) && !math.IsInf(r.PercentDelta, 0) {
+				percent := r.PercentDelta
+				apiComparison.Rollups[idx].PercentDelta = &percent
+			}
+		}
+	default:
+		return errors.New("incorrect type when converting ResultComparison type")
+	}
+	return nil
+}
+
+func (apiComparison *APIPerformanceComparison) Export(i interface{}) (interface{}, error) {
+	return nil, errors.Errorf("Export is not implemented for APIPerformanceComparison")
 }
@@ -1,13 +1,17 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
 	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
 	"github.com/mongodb/ftdc/events"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestImportHelperFunctions(t *testing.T) {
@@ -21,6 +25,7 @@ func TestImportHelperFunctions(t *testing.T) {
 			input: dbmodel.PerformanceResultInfo{
 				Project:   "project",
 				Version:   "version",
+				Variant:   "variant",
 				TaskName:  "taskname",
 				TaskID:    "taskid",
 				Execution: 1,
@@ -38,6 +43,7 @@ func TestImportHelperFunctions(t *testing.T) {
 			expectedOutput: APIPerformanceResultInfo{
 				Project:   ToAPIString("project"),
 				Version:   ToAPIString("version"),
+				Variant:   ToAPIString("variant"),
 				TaskName:  ToAPIString("taskname"),
 				TaskID:    ToAPIString("taskid"),
 				Execution: 1,
@@ -118,14 +124,20 @@ func TestImportHelperFunctions(t *testing.T) {
 		{
 			name: "TestgetPerfRollupValue",
 			input: dbmodel.PerfRollupValue{
-				Name:    "name",
-				Value:   "value",
-				Version: 1,
+				Name:         "name",
+				Value:        "value",
+				Version:      1,
+				MetricType:   dbmodel.MetricTypeMean,
+				Calculator:   "calculator",
+				CalculatedAt: time.Date(2012, time.December, 31, 23, 59, 59, 0, time.UTC),
 			},
 			expectedOutput: APIPerfRollupValue{
-				Name:    ToAPIString("name"),
-				Value:   "value",
-				Version: 1,
+				Name:         ToAPIString("name"),
+				Value:        "value",
+				Version:      1,
+				MetricType:   ToAPIString(string(dbmodel.MetricTypeMean)),
+				Calculator:   ToAPIString("calculator"),
+				CalculatedAt: NewTime(time.Date(2012, time.December, 31, 23, 59, 59, 0, time.UTC)),
 			},
 		},
 		{
@@ -133,20 +145,23 @@ func TestImportHelperFunctions(t *testing.T) {
 			input: &dbmodel.PerfRollups{
 				Stats: []dbmodel.PerfRollupValue{
 					dbmodel.PerfRollupValue{
-						Name:    "stat0",
-						Value:   "value0",
-						Version: 1,
+						Name:       "stat0",
+						Value:      "value0",
+						Version:    1,
+						MetricType: dbmodel.MetricTypeMean,
 					},
 					dbmodel.PerfRollupValue{
 						Name:          "stat1",
 						Value:         "value1",
 						Version:       2,
+						MetricType:    dbmodel.MetricTypeMean,
 						UserSubmitted: true,
 					},
 					dbmodel.PerfRollupValue{
-						Name:    "stat2",
-						Value:   "value2",
-						Version: 3,
+						Name:       "stat2",
+						Value:      "value2",
+						Version:    3,
+						MetricType: dbmodel.MetricTypeMean,
 					},
 				},
 				ProcessedAt: time.Date(2012, time.December, 31, 23, 59, 59, 0, time.UTC),
@@ -156,20 +171,26 @@ func TestImportHelperFunctions(t *testing.T) {
 			expectedOutput: APIPerfRollups{
 				Stats: []APIPerfRollupValue{
 					APIPerfRollupValue{
-						Name:    ToAPIString("stat0"),
-						Value:   "value0",
-						Version: 1,
+						Name:       ToAPIString("stat0"),
+						Value:      "value0",
+						Version:    1,
+						MetricType: ToAPIString(string(dbmodel.MetricTypeMean)),
+						Calculator: ToAPIString(""),
 					},
 					APIPerfRollupValue{
 						Name:          ToAPIString("stat1"),
 						Value:         "value1",
 						Version:       2,
+						MetricType:    ToAPIString(string(dbmodel.MetricTypeMean)),
 						UserSubmitted: true,
+						Calculator:    ToAPIString(""),
 					},
 					APIPerfRollupValue{
-						Name:    ToAPIString("stat2"),
-						Value:   "value2",
-						Version: 3,
+						Name:       ToAPIString("stat2"),
+						Value:      "value2",
+						Version:    3,
+						MetricType: ToAPIString(string(dbmodel.MetricTypeMean)),
+						Calculator: ToAPIString(""),
 					},
 				},
 				ProcessedAt: NewTime(time.Date(2012, time.December, 31, 23, 59, 59, 0, time.UTC)),
@@ -213,6 +234,7 @@ func TestImport(t *testing.T) {
 				Info: dbmodel.PerformanceResultInfo{
 					Project:   "project",
 					Version:   "version",
+					Variant:   "variant",
 					TaskName:  "taskname",
 					TaskID:    "taskid",
 					Execution: 1,
@@ -281,31 +303,53 @@ func TestImport(t *testing.T) {
 				Rollups: &dbmodel.PerfRollups{
 					Stats: []dbmodel.PerfRollupValue{
 						dbmodel.PerfRollupValue{
-							Name:    "stat0",
-							Value:   "value0",
-							Version: 1,
+							Name:       "stat0",
+							Value:      "value0",
+							Version:    1,
+							MetricType: dbmodel.MetricTypeMean,
 						},
 						dbmodel.PerfRollupValue{
-							Name:    "stat1",
-							Value:   "value1",
-							Version: 2,
+							Name:       "stat1",
+							Value:      "value1",
+							Version:    2,
+							MetricType: dbmodel.MetricTypeMean,
 						},
 						dbmodel.PerfRollupValue{
-							Name:    "stat2",
-							Value:   "value2",
-							Version: 3,
+							Name:       "stat2",
+							Value:      "value2",
+							Version:    3,
+							MetricType: dbmodel.MetricTypeMean,
 						},
 					},
 					ProcessedAt: time.Date(2012, time.December, 31, 23, 59, 59, 0, time.UTC),
 					Count:       3,
 					Valid:       true,
 				},
+				TrialSummaries: []dbmodel.PerfTrialSummary{
+					{
+						TestName:               "testname",
+						Arguments:              map[string]int32{"threads": 4},
+						Rollup:                 "latency",
+						Trials:                 2,
+						Mean:                   2,
+						Median:                 2,
+						Min:                    1,
+						Max:                    3,
+						StdDev:                 1.5,
+						CoefficientOfVariation: 0.75,
+						ConfidenceLevel:        0.95,
+						ConfidenceLower:        1,
+						ConfidenceUpper:        3,
+					},
+				},
+				TrialSummariesUpdatedAt: time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC),
 			},
 			expected: APIPerformanceResult{
 				Name: ToAPIString("ID"),
 				Info: APIPerformanceResultInfo{
 					Project:   ToAPIString("project"),
 					Version:   ToAPIString("version"),
+					Variant:   ToAPIString("variant"),
 					TaskName:  ToAPIString("taskname"),
 					TaskID:    ToAPIString("taskid"),
 					Execution: 1,
@@ -374,25 +418,49 @@ func TestImport(t *testing.T) {
 				Rollups: &APIPerfRollups{
 					Stats: []APIPerfRollupValue{
 						APIPerfRollupValue{
-							Name:    ToAPIString("stat0"),
-							Value:   "value0",
-							Version: 1,
+							Name:       ToAPIString("stat0"),
+							Value:      "value0",
+							Version:    1,
+							MetricType: ToAPIString(string(dbmodel.MetricTypeMean)),
+							Calculator: ToAPIString(""),
 						},
 						APIPerfRollupValue{
-							Name:    ToAPIString("stat1"),
-							Value:   "value1",
-							Version: 2,
+							Name:       ToAPIString("stat1"),
+							Value:      "value1",
+							Version:    2,
+							MetricType: ToAPIString(string(dbmodel.MetricTypeMean)),
+							Calculator: ToAPIString(""),
 						},
 						APIPerfRollupValue{
-							Name:    ToAPIString("stat2"),
-							Value:   "value2",
-							Version: 3,
+							Name:       ToAPIString("stat2"),
+							Value:      "value2",
+							Version:    3,
+							MetricType: ToAPIString(string(dbmodel.MetricTypeMean)),
+							Calculator: ToAPIString(""),
 						},
 					},
 					ProcessedAt: NewTime(time.Date(2012, time.December, 31, 23, 59, 59, 0, time.UTC)),
 					Count:       3,
 					Valid:       true,
 				},
+				TrialSummaries: []APIPerfTrialSummary{
+					{
+						TestName:               ToAPIString("testname"),
+						Arguments:              map[string]int32{"threads": 4},
+						Rollup:                 ToAPIString("latency"),
+						Trials:                 2,
+						Mean:                   2,
+						Median:                 2,
+						Min:                    1,
+						Max:                    3,
+						StdDev:                 1.5,
+						CoefficientOfVariation: 0.75,
+						ConfidenceLevel:        0.95,
+						ConfidenceLower:        1,
+						ConfidenceUpper:        3,
+					},
+				},
+				TrialSummariesUpdatedAt: NewTime(time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)),
 			},
 		},
 		{
@@ -415,3 +483,197 @@ func TestImport(t *testing.T) {
 	}
 
 }
+
+func TestExportHelperFunctions(t *testing.T) {
+	t.Run("PerformanceResultInfo", func(t *testing.T) {
+		info := dbmodel.PerformanceResultInfo{
+			Project:   "project",
+			Version:   "version",
+			Variant:   "variant",
+			TaskName:  "taskname",
+			TaskID:    "taskid",
+			Execution: 1,
+			TestName:  "testname",
+			Trial:     1,
+			Parent:    "parent",
+			Tags:      []string{"tag0", "tag1"},
+			Arguments: map[string]int32{"argument0": 0},
+			Schema:    1,
+		}
+		apiInfo := getPerformanceResultInfo(info)
+		assert.Equal(t, info, apiInfo.Export())
+	})
+	t.Run("ArtifactInfo", func(t *testing.T) {
+		apiArtifact := APIArtifactInfo{
+			Type:   ToAPIString(string(dbmodel.PailLocal)),
+			Bucket: ToAPIString("bucket"),
+			Path:   ToAPIString("path"),
+			Format: ToAPIString(string(dbmodel.FileFTDC)),
+			Schema: ToAPIString(string(dbmodel.SchemaRawEvents)),
+		}
+		artifact, err := apiArtifact.Export()
+		assert.NoError(t, err)
+		assert.Equal(t, dbmodel.PailType(dbmodel.PailLocal), artifact.Type)
+		assert.Equal(t, "path", artifact.Path)
+		assert.Equal(t, dbmodel.FileCompression(dbmodel.FileUncompressed), artifact.Compression)
+		assert.False(t, artifact.CreatedAt.IsZero())
+
+		apiArtifact.Format = ToAPIString("invalid")
+		_, err = apiArtifact.Export()
+		assert.Error(t, err)
+
+		_, err = (&APIArtifactInfo{}).Export()
+		assert.Error(t, err)
+	})
+	t.Run("PerfRollupValue", func(t *testing.T) {
+		apiRollup := APIPerfRollupValue{
+			Name:       ToAPIString("name"),
+			Value:      1.5,
+			Version:    2,
+			MetricType: ToAPIString(string(dbmodel.MetricTypeMax)),
+			Calculator: ToAPIString("calculator"),
+		}
+		rollup, err := apiRollup.Export()
+		assert.NoError(t, err)
+		assert.Equal(t, dbmodel.PerfRollupValue{
+			Name:       "name",
+			Value:      1.5,
+			Version:    2,
+			MetricType: dbmodel.MetricTypeMax,
+			Calculator: "calculator",
+		}, rollup)
+
+		apiRollup.Value = nil
+		_, err = apiRollup.Export()
+		assert.Error(t, err)
+
+		_, err = (&APIPerfRollupValue{Value: 1}).Export()
+		assert.Error(t, err)
+
+		rollup, err = (&APIPerfRollupValue{Name: ToAPIString("ops"), Value: 1}).Export()
+		assert.NoError(t, err)
+		assert.Empty(t, rollup.MetricType)
+
+		_, err = (&APIPerfRollupValue{
+			Name:       ToAPIString("ops"),
+			Value:      1,
+			MetricType: ToAPIString("unknown"),
+		}).Export()
+		assert.Error(t, err)
+	})
+	t.Run("PerfBaselineChange", func(t *testing.T) {
+		apiChange := APIPerfBaselineChange{
+			Version: ToAPIString("version"),
+			SetBy:   ToAPIString("user"),
+			SetAt:   NewTime(time.Now()),
+		}
+		change, err := apiChange.Export()
+		assert.NoError(t, err)
+		assert.Equal(t, dbmodel.PerfBaselineChange{Version: "version", SetBy: "user"}, change)
+
+		_, err = (&APIPerfBaselineChange{PerfResultID: ToAPIString("perf")}).Export()
+		assert.Error(t, err)
+
+		_, err = (&APIPerfBaselineChange{SetBy: ToAPIString("user")}).Export()
+		assert.Error(t, err)
+	})
+}
+
+func TestImportPerfBaseline(t *testing.T) {
+	setAt := time.Date(2019, time.March, 1, 0, 0, 0, 0, time.UTC)
+	baseline := dbmodel.PerfBaseline{
+		Key:          dbmodel.PerfBaselineKey{Project: "project", Variant: "variant", TaskName: "task"},
+		Version:      "v2",
+		PerfResultID: "perf",
+		SetBy:        "other",
+		SetAt:        setAt,
+		History: []dbmodel.PerfBaselineChange{
+			{Version: "v1", SetBy: "user", SetAt: setAt.Add(-time.Hour)},
+			{Version: "v2", PerfResultID: "perf", SetBy: "other", SetAt: setAt},
+		},
+	}
+
+	apiBaseline := APIPerfBaseline{}
+	require.NoError(t, apiBaseline.Import(baseline))
+	assert.Equal(t, "project", *apiBaseline.Project)
+	assert.Equal(t, "variant", *apiBaseline.Variant)
+	assert.Equal(t, "task", *apiBaseline.TaskName)
+	assert.Equal(t, "v2", *apiBaseline.Version)
+	assert.Equal(t, "perf", *apiBaseline.PerfResultID)
+	assert.Equal(t, "other", *apiBaseline.SetBy)
+	assert.Equal(t, NewTime(setAt), apiBaseline.SetAt)
+	require.Len(t, apiBaseline.History, 2)
+	assert.Equal(t, "v1", *apiBaseline.History[0].Version)
+	assert.Equal(t, "user", *apiBaseline.History[0].SetBy)
+	assert.Equal(t, NewTime(setAt.Add(-time.Hour)), apiBaseline.History[0].SetAt)
+
+	assert.Error(t, apiBaseline.Import(baseline.History[0]))
+}
+
+func TestImportPerfRollupHistory(t *testing.T) {
+	rollups := &dbmodel.PerfRollupHistory{
+		Name:   "mean",
+		Latest: &dbmodel.PerfRollupValue{Name: "mean", Value: 2.0, Version: 2, Calculator: "second"},
+		Values: []dbmodel.PerfRollupValue{
+			{Name: "mean", Value: 1.0, Version: 1, Calculator: "first"},
+			{Name: "mean", Value: 2.0, Version: 2, Calculator: "second"},
+		},
+	}
+
+	history := APIPerfRollupHistory{}
+	assert.NoError(t, history.Import(rollups))
+	assert.Equal(t, "mean", FromAPIString(history.Name))
+	if assert.NotNil(t, history.Latest) {
+		assert.Equal(t, 2.0, history.Latest.Value)
+		assert.Equal(t, "second", FromAPIString(history.Latest.Calculator))
+	}
+	if assert.Len(t, history.History, 2) {
+		assert.Equal(t, 1.0, history.History[0].Value)
+		assert.Equal(t, "first", FromAPIString(history.History[0].Calculator))
+		assert.Equal(t, 2.0, history.History[1].Value)
+	}
+
+	history = APIPerfRollupHistory{}
+	assert.NoError(t, history.Import(&dbmodel.PerfRollupHistory{Name: "DNE"}))
+	assert.Equal(t, "DNE", FromAPIString(history.Name))
+	assert.Nil(t, history.Latest)
+	assert.Empty(t, history.History)
+
+	assert.Error(t, history.Import(dbmodel.PerfRollupHistory{}))
+}
+
+func TestImportNonFiniteValues(t *testing.T) {
+	apiResult := APIPerformanceResult{}
+	require.NoError(t, apiResult.Import(dbmodel.PerformanceResult{
+		Rollups: &dbmodel.PerfRollups{
+			Stats: []dbmodel.PerfRollupValue{
+				{Name: "latency", Value: math.Inf(1)},
+				{Name: "mean", Value: math.NaN()},
+				{Name: "ops", Value: 1.0},
+			},
+		},
+	}))
+	require.NotNil(t, apiResult.Rollups)
+	require.Len(t, apiResult.Rollups.Stats, 3)
+	assert.Nil(t, apiResult.Rollups.Stats[0].Value)
+	assert.Nil(t, apiResult.Rollups.Stats[1].Value)
+	assert.Equal(t, 1.0, apiResult.Rollups.Stats[2].Value)
+	_, err := json.Marshal(apiResult)
+	assert.NoError(t, err)
+
+	apiComparison := APIPerformanceComparison{}
+	require.NoError(t, apiComparison.Import(perf.ResultComparison{
+		Rollups: []perf.RollupComparison{
+			{Name: "zero", PercentDelta: math.NaN()},
+			{Name: "inf", PercentDelta: math.Inf(-1)},
+			{Name: "finite", PercentDelta: 10},
+		},
+	}))
+	require.Len(t, apiComparison.Rollups, 3)
+	assert.Nil(t, apiComparison.Rollups[0].PercentDelta)
+	assert.Nil(t, apiComparison.Rollups[1].PercentDelta)
+	require.NotNil(t, apiComparison.Rollups[2].PercentDelta)
+	assert.Equal(t, 10.0, *apiComparison.Rollups[2].PercentDelta)
+	_, err = json.Marshal(apiComparison)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,148 @@
+package rest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/util"
+	"github.com/evergreen-ci/gimlet"
+	"github.com/pkg/errors"
+)
+
+const (
+	perfPageCursorParam = "cursor"
+	perfPageLimitParam  = "limit"
+	perfPageSortParam   = "sort"
+
+	defaultPerfPageLimit = 100
+	maxPerfPageLimit     = 1000
+)
+
+// perfPage holds the pagination parameters of a /perf query route.
+//
+// Page links only carry the cursor and limit parameters, so the
+// cursor returned to clients is a page key that also encodes the rest
+// of the query (filters and sort order), which is restored when the
+// next page is requested.
+type perfPage struct {
+	data.PerfPagination
+	query   url.Values
+	baseURL string
+}
+
+// parsePerfQuery parses the interval and the pagination parameters of
+// the request, and returns the query values to use for the remaining
+// parameters, which are restored from the cursor when there is one.
+func parsePerfQuery(r *http.Request) (url.Values, util.TimeRange, perfPage, error) {
+	page := perfPage{baseURL: requestBaseURL(r)}
+	vals := r.URL.Query()
+
+	if key := vals.Get(perfPageCursorParam); key != "" {
+		query, cursor, err := decodePerfPageKey(key)
+		if err != nil {
+			return nil, util.TimeRange{}, page, errors.Wrapf(err, "problem parsing %s", perfPageCursorParam)
+		}
+		query.Set(perfPageLimitParam, vals.Get(perfPageLimitParam))
+		vals = query
+		page.Cursor = cursor
+	}
+
+	// results are only paginated when a page is requested, so that
+	// clients that predate pagination still get all of the results.
+	if page.Cursor != "" {
+		page.Limit = defaultPerfPageLimit
+	}
+	if limit := vals.Get(perfPageLimitParam); limit != "" {
+		var err error
+		page.Limit, err = strconv.Atoi(limit)
+		if err != nil || page.Limit <= 0 || page.Limit > maxPerfPageLimit {
+			return nil, util.TimeRange{}, page, errors.Errorf("%s must be between 1 and %d", perfPageLimitParam, maxPerfPageLimit)
+		}
+	}
+
+	page.Sort = vals.Get(perfPageSortParam)
+	if strings.HasPrefix(page.Sort, "-") {
+		page.Sort = page.Sort[1:]
+		page.Descending = true
+	}
+
+	interval, err := parseInterval(vals)
+	if err != nil {
+		return nil, util.TimeRange{}, page, errors.WithStack(err)
+	}
+
+	page.query = url.Values{}
+	for key, values := range vals {
+		if key != perfPageCursorParam && key != perfPageLimitParam {
+			page.query[key] = values
+		}
+	}
+	// pin the end of the interval, which otherwise defaults to the
+	// current time, so that later pages see the same results.
+	page.query.Set("finished_before", interval.EndAt.Format(time.RFC3339Nano))
+
+	return vals, interval, page, nil
+}
+
+// respond returns the results with a link to the next page, if there
+// is one.
+func (p perfPage) respond(results interface{}, next string) gimlet.Responder {
+	resp := gimlet.NewJSONResponse(results)
+	if next == "" {
+		return resp
+	}
+
+	err := resp.SetPages(&gimlet.ResponsePages{
+		Next: &gimlet.Page{
+			BaseURL:         p.baseURL,
+			KeyQueryParam:   perfPageCursorParam,
+			LimitQueryParam: perfPageLimitParam,
+			Key:             encodePerfPageKey(p.query, next),
+			Limit:           p.Limit,
+			Relation:        "next",
+		},
+	})
+	if err != nil {
+		return gimlet.MakeJSONInternalErrorResponder(errors.Wrap(err, "problem paginating response"))
+	}
+
+	return resp
+}
+
+func encodePerfPageKey(query url.Values, cursor string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s\n%s", cursor, query.Encode())))
+}
+
+func decodePerfPageKey(key string) (url.Values, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(key)
+	if err != nil {
+		return nil, "", errors.New("malformed cursor")
+	}
+
+	parts := strings.SplitN(string(raw), "\n", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		return nil, "", errors.New("malformed cursor")
+	}
+
+	query, err := url.ParseQuery(parts[1])
+	if err != nil {
+		return nil, "", errors.New("malformed cursor")
+	}
+
+	return query, parts[0], nil
+}
+
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	return fmt.Sprintf("%s://%s", scheme, r.Host)
+}
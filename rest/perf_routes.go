@@ -2,14 +2,22 @@ package rest
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
 	"github.com/evergreen-ci/cedar/rest/data"
+	"github.com/evergreen-ci/cedar/rest/model"
 	"github.com/evergreen-ci/cedar/util"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
 	"github.com/pkg/errors"
 )
 
@@ -59,6 +67,7 @@ type perfGetByTaskIdHandler struct {
 	taskId   string
 	interval util.TimeRange
 	tags     []string
+	page     perfPage
 	sc       data.Connector
 }
 
@@ -78,21 +87,24 @@ func (h *perfGetByTaskIdHandler) Factory() gimlet.RouteHandler {
 // Parse fetches the task_id from the http request.
 func (h *perfGetByTaskIdHandler) Parse(ctx context.Context, r *http.Request) error {
 	h.taskId = gimlet.GetVars(r)["task_id"]
-	vals := r.URL.Query()
+	vals, interval, page, err := parsePerfQuery(r)
+	if err != nil {
+		return err
+	}
+	h.interval = interval
+	h.page = page
 	h.tags = vals["tags"]
-	var err error
-	h.interval, err = parseInterval(vals)
-	return err
+	return nil
 }
 
 // Run calls the data FindPerformanceResultsByTaskId and function returns the
 // PerformanceResults from the provider.
 func (h *perfGetByTaskIdHandler) Run(ctx context.Context) gimlet.Responder {
-	perfResults, err := h.sc.FindPerformanceResultsByTaskId(h.taskId, h.interval, h.tags...)
+	perfResults, next, err := h.sc.FindPerformanceResultsByTaskId(h.taskId, h.interval, h.page.PerfPagination, h.tags...)
 	if err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error getting performance results by task_id '%s'", h.taskId))
 	}
-	return gimlet.NewJSONResponse(perfResults)
+	return h.page.respond(perfResults, next)
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -103,6 +115,7 @@ type perfGetByTaskNameHandler struct {
 	taskName string
 	interval util.TimeRange
 	tags     []string
+	page     perfPage
 	sc       data.Connector
 }
 
@@ -122,21 +135,24 @@ func (h *perfGetByTaskNameHandler) Factory() gimlet.RouteHandler {
 // Parse fetches the task_name from the http request.
 func (h *perfGetByTaskNameHandler) Parse(ctx context.Context, r *http.Request) error {
 	h.taskName = gimlet.GetVars(r)["task_name"]
-	vals := r.URL.Query()
+	vals, interval, page, err := parsePerfQuery(r)
+	if err != nil {
+		return err
+	}
+	h.interval = interval
+	h.page = page
 	h.tags = vals["tags"]
-	var err error
-	h.interval, err = parseInterval(vals)
-	return err
+	return nil
 }
 
 // Run calls the data FindPerformanceResultsByTaskName function and returns the
 // PerformanceResults from the provider.
 func (h *perfGetByTaskNameHandler) Run(ctx context.Context) gimlet.Responder {
-	perfResults, err := h.sc.FindPerformanceResultsByTaskName(h.taskName, h.interval, h.tags...)
+	perfResults, next, err := h.sc.FindPerformanceResultsByTaskName(h.taskName, h.interval, h.page.PerfPagination, h.tags...)
 	if err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error getting performance results by task_id '%s'", h.taskName))
 	}
-	return gimlet.NewJSONResponse(perfResults)
+	return h.page.respond(perfResults, next)
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -147,6 +163,7 @@ type perfGetByVersionHandler struct {
 	version  string
 	interval util.TimeRange
 	tags     []string
+	page     perfPage
 	sc       data.Connector
 }
 
@@ -166,21 +183,24 @@ func (h *perfGetByVersionHandler) Factory() gimlet.RouteHandler {
 // Parse fetches the version from the http request.
 func (h *perfGetByVersionHandler) Parse(ctx context.Context, r *http.Request) error {
 	h.version = gimlet.GetVars(r)["version"]
-	vals := r.URL.Query()
+	vals, interval, page, err := parsePerfQuery(r)
+	if err != nil {
+		return err
+	}
+	h.interval = interval
+	h.page = page
 	h.tags = vals["tags"]
-	var err error
-	h.interval, err = parseInterval(vals)
-	return err
+	return nil
 }
 
 // Run calls the data FindPerformanceResultsByVersion function returns the
 // PerformanceResult from the provider.
 func (h *perfGetByVersionHandler) Run(ctx context.Context) gimlet.Responder {
-	perfResults, err := h.sc.FindPerformanceResultsByVersion(h.version, h.interval, h.tags...)
+	perfResults, next, err := h.sc.FindPerformanceResultsByVersion(h.version, h.interval, h.page.PerfPagination, h.tags...)
 	if err != nil {
 		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error getting performance results by version '%s'", h.version))
 	}
-	return gimlet.NewJSONResponse(perfResults)
+	return h.page.respond(perfResults, next)
 }
 
 ///////////////////////////////////////////////////////////////////////////////
@@ -227,10 +247,863 @@ func (h *perfGetChildrenHandler) Run(ctx context.Context) gimlet.Responder {
 	return gimlet.NewJSONResponse(perfResults)
 }
 
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /perf/{id}/histogram
+
+type perfGetHistogramHandler struct {
+	id string
+	sc data.Connector
+}
+
+func makeGetPerfHistogram(sc data.Connector) gimlet.RouteHandler {
+	return &perfGetHistogramHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfGetHistogramHandler.
+func (h *perfGetHistogramHandler) Factory() gimlet.RouteHandler {
+	return &perfGetHistogramHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the id from the http request.
+func (h *perfGetHistogramHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["id"]
+	return nil
+}
+
+// Run calls the data FindPerformanceResultHistograms function and returns
+// the merged histograms from the provider.
+func (h *perfGetHistogramHandler) Run(ctx context.Context) gimlet.Responder {
+	histograms, err := h.sc.FindPerformanceResultHistograms(ctx, h.id)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error getting histograms for performance result '%s'", h.id))
+	}
+	return gimlet.NewJSONResponse(histograms)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /perf/{id}/chart?metric={metric}&points={points}
+
+type perfGetChartHandler struct {
+	id   string
+	opts perf.ChartOptions
+	sc   data.Connector
+}
+
+func makeGetPerfChart(sc data.Connector) gimlet.RouteHandler {
+	return &perfGetChartHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfGetChartHandler.
+func (h *perfGetChartHandler) Factory() gimlet.RouteHandler {
+	return &perfGetChartHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the id, the optional metric, and the optional number
+// of points from the http request.
+func (h *perfGetChartHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["id"]
+	vals := r.URL.Query()
+	h.opts = perf.ChartOptions{
+		Metric:    vals.Get("metric"),
+		MaxPoints: perf.DefaultChartPoints,
+	}
+
+	if points := vals.Get("points"); points != "" {
+		var err error
+		h.opts.MaxPoints, err = strconv.Atoi(points)
+		if err != nil {
+			return gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    fmt.Sprintf("invalid number of points '%s'", points),
+			}
+		}
+	}
+	if err := h.opts.Validate(); err != nil {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    err.Error(),
+		}
+	}
+
+	return nil
+}
+
+// Run calls the data FindPerformanceResultChart function and returns
+// the downsampled timeseries from the provider.
+func (h *perfGetChartHandler) Run(ctx context.Context) gimlet.Responder {
+	chart, err := h.sc.FindPerformanceResultChart(ctx, h.id, h.opts)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error getting chart for performance result '%s'", h.id))
+	}
+	return gimlet.NewJSONResponse(chart)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /perf/{id}/rollups/{name}
+
+type perfGetRollupHistoryHandler struct {
+	id   string
+	name string
+	sc   data.Connector
+}
+
+func makeGetPerfRollupHistory(sc data.Connector) gimlet.RouteHandler {
+	return &perfGetRollupHistoryHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfGetRollupHistoryHandler.
+func (h *perfGetRollupHistoryHandler) Factory() gimlet.RouteHandler {
+	return &perfGetRollupHistoryHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the id and the rollup name from the http request.
+func (h *perfGetRollupHistoryHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.id = vars["id"]
+	h.name = vars["name"]
+	return nil
+}
+
+// Run calls the data FindPerformanceResultRollupHistory function and
+// returns the current value and the history of the rollup from the
+// provider.
+func (h *perfGetRollupHistoryHandler) Run(ctx context.Context) gimlet.Responder {
+	history, err := h.sc.FindPerformanceResultRollupHistory(h.id, h.name)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error getting rollup '%s' of performance result '%s'", h.name, h.id))
+	}
+	return gimlet.NewJSONResponse(history)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /perf/{id}/timeseries
+
+// makeGetPerfTimeseries returns a plain http handler, rather than a
+// gimlet route handler, since the decoded timeseries must be streamed
+// to the client instead of buffered in a response.
+func makeGetPerfTimeseries(sc data.Connector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := gimlet.GetVars(r)["id"]
+		opts, err := parseTimeseriesOptions(r.URL.Query())
+		if err != nil {
+			gimlet.WriteResponse(w, gimlet.MakeJSONErrorResponder(gimlet.ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Message:    err.Error(),
+			}))
+			return
+		}
+
+		timeseries, err := sc.FindPerformanceResultTimeseries(r.Context(), id)
+		if err != nil {
+			gimlet.WriteResponse(w, gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error getting timeseries for performance result '%s'", id)))
+			return
+		}
+		defer timeseries.Close()
+
+		w.Header().Set("Content-Type", timeseriesContentTypes[opts.Format])
+		w.WriteHeader(http.StatusOK)
+
+		// the status has already been sent, so errors can only be
+		// logged and signaled by truncating the response.
+		grip.Warning(message.WrapError(perf.ExportTimeseries(r.Context(), timeseries, w, opts), message.Fields{
+			"message": "problem streaming timeseries",
+			"id":      id,
+			"format":  opts.Format,
+		}))
+	}
+}
+
+var timeseriesContentTypes = map[dbmodel.FileDataFormat]string{
+	dbmodel.FileFTDC: "application/octet-stream",
+	dbmodel.FileJSON: "application/json; charset=utf-8",
+	dbmodel.FileCSV:  "text/csv; charset=utf-8",
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /perf/project/{project}/change_points
+
+type perfGetChangePointsHandler struct {
+	project string
+	filter  data.ChangePointFilter
+	sc      data.Connector
+}
+
+func makeGetPerfChangePoints(sc data.Connector) gimlet.RouteHandler {
+	return &perfGetChangePointsHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfGetChangePointsHandler.
+func (h *perfGetChangePointsHandler) Factory() gimlet.RouteHandler {
+	return &perfGetChangePointsHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the project and the optional filters from the http
+// request.
+func (h *perfGetChangePointsHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.project = gimlet.GetVars(r)["project"]
+	vals := r.URL.Query()
+	h.filter = data.ChangePointFilter{
+		Variant:     vals.Get("variant"),
+		TaskName:    vals.Get("task_name"),
+		TestName:    vals.Get("test_name"),
+		Measurement: vals.Get("measurement"),
+	}
+	return nil
+}
+
+// Run calls the data FindChangePointsByProject function and returns the
+// change points from the provider.
+func (h *perfGetChangePointsHandler) Run(ctx context.Context) gimlet.Responder {
+	changePoints, err := h.sc.FindChangePointsByProject(h.project, h.filter)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error getting change points for project '%s'", h.project))
+	}
+	return gimlet.NewJSONResponse(changePoints)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /perf/noisy?project={project}
+
+type perfGetNoisyHandler struct {
+	project string
+	sc      data.Connector
+}
+
+func makeGetPerfNoisy(sc data.Connector) gimlet.RouteHandler {
+	return &perfGetNoisyHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfGetNoisyHandler.
+func (h *perfGetNoisyHandler) Factory() gimlet.RouteHandler {
+	return &perfGetNoisyHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the required project from the http request.
+func (h *perfGetNoisyHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.project = r.URL.Query().Get("project")
+	if h.project == "" {
+		return gimlet.ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Message:    "must specify a project",
+		}
+	}
+	return nil
+}
+
+// Run calls the data FindNoisyPerfSeries function and returns the
+// noisy series from the provider.
+func (h *perfGetNoisyHandler) Run(ctx context.Context) gimlet.Responder {
+	scores, err := h.sc.FindNoisyPerfSeries(h.project)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error getting noisy series for project '%s'", h.project))
+	}
+	return gimlet.NewJSONResponse(scores)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /perf/project/{project}/history
+
+type perfGetHistoryHandler struct {
+	project string
+	filter  data.PerfHistoryFilter
+	sc      data.Connector
+}
+
+func makeGetPerfHistory(sc data.Connector) gimlet.RouteHandler {
+	return &perfGetHistoryHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfGetHistoryHandler.
+func (h *perfGetHistoryHandler) Factory() gimlet.RouteHandler {
+	return &perfGetHistoryHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the project and the series from the http request. The
+// variant, task, test, and metric are required, and each argument of
+// the test is given as "args=name:value".
+func (h *perfGetHistoryHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.project = gimlet.GetVars(r)["project"]
+	vals := r.URL.Query()
+	h.filter = data.PerfHistoryFilter{
+		Variant:  vals.Get("variant"),
+		TaskName: vals.Get("task"),
+		TestName: vals.Get("test"),
+		Metric:   vals.Get("metric"),
+	}
+	for _, param := range []string{"variant", "task", "test", "metric"} {
+		if vals.Get(param) == "" {
+			return errors.Errorf("must specify %s", param)
+		}
+	}
+
+	var err error
+	h.filter.Arguments, err = parseArguments(vals["args"])
+	return err
+}
+
+// Run calls the data FindPerformanceHistory function and returns the
+// history from the provider.
+func (h *perfGetHistoryHandler) Run(ctx context.Context) gimlet.Responder {
+	history, err := h.sc.FindPerformanceHistory(h.project, h.filter)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error getting history for project '%s'", h.project))
+	}
+	return gimlet.NewJSONResponse(history)
+}
+
+// parseArguments parses test arguments of the form "name:value".
+func parseArguments(args []string) (map[string]int32, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	out := make(map[string]int32, len(args))
+	for _, arg := range args {
+		parts := strings.SplitN(arg, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("argument '%s' must be of the form name:value", arg)
+		}
+
+		val, err := strconv.ParseInt(parts[1], 10, 32)
+		if err != nil {
+			return nil, errors.Errorf("value of argument '%s' must be an integer", parts[0])
+		}
+		out[parts[0]] = int32(val)
+	}
+
+	return out, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /perf/project/{project}/baseline
+
+type perfGetBaselineHandler struct {
+	project string
+	variant string
+	task    string
+	sc      data.Connector
+}
+
+func makeGetPerfBaseline(sc data.Connector) gimlet.RouteHandler {
+	return &perfGetBaselineHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfGetBaselineHandler.
+func (h *perfGetBaselineHandler) Factory() gimlet.RouteHandler {
+	return &perfGetBaselineHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the project, variant and task from the http request.
+// The variant and task are required.
+func (h *perfGetBaselineHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.project, h.variant, h.task, err = parseBaselineKey(r)
+	return err
+}
+
+// Run calls the data FindPerformanceBaseline function and returns the
+// baseline from the provider.
+func (h *perfGetBaselineHandler) Run(ctx context.Context) gimlet.Responder {
+	baseline, err := h.sc.FindPerformanceBaseline(h.project, h.variant, h.task)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error getting baseline for project '%s'", h.project))
+	}
+	return gimlet.NewJSONResponse(baseline)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// PUT /perf/project/{project}/baseline
+
+type perfSetBaselineHandler struct {
+	project string
+	variant string
+	task    string
+	change  model.APIPerfBaselineChange
+	sc      data.Connector
+}
+
+func makeSetPerfBaseline(sc data.Connector) gimlet.RouteHandler {
+	return &perfSetBaselineHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfSetBaselineHandler.
+func (h *perfSetBaselineHandler) Factory() gimlet.RouteHandler {
+	return &perfSetBaselineHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the project, variant and task from the http request,
+// and the version or performance result of the baseline from the
+// body. The user who set the baseline is the authenticated user, if
+// any, and is otherwise taken from the body.
+func (h *perfSetBaselineHandler) Parse(ctx context.Context, r *http.Request) error {
+	var err error
+	h.project, h.variant, h.task, err = parseBaselineKey(r)
+	if err != nil {
+		return err
+	}
+
+	h.change = model.APIPerfBaselineChange{}
+	if err = gimlet.GetJSON(r.Body, &h.change); err != nil {
+		return errors.Wrap(err, "problem parsing baseline")
+	}
+	if user := gimlet.GetUser(ctx); user != nil {
+		h.change.SetBy = model.ToAPIString(user.Username())
+	}
+
+	return nil
+}
+
+// Run calls the data SetPerformanceBaseline function and returns the
+// updated baseline from the provider.
+func (h *perfSetBaselineHandler) Run(ctx context.Context) gimlet.Responder {
+	baseline, err := h.sc.SetPerformanceBaseline(h.project, h.variant, h.task, h.change)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error setting baseline for project '%s'", h.project))
+	}
+	return gimlet.NewJSONResponse(baseline)
+}
+
+// parseBaselineKey parses the project, variant and task of a baseline
+// from the http request.
+func parseBaselineKey(r *http.Request) (string, string, string, error) {
+	project := gimlet.GetVars(r)["project"]
+	vals := r.URL.Query()
+	for _, param := range []string{"variant", "task"} {
+		if vals.Get(param) == "" {
+			return "", "", "", errors.Errorf("must specify %s", param)
+		}
+	}
+
+	return project, vals.Get("variant"), vals.Get("task"), nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// GET /perf/compare/version/{base}/{comparison}
+// GET /perf/compare/task_id/{base}/{comparison}
+// GET /perf/compare/baseline/{comparison}
+
+const (
+	perfCompareByVersion  = "version"
+	perfCompareByTaskId   = "task_id"
+	perfCompareByBaseline = "baseline"
+)
+
+type perfCompareHandler struct {
+	by         string
+	base       string
+	comparison string
+	tags       []string
+	sc         data.Connector
+}
+
+func makeComparePerfByVersion(sc data.Connector) gimlet.RouteHandler {
+	return &perfCompareHandler{
+		by: perfCompareByVersion,
+		sc: sc,
+	}
+}
+
+func makeComparePerfByTaskId(sc data.Connector) gimlet.RouteHandler {
+	return &perfCompareHandler{
+		by: perfCompareByTaskId,
+		sc: sc,
+	}
+}
+
+func makeComparePerfToBaseline(sc data.Connector) gimlet.RouteHandler {
+	return &perfCompareHandler{
+		by: perfCompareByBaseline,
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfCompareHandler.
+func (h *perfCompareHandler) Factory() gimlet.RouteHandler {
+	return &perfCompareHandler{
+		by: h.by,
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the base and comparison versions or task ids from the
+// http request. There is no base version when comparing to the
+// baselines of the tasks.
+func (h *perfCompareHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.base = vars["base"]
+	h.comparison = vars["comparison"]
+	h.tags = r.URL.Query()["tags"]
+	return nil
+}
+
+// Run calls the data ComparePerformanceResultsByVersion,
+// ComparePerformanceResultsByTaskId or
+// ComparePerformanceResultsToBaseline function and returns the
+// comparisons from the provider.
+func (h *perfCompareHandler) Run(ctx context.Context) gimlet.Responder {
+	var (
+		comparisons []model.APIPerformanceComparison
+		err         error
+	)
+	switch h.by {
+	case perfCompareByVersion:
+		comparisons, err = h.sc.ComparePerformanceResultsByVersion(h.base, h.comparison, h.tags...)
+	case perfCompareByTaskId:
+		comparisons, err = h.sc.ComparePerformanceResultsByTaskId(h.base, h.comparison, h.tags...)
+	case perfCompareByBaseline:
+		comparisons, err = h.sc.ComparePerformanceResultsToBaseline(h.comparison, h.tags...)
+	default:
+		err = errors.Errorf("cannot compare performance results by '%s'", h.by)
+	}
+	if err != nil {
+		compared := fmt.Sprintf("%s '%s' and '%s'", h.by, h.base, h.comparison)
+		if h.by == perfCompareByBaseline {
+			compared = fmt.Sprintf("version '%s' and its baselines", h.comparison)
+		}
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error comparing performance results for %s", compared))
+	}
+	return gimlet.NewJSONResponse(comparisons)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// POST /perf
+
+type perfCreateHandler struct {
+	data model.APIPerformanceResultData
+	sc   data.Connector
+}
+
+func makeCreatePerf(sc data.Connector) gimlet.RouteHandler {
+	return &perfCreateHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfCreateHandler.
+func (h *perfCreateHandler) Factory() gimlet.RouteHandler {
+	return &perfCreateHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the performance result info, artifacts, and rollups
+// from the http request body.
+func (h *perfCreateHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.data = model.APIPerformanceResultData{}
+	return errors.Wrap(gimlet.GetJSON(r.Body, &h.data), "problem parsing performance result")
+}
+
+// Run calls the data CreatePerformanceResult function and returns the
+// new PerformanceResult from the provider.
+func (h *perfCreateHandler) Run(ctx context.Context) gimlet.Responder {
+	perfResult, err := h.sc.CreatePerformanceResult(h.data)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrap(err, "Error creating performance result"))
+	}
+	return gimlet.NewJSONResponse(perfResult)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// POST /perf/{id}/artifacts
+
+type perfAddArtifactsHandler struct {
+	id        string
+	artifacts []model.APIArtifactInfo
+	sc        data.Connector
+}
+
+func makeAddPerfArtifacts(sc data.Connector) gimlet.RouteHandler {
+	return &perfAddArtifactsHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfAddArtifactsHandler.
+func (h *perfAddArtifactsHandler) Factory() gimlet.RouteHandler {
+	return &perfAddArtifactsHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the id and the artifacts from the http request.
+func (h *perfAddArtifactsHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["id"]
+	h.artifacts = []model.APIArtifactInfo{}
+	return errors.Wrap(gimlet.GetJSON(r.Body, &h.artifacts), "problem parsing artifacts")
+}
+
+// Run calls the data AddPerformanceResultArtifacts function and
+// returns the updated PerformanceResult from the provider.
+func (h *perfAddArtifactsHandler) Run(ctx context.Context) gimlet.Responder {
+	perfResult, err := h.sc.AddPerformanceResultArtifacts(h.id, h.artifacts)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error adding artifacts to performance result '%s'", h.id))
+	}
+	return gimlet.NewJSONResponse(perfResult)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// PUT /perf/{id}/rollups
+
+type perfAddRollupsHandler struct {
+	id      string
+	rollups []model.APIPerfRollupValue
+	sc      data.Connector
+}
+
+func makeAddPerfRollups(sc data.Connector) gimlet.RouteHandler {
+	return &perfAddRollupsHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfAddRollupsHandler.
+func (h *perfAddRollupsHandler) Factory() gimlet.RouteHandler {
+	return &perfAddRollupsHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the id and the rollups from the http request.
+func (h *perfAddRollupsHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["id"]
+	h.rollups = []model.APIPerfRollupValue{}
+	return errors.Wrap(gimlet.GetJSON(r.Body, &h.rollups), "problem parsing rollups")
+}
+
+// Run calls the data AddPerformanceResultRollups function and returns
+// the updated PerformanceResult from the provider.
+func (h *perfAddRollupsHandler) Run(ctx context.Context) gimlet.Responder {
+	perfResult, err := h.sc.AddPerformanceResultRollups(h.id, h.rollups)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error adding rollups to performance result '%s'", h.id))
+	}
+	return gimlet.NewJSONResponse(perfResult)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// POST /perf/{id}/rollups/{name}/revert
+
+type perfRevertRollupHandler struct {
+	id      string
+	name    string
+	version int
+	sc      data.Connector
+}
+
+func makeRevertPerfRollup(sc data.Connector) gimlet.RouteHandler {
+	return &perfRevertRollupHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfRevertRollupHandler.
+func (h *perfRevertRollupHandler) Factory() gimlet.RouteHandler {
+	return &perfRevertRollupHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the id, the rollup name, and the version to revert to
+// from the http request. The body is of the form {"version": <int>}.
+func (h *perfRevertRollupHandler) Parse(ctx context.Context, r *http.Request) error {
+	vars := gimlet.GetVars(r)
+	h.id = vars["id"]
+	h.name = vars["name"]
+
+	body := struct {
+		Version *int `json:"version"`
+	}{}
+	if err := gimlet.GetJSON(r.Body, &body); err != nil {
+		return errors.Wrap(err, "problem parsing revert request")
+	}
+	if body.Version == nil {
+		return errors.New("must specify a version")
+	}
+	h.version = *body.Version
+
+	return nil
+}
+
+// Run calls the data RevertPerformanceResultRollup function and returns
+// the updated PerformanceResult from the provider.
+func (h *perfRevertRollupHandler) Run(ctx context.Context) gimlet.Responder {
+	perfResult, err := h.sc.RevertPerformanceResultRollup(h.id, h.name, h.version)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error reverting rollup '%s' of performance result '%s'", h.name, h.id))
+	}
+	return gimlet.NewJSONResponse(perfResult)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// POST /perf/{id}/timeseries
+
+type perfAddTimeseriesHandler struct {
+	id     string
+	format string
+	body   io.ReadCloser
+	sc     data.Connector
+}
+
+func makeAddPerfTimeseries(sc data.Connector) gimlet.RouteHandler {
+	return &perfAddTimeseriesHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfAddTimeseriesHandler.
+func (h *perfAddTimeseriesHandler) Factory() gimlet.RouteHandler {
+	return &perfAddTimeseriesHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the id and the format from the http request, which
+// defaults to FTDC. The body is read when the handler runs, so that
+// the data is streamed to storage.
+func (h *perfAddTimeseriesHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["id"]
+	h.format = r.URL.Query().Get("format")
+	if h.format == "" {
+		h.format = "ftdc"
+	}
+	if r.Body == nil {
+		return errors.New("no timeseries data")
+	}
+	h.body = r.Body
+	return nil
+}
+
+// Run calls the data AddPerformanceResultTimeseries function and
+// returns the updated PerformanceResult from the provider.
+func (h *perfAddTimeseriesHandler) Run(ctx context.Context) gimlet.Responder {
+	defer h.body.Close()
+
+	perfResult, err := h.sc.AddPerformanceResultTimeseries(ctx, h.id, h.format, h.body)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error adding timeseries to performance result '%s'", h.id))
+	}
+	return gimlet.NewJSONResponse(perfResult)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+//
+// POST /perf/{id}/close
+
+type perfCloseHandler struct {
+	id string
+	sc data.Connector
+}
+
+func makeClosePerf(sc data.Connector) gimlet.RouteHandler {
+	return &perfCloseHandler{
+		sc: sc,
+	}
+}
+
+// Factory returns a pointer to a new perfCloseHandler.
+func (h *perfCloseHandler) Factory() gimlet.RouteHandler {
+	return &perfCloseHandler{
+		sc: h.sc,
+	}
+}
+
+// Parse fetches the id from the http request.
+func (h *perfCloseHandler) Parse(ctx context.Context, r *http.Request) error {
+	h.id = gimlet.GetVars(r)["id"]
+	return nil
+}
+
+// Run calls the data ClosePerformanceResult function and returns the
+// completed PerformanceResult from the provider.
+func (h *perfCloseHandler) Run(ctx context.Context) gimlet.Responder {
+	perfResult, err := h.sc.ClosePerformanceResult(h.id)
+	if err != nil {
+		return gimlet.MakeJSONErrorResponder(errors.Wrapf(err, "Error closing performance result '%s'", h.id))
+	}
+	return gimlet.NewJSONResponse(perfResult)
+}
+
 ///////////////////////////////////////////////////////////////////////////////
 //
 // Helper functions
 
+func parseTimeseriesOptions(vals url.Values) (perf.TimeseriesExportOptions, error) {
+	opts := perf.TimeseriesExportOptions{
+		Format: dbmodel.FileDataFormat(vals.Get("format")),
+	}
+	if opts.Format == "" {
+		opts.Format = dbmodel.FileJSON
+	}
+
+	for _, bound := range []struct {
+		name  string
+		value *time.Time
+	}{
+		{name: "start", value: &opts.Start},
+		{name: "end", value: &opts.End},
+	} {
+		if vals.Get(bound.name) == "" {
+			continue
+		}
+
+		t, err := time.ParseInLocation(time.RFC3339, vals.Get(bound.name), time.UTC)
+		if err != nil {
+			return perf.TimeseriesExportOptions{}, errors.Errorf("problem parsing %s time '%s'", bound.name, vals.Get(bound.name))
+		}
+		*bound.value = t
+	}
+
+	return opts, errors.WithStack(opts.Validate())
+}
+
 func parseInterval(vals url.Values) (util.TimeRange, error) {
 	interval := util.TimeRange{}
 	startedAfter := vals.Get("started_after")
@@ -1,16 +1,29 @@
 package rest
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	dbmodel "github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
 	"github.com/evergreen-ci/cedar/rest/data"
 	"github.com/evergreen-ci/cedar/rest/model"
 	"github.com/evergreen-ci/cedar/util"
 	"github.com/evergreen-ci/gimlet"
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/ftdc/events"
+	"github.com/mongodb/ftdc/hdrhist"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -79,16 +92,73 @@ func (s *PerfHandlerSuite) setup() {
 			},
 		},
 	}
+	s.sc.CachedChangePoints = []model.APIChangePoint{
+		{
+			ID:           model.ToAPIString("cp0"),
+			Project:      model.ToAPIString("project0"),
+			Variant:      model.ToAPIString("variant0"),
+			TaskName:     model.ToAPIString("taskname0"),
+			TestName:     model.ToAPIString("test0"),
+			Measurement:  model.ToAPIString("latencyMedian"),
+			PerfResultID: model.ToAPIString("abc"),
+			Magnitude:    0.5,
+			Confidence:   0.99,
+		},
+		{
+			ID:           model.ToAPIString("cp1"),
+			Project:      model.ToAPIString("project0"),
+			Variant:      model.ToAPIString("variant1"),
+			TaskName:     model.ToAPIString("taskname0"),
+			TestName:     model.ToAPIString("test0"),
+			Measurement:  model.ToAPIString("latencyMedian"),
+			PerfResultID: model.ToAPIString("def"),
+			Magnitude:    -0.2,
+			Confidence:   0.96,
+		},
+		{
+			ID:           model.ToAPIString("cp2"),
+			Project:      model.ToAPIString("project1"),
+			Variant:      model.ToAPIString("variant0"),
+			TaskName:     model.ToAPIString("taskname1"),
+			TestName:     model.ToAPIString("test1"),
+			Measurement:  model.ToAPIString("throughputOpsMedian"),
+			PerfResultID: model.ToAPIString("lmn"),
+			Magnitude:    0.1,
+			Confidence:   0.97,
+		},
+	}
+	s.sc.CachedPerfNoise = []model.APIPerfNoise{
+		{
+			ID:          model.ToAPIString("noise0"),
+			Project:     model.ToAPIString("project0"),
+			TestName:    model.ToAPIString("test0"),
+			Measurement: model.ToAPIString("latencyMedian"),
+			Score:       0.3,
+			Threshold:   0.1,
+			Noisy:       true,
+		},
+		{
+			ID:          model.ToAPIString("noise1"),
+			Project:     model.ToAPIString("project0"),
+			TestName:    model.ToAPIString("test0"),
+			Measurement: model.ToAPIString("throughputOpsMedian"),
+			Score:       0.01,
+			Threshold:   0.1,
+		},
+	}
 	s.sc.ChildMap = map[string][]string{
 		"abc": []string{"def"},
 		"def": []string{"jkl"},
 	}
 	s.rh = map[string]gimlet.RouteHandler{
-		"id":        makeGetPerfById(&s.sc),
-		"task_id":   makeGetPerfByTaskId(&s.sc),
-		"task_name": makeGetPerfByTaskName(&s.sc),
-		"version":   makeGetPerfByVersion(&s.sc),
-		"children":  makeGetPerfChildren(&s.sc),
+		"id":            makeGetPerfById(&s.sc),
+		"task_id":       makeGetPerfByTaskId(&s.sc),
+		"task_name":     makeGetPerfByTaskName(&s.sc),
+		"version":       makeGetPerfByVersion(&s.sc),
+		"children":      makeGetPerfChildren(&s.sc),
+		"histogram":     makeGetPerfHistogram(&s.sc),
+		"change_points": makeGetPerfChangePoints(&s.sc),
+		"noisy":         makeGetPerfNoisy(&s.sc),
 	}
 }
 
@@ -209,6 +279,63 @@ func (s *PerfHandlerSuite) TestPerfGetByVersionHandlerNotFound() {
 	s.NotEqual(http.StatusOK, resp.Status())
 }
 
+func (s *PerfHandlerSuite) TestPerfGetByTaskIdHandlerPagination() {
+	app := gimlet.NewApp()
+	app.AddRoute("/perf/task_id/{task_id}").Version(1).Get().RouteHandler(makeGetPerfByTaskId(&s.sc))
+	s.Require().NoError(app.Resolve())
+	router, err := app.Router()
+	s.Require().NoError(err)
+
+	names := []string{}
+	next := "/v1/perf/task_id/123?tags=a&sort=-created_at&limit=2"
+	for pages := 0; next != ""; pages++ {
+		s.Require().True(pages < 3, "too many pages")
+
+		rw := httptest.NewRecorder()
+		router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, next, nil))
+		s.Require().Equal(http.StatusOK, rw.Code, rw.Body.String())
+
+		results := []model.APIPerformanceResult{}
+		s.Require().NoError(json.Unmarshal(rw.Body.Bytes(), &results))
+		s.True(len(results) <= 2)
+		for _, result := range results {
+			names = append(names, *result.Name)
+		}
+
+		next = ""
+		if link := rw.Header().Get("Link"); link != "" {
+			s.Contains(link, `rel="next"`)
+			linkURL, err := url.Parse(link[strings.Index(link, "<")+1 : strings.Index(link, ">")])
+			s.Require().NoError(err)
+			s.Equal("2", linkURL.Query().Get("limit"))
+			next = linkURL.RequestURI()
+		}
+	}
+	// results are sorted by creation time descending and then by name,
+	// and the tags filter is preserved across pages.
+	s.Equal([]string{"jkl", "def", "abc"}, names)
+
+	// without a limit or a cursor, all of the results are returned.
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/v1/perf/task_id/123?tags=a", nil))
+	s.Require().Equal(http.StatusOK, rw.Code, rw.Body.String())
+	s.Empty(rw.Header().Get("Link"))
+	results := []model.APIPerformanceResult{}
+	s.Require().NoError(json.Unmarshal(rw.Body.Bytes(), &results))
+	s.Len(results, 3)
+
+	for _, invalid := range []string{
+		"/v1/perf/task_id/123?limit=0",
+		"/v1/perf/task_id/123?limit=100000",
+		"/v1/perf/task_id/123?sort=name",
+		"/v1/perf/task_id/123?cursor=invalid",
+	} {
+		rw := httptest.NewRecorder()
+		router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, invalid, nil))
+		s.Equal(http.StatusBadRequest, rw.Code, invalid)
+	}
+}
+
 func (s *PerfHandlerSuite) TestPerfGetChildrenHandlerFound() {
 	rh := s.rh["children"]
 	rh.(*perfGetChildrenHandler).id = "abc"
@@ -236,6 +363,689 @@ func (s *PerfHandlerSuite) TestPerfGetChildrenHandlerNotFound() {
 	s.NotEqual(http.StatusOK, resp.Status())
 }
 
+func (s *PerfHandlerSuite) TestPerfGetHistogramHandlerFound() {
+	tmpDir, err := ioutil.TempDir("", "perf-histogram")
+	s.Require().NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	collector := ftdc.NewDynamicCollector(10)
+	point := &events.PerformanceHDR{}
+	point.Timers.Duration = hdrhist.New(1, int64(time.Second), 2)
+	for i := 1; i <= 10; i++ {
+		s.Require().NoError(point.Timers.Duration.RecordValue(int64(i * int(time.Millisecond))))
+		s.Require().NoError(collector.Add(perf.MarshalHistogramPoint(point)))
+	}
+	payload, err := collector.Resolve()
+	s.Require().NoError(err)
+	s.Require().NoError(ioutil.WriteFile(filepath.Join(tmpDir, "histogram.ftdc"), payload, 0644))
+
+	sc := data.MockConnector{
+		CachedPerformanceResults: map[string]model.APIPerformanceResult{
+			"hist": model.APIPerformanceResult{
+				Name: model.ToAPIString("hist"),
+				Artifacts: []model.APIArtifactInfo{
+					{
+						Type:   model.ToAPIString(string(dbmodel.PailLocal)),
+						Bucket: model.ToAPIString(tmpDir),
+						Path:   model.ToAPIString("histogram.ftdc"),
+						Format: model.ToAPIString(string(dbmodel.FileFTDC)),
+						Schema: model.ToAPIString(string(dbmodel.SchemaHistogram)),
+					},
+				},
+			},
+		},
+	}
+	rh := makeGetPerfHistogram(&sc)
+	rh.(*perfGetHistogramHandler).id = "hist"
+
+	resp := rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusOK, resp.Status())
+	histograms, ok := resp.Data().([]model.APIPerformanceHistogram)
+	s.Require().True(ok)
+	s.Require().Len(histograms, 1)
+	s.Equal("timers.duration", model.FromAPIString(histograms[0].Name))
+	s.Equal(int64(10), histograms[0].Count)
+	s.Len(histograms[0].Buckets, 10)
+}
+
+func (s *PerfHandlerSuite) TestPerfGetHistogramHandlerNotFound() {
+	rh := s.rh["histogram"]
+	for _, id := range []string{"DNE", "abc"} {
+		rh.(*perfGetHistogramHandler).id = id
+
+		resp := rh.Run(context.TODO())
+		s.Require().NotNil(resp)
+		s.Equal(http.StatusNotFound, resp.Status())
+	}
+}
+
+func (s *PerfHandlerSuite) TestPerfGetTimeseriesHandler() {
+	tmpDir, err := ioutil.TempDir("", "perf-timeseries")
+	s.Require().NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	start := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+	stream := make(chan events.Performance, 5)
+	for i := 0; i < 5; i++ {
+		stream <- events.Performance{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Counters:  events.PerformanceCounters{Number: int64(i)},
+		}
+	}
+	close(stream)
+	payload := &bytes.Buffer{}
+	gz := gzip.NewWriter(payload)
+	s.Require().NoError(dbmodel.DumpPerformanceSeries(context.TODO(), stream, nil, gz))
+	s.Require().NoError(gz.Close())
+	s.Require().NoError(ioutil.WriteFile(filepath.Join(tmpDir, "events.ftdc.gz"), payload.Bytes(), 0644))
+
+	sc := data.MockConnector{
+		CachedPerformanceResults: map[string]model.APIPerformanceResult{
+			"ts": model.APIPerformanceResult{
+				Name: model.ToAPIString("ts"),
+				Artifacts: []model.APIArtifactInfo{
+					{
+						Type:        model.ToAPIString(string(dbmodel.PailLocal)),
+						Bucket:      model.ToAPIString(tmpDir),
+						Path:        model.ToAPIString("events.ftdc.gz"),
+						Format:      model.ToAPIString(string(dbmodel.FileFTDC)),
+						Compression: model.ToAPIString(string(dbmodel.FileGz)),
+						Schema:      model.ToAPIString(string(dbmodel.SchemaRawEvents)),
+					},
+				},
+			},
+			"empty": model.APIPerformanceResult{
+				Name: model.ToAPIString("empty"),
+			},
+		},
+	}
+	app := gimlet.NewApp()
+	app.AddRoute("/perf/{id}/timeseries").Version(1).Get().Handler(makeGetPerfTimeseries(&sc))
+	s.Require().NoError(app.Resolve())
+	router, err := app.Router()
+	s.Require().NoError(err)
+
+	for _, test := range []struct {
+		name   string
+		url    string
+		status int
+		body   string
+	}{
+		{
+			name:   "JSON",
+			url:    "/v1/perf/ts/timeseries?start=2019-01-01T00:00:03Z",
+			status: http.StatusOK,
+			body:   `"n":3`,
+		},
+		{
+			name:   "CSV",
+			url:    "/v1/perf/ts/timeseries?format=csv&end=2019-01-01T00:00:01Z",
+			status: http.StatusOK,
+			body:   "2019-01-01T00:00:01Z",
+		},
+		{
+			name:   "InvalidFormat",
+			url:    "/v1/perf/ts/timeseries?format=text",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "InvalidTime",
+			url:    "/v1/perf/ts/timeseries?start=yesterday",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "NoTimeseries",
+			url:    "/v1/perf/empty/timeseries",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "NotFound",
+			url:    "/v1/perf/DNE/timeseries",
+			status: http.StatusNotFound,
+		},
+	} {
+		rw := httptest.NewRecorder()
+		router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, test.url, nil))
+		s.Equal(test.status, rw.Code, test.name)
+		s.Contains(rw.Body.String(), test.body, test.name)
+	}
+}
+
+func (s *PerfHandlerSuite) TestPerfGetChartHandler() {
+	tmpDir, err := ioutil.TempDir("", "perf-chart")
+	s.Require().NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	start := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+	stream := make(chan events.Performance, 100)
+	for i := 0; i < 100; i++ {
+		stream <- events.Performance{
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+			Counters:  events.PerformanceCounters{Number: int64(i), Operations: int64(i % 10)},
+		}
+	}
+	close(stream)
+	payload := &bytes.Buffer{}
+	s.Require().NoError(dbmodel.DumpPerformanceSeries(context.TODO(), stream, nil, payload))
+	s.Require().NoError(ioutil.WriteFile(filepath.Join(tmpDir, "events.ftdc"), payload.Bytes(), 0644))
+
+	sc := data.MockConnector{
+		CachedPerformanceResults: map[string]model.APIPerformanceResult{
+			"ts": model.APIPerformanceResult{
+				Name: model.ToAPIString("ts"),
+				Artifacts: []model.APIArtifactInfo{
+					{
+						Type:        model.ToAPIString(string(dbmodel.PailLocal)),
+						Bucket:      model.ToAPIString(tmpDir),
+						Path:        model.ToAPIString("events.ftdc"),
+						Format:      model.ToAPIString(string(dbmodel.FileFTDC)),
+						Compression: model.ToAPIString(string(dbmodel.FileUncompressed)),
+						Schema:      model.ToAPIString(string(dbmodel.SchemaRawEvents)),
+					},
+				},
+			},
+			"empty": model.APIPerformanceResult{
+				Name: model.ToAPIString("empty"),
+			},
+		},
+	}
+	app := gimlet.NewApp()
+	app.AddRoute("/perf/{id}/chart").Version(1).Get().RouteHandler(makeGetPerfChart(&sc))
+	s.Require().NoError(app.Resolve())
+	router, err := app.Router()
+	s.Require().NoError(err)
+
+	for _, test := range []struct {
+		name   string
+		url    string
+		status int
+		series map[string]int
+	}{
+		{
+			name:   "Metric",
+			url:    "/v1/perf/ts/chart?metric=counters.ops&points=20",
+			status: http.StatusOK,
+			series: map[string]int{"counters.ops": 20},
+		},
+		{
+			name:   "AllMetrics",
+			url:    "/v1/perf/ts/chart",
+			status: http.StatusOK,
+			series: map[string]int{"counters.n": 100, "counters.ops": 100},
+		},
+		{
+			name:   "InvalidPoints",
+			url:    "/v1/perf/ts/chart?points=many",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "TooFewPoints",
+			url:    "/v1/perf/ts/chart?points=2",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "MissingMetric",
+			url:    "/v1/perf/ts/chart?metric=counters.foo",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "NoTimeseries",
+			url:    "/v1/perf/empty/chart",
+			status: http.StatusNotFound,
+		},
+		{
+			name:   "NotFound",
+			url:    "/v1/perf/DNE/chart",
+			status: http.StatusNotFound,
+		},
+	} {
+		rw := httptest.NewRecorder()
+		router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, test.url, nil))
+		s.Require().Equal(test.status, rw.Code, test.name)
+		if test.status != http.StatusOK {
+			continue
+		}
+
+		chart := &model.APIPerfChart{}
+		s.Require().NoError(json.Unmarshal(rw.Body.Bytes(), chart), test.name)
+		s.Equal("ts", model.FromAPIString(chart.PerfID), test.name)
+		points := map[string]int{}
+		for _, series := range chart.Series {
+			points[model.FromAPIString(series.Metric)] = len(series.Points)
+		}
+		for metric, expected := range test.series {
+			s.Equal(expected, points[metric], metric)
+		}
+	}
+}
+
+func (s *PerfHandlerSuite) TestPerfGetChangePointsHandler() {
+	rh := s.rh["change_points"]
+	for _, test := range []struct {
+		name     string
+		project  string
+		filter   data.ChangePointFilter
+		expected []model.APIChangePoint
+	}{
+		{
+			name:     "Project",
+			project:  "project0",
+			expected: s.sc.CachedChangePoints[:2],
+		},
+		{
+			name:     "Variant",
+			project:  "project0",
+			filter:   data.ChangePointFilter{Variant: "variant1"},
+			expected: s.sc.CachedChangePoints[1:2],
+		},
+		{
+			name:     "NoMatches",
+			project:  "project1",
+			filter:   data.ChangePointFilter{Measurement: "latencyMedian"},
+			expected: []model.APIChangePoint{},
+		},
+	} {
+		rh.(*perfGetChangePointsHandler).project = test.project
+		rh.(*perfGetChangePointsHandler).filter = test.filter
+
+		resp := rh.Run(context.TODO())
+		s.Require().NotNil(resp, test.name)
+		s.Equal(http.StatusOK, resp.Status(), test.name)
+		s.Equal(test.expected, resp.Data(), test.name)
+	}
+}
+
+func (s *PerfHandlerSuite) TestPerfGetNoisyHandler() {
+	rh := s.rh["noisy"]
+
+	req := httptest.NewRequest(http.MethodGet, "/perf/noisy", nil)
+	s.Error(rh.Parse(context.TODO(), req))
+
+	req = httptest.NewRequest(http.MethodGet, "/perf/noisy?project=project0", nil)
+	s.Require().NoError(rh.Parse(context.TODO(), req))
+	resp := rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal(s.sc.CachedPerfNoise[:1], resp.Data())
+
+	rh.(*perfGetNoisyHandler).project = "project1"
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusOK, resp.Status())
+	s.Equal([]model.APIPerfNoise{}, resp.Data())
+}
+
+func (s *PerfHandlerSuite) TestPerfGetHistoryHandler() {
+	result := func(name, version string, execution, trial int, args map[string]int32, createdAt time.Time, value interface{}) model.APIPerformanceResult {
+		return model.APIPerformanceResult{
+			Name:      model.ToAPIString(name),
+			CreatedAt: model.NewTime(createdAt),
+			Info: model.APIPerformanceResultInfo{
+				Project:   model.ToAPIString("project"),
+				Version:   model.ToAPIString(version),
+				Variant:   model.ToAPIString("variant"),
+				TaskName:  model.ToAPIString("task"),
+				TestName:  model.ToAPIString("test"),
+				Execution: execution,
+				Trial:     trial,
+				Arguments: args,
+			},
+			Rollups: &model.APIPerfRollups{
+				Stats: []model.APIPerfRollupValue{{Name: model.ToAPIString("latency"), Value: value}},
+			},
+		}
+	}
+	start := time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC)
+	args := map[string]int32{"threads": 4}
+	sc := data.MockConnector{
+		CachedPerformanceResults: map[string]model.APIPerformanceResult{
+			"v2":       result("v2", "2", 0, 0, args, start.Add(2*time.Hour), 2.0),
+			"v1":       result("v1", "1", 0, 0, args, start, 1.0),
+			"v1-retry": result("v1-retry", "1", 1, 0, args, start.Add(time.Minute), 1.5),
+			"v1-trial": result("v1-trial", "1", 1, 1, args, start.Add(time.Minute), 100.0),
+			"v3-args":  result("v3-args", "3", 0, 0, map[string]int32{"threads": 8}, start.Add(3*time.Hour), 3.0),
+		},
+	}
+	app := gimlet.NewApp()
+	app.AddRoute("/perf/project/{project}/history").Version(1).Get().RouteHandler(makeGetPerfHistory(&sc))
+	s.Require().NoError(app.Resolve())
+	router, err := app.Router()
+	s.Require().NoError(err)
+
+	rw := httptest.NewRecorder()
+	router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet,
+		"/v1/perf/project/project/history?variant=variant&task=task&test=test&metric=latency&args=threads:4", nil))
+	s.Require().Equal(http.StatusOK, rw.Code, rw.Body.String())
+
+	points := []model.APIPerfHistoryPoint{}
+	s.Require().NoError(json.Unmarshal(rw.Body.Bytes(), &points))
+	s.Require().Len(points, 2)
+	s.Equal("1", *points[0].Version)
+	s.Equal(1.5, points[0].Value)
+	s.Equal("2", *points[1].Version)
+	s.Equal(2.0, points[1].Value)
+
+	for _, test := range []struct {
+		url    string
+		status int
+	}{
+		{url: "/v1/perf/project/project/history?variant=variant&task=task&test=test&metric=latency", status: http.StatusOK},
+		{url: "/v1/perf/project/project/history?variant=variant&task=task&test=test", status: http.StatusBadRequest},
+		{url: "/v1/perf/project/project/history?variant=variant&task=task&test=test&metric=latency&args=threads", status: http.StatusBadRequest},
+		{url: "/v1/perf/project/project/history?variant=variant&task=task&test=test&metric=latency&args=threads:four", status: http.StatusBadRequest},
+	} {
+		rw = httptest.NewRecorder()
+		router.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, test.url, nil))
+		s.Equal(test.status, rw.Code, test.url)
+	}
+}
+
+func (s *PerfHandlerSuite) TestPerfBaselineHandlers() {
+	result := func(name, version, taskID string, execution int, value float64) model.APIPerformanceResult {
+		return model.APIPerformanceResult{
+			Name:      model.ToAPIString(name),
+			CreatedAt: model.NewTime(time.Date(2019, time.January, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(len(version)) * time.Hour)),
+			Info: model.APIPerformanceResultInfo{
+				Project:   model.ToAPIString("project"),
+				Version:   model.ToAPIString(version),
+				Variant:   model.ToAPIString("variant"),
+				TaskName:  model.ToAPIString("task"),
+				TaskID:    model.ToAPIString(taskID),
+				Execution: execution,
+				TestName:  model.ToAPIString("test"),
+			},
+			Rollups: &model.APIPerfRollups{
+				Stats: []model.APIPerfRollupValue{{Name: model.ToAPIString("latency"), Value: value}},
+			},
+		}
+	}
+	sc := data.MockConnector{
+		CachedPerformanceResults: map[string]model.APIPerformanceResult{
+			"base":       result("base", "v1", "base-task", 0, 100),
+			"base-retry": result("base-retry", "v1", "base-task", 1, 200),
+			"next":       result("next", "v11", "next-task", 0, 150),
+			"patch":      result("patch", "v111", "patch-task", 0, 300),
+		},
+	}
+	app := gimlet.NewApp()
+	app.AddRoute("/perf/project/{project}/baseline").Version(1).Get().RouteHandler(makeGetPerfBaseline(&sc))
+	app.AddRoute("/perf/project/{project}/baseline").Version(1).Put().RouteHandler(makeSetPerfBaseline(&sc))
+	app.AddRoute("/perf/project/{project}/history").Version(1).Get().RouteHandler(makeGetPerfHistory(&sc))
+	app.AddRoute("/perf/compare/baseline/{comparison}").Version(1).Get().RouteHandler(makeComparePerfToBaseline(&sc))
+	s.Require().NoError(app.Resolve())
+	router, err := app.Router()
+	s.Require().NoError(err)
+
+	serve := func(method, url string, body interface{}) *httptest.ResponseRecorder {
+		var payload []byte
+		if body != nil {
+			payload, err = json.Marshal(body)
+			s.Require().NoError(err)
+		}
+		rw := httptest.NewRecorder()
+		router.ServeHTTP(rw, httptest.NewRequest(method, url, bytes.NewReader(payload)))
+		return rw
+	}
+	baselineURL := "/v1/perf/project/project/baseline?variant=variant&task=task"
+
+	rw := serve(http.MethodGet, baselineURL, nil)
+	s.Equal(http.StatusNotFound, rw.Code)
+	rw = serve(http.MethodGet, "/v1/perf/compare/baseline/v111", nil)
+	s.Equal(http.StatusNotFound, rw.Code)
+
+	for _, test := range []struct {
+		url    string
+		change model.APIPerfBaselineChange
+		status int
+	}{
+		{url: "/v1/perf/project/project/baseline?variant=variant", change: model.APIPerfBaselineChange{Version: model.ToAPIString("v1"), SetBy: model.ToAPIString("user")}, status: http.StatusBadRequest},
+		{url: baselineURL, change: model.APIPerfBaselineChange{Version: model.ToAPIString("v1")}, status: http.StatusBadRequest},
+		{url: baselineURL, change: model.APIPerfBaselineChange{SetBy: model.ToAPIString("user")}, status: http.StatusBadRequest},
+		{url: baselineURL, change: model.APIPerfBaselineChange{Version: model.ToAPIString("DNE"), SetBy: model.ToAPIString("user")}, status: http.StatusNotFound},
+		{url: baselineURL, change: model.APIPerfBaselineChange{PerfResultID: model.ToAPIString("DNE"), SetBy: model.ToAPIString("user")}, status: http.StatusNotFound},
+		{url: baselineURL, change: model.APIPerfBaselineChange{Version: model.ToAPIString("v11"), PerfResultID: model.ToAPIString("base"), SetBy: model.ToAPIString("user")}, status: http.StatusBadRequest},
+		{url: "/v1/perf/project/project/baseline?variant=other&task=task", change: model.APIPerfBaselineChange{PerfResultID: model.ToAPIString("base"), SetBy: model.ToAPIString("user")}, status: http.StatusBadRequest},
+	} {
+		rw = serve(http.MethodPut, test.url, test.change)
+		s.Equal(test.status, rw.Code, rw.Body.String())
+	}
+	s.Empty(sc.CachedPerfBaselines)
+
+	rw = serve(http.MethodPut, baselineURL, model.APIPerfBaselineChange{Version: model.ToAPIString("v1"), SetBy: model.ToAPIString("user")})
+	s.Require().Equal(http.StatusOK, rw.Code, rw.Body.String())
+	rw = serve(http.MethodPut, baselineURL, model.APIPerfBaselineChange{PerfResultID: model.ToAPIString("base-retry"), SetBy: model.ToAPIString("other")})
+	s.Require().Equal(http.StatusOK, rw.Code, rw.Body.String())
+
+	rw = serve(http.MethodGet, baselineURL, nil)
+	s.Require().Equal(http.StatusOK, rw.Code, rw.Body.String())
+	baseline := model.APIPerfBaseline{}
+	s.Require().NoError(json.Unmarshal(rw.Body.Bytes(), &baseline))
+	s.Equal("v1", model.FromAPIString(baseline.Version))
+	s.Equal("base-retry", model.FromAPIString(baseline.PerfResultID))
+	s.Equal("other", model.FromAPIString(baseline.SetBy))
+	s.False(time.Time(baseline.SetAt).IsZero())
+	s.Require().Len(baseline.History, 2)
+	s.Equal("user", model.FromAPIString(baseline.History[0].SetBy))
+	s.Empty(model.FromAPIString(baseline.History[0].PerfResultID))
+	s.Equal("base-retry", model.FromAPIString(baseline.History[1].PerfResultID))
+
+	rw = serve(http.MethodGet, "/v1/perf/compare/baseline/v111", nil)
+	s.Require().Equal(http.StatusOK, rw.Code, rw.Body.String())
+	comparisons := []model.APIPerformanceComparison{}
+	s.Require().NoError(json.Unmarshal(rw.Body.Bytes(), &comparisons))
+	s.Require().Len(comparisons, 1)
+	s.Equal([]string{"base-retry"}, comparisons[0].BaseIDs)
+	s.Equal([]string{"patch"}, comparisons[0].ComparisonIDs)
+	s.Require().Len(comparisons[0].Rollups, 1)
+	s.Equal(200.0, comparisons[0].Rollups[0].Base)
+	s.Equal(300.0, comparisons[0].Rollups[0].Comparison)
+
+	rw = serve(http.MethodGet, "/v1/perf/project/project/history?variant=variant&task=task&test=test&metric=latency", nil)
+	s.Require().Equal(http.StatusOK, rw.Code, rw.Body.String())
+	points := []model.APIPerfHistoryPoint{}
+	s.Require().NoError(json.Unmarshal(rw.Body.Bytes(), &points))
+	s.Require().Len(points, 3)
+	s.Equal("v1", model.FromAPIString(points[0].Version))
+	s.True(points[0].Baseline)
+	s.False(points[1].Baseline)
+	s.Require().NotNil(points[2].BaselinePercentDelta)
+	s.Equal(50.0, *points[2].BaselinePercentDelta)
+}
+
+func (s *PerfHandlerSuite) TestPerfWriteHandlers() {
+	tmpDir, err := ioutil.TempDir("", "perf-write")
+	s.Require().NoError(err)
+	defer os.RemoveAll(tmpDir)
+
+	sc := data.MockConnector{Bucket: tmpDir}
+
+	rh := makeCreatePerf(&sc)
+	rh.(*perfCreateHandler).data = model.APIPerformanceResultData{
+		Info: model.APIPerformanceResultInfo{
+			Project:  model.ToAPIString("project"),
+			TaskID:   model.ToAPIString("task"),
+			TestName: model.ToAPIString("test"),
+		},
+		Rollups: []model.APIPerfRollupValue{
+			{Name: model.ToAPIString("ops"), Value: 10},
+		},
+	}
+	resp := rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	created, ok := resp.Data().(*model.APIPerformanceResult)
+	s.Require().True(ok)
+	id := model.FromAPIString(created.Name)
+	s.NotEmpty(id)
+	s.Contains(sc.CachedPerformanceResults, id)
+
+	rh = makeCreatePerf(&sc)
+	rh.(*perfCreateHandler).data = model.APIPerformanceResultData{
+		Info: model.APIPerformanceResultInfo{
+			Project: model.ToAPIString("project"),
+			Schema:  1000,
+		},
+	}
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusBadRequest, resp.Status())
+
+	rh = makeAddPerfArtifacts(&sc)
+	rh.(*perfAddArtifactsHandler).id = id
+	rh.(*perfAddArtifactsHandler).artifacts = []model.APIArtifactInfo{
+		{
+			Type:   model.ToAPIString(string(dbmodel.PailLocal)),
+			Bucket: model.ToAPIString(tmpDir),
+			Path:   model.ToAPIString("existing.ftdc"),
+			Format: model.ToAPIString(string(dbmodel.FileFTDC)),
+			Schema: model.ToAPIString(string(dbmodel.SchemaRawEvents)),
+		},
+	}
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	s.Len(resp.Data().(*model.APIPerformanceResult).Artifacts, 1)
+	s.Equal(string(dbmodel.FileUncompressed), model.FromAPIString(resp.Data().(*model.APIPerformanceResult).Artifacts[0].Compression))
+
+	rh.(*perfAddArtifactsHandler).artifacts = []model.APIArtifactInfo{{Path: model.ToAPIString("invalid")}}
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusBadRequest, resp.Status())
+
+	rh = makeAddPerfRollups(&sc)
+	rh.(*perfAddRollupsHandler).id = id
+	rh.(*perfAddRollupsHandler).rollups = []model.APIPerfRollupValue{
+		{Name: model.ToAPIString("ops"), Value: 20},
+		{Name: model.ToAPIString("size"), Value: 30},
+	}
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	s.Equal(2, resp.Data().(*model.APIPerformanceResult).Rollups.Count)
+
+	rh.(*perfAddRollupsHandler).rollups = []model.APIPerfRollupValue{{Name: model.ToAPIString("empty")}}
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusBadRequest, resp.Status())
+
+	rh.(*perfAddRollupsHandler).rollups = []model.APIPerfRollupValue{
+		{Name: model.ToAPIString("ops"), Value: 40, Version: 2, Calculator: model.ToAPIString("new")},
+	}
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Require().Equal(http.StatusOK, resp.Status())
+
+	rh = makeGetPerfRollupHistory(&sc)
+	rh.(*perfGetRollupHistoryHandler).id = id
+	rh.(*perfGetRollupHistoryHandler).name = "ops"
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	history, ok := resp.Data().(*model.APIPerfRollupHistory)
+	s.Require().True(ok)
+	s.Require().NotNil(history.Latest)
+	s.Equal(40, history.Latest.Value)
+	s.Require().Len(history.History, 3)
+	for i, value := range []int{10, 20, 40} {
+		s.Equal(value, history.History[i].Value)
+	}
+
+	rh.(*perfGetRollupHistoryHandler).name = "DNE"
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusNotFound, resp.Status())
+
+	rh = makeRevertPerfRollup(&sc)
+	rh.(*perfRevertRollupHandler).id = id
+	rh.(*perfRevertRollupHandler).name = "ops"
+	rh.(*perfRevertRollupHandler).version = 0
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	for _, stat := range resp.Data().(*model.APIPerformanceResult).Rollups.Stats {
+		if model.FromAPIString(stat.Name) == "ops" {
+			s.Equal(20, stat.Value)
+		}
+	}
+
+	rh.(*perfRevertRollupHandler).version = 3
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusNotFound, resp.Status())
+
+	rh = makeAddPerfTimeseries(&sc)
+	for _, test := range []struct {
+		name   string
+		format string
+		body   string
+		status int
+	}{
+		{
+			name:   "Array",
+			format: "json",
+			body:   `[{"counters": {"n": 1, "ops": 1}}, {"counters": {"n": 2, "ops": 2}}]`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "Stream",
+			format: "json",
+			body:   "{\"counters\": {\"n\": 1}}\n{\"counters\": {\"n\": 2}}\n",
+			status: http.StatusOK,
+		},
+		{
+			name:   "InvalidJSON",
+			format: "json",
+			body:   `[{"counters": `,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "Empty",
+			format: "json",
+			body:   "[]",
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "UnsupportedFormat",
+			format: "csv",
+			body:   "n,ops\n1,1\n",
+			status: http.StatusBadRequest,
+		},
+	} {
+		rh.(*perfAddTimeseriesHandler).id = id
+		rh.(*perfAddTimeseriesHandler).format = test.format
+		rh.(*perfAddTimeseriesHandler).body = ioutil.NopCloser(strings.NewReader(test.body))
+
+		resp = rh.Run(context.TODO())
+		s.Require().NotNil(resp, test.name)
+		s.Equal(test.status, resp.Status(), test.name)
+	}
+	s.Len(sc.CachedPerformanceResults[id].Artifacts, 3)
+
+	rh.(*perfAddTimeseriesHandler).format = "ftdc"
+	rh.(*perfAddTimeseriesHandler).body = ioutil.NopCloser(strings.NewReader("not ftdc"))
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusBadRequest, resp.Status())
+
+	// only the valid timeseries data is kept in the bucket.
+	files, err := ioutil.ReadDir(filepath.Join(tmpDir, id))
+	s.Require().NoError(err)
+	s.Len(files, 2)
+
+	rh = makeClosePerf(&sc)
+	rh.(*perfCloseHandler).id = id
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Require().Equal(http.StatusOK, resp.Status())
+	s.False(time.Time(resp.Data().(*model.APIPerformanceResult).CompletedAt).IsZero())
+
+	rh.(*perfCloseHandler).id = "DNE"
+	resp = rh.Run(context.TODO())
+	s.Require().NotNil(resp)
+	s.Equal(http.StatusNotFound, resp.Status())
+}
+
 func (s *PerfHandlerSuite) TestParse() {
 	for _, test := range []struct {
 		urlString string
@@ -27,6 +27,8 @@ type StatusResponse struct {
 	QueueStats   amboy.QueueStats `json:"queue,omitempty"`
 	QueueRunning bool             `json:"running"`
 	RPCInfo      []string         `json:"rpc_service"`
+
+	RollupRecalculation *model.PerfRollupRecalculationStatus `json:"rollup_recalculation,omitempty"`
 }
 
 // statusHandler processes the GET request for
@@ -41,6 +43,14 @@ func (s *Service) statusHandler(w http.ResponseWriter, r *http.Request) {
 		resp.QueueStats = s.queue.Stats()
 	}
 
+	recalculation := &model.PerfRollupRecalculationStatus{}
+	recalculation.Setup(s.Environment)
+	if err := recalculation.Find(); err != nil {
+		grip.Warning(err)
+	} else if !recalculation.IsNil() {
+		resp.RollupRecalculation = recalculation
+	}
+
 	gimlet.WriteJSON(w, resp)
 }
 
@@ -271,7 +281,7 @@ func (s *Service) simpleLogGetText(w http.ResponseWriter, r *http.Request) {
 	for _, l := range allLogs.Slice() {
 		bucket, ok := buckets[l.Bucket]
 		if !ok {
-			bucket, err = pail.NewS3Bucket(pail.S3Options{Name: l.Bucket})
+			bucket, err = model.PailS3.Create(s.Environment, l.Bucket)
 			if err != nil {
 				gimlet.WriteTextError(w, err.Error())
 				return
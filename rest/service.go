@@ -111,9 +111,27 @@ func (s *Service) addRoutes() {
 	s.app.AddRoute("/depgraph/{id}/edges").Version(1).Post().Handler(s.addDepGraphEdges)
 	s.app.AddRoute("/depgraph/{id}/edges").Version(1).Get().Handler(s.getDepGraphEdges)
 
+	s.app.AddRoute("/perf").Version(1).Post().RouteHandler(makeCreatePerf(s.sc))
+	s.app.AddRoute("/perf/noisy").Version(1).Get().RouteHandler(makeGetPerfNoisy(s.sc))
 	s.app.AddRoute("/perf/{id}").Version(1).Get().RouteHandler(makeGetPerfById(s.sc))
+	s.app.AddRoute("/perf/{id}/artifacts").Version(1).Post().RouteHandler(makeAddPerfArtifacts(s.sc))
+	s.app.AddRoute("/perf/{id}/rollups").Version(1).Put().RouteHandler(makeAddPerfRollups(s.sc))
+	s.app.AddRoute("/perf/{id}/rollups/{name}").Version(1).Get().RouteHandler(makeGetPerfRollupHistory(s.sc))
+	s.app.AddRoute("/perf/{id}/rollups/{name}/revert").Version(1).Post().RouteHandler(makeRevertPerfRollup(s.sc))
+	s.app.AddRoute("/perf/{id}/timeseries").Version(1).Post().RouteHandler(makeAddPerfTimeseries(s.sc))
+	s.app.AddRoute("/perf/{id}/close").Version(1).Post().RouteHandler(makeClosePerf(s.sc))
 	s.app.AddRoute("/perf/task_id/{task_id}").Version(1).Get().RouteHandler(makeGetPerfByTaskId(s.sc))
 	s.app.AddRoute("/perf/task_name/{task_name}").Version(1).Get().RouteHandler(makeGetPerfByTaskName(s.sc))
 	s.app.AddRoute("/perf/version/{version}").Version(1).Get().RouteHandler(makeGetPerfByVersion(s.sc))
 	s.app.AddRoute("/perf/children/{id}").Version(1).Get().RouteHandler(makeGetPerfChildren(s.sc))
+	s.app.AddRoute("/perf/{id}/histogram").Version(1).Get().RouteHandler(makeGetPerfHistogram(s.sc))
+	s.app.AddRoute("/perf/{id}/chart").Version(1).Get().RouteHandler(makeGetPerfChart(s.sc))
+	s.app.AddRoute("/perf/{id}/timeseries").Version(1).Get().Handler(makeGetPerfTimeseries(s.sc))
+	s.app.AddRoute("/perf/project/{project}/change_points").Version(1).Get().RouteHandler(makeGetPerfChangePoints(s.sc))
+	s.app.AddRoute("/perf/project/{project}/history").Version(1).Get().RouteHandler(makeGetPerfHistory(s.sc))
+	s.app.AddRoute("/perf/project/{project}/baseline").Version(1).Get().RouteHandler(makeGetPerfBaseline(s.sc))
+	s.app.AddRoute("/perf/project/{project}/baseline").Version(1).Put().RouteHandler(makeSetPerfBaseline(s.sc))
+	s.app.AddRoute("/perf/compare/version/{base}/{comparison}").Version(1).Get().RouteHandler(makeComparePerfByVersion(s.sc))
+	s.app.AddRoute("/perf/compare/task_id/{base}/{comparison}").Version(1).Get().RouteHandler(makeComparePerfByTaskId(s.sc))
+	s.app.AddRoute("/perf/compare/baseline/{comparison}").Version(1).Get().RouteHandler(makeComparePerfToBaseline(s.sc))
 }
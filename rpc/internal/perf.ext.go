@@ -4,6 +4,7 @@ import (
 	"github.com/evergreen-ci/cedar/model"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/mongodb/ftdc/events"
+	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
 )
 
@@ -94,6 +95,8 @@ func (s SchemaType) Export() model.FileSchema {
 		return model.SchemaIntervalSummary
 	case SchemaType_HISTOGRAM:
 		return model.SchemaHistogram
+	case SchemaType_CUSTOM_EVENTS:
+		return model.SchemaCustomEvents
 	default:
 		return model.SchemaRawEvents
 	}
@@ -103,6 +106,7 @@ func (m *ResultID) Export() *model.PerformanceResultInfo {
 	return &model.PerformanceResultInfo{
 		Project:   m.Project,
 		Version:   m.Version,
+		Variant:   m.Variant,
 		TaskID:    m.TaskId,
 		TaskName:  m.TaskName,
 		Execution: int(m.Execution),
@@ -144,7 +148,12 @@ func (r *ResultData) Export() (*model.PerformanceResult, error) {
 		artifacts = append(artifacts, *artifact)
 	}
 
-	return model.CreatePerformanceResult(*r.Id.Export(), artifacts), nil
+	info := r.Id.Export()
+	if err := info.ValidateSchema(); err != nil {
+		return nil, errors.Wrap(err, "invalid result id")
+	}
+
+	return model.CreatePerformanceResult(*info, artifacts), nil
 }
 
 func (m *MetricsPoint) Export() (*events.Performance, error) {
@@ -177,6 +186,37 @@ func (m *MetricsPoint) Export() (*events.Performance, error) {
 	return point, nil
 }
 
+func (m *CustomMetricsPoint) Export() (events.Custom, error) {
+	ts, err := ptypes.Timestamp(m.Time)
+	if err != nil {
+		return nil, errors.Wrap(err, "problem converting timestamp value")
+	}
+
+	point := events.MakeCustom(len(m.Metrics) + 1)
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(point.Add("ts", ts))
+	for _, metric := range m.Metrics {
+		if metric.Name == "" || metric.Name == "ts" {
+			catcher.Add(errors.Errorf("invalid custom metric name '%s'", metric.Name))
+			continue
+		}
+
+		switch v := metric.Value.(type) {
+		case *CustomMetric_Int:
+			catcher.Add(point.Add(metric.Name, v.Int))
+		case *CustomMetric_Fl:
+			catcher.Add(point.Add(metric.Name, v.Fl))
+		default:
+			catcher.Add(errors.Errorf("custom metric '%s' has no value", metric.Name))
+		}
+	}
+	if catcher.HasErrors() {
+		return nil, catcher.Resolve()
+	}
+
+	return point, nil
+}
+
 func (r *RollupValue) Export() model.PerfRollupValue {
 	return model.PerfRollupValue{
 		Name:          r.Name,
@@ -0,0 +1,50 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomMetricsPointExport(t *testing.T) {
+	now := time.Now().UTC().Round(time.Millisecond)
+	ts, err := ptypes.TimestampProto(now)
+	require.NoError(t, err)
+
+	t.Run("Valid", func(t *testing.T) {
+		point, err := (&CustomMetricsPoint{
+			Time: ts,
+			Metrics: []*CustomMetric{
+				{Name: "hits", Value: &CustomMetric_Int{Int: 42}},
+				{Name: "ratio", Value: &CustomMetric_Fl{Fl: 0.5}},
+			},
+		}).Export()
+		require.NoError(t, err)
+		require.Len(t, point, 3)
+
+		values := map[string]interface{}{}
+		for _, metric := range point {
+			values[metric.Name] = metric.Value
+		}
+		assert.Equal(t, now, values["ts"])
+		assert.Equal(t, int64(42), values["hits"])
+		assert.Equal(t, 0.5, values["ratio"])
+	})
+	t.Run("InvalidTimestamp", func(t *testing.T) {
+		_, err := (&CustomMetricsPoint{}).Export()
+		assert.Error(t, err)
+	})
+	t.Run("InvalidMetrics", func(t *testing.T) {
+		for _, metric := range []*CustomMetric{
+			{Value: &CustomMetric_Int{Int: 1}},
+			{Name: "ts", Value: &CustomMetric_Int{Int: 1}},
+			{Name: "hits"},
+		} {
+			_, err := (&CustomMetricsPoint{Time: ts, Metrics: []*CustomMetric{metric}}).Export()
+			assert.Error(t, err)
+		}
+	})
+}
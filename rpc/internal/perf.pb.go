@@ -134,6 +134,7 @@ const (
 	SchemaType_COLLAPSED_EVENTS       SchemaType = 1
 	SchemaType_INTERVAL_SUMMARIZATION SchemaType = 2
 	SchemaType_HISTOGRAM              SchemaType = 3
+	SchemaType_CUSTOM_EVENTS          SchemaType = 4
 )
 
 var SchemaType_name = map[int32]string{
@@ -141,6 +142,7 @@ var SchemaType_name = map[int32]string{
 	1: "COLLAPSED_EVENTS",
 	2: "INTERVAL_SUMMARIZATION",
 	3: "HISTOGRAM",
+	4: "CUSTOM_EVENTS",
 }
 
 var SchemaType_value = map[string]int32{
@@ -148,6 +150,7 @@ var SchemaType_value = map[string]int32{
 	"COLLAPSED_EVENTS":       1,
 	"INTERVAL_SUMMARIZATION": 2,
 	"HISTOGRAM":              3,
+	"CUSTOM_EVENTS":          4,
 }
 
 func (x SchemaType) String() string {
@@ -228,6 +231,7 @@ type ResultID struct {
 	Tags                 []string         `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
 	Arguments            map[string]int32 `protobuf:"bytes,10,rep,name=arguments,proto3" json:"arguments,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
 	Schema               int32            `protobuf:"varint,11,opt,name=schema,proto3" json:"schema,omitempty"`
+	Variant              string           `protobuf:"bytes,12,opt,name=variant,proto3" json:"variant,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
 	XXX_unrecognized     []byte           `json:"-"`
 	XXX_sizecache        int32            `json:"-"`
@@ -335,6 +339,13 @@ func (m *ResultID) GetSchema() int32 {
 	return 0
 }
 
+func (m *ResultID) GetVariant() string {
+	if m != nil {
+		return m.Variant
+	}
+	return ""
+}
+
 type ResultData struct {
 	Id                   *ResultID       `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Artifacts            []*ArtifactInfo `protobuf:"bytes,2,rep,name=artifacts,proto3" json:"artifacts,omitempty"`
@@ -901,6 +912,244 @@ func (m *MetricsEvent) GetEvent() []*MetricsPoint {
 	return nil
 }
 
+type CustomMetric struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Types that are valid to be assigned to Value:
+	//	*CustomMetric_Int
+	//	*CustomMetric_Fl
+	Value                isCustomMetric_Value `protobuf_oneof:"value"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *CustomMetric) Reset()         { *m = CustomMetric{} }
+func (m *CustomMetric) String() string { return proto.CompactTextString(m) }
+func (*CustomMetric) ProtoMessage()    {}
+func (*CustomMetric) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c323b185c5dcff5, []int{11}
+}
+
+func (m *CustomMetric) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomMetric.Unmarshal(m, b)
+}
+func (m *CustomMetric) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomMetric.Marshal(b, m, deterministic)
+}
+func (m *CustomMetric) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomMetric.Merge(m, src)
+}
+func (m *CustomMetric) XXX_Size() int {
+	return xxx_messageInfo_CustomMetric.Size(m)
+}
+func (m *CustomMetric) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomMetric.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomMetric proto.InternalMessageInfo
+
+func (m *CustomMetric) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type isCustomMetric_Value interface {
+	isCustomMetric_Value()
+}
+
+type CustomMetric_Int struct {
+	Int int64 `protobuf:"varint,2,opt,name=int,proto3,oneof"`
+}
+
+type CustomMetric_Fl struct {
+	Fl float64 `protobuf:"fixed64,3,opt,name=fl,proto3,oneof"`
+}
+
+func (*CustomMetric_Int) isCustomMetric_Value() {}
+
+func (*CustomMetric_Fl) isCustomMetric_Value() {}
+
+func (m *CustomMetric) GetValue() isCustomMetric_Value {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *CustomMetric) GetInt() int64 {
+	if x, ok := m.GetValue().(*CustomMetric_Int); ok {
+		return x.Int
+	}
+	return 0
+}
+
+func (m *CustomMetric) GetFl() float64 {
+	if x, ok := m.GetValue().(*CustomMetric_Fl); ok {
+		return x.Fl
+	}
+	return 0
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*CustomMetric) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _CustomMetric_OneofMarshaler, _CustomMetric_OneofUnmarshaler, _CustomMetric_OneofSizer, []interface{}{
+		(*CustomMetric_Int)(nil),
+		(*CustomMetric_Fl)(nil),
+	}
+}
+
+func _CustomMetric_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*CustomMetric)
+	// value
+	switch x := m.Value.(type) {
+	case *CustomMetric_Int:
+		b.EncodeVarint(2<<3 | proto.WireVarint)
+		b.EncodeVarint(uint64(x.Int))
+	case *CustomMetric_Fl:
+		b.EncodeVarint(3<<3 | proto.WireFixed64)
+		b.EncodeFixed64(math.Float64bits(x.Fl))
+	case nil:
+	default:
+		return fmt.Errorf("CustomMetric.Value has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _CustomMetric_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*CustomMetric)
+	switch tag {
+	case 2: // value.int
+		if wire != proto.WireVarint {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.Value = &CustomMetric_Int{int64(x)}
+		return true, err
+	case 3: // value.fl
+		if wire != proto.WireFixed64 {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeFixed64()
+		m.Value = &CustomMetric_Fl{math.Float64frombits(x)}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _CustomMetric_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*CustomMetric)
+	// value
+	switch x := m.Value.(type) {
+	case *CustomMetric_Int:
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(x.Int))
+	case *CustomMetric_Fl:
+		n += 1 // tag and wire
+		n += 8
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+type CustomMetricsPoint struct {
+	Time                 *timestamp.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	Metrics              []*CustomMetric      `protobuf:"bytes,2,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *CustomMetricsPoint) Reset()         { *m = CustomMetricsPoint{} }
+func (m *CustomMetricsPoint) String() string { return proto.CompactTextString(m) }
+func (*CustomMetricsPoint) ProtoMessage()    {}
+func (*CustomMetricsPoint) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c323b185c5dcff5, []int{12}
+}
+
+func (m *CustomMetricsPoint) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomMetricsPoint.Unmarshal(m, b)
+}
+func (m *CustomMetricsPoint) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomMetricsPoint.Marshal(b, m, deterministic)
+}
+func (m *CustomMetricsPoint) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomMetricsPoint.Merge(m, src)
+}
+func (m *CustomMetricsPoint) XXX_Size() int {
+	return xxx_messageInfo_CustomMetricsPoint.Size(m)
+}
+func (m *CustomMetricsPoint) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomMetricsPoint.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomMetricsPoint proto.InternalMessageInfo
+
+func (m *CustomMetricsPoint) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *CustomMetricsPoint) GetMetrics() []*CustomMetric {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
+type CustomMetricsEvent struct {
+	Id                   string                `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Event                []*CustomMetricsPoint `protobuf:"bytes,2,rep,name=event,proto3" json:"event,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
+}
+
+func (m *CustomMetricsEvent) Reset()         { *m = CustomMetricsEvent{} }
+func (m *CustomMetricsEvent) String() string { return proto.CompactTextString(m) }
+func (*CustomMetricsEvent) ProtoMessage()    {}
+func (*CustomMetricsEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c323b185c5dcff5, []int{13}
+}
+
+func (m *CustomMetricsEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CustomMetricsEvent.Unmarshal(m, b)
+}
+func (m *CustomMetricsEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CustomMetricsEvent.Marshal(b, m, deterministic)
+}
+func (m *CustomMetricsEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CustomMetricsEvent.Merge(m, src)
+}
+func (m *CustomMetricsEvent) XXX_Size() int {
+	return xxx_messageInfo_CustomMetricsEvent.Size(m)
+}
+func (m *CustomMetricsEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_CustomMetricsEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CustomMetricsEvent proto.InternalMessageInfo
+
+func (m *CustomMetricsEvent) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *CustomMetricsEvent) GetEvent() []*CustomMetricsPoint {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
 type RollupValue struct {
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// Types that are valid to be assigned to Value:
@@ -919,7 +1168,7 @@ func (m *RollupValue) Reset()         { *m = RollupValue{} }
 func (m *RollupValue) String() string { return proto.CompactTextString(m) }
 func (*RollupValue) ProtoMessage()    {}
 func (*RollupValue) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0c323b185c5dcff5, []int{11}
+	return fileDescriptor_0c323b185c5dcff5, []int{14}
 }
 
 func (m *RollupValue) XXX_Unmarshal(b []byte) error {
@@ -1081,7 +1330,7 @@ func (m *ArtifactData) Reset()         { *m = ArtifactData{} }
 func (m *ArtifactData) String() string { return proto.CompactTextString(m) }
 func (*ArtifactData) ProtoMessage()    {}
 func (*ArtifactData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0c323b185c5dcff5, []int{12}
+	return fileDescriptor_0c323b185c5dcff5, []int{15}
 }
 
 func (m *ArtifactData) XXX_Unmarshal(b []byte) error {
@@ -1128,7 +1377,7 @@ func (m *RollupData) Reset()         { *m = RollupData{} }
 func (m *RollupData) String() string { return proto.CompactTextString(m) }
 func (*RollupData) ProtoMessage()    {}
 func (*RollupData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_0c323b185c5dcff5, []int{13}
+	return fileDescriptor_0c323b185c5dcff5, []int{16}
 }
 
 func (m *RollupData) XXX_Unmarshal(b []byte) error {
@@ -1163,6 +1412,84 @@ func (m *RollupData) GetRollups() []*RollupValue {
 	return nil
 }
 
+type ResultIDSchemasRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResultIDSchemasRequest) Reset()         { *m = ResultIDSchemasRequest{} }
+func (m *ResultIDSchemasRequest) String() string { return proto.CompactTextString(m) }
+func (*ResultIDSchemasRequest) ProtoMessage()    {}
+func (*ResultIDSchemasRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c323b185c5dcff5, []int{17}
+}
+
+func (m *ResultIDSchemasRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResultIDSchemasRequest.Unmarshal(m, b)
+}
+func (m *ResultIDSchemasRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResultIDSchemasRequest.Marshal(b, m, deterministic)
+}
+func (m *ResultIDSchemasRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResultIDSchemasRequest.Merge(m, src)
+}
+func (m *ResultIDSchemasRequest) XXX_Size() int {
+	return xxx_messageInfo_ResultIDSchemasRequest.Size(m)
+}
+func (m *ResultIDSchemasRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResultIDSchemasRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResultIDSchemasRequest proto.InternalMessageInfo
+
+type ResultIDSchemas struct {
+	Schemas              []int32  `protobuf:"varint,1,rep,packed,name=schemas,proto3" json:"schemas,omitempty"`
+	Latest               int32    `protobuf:"varint,2,opt,name=latest,proto3" json:"latest,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ResultIDSchemas) Reset()         { *m = ResultIDSchemas{} }
+func (m *ResultIDSchemas) String() string { return proto.CompactTextString(m) }
+func (*ResultIDSchemas) ProtoMessage()    {}
+func (*ResultIDSchemas) Descriptor() ([]byte, []int) {
+	return fileDescriptor_0c323b185c5dcff5, []int{18}
+}
+
+func (m *ResultIDSchemas) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ResultIDSchemas.Unmarshal(m, b)
+}
+func (m *ResultIDSchemas) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ResultIDSchemas.Marshal(b, m, deterministic)
+}
+func (m *ResultIDSchemas) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResultIDSchemas.Merge(m, src)
+}
+func (m *ResultIDSchemas) XXX_Size() int {
+	return xxx_messageInfo_ResultIDSchemas.Size(m)
+}
+func (m *ResultIDSchemas) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResultIDSchemas.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResultIDSchemas proto.InternalMessageInfo
+
+func (m *ResultIDSchemas) GetSchemas() []int32 {
+	if m != nil {
+		return m.Schemas
+	}
+	return nil
+}
+
+func (m *ResultIDSchemas) GetLatest() int32 {
+	if m != nil {
+		return m.Latest
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("cedar.StorageLocation", StorageLocation_name, StorageLocation_value)
 	proto.RegisterEnum("cedar.DataFormat", DataFormat_name, DataFormat_value)
@@ -1181,103 +1508,117 @@ func init() {
 	proto.RegisterType((*MetricsTimers)(nil), "cedar.MetricsTimers")
 	proto.RegisterType((*MetricsGauges)(nil), "cedar.MetricsGauges")
 	proto.RegisterType((*MetricsEvent)(nil), "cedar.MetricsEvent")
+	proto.RegisterType((*CustomMetric)(nil), "cedar.CustomMetric")
+	proto.RegisterType((*CustomMetricsPoint)(nil), "cedar.CustomMetricsPoint")
+	proto.RegisterType((*CustomMetricsEvent)(nil), "cedar.CustomMetricsEvent")
 	proto.RegisterType((*RollupValue)(nil), "cedar.RollupValue")
 	proto.RegisterType((*ArtifactData)(nil), "cedar.ArtifactData")
 	proto.RegisterType((*RollupData)(nil), "cedar.RollupData")
+	proto.RegisterType((*ResultIDSchemasRequest)(nil), "cedar.ResultIDSchemasRequest")
+	proto.RegisterType((*ResultIDSchemas)(nil), "cedar.ResultIDSchemas")
 }
 
 func init() { proto.RegisterFile("perf.proto", fileDescriptor_0c323b185c5dcff5) }
 
 var fileDescriptor_0c323b185c5dcff5 = []byte{
-	// 1400 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xdb, 0x6e, 0xdb, 0x46,
-	0x13, 0x36, 0x45, 0x1d, 0x47, 0x3e, 0x30, 0x9b, 0xc0, 0xd1, 0xef, 0xbf, 0x48, 0x0c, 0x01, 0x01,
-	0x1c, 0x23, 0x50, 0x5a, 0x05, 0x01, 0xe2, 0x34, 0x69, 0xc0, 0x48, 0x8c, 0xc5, 0xd4, 0xa2, 0xd4,
-	0x25, 0x6d, 0xa7, 0x06, 0x0a, 0x81, 0xa6, 0x56, 0x0e, 0x6b, 0x8a, 0x14, 0xb8, 0xab, 0xa4, 0xee,
-	0x7d, 0x6f, 0x0b, 0xf4, 0x6d, 0xfa, 0x12, 0xed, 0x0b, 0xf4, 0x65, 0x8a, 0x3d, 0x50, 0x96, 0x98,
-	0xd6, 0x45, 0xae, 0xb4, 0x33, 0xf3, 0xcd, 0x61, 0x67, 0x86, 0xdf, 0x0a, 0x60, 0x46, 0xd2, 0x49,
-	0x6b, 0x96, 0x26, 0x2c, 0x41, 0xa5, 0x80, 0x8c, 0xfd, 0x74, 0xe7, 0xde, 0x45, 0x92, 0x5c, 0x44,
-	0xe4, 0xb1, 0x50, 0x9e, 0xcf, 0x27, 0x8f, 0xc7, 0xf3, 0xd4, 0x67, 0x61, 0x12, 0x4b, 0xd8, 0xce,
-	0xfd, 0xbc, 0x9d, 0x85, 0x53, 0x42, 0x99, 0x3f, 0x9d, 0x49, 0x40, 0xf3, 0x17, 0x1d, 0xaa, 0x98,
-	0xd0, 0x79, 0xc4, 0xec, 0x2e, 0x6a, 0x40, 0x65, 0x96, 0x26, 0x3f, 0x92, 0x80, 0x35, 0xb4, 0x5d,
-	0x6d, 0xaf, 0x86, 0x33, 0x91, 0x5b, 0x3e, 0x90, 0x94, 0x86, 0x49, 0xdc, 0x28, 0x48, 0x8b, 0x12,
-	0xd1, 0xff, 0xa1, 0xc6, 0x7c, 0x7a, 0x39, 0x8a, 0xfd, 0x29, 0x69, 0xe8, 0xc2, 0x56, 0xe5, 0x0a,
-	0xc7, 0x9f, 0x12, 0xf4, 0x05, 0xd4, 0xc8, 0x4f, 0x24, 0x98, 0xf3, 0x8a, 0x1a, 0xc5, 0x5d, 0x6d,
-	0xaf, 0x84, 0xaf, 0x15, 0xe8, 0x2e, 0x54, 0x84, 0x6b, 0x38, 0x6e, 0x94, 0x84, 0x63, 0x99, 0x8b,
-	0xf6, 0x58, 0xc4, 0x24, 0x94, 0xc9, 0x98, 0x65, 0x15, 0x93, 0x50, 0x26, 0x62, 0x6e, 0x43, 0x79,
-	0xe6, 0xa7, 0x24, 0x66, 0x8d, 0x8a, 0x74, 0x92, 0x12, 0xba, 0x03, 0x25, 0x96, 0x86, 0x7e, 0xd4,
-	0xa8, 0x8a, 0x3c, 0x52, 0x40, 0x08, 0x8a, 0xcc, 0xbf, 0xa0, 0x8d, 0xda, 0xae, 0xbe, 0x57, 0xc3,
-	0xe2, 0x8c, 0x5e, 0x40, 0xcd, 0x4f, 0x2f, 0xe6, 0x53, 0x12, 0x33, 0xda, 0x80, 0x5d, 0x7d, 0xaf,
-	0xde, 0xbe, 0xd7, 0x12, 0xfd, 0x6c, 0x65, 0xad, 0x68, 0x99, 0x19, 0xc0, 0x8a, 0x59, 0x7a, 0x85,
-	0xaf, 0x1d, 0x78, 0x7e, 0x1a, 0xbc, 0x27, 0x53, 0xbf, 0x51, 0x17, 0x89, 0x94, 0xb4, 0xf3, 0x02,
-	0x36, 0x57, 0x9d, 0x90, 0x01, 0xfa, 0x25, 0xb9, 0x52, 0xad, 0xe4, 0x47, 0x5e, 0xe3, 0x07, 0x3f,
-	0x9a, 0x13, 0xd1, 0xc4, 0x12, 0x96, 0xc2, 0xf3, 0xc2, 0x33, 0xad, 0xf9, 0xab, 0x06, 0x20, 0x93,
-	0x77, 0x7d, 0xe6, 0xa3, 0xfb, 0x50, 0x08, 0xc7, 0xc2, 0xb3, 0xde, 0xde, 0xca, 0xd5, 0x86, 0x0b,
-	0xe1, 0x18, 0x7d, 0xc5, 0xef, 0xc0, 0xc2, 0x89, 0x1f, 0x30, 0xda, 0x28, 0x88, 0x3b, 0xdc, 0x56,
-	0x38, 0x53, 0xe9, 0xed, 0x78, 0x92, 0xe0, 0x6b, 0x14, 0x7a, 0x04, 0x95, 0x34, 0x89, 0xa2, 0xf9,
-	0x8c, 0x36, 0x74, 0xe1, 0x80, 0xb2, 0xc0, 0x42, 0x7b, 0xc2, 0x6b, 0xc1, 0x19, 0xa4, 0xf9, 0x47,
-	0x01, 0xd6, 0x97, 0x23, 0xa1, 0x36, 0x54, 0xa3, 0x24, 0x10, 0xcb, 0x25, 0x0a, 0xdb, 0x6c, 0x6f,
-	0x2b, 0x7f, 0x97, 0x25, 0xa9, 0x7f, 0x41, 0x8e, 0x94, 0x15, 0x2f, 0x70, 0xbc, 0x57, 0xe7, 0xf3,
-	0xe0, 0x92, 0x30, 0xb5, 0x35, 0x4a, 0xe2, 0x53, 0x99, 0xf9, 0xec, 0xbd, 0xda, 0x17, 0x71, 0x46,
-	0x0f, 0xa1, 0x3c, 0x49, 0xd2, 0xa9, 0xcf, 0xc4, 0xa2, 0x6c, 0xb6, 0x6f, 0xa9, 0xe8, 0xbc, 0x1f,
-	0x6f, 0x84, 0x01, 0x2b, 0x00, 0x7a, 0x06, 0xf5, 0x20, 0x99, 0xce, 0x52, 0x42, 0xc5, 0x46, 0x96,
-	0x56, 0xaa, 0xe9, 0x5c, 0x5b, 0xbc, 0xab, 0x19, 0xc1, 0xcb, 0x50, 0x9e, 0x44, 0x0d, 0xaf, 0xbc,
-	0x92, 0xc4, 0x15, 0x4a, 0x81, 0x57, 0x80, 0xc5, 0xe6, 0x54, 0x96, 0x36, 0xe7, 0x00, 0x20, 0x48,
-	0x89, 0xcf, 0xc8, 0x78, 0xe4, 0x33, 0xb1, 0x68, 0xf5, 0xf6, 0x4e, 0x4b, 0x7e, 0x63, 0xad, 0xec,
-	0x1b, 0x6b, 0x79, 0xd9, 0x37, 0x86, 0x6b, 0x0a, 0x6d, 0xb2, 0x66, 0x07, 0x8c, 0x3e, 0x61, 0x69,
-	0x18, 0x50, 0x97, 0xa4, 0x21, 0xa1, 0x56, 0x3c, 0x46, 0x9b, 0x8b, 0x29, 0xd7, 0xc4, 0x50, 0xef,
-	0x43, 0x3d, 0xa4, 0x23, 0x5e, 0x6f, 0x44, 0x98, 0x5c, 0x92, 0x2a, 0x86, 0x90, 0x76, 0x94, 0xa6,
-	0xf9, 0x35, 0x6c, 0xa9, 0x20, 0x98, 0xd0, 0x59, 0x12, 0x53, 0xf2, 0x49, 0x8c, 0x06, 0x54, 0xe8,
-	0x3c, 0x08, 0x08, 0xa5, 0xca, 0x3f, 0x13, 0x9b, 0x0e, 0xac, 0xbb, 0x24, 0x1e, 0x7f, 0xbe, 0x27,
-	0x5f, 0xdb, 0x20, 0x99, 0xc7, 0x4c, 0xcc, 0x4b, 0xc7, 0x52, 0x68, 0xfe, 0xa9, 0xc1, 0xba, 0xaa,
-	0x66, 0x98, 0x84, 0x31, 0x43, 0x2d, 0x28, 0xf2, 0xab, 0xab, 0xb5, 0xbd, 0xa9, 0x2f, 0x02, 0xc7,
-	0x37, 0x4a, 0x44, 0x22, 0xa9, 0xcc, 0x58, 0x5f, 0xcc, 0x50, 0x85, 0xed, 0x28, 0x2b, 0x5e, 0xe0,
-	0xd0, 0x23, 0x28, 0x73, 0x0a, 0x4b, 0xa9, 0xa8, 0xa5, 0xde, 0xbe, 0xb3, 0xea, 0xe1, 0x09, 0x1b,
-	0x56, 0x18, 0x8e, 0xbe, 0xf0, 0xe7, 0x17, 0x84, 0x8a, 0x9d, 0xfa, 0x04, 0x7d, 0x28, 0x6c, 0x58,
-	0x61, 0x9a, 0x83, 0x45, 0x77, 0xb3, 0xc4, 0xfc, 0x13, 0x4e, 0x66, 0x54, 0xdc, 0x48, 0xc7, 0xfc,
-	0xc8, 0xd7, 0x82, 0x86, 0x3f, 0xcb, 0xe1, 0xe8, 0x58, 0x9c, 0xf9, 0x9a, 0x93, 0x34, 0x4d, 0x54,
-	0x51, 0x3a, 0x56, 0x52, 0xf3, 0x23, 0x6c, 0xac, 0xd4, 0x85, 0x9e, 0x42, 0x35, 0x23, 0x68, 0xd5,
-	0xa5, 0xff, 0x7d, 0xd2, 0xa5, 0xae, 0x02, 0xe0, 0x05, 0x14, 0x3d, 0x86, 0x12, 0x4b, 0x98, 0x1f,
-	0xa9, 0x2e, 0xdd, 0xe0, 0x23, 0x71, 0xcd, 0xd3, 0x45, 0x62, 0x79, 0x45, 0x3e, 0x41, 0xca, 0x7c,
-	0x46, 0xd4, 0x4d, 0xa4, 0xc0, 0x27, 0xfe, 0x31, 0x49, 0x2f, 0xb3, 0xfe, 0xeb, 0x38, 0x13, 0xf9,
-	0x8d, 0x26, 0x7e, 0x18, 0x91, 0xb1, 0xb8, 0x51, 0x15, 0x2b, 0xa9, 0x69, 0x2f, 0x46, 0x6e, 0x7d,
-	0xe0, 0xa4, 0x9b, 0xdf, 0xa1, 0x87, 0x50, 0x12, 0x06, 0xc5, 0x30, 0xb7, 0x57, 0xfb, 0x2d, 0xd6,
-	0x04, 0x4b, 0x44, 0xf3, 0x77, 0x0d, 0xea, 0x4b, 0xcc, 0xc3, 0x1b, 0x2b, 0xf8, 0x5e, 0x06, 0x13,
-	0x67, 0x84, 0x40, 0x0f, 0x63, 0x49, 0x1e, 0x7a, 0x6f, 0x0d, 0x73, 0x01, 0x19, 0x50, 0x98, 0x44,
-	0xa2, 0x2c, 0xad, 0xb7, 0x86, 0x0b, 0x93, 0x08, 0x3d, 0x80, 0x22, 0xbb, 0x9a, 0x91, 0x1c, 0x6f,
-	0xc8, 0xd8, 0xe2, 0x93, 0x16, 0xe6, 0xe5, 0x37, 0xac, 0x24, 0x6f, 0x9b, 0xbd, 0x61, 0x0f, 0x60,
-	0x73, 0x4e, 0x49, 0x3a, 0xa2, 0xf3, 0xf3, 0x69, 0xc8, 0x18, 0x19, 0x0b, 0x76, 0xa8, 0xe2, 0x0d,
-	0xae, 0x75, 0x33, 0xe5, 0xeb, 0x8a, 0x62, 0xef, 0xe6, 0x77, 0xd7, 0xd4, 0x28, 0xd8, 0x3a, 0xdf,
-	0x85, 0xcf, 0x27, 0xe7, 0xe6, 0x5b, 0x00, 0x59, 0xf0, 0x3f, 0x06, 0x5c, 0xa2, 0xee, 0xc2, 0x7f,
-	0x52, 0xf7, 0xfe, 0x29, 0x6c, 0xe5, 0x28, 0x19, 0xd5, 0xa1, 0x72, 0xec, 0x7c, 0xeb, 0x0c, 0x4e,
-	0x1d, 0x63, 0x0d, 0xad, 0x43, 0xb5, 0x63, 0x75, 0x4d, 0x3c, 0x72, 0x9f, 0x18, 0x1a, 0xda, 0x04,
-	0x18, 0xe2, 0xc1, 0x5b, 0xab, 0xe3, 0x71, 0xb9, 0x80, 0x00, 0xca, 0x87, 0xd8, 0xee, 0xbe, 0x71,
-	0x0d, 0x1d, 0x6d, 0x40, 0xcd, 0x1a, 0xf6, 0xac, 0xbe, 0x85, 0xcd, 0x23, 0xa3, 0xb8, 0xff, 0x12,
-	0xe0, 0x9a, 0x8d, 0x51, 0x15, 0x8a, 0x9e, 0xf5, 0xce, 0x33, 0xd6, 0xf8, 0xe9, 0x8d, 0xd7, 0xed,
-	0x18, 0x1a, 0x3f, 0xbd, 0x76, 0x07, 0x8e, 0x51, 0xe0, 0xa7, 0xb7, 0xfc, 0xa4, 0xa3, 0x0a, 0xe8,
-	0x1d, 0xf7, 0xc4, 0x28, 0xee, 0xbf, 0x82, 0xad, 0x1c, 0x39, 0x73, 0x94, 0x33, 0x70, 0x2c, 0x63,
-	0x0d, 0xd5, 0xa0, 0xe4, 0x99, 0xf8, 0xf0, 0xcc, 0xd0, 0xb8, 0xc3, 0x99, 0x3d, 0x34, 0x0a, 0xa8,
-	0x0c, 0x85, 0xc3, 0x33, 0x43, 0xe7, 0xbf, 0xef, 0xce, 0x8c, 0xe2, 0xfe, 0x0f, 0x00, 0xd7, 0x44,
-	0xcd, 0x0b, 0xc7, 0xe6, 0xe9, 0xc8, 0x3a, 0xb1, 0x1c, 0xcf, 0x35, 0xd6, 0xd0, 0x1d, 0x30, 0x3a,
-	0x83, 0xa3, 0x23, 0x73, 0xe8, 0x5a, 0xdd, 0x4c, 0xab, 0xa1, 0x1d, 0xd8, 0xb6, 0x1d, 0xcf, 0xc2,
-	0x27, 0xe6, 0xd1, 0xc8, 0x3d, 0xee, 0xf7, 0x4d, 0x6c, 0x9f, 0x99, 0x9e, 0x2d, 0x6a, 0xdc, 0x80,
-	0x5a, 0xcf, 0x76, 0xbd, 0xc1, 0x21, 0x36, 0xfb, 0x86, 0xbe, 0xff, 0x97, 0x96, 0x0d, 0x41, 0xc4,
-	0xaf, 0x80, 0xee, 0x1e, 0xf7, 0xe5, 0xf5, 0xfa, 0x96, 0xe9, 0x18, 0x1a, 0xef, 0x4d, 0xdf, 0xea,
-	0xda, 0x26, 0x77, 0xae, 0x80, 0xde, 0x37, 0xdf, 0xc9, 0xfb, 0xf5, 0x6d, 0xc7, 0x28, 0xa2, 0x6d,
-	0x40, 0xae, 0x67, 0x3a, 0x5d, 0x13, 0x77, 0x47, 0x5d, 0xeb, 0xc4, 0x96, 0x69, 0x4a, 0xbc, 0x50,
-	0xaf, 0x87, 0x07, 0xc7, 0x87, 0xbd, 0xe1, 0xb1, 0x67, 0x94, 0xf9, 0x30, 0x8e, 0x4c, 0xcf, 0x72,
-	0x3a, 0xdf, 0x1b, 0x15, 0x74, 0x1b, 0xb6, 0x86, 0x16, 0xee, 0x58, 0x8e, 0x67, 0x1f, 0x59, 0xa3,
-	0x83, 0x03, 0xaf, 0x67, 0x54, 0xf3, 0xca, 0xa7, 0x5e, 0xcf, 0xa8, 0xe5, 0x95, 0x5f, 0x7a, 0x3d,
-	0x03, 0x72, 0xca, 0x67, 0x5c, 0x59, 0xcf, 0x29, 0x9f, 0x72, 0xe5, 0x7a, 0xfb, 0x37, 0x1d, 0xee,
-	0x76, 0xf8, 0xd2, 0x0c, 0x49, 0x2a, 0xde, 0xd1, 0x38, 0x20, 0xea, 0xbb, 0x44, 0xaf, 0x00, 0x75,
-	0xc4, 0x4b, 0x25, 0x15, 0xf2, 0x85, 0x42, 0xb7, 0x56, 0xfe, 0x78, 0xf0, 0xc9, 0xef, 0xe4, 0x08,
-	0x7a, 0xf1, 0x96, 0xbc, 0x04, 0xc3, 0x64, 0xcc, 0x0f, 0xde, 0x2f, 0xfd, 0x87, 0xf9, 0x0c, 0xf7,
-	0x6f, 0x60, 0x4b, 0xba, 0x9b, 0x8b, 0x7f, 0x2b, 0xf9, 0x0f, 0xe6, 0x46, 0xff, 0xe7, 0xb0, 0xa1,
-	0xd2, 0xcb, 0x4f, 0x00, 0xad, 0x92, 0xc0, 0x8d, 0xbe, 0x07, 0x50, 0xe7, 0xcf, 0x62, 0xd6, 0x8a,
-	0x1c, 0x65, 0x09, 0xae, 0xda, 0xc9, 0x94, 0xcb, 0xef, 0xe7, 0x9e, 0x86, 0x5e, 0xc1, 0x7a, 0x27,
-	0x4a, 0xe8, 0xa2, 0x8d, 0x77, 0x57, 0x7d, 0x17, 0x0f, 0xfd, 0xbf, 0xe5, 0x7e, 0x0d, 0x67, 0xd5,
-	0x90, 0x3f, 0x34, 0xb1, 0x1f, 0x9d, 0x97, 0x05, 0x9b, 0x3f, 0xf9, 0x3b, 0x00, 0x00, 0xff, 0xff,
-	0xd5, 0xf3, 0xec, 0x1c, 0xe6, 0x0b, 0x00, 0x00,
+	// 1550 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0x6b, 0x72, 0xdb, 0xc8,
+	0x11, 0x16, 0x08, 0x3e, 0x9b, 0x94, 0x08, 0xcd, 0xba, 0x64, 0x2c, 0x93, 0xac, 0x55, 0xa8, 0xda,
+	0x2a, 0xad, 0x6a, 0x43, 0x25, 0xdc, 0x72, 0x95, 0xb5, 0xd9, 0x8d, 0x0b, 0x06, 0x61, 0x91, 0xb6,
+	0xf8, 0xf0, 0x00, 0x92, 0x1c, 0xfd, 0x61, 0x41, 0xe4, 0x50, 0x46, 0x44, 0x02, 0x0c, 0x66, 0x68,
+	0x47, 0x39, 0x44, 0xae, 0x90, 0x5f, 0xb9, 0x43, 0x2e, 0x91, 0x5c, 0x20, 0x97, 0x49, 0xcd, 0x03,
+	0x7c, 0xc0, 0x8a, 0x1d, 0xe7, 0x17, 0xa6, 0xbb, 0xbf, 0xee, 0xe9, 0x17, 0xba, 0x07, 0x60, 0x41,
+	0x92, 0x69, 0x73, 0x91, 0xc4, 0x2c, 0x46, 0x85, 0x31, 0x99, 0x04, 0x49, 0xe3, 0x9b, 0xdb, 0x38,
+	0xbe, 0x9d, 0x91, 0x13, 0xc1, 0xbc, 0x59, 0x4e, 0x4f, 0x26, 0xcb, 0x24, 0x60, 0x61, 0x1c, 0x49,
+	0x58, 0xe3, 0x49, 0x56, 0xce, 0xc2, 0x39, 0xa1, 0x2c, 0x98, 0x2f, 0x24, 0xc0, 0xfa, 0x9b, 0x0e,
+	0x65, 0x4c, 0xe8, 0x72, 0xc6, 0xba, 0x6d, 0x64, 0x42, 0x69, 0x91, 0xc4, 0x7f, 0x24, 0x63, 0x66,
+	0x6a, 0x87, 0xda, 0x51, 0x05, 0xa7, 0x24, 0x97, 0xbc, 0x27, 0x09, 0x0d, 0xe3, 0xc8, 0xcc, 0x49,
+	0x89, 0x22, 0xd1, 0x2f, 0xa0, 0xc2, 0x02, 0x7a, 0x37, 0x8a, 0x82, 0x39, 0x31, 0x75, 0x21, 0x2b,
+	0x73, 0x46, 0x3f, 0x98, 0x13, 0xf4, 0x4b, 0xa8, 0x90, 0x3f, 0x93, 0xf1, 0x92, 0x7b, 0x64, 0xe6,
+	0x0f, 0xb5, 0xa3, 0x02, 0x5e, 0x33, 0xd0, 0x63, 0x28, 0x09, 0xd5, 0x70, 0x62, 0x16, 0x84, 0x62,
+	0x91, 0x93, 0xdd, 0x89, 0xb0, 0x49, 0x28, 0x93, 0x36, 0x8b, 0xca, 0x26, 0xa1, 0x4c, 0xd8, 0x3c,
+	0x80, 0xe2, 0x22, 0x48, 0x48, 0xc4, 0xcc, 0x92, 0x54, 0x92, 0x14, 0x7a, 0x04, 0x05, 0x96, 0x84,
+	0xc1, 0xcc, 0x2c, 0x8b, 0x7b, 0x24, 0x81, 0x10, 0xe4, 0x59, 0x70, 0x4b, 0xcd, 0xca, 0xa1, 0x7e,
+	0x54, 0xc1, 0xe2, 0x8c, 0x7e, 0x82, 0x4a, 0x90, 0xdc, 0x2e, 0xe7, 0x24, 0x62, 0xd4, 0x84, 0x43,
+	0xfd, 0xa8, 0xda, 0xfa, 0xa6, 0x29, 0xf2, 0xd9, 0x4c, 0x53, 0xd1, 0xb4, 0x53, 0x80, 0x1b, 0xb1,
+	0xe4, 0x1e, 0xaf, 0x15, 0xf8, 0xfd, 0x74, 0xfc, 0x8e, 0xcc, 0x03, 0xb3, 0x2a, 0x2e, 0x52, 0x94,
+	0x48, 0x51, 0x90, 0x84, 0x41, 0xc4, 0xcc, 0x9a, 0x4a, 0x91, 0x24, 0x1b, 0x3f, 0xc1, 0xde, 0xb6,
+	0x39, 0x64, 0x80, 0x7e, 0x47, 0xee, 0x55, 0x92, 0xf9, 0x91, 0x7b, 0xff, 0x3e, 0x98, 0x2d, 0x89,
+	0x48, 0x6f, 0x01, 0x4b, 0xe2, 0xc7, 0xdc, 0x33, 0xcd, 0xfa, 0xab, 0x06, 0x20, 0xdd, 0x6a, 0x07,
+	0x2c, 0x40, 0x4f, 0x20, 0x17, 0x4e, 0x84, 0x66, 0xb5, 0x55, 0xcf, 0x78, 0x8d, 0x73, 0xe1, 0x04,
+	0xfd, 0x96, 0x47, 0xc7, 0xc2, 0x69, 0x30, 0x66, 0xd4, 0xcc, 0x89, 0xe8, 0xbe, 0x52, 0x38, 0x5b,
+	0xf1, 0xbb, 0xd1, 0x34, 0xc6, 0x6b, 0x14, 0xfa, 0x1e, 0x4a, 0x49, 0x3c, 0x9b, 0x2d, 0x17, 0xd4,
+	0xd4, 0x85, 0x02, 0x4a, 0x0d, 0x0b, 0xee, 0x25, 0xf7, 0x05, 0xa7, 0x10, 0xeb, 0x9f, 0x39, 0xa8,
+	0x6d, 0x5a, 0x42, 0x2d, 0x28, 0xcf, 0xe2, 0xb1, 0x68, 0x3b, 0xe1, 0xd8, 0x5e, 0xeb, 0x40, 0xe9,
+	0x7b, 0x2c, 0x4e, 0x82, 0x5b, 0x72, 0xae, 0xa4, 0x78, 0x85, 0xe3, 0x59, 0xbc, 0x59, 0x8e, 0xef,
+	0x08, 0x53, 0xfd, 0xa4, 0x28, 0x5e, 0xaf, 0x45, 0xc0, 0xde, 0xa9, 0x4e, 0x12, 0x67, 0xf4, 0x1d,
+	0x14, 0xa7, 0x71, 0x32, 0x0f, 0x98, 0x68, 0xa1, 0xbd, 0xd6, 0xbe, 0xb2, 0xce, 0xf3, 0xf1, 0x52,
+	0x08, 0xb0, 0x02, 0xa0, 0x67, 0x50, 0x1d, 0xc7, 0xf3, 0x45, 0x42, 0xa8, 0xe8, 0xd5, 0xc2, 0x96,
+	0x37, 0xce, 0x5a, 0xe2, 0xdf, 0x2f, 0x08, 0xde, 0x84, 0xf2, 0x4b, 0x54, 0x59, 0x8b, 0x5b, 0x97,
+	0x78, 0x82, 0x29, 0xf0, 0x69, 0xa5, 0xd3, 0x9e, 0x2a, 0x6d, 0xf4, 0xd4, 0x29, 0xc0, 0x38, 0x21,
+	0x01, 0x23, 0x93, 0x51, 0xc0, 0x44, 0x0b, 0x56, 0x5b, 0x8d, 0xa6, 0xfc, 0xfb, 0x9a, 0xe9, 0xdf,
+	0xd7, 0xf4, 0xd3, 0xbf, 0x0f, 0x57, 0x14, 0xda, 0x66, 0x96, 0x03, 0x46, 0x8f, 0xb0, 0x24, 0x1c,
+	0x53, 0x8f, 0x24, 0x21, 0xa1, 0x6e, 0x34, 0x41, 0x7b, 0xab, 0x2a, 0x57, 0x44, 0x51, 0x9f, 0x40,
+	0x35, 0xa4, 0x23, 0xee, 0xef, 0x8c, 0x30, 0xd9, 0x24, 0x65, 0x0c, 0x21, 0x75, 0x14, 0xc7, 0xfa,
+	0x1d, 0xd4, 0x95, 0x11, 0x4c, 0xe8, 0x22, 0x8e, 0x28, 0xf9, 0xc8, 0x86, 0x09, 0x25, 0xba, 0x1c,
+	0x8f, 0x09, 0xa5, 0x4a, 0x3f, 0x25, 0xad, 0x3e, 0xd4, 0x3c, 0x12, 0x4d, 0xbe, 0x5c, 0x93, 0xb7,
+	0xed, 0x38, 0x5e, 0x46, 0x4c, 0xd4, 0x4b, 0xc7, 0x92, 0xb0, 0xfe, 0xa5, 0x41, 0x4d, 0x79, 0x33,
+	0x8c, 0xc3, 0x88, 0xa1, 0x26, 0xe4, 0x79, 0xe8, 0xa6, 0xf6, 0xd9, 0xbc, 0x08, 0x1c, 0xef, 0x28,
+	0x61, 0x89, 0x24, 0xf2, 0xc6, 0xea, 0xaa, 0x86, 0xca, 0xac, 0xa3, 0xa4, 0x78, 0x85, 0x43, 0xdf,
+	0x43, 0x91, 0x0f, 0xb7, 0x84, 0x0a, 0x5f, 0xaa, 0xad, 0x47, 0xdb, 0x1a, 0xbe, 0x90, 0x61, 0x85,
+	0xe1, 0xe8, 0xdb, 0x60, 0x79, 0x4b, 0xa8, 0x99, 0x7f, 0x08, 0x7d, 0x26, 0x64, 0x58, 0x61, 0xac,
+	0x01, 0xd4, 0x33, 0x17, 0xf3, 0x5f, 0x38, 0x5e, 0x50, 0x11, 0x91, 0x8e, 0xf9, 0x91, 0xb7, 0x05,
+	0x0d, 0xff, 0x22, 0x8b, 0xa3, 0x63, 0x71, 0xe6, 0x6d, 0x4e, 0x92, 0x24, 0x56, 0x4e, 0xe9, 0x58,
+	0x51, 0xd6, 0x07, 0xd8, 0xdd, 0xf2, 0x0b, 0x3d, 0x85, 0x72, 0x3a, 0xba, 0x55, 0x96, 0xbe, 0xfe,
+	0x28, 0x4b, 0x6d, 0x05, 0xc0, 0x2b, 0x28, 0x3a, 0x81, 0x02, 0x8b, 0x59, 0x30, 0x33, 0x73, 0x9f,
+	0xd3, 0x91, 0x38, 0xeb, 0x0a, 0x76, 0xb7, 0x42, 0xe4, 0x15, 0xa4, 0x2c, 0x60, 0x44, 0x45, 0x22,
+	0x09, 0x5e, 0xf1, 0x0f, 0x71, 0x72, 0x97, 0xe6, 0x5f, 0xc7, 0x29, 0xc9, 0x23, 0x9a, 0x06, 0xe1,
+	0x8c, 0x4c, 0x44, 0x44, 0x65, 0xac, 0x28, 0xab, 0xbb, 0x2a, 0xb9, 0xfb, 0x9e, 0x8f, 0xe3, 0x6c,
+	0x0f, 0x7d, 0x07, 0x05, 0x21, 0x30, 0xf5, 0xad, 0x91, 0xb4, 0xd9, 0x26, 0x58, 0x22, 0xac, 0x37,
+	0x50, 0x73, 0x96, 0x94, 0xc5, 0x73, 0x29, 0xe4, 0x89, 0x15, 0x9b, 0x40, 0x1a, 0x13, 0x67, 0x84,
+	0x40, 0x0f, 0x23, 0x39, 0x3c, 0xf4, 0xce, 0x0e, 0xe6, 0x04, 0x32, 0x20, 0x37, 0x9d, 0x09, 0xb7,
+	0xb4, 0xce, 0x0e, 0xce, 0x4d, 0x67, 0x2f, 0x4a, 0x6a, 0xaa, 0x5a, 0x14, 0xd0, 0xa6, 0xc9, 0x75,
+	0x5b, 0xb2, 0xff, 0xb1, 0x2d, 0x39, 0x0e, 0xfd, 0x1a, 0x4a, 0x73, 0xa9, 0x9f, 0x19, 0xac, 0x9b,
+	0xb6, 0x71, 0x8a, 0xb1, 0x2e, 0x32, 0x97, 0x3e, 0x9c, 0x98, 0x13, 0x28, 0x10, 0x91, 0x18, 0x69,
+	0xf2, 0xeb, 0x07, 0x4c, 0xa6, 0xe9, 0x11, 0x38, 0xeb, 0x1f, 0x1a, 0x54, 0x37, 0x06, 0xf3, 0xff,
+	0x9f, 0x1e, 0xf4, 0x2d, 0xe4, 0xd9, 0xfd, 0x82, 0x64, 0xc6, 0xaa, 0xb4, 0x2d, 0x26, 0x9e, 0x10,
+	0x6f, 0x2e, 0xff, 0x82, 0x6c, 0x06, 0x45, 0xa2, 0x6f, 0x61, 0x6f, 0x49, 0x49, 0x32, 0xa2, 0xcb,
+	0x9b, 0x79, 0xc8, 0x18, 0x99, 0x88, 0xe1, 0x59, 0xc6, 0xbb, 0x9c, 0xeb, 0xa5, 0xcc, 0x75, 0x19,
+	0xde, 0xac, 0x37, 0x87, 0x58, 0x66, 0xd9, 0x5c, 0x7c, 0xf9, 0xee, 0xb2, 0x5e, 0x01, 0x48, 0x87,
+	0x1f, 0x34, 0xb8, 0xb1, 0xd9, 0x72, 0x9f, 0xdf, 0x6c, 0x26, 0x1c, 0xa4, 0xab, 0x54, 0x8e, 0x7d,
+	0x8a, 0xc9, 0x9f, 0x96, 0x84, 0xf2, 0x19, 0x5d, 0xcf, 0x48, 0xc4, 0x50, 0x94, 0x47, 0x53, 0x3b,
+	0xd4, 0x8f, 0x0a, 0x38, 0x25, 0xf9, 0x2f, 0x32, 0x0b, 0x18, 0xa1, 0x4c, 0x2d, 0x73, 0x45, 0x1d,
+	0x5f, 0x41, 0x3d, 0xb3, 0x10, 0x51, 0x15, 0x4a, 0x17, 0xfd, 0xd7, 0xfd, 0xc1, 0x55, 0xdf, 0xd8,
+	0x41, 0x35, 0x28, 0x3b, 0x6e, 0xdb, 0xc6, 0x23, 0xef, 0x07, 0x43, 0x43, 0x7b, 0x00, 0x43, 0x3c,
+	0x78, 0xe5, 0x3a, 0x3e, 0xa7, 0x73, 0x08, 0xa0, 0x78, 0x86, 0xbb, 0xed, 0x97, 0x9e, 0xa1, 0xa3,
+	0x5d, 0xa8, 0xb8, 0xc3, 0x8e, 0xdb, 0x73, 0xb1, 0x7d, 0x6e, 0xe4, 0x8f, 0x7f, 0x06, 0x58, 0xef,
+	0x42, 0x54, 0x86, 0xbc, 0xef, 0xbe, 0xf5, 0x8d, 0x1d, 0x7e, 0x7a, 0xe9, 0xb7, 0x1d, 0x43, 0xe3,
+	0xa7, 0x17, 0xde, 0xa0, 0x6f, 0xe4, 0xf8, 0xe9, 0x15, 0x3f, 0xe9, 0xa8, 0x04, 0xba, 0xe3, 0x5d,
+	0x1a, 0xf9, 0xe3, 0xe7, 0x50, 0xcf, 0xac, 0x46, 0x8e, 0xea, 0x0f, 0xfa, 0xae, 0xb1, 0x83, 0x2a,
+	0x50, 0xf0, 0x6d, 0x7c, 0x76, 0x6d, 0x68, 0x5c, 0xe1, 0xba, 0x3b, 0x34, 0x72, 0xa8, 0x08, 0xb9,
+	0xb3, 0x6b, 0x43, 0xe7, 0xdf, 0xb7, 0xd7, 0x46, 0xfe, 0x78, 0x01, 0xb0, 0x5e, 0x93, 0xdc, 0x71,
+	0x6c, 0x5f, 0x8d, 0xdc, 0x4b, 0xb7, 0xef, 0x7b, 0xc6, 0x0e, 0x7a, 0x04, 0x86, 0x33, 0x38, 0x3f,
+	0xb7, 0x87, 0x9e, 0xdb, 0x4e, 0xb9, 0x1a, 0x6a, 0xc0, 0x41, 0xb7, 0xef, 0xbb, 0xf8, 0xd2, 0x3e,
+	0x1f, 0x79, 0x17, 0xbd, 0x9e, 0x8d, 0xbb, 0xd7, 0xb6, 0xdf, 0x15, 0x3e, 0xee, 0x42, 0xa5, 0xd3,
+	0xf5, 0xfc, 0xc1, 0x19, 0xb6, 0x7b, 0x86, 0x8e, 0xf6, 0x61, 0xd7, 0xb9, 0xf0, 0xfc, 0x41, 0x2f,
+	0xd5, 0xce, 0x1f, 0xff, 0x5b, 0x4b, 0xcb, 0x2e, 0xae, 0x2c, 0x81, 0xee, 0x5d, 0xf4, 0x64, 0xc4,
+	0x3d, 0xd7, 0xee, 0x1b, 0x1a, 0x4f, 0x57, 0xcf, 0x6d, 0x77, 0x6d, 0x6e, 0xaf, 0x04, 0x7a, 0xcf,
+	0x7e, 0x2b, 0x43, 0xee, 0x75, 0xfb, 0x46, 0x1e, 0x1d, 0x00, 0xf2, 0x7c, 0xbb, 0xdf, 0xb6, 0x71,
+	0x7b, 0xd4, 0x76, 0x2f, 0xbb, 0xf2, 0xe6, 0x02, 0xf7, 0xdd, 0xef, 0xe0, 0xc1, 0xc5, 0x59, 0x67,
+	0x78, 0xe1, 0x1b, 0x45, 0x5e, 0x9f, 0x73, 0xdb, 0x77, 0xfb, 0xce, 0x1f, 0x8c, 0x12, 0xfa, 0x0a,
+	0xea, 0x43, 0x17, 0x3b, 0x6e, 0xdf, 0xef, 0x9e, 0xbb, 0xa3, 0xd3, 0x53, 0xbf, 0x63, 0x94, 0xb3,
+	0xcc, 0xa7, 0x7e, 0xc7, 0xa8, 0x64, 0x99, 0xbf, 0xf1, 0x3b, 0x06, 0x64, 0x98, 0xcf, 0x38, 0xb3,
+	0x9a, 0x61, 0x3e, 0xe5, 0xcc, 0x5a, 0xeb, 0xef, 0x79, 0x78, 0xec, 0xf0, 0x36, 0x1d, 0x92, 0x44,
+	0x3c, 0x6c, 0xa2, 0x31, 0x51, 0x93, 0x00, 0x3d, 0x07, 0xe4, 0x88, 0xa7, 0x83, 0x64, 0xc8, 0x27,
+	0x03, 0xda, 0xdf, 0x7a, 0x09, 0xf2, 0x66, 0x68, 0x64, 0x36, 0xe6, 0x6a, 0xb9, 0xff, 0x0c, 0x86,
+	0xcd, 0x58, 0x30, 0x7e, 0xb7, 0xc6, 0x7e, 0x89, 0xfa, 0xef, 0xa1, 0x2e, 0xd5, 0xed, 0xd5, 0xf3,
+	0x31, 0xfb, 0x8b, 0x7e, 0x52, 0xff, 0x47, 0xd8, 0x55, 0xd7, 0xcb, 0x9f, 0x0e, 0x6d, 0x8f, 0x9d,
+	0x4f, 0xea, 0x9e, 0x42, 0x95, 0xbf, 0x53, 0xd2, 0x54, 0x64, 0x76, 0x88, 0x18, 0xaf, 0x8d, 0x94,
+	0xb9, 0xf9, 0xa0, 0x39, 0xd2, 0x90, 0x0b, 0xfb, 0x9c, 0xb3, 0x35, 0x55, 0xd1, 0x83, 0xb3, 0xf6,
+	0x93, 0x66, 0x9e, 0x43, 0xcd, 0x99, 0xc5, 0x74, 0x55, 0x8d, 0xc7, 0xdb, 0x2e, 0xac, 0x1e, 0x70,
+	0xff, 0x35, 0x84, 0xd7, 0x80, 0xce, 0x08, 0xcb, 0xce, 0x92, 0x5f, 0x65, 0x1e, 0xf2, 0xdb, 0xd3,
+	0xa7, 0x71, 0xf0, 0xb0, 0xf8, 0x05, 0x5c, 0x97, 0x43, 0xfe, 0x1a, 0x89, 0x82, 0xd9, 0x4d, 0x51,
+	0x6c, 0xad, 0x1f, 0xfe, 0x33, 0x00, 0x8c, 0x7c, 0x5d, 0x79, 0x25, 0x0e, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1297,7 +1638,9 @@ type CedarPerformanceMetricsClient interface {
 	AttachArtifacts(ctx context.Context, in *ArtifactData, opts ...grpc.CallOption) (*MetricsResponse, error)
 	AttachRollups(ctx context.Context, in *RollupData, opts ...grpc.CallOption) (*MetricsResponse, error)
 	SendMetrics(ctx context.Context, opts ...grpc.CallOption) (CedarPerformanceMetrics_SendMetricsClient, error)
+	SendCustomMetrics(ctx context.Context, opts ...grpc.CallOption) (CedarPerformanceMetrics_SendCustomMetricsClient, error)
 	CloseMetrics(ctx context.Context, in *MetricsSeriesEnd, opts ...grpc.CallOption) (*MetricsResponse, error)
+	GetResultIDSchemas(ctx context.Context, in *ResultIDSchemasRequest, opts ...grpc.CallOption) (*ResultIDSchemas, error)
 }
 
 type cedarPerformanceMetricsClient struct {
@@ -1378,6 +1721,40 @@ func (x *cedarPerformanceMetricsSendMetricsClient) CloseAndRecv() (*SendResponse
 	return m, nil
 }
 
+func (c *cedarPerformanceMetricsClient) SendCustomMetrics(ctx context.Context, opts ...grpc.CallOption) (CedarPerformanceMetrics_SendCustomMetricsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_CedarPerformanceMetrics_serviceDesc.Streams[1], "/cedar.CedarPerformanceMetrics/SendCustomMetrics", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &cedarPerformanceMetricsSendCustomMetricsClient{stream}
+	return x, nil
+}
+
+type CedarPerformanceMetrics_SendCustomMetricsClient interface {
+	Send(*CustomMetricsEvent) error
+	CloseAndRecv() (*SendResponse, error)
+	grpc.ClientStream
+}
+
+type cedarPerformanceMetricsSendCustomMetricsClient struct {
+	grpc.ClientStream
+}
+
+func (x *cedarPerformanceMetricsSendCustomMetricsClient) Send(m *CustomMetricsEvent) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *cedarPerformanceMetricsSendCustomMetricsClient) CloseAndRecv() (*SendResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(SendResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func (c *cedarPerformanceMetricsClient) CloseMetrics(ctx context.Context, in *MetricsSeriesEnd, opts ...grpc.CallOption) (*MetricsResponse, error) {
 	out := new(MetricsResponse)
 	err := c.cc.Invoke(ctx, "/cedar.CedarPerformanceMetrics/CloseMetrics", in, out, opts...)
@@ -1387,6 +1764,15 @@ func (c *cedarPerformanceMetricsClient) CloseMetrics(ctx context.Context, in *Me
 	return out, nil
 }
 
+func (c *cedarPerformanceMetricsClient) GetResultIDSchemas(ctx context.Context, in *ResultIDSchemasRequest, opts ...grpc.CallOption) (*ResultIDSchemas, error) {
+	out := new(ResultIDSchemas)
+	err := c.cc.Invoke(ctx, "/cedar.CedarPerformanceMetrics/GetResultIDSchemas", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CedarPerformanceMetricsServer is the server API for CedarPerformanceMetrics service.
 type CedarPerformanceMetricsServer interface {
 	CreateMetricSeries(context.Context, *ResultData) (*MetricsResponse, error)
@@ -1394,7 +1780,9 @@ type CedarPerformanceMetricsServer interface {
 	AttachArtifacts(context.Context, *ArtifactData) (*MetricsResponse, error)
 	AttachRollups(context.Context, *RollupData) (*MetricsResponse, error)
 	SendMetrics(CedarPerformanceMetrics_SendMetricsServer) error
+	SendCustomMetrics(CedarPerformanceMetrics_SendCustomMetricsServer) error
 	CloseMetrics(context.Context, *MetricsSeriesEnd) (*MetricsResponse, error)
+	GetResultIDSchemas(context.Context, *ResultIDSchemasRequest) (*ResultIDSchemas, error)
 }
 
 func RegisterCedarPerformanceMetricsServer(s *grpc.Server, srv CedarPerformanceMetricsServer) {
@@ -1499,6 +1887,32 @@ func (x *cedarPerformanceMetricsSendMetricsServer) Recv() (*MetricsEvent, error)
 	return m, nil
 }
 
+func _CedarPerformanceMetrics_SendCustomMetrics_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CedarPerformanceMetricsServer).SendCustomMetrics(&cedarPerformanceMetricsSendCustomMetricsServer{stream})
+}
+
+type CedarPerformanceMetrics_SendCustomMetricsServer interface {
+	SendAndClose(*SendResponse) error
+	Recv() (*CustomMetricsEvent, error)
+	grpc.ServerStream
+}
+
+type cedarPerformanceMetricsSendCustomMetricsServer struct {
+	grpc.ServerStream
+}
+
+func (x *cedarPerformanceMetricsSendCustomMetricsServer) SendAndClose(m *SendResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *cedarPerformanceMetricsSendCustomMetricsServer) Recv() (*CustomMetricsEvent, error) {
+	m := new(CustomMetricsEvent)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 func _CedarPerformanceMetrics_CloseMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(MetricsSeriesEnd)
 	if err := dec(in); err != nil {
@@ -1517,6 +1931,24 @@ func _CedarPerformanceMetrics_CloseMetrics_Handler(srv interface{}, ctx context.
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CedarPerformanceMetrics_GetResultIDSchemas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResultIDSchemasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CedarPerformanceMetricsServer).GetResultIDSchemas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/cedar.CedarPerformanceMetrics/GetResultIDSchemas",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CedarPerformanceMetricsServer).GetResultIDSchemas(ctx, req.(*ResultIDSchemasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _CedarPerformanceMetrics_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "cedar.CedarPerformanceMetrics",
 	HandlerType: (*CedarPerformanceMetricsServer)(nil),
@@ -1541,6 +1973,10 @@ var _CedarPerformanceMetrics_serviceDesc = grpc.ServiceDesc{
 			MethodName: "CloseMetrics",
 			Handler:    _CedarPerformanceMetrics_CloseMetrics_Handler,
 		},
+		{
+			MethodName: "GetResultIDSchemas",
+			Handler:    _CedarPerformanceMetrics_GetResultIDSchemas_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1548,6 +1984,11 @@ var _CedarPerformanceMetrics_serviceDesc = grpc.ServiceDesc{
 			Handler:       _CedarPerformanceMetrics_SendMetrics_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "SendCustomMetrics",
+			Handler:       _CedarPerformanceMetrics_SendCustomMetrics_Handler,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "perf.proto",
 }
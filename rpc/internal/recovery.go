@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"github.com/mongodb/grip/recovery"
+	"golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServerOptions returns the options for servers that the service is
+// attached to. The options recover from panics in handlers, so that
+// one bad request fails with an internal error rather than taking
+// down the server.
+func ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(recoverUnary),
+		grpc.StreamInterceptor(recoverStream),
+	}
+}
+
+func recoverUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			resp = nil
+			err = panicStatus(p, info.FullMethod)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+func recoverStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = panicStatus(p, info.FullMethod)
+		}
+	}()
+
+	return handler(srv, ss)
+}
+
+// panicStatus logs the panic and converts it to an internal error.
+func panicStatus(p interface{}, method string) error {
+	err := recovery.HandlePanicWithError(p, nil, "handling rpc", method)
+	return status.Errorf(codes.Internal, "panic in %s: %s", method, err.Error())
+}
@@ -0,0 +1,44 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRecoveryInterceptors(t *testing.T) {
+	t.Run("Unary", func(t *testing.T) {
+		resp, err := recoverUnary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test/Unary"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				return "ok", nil
+			})
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+
+		resp, err = recoverUnary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/test/Unary"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				panic("bad request")
+			})
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Contains(t, err.Error(), "bad request")
+	})
+	t.Run("Stream", func(t *testing.T) {
+		err := recoverStream(nil, nil, &grpc.StreamServerInfo{FullMethod: "/test/Stream"},
+			func(srv interface{}, stream grpc.ServerStream) error {
+				return nil
+			})
+		assert.NoError(t, err)
+
+		err = recoverStream(nil, nil, &grpc.StreamServerInfo{FullMethod: "/test/Stream"},
+			func(srv interface{}, stream grpc.ServerStream) error {
+				panic("bad stream")
+			})
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Contains(t, err.Error(), "bad stream")
+	})
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/evergreen-ci/cedar"
 	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/units"
 	"github.com/mongodb/ftdc/events"
 	"github.com/mongodb/grip"
 	"github.com/pkg/errors"
@@ -58,6 +59,9 @@ func (srv *perfService) AttachResultData(ctx context.Context, result *ResultData
 	record := &model.PerformanceResult{}
 	record.Setup(srv.env)
 	record.Info = *result.Id.Export()
+	if err := record.Info.ValidateSchema(); err != nil {
+		return nil, errors.Wrap(err, "invalid result id")
+	}
 	record.ID = record.Info.ID()
 
 	if err := record.Find(); err != nil {
@@ -84,6 +88,13 @@ func (srv *perfService) AttachResultData(ctx context.Context, result *ResultData
 	if err := record.Save(); err != nil {
 		return resp, errors.Wrapf(err, "problem saving document '%s'", record.ID)
 	}
+
+	if len(result.Artifacts) > 0 {
+		if err := srv.addFTDCRollupsJob(record); err != nil {
+			return resp, errors.WithStack(err)
+		}
+	}
+
 	resp.Success = true
 	return resp, nil
 }
@@ -112,6 +123,13 @@ func (srv *perfService) AttachArtifacts(ctx context.Context, artifactData *Artif
 	if err := record.Save(); err != nil {
 		return resp, errors.Wrapf(err, "problem saving document '%s'", record.ID)
 	}
+
+	if len(artifactData.Artifacts) > 0 {
+		if err := srv.addFTDCRollupsJob(record); err != nil {
+			return resp, errors.WithStack(err)
+		}
+	}
+
 	resp.Success = true
 	return resp, nil
 }
@@ -148,56 +166,205 @@ func (srv *perfService) SendMetrics(stream CedarPerformanceMetrics_SendMetricsSe
 	//     longer than we often do, which may lead to load
 	//     balancer shenanigans
 
-	ctx := stream.Context()
-	catcher := grip.NewBasicCatcher()
 	pipe := make(chan events.Performance)
+	return srv.storeMetricsStream(stream, metricsStream{
+		recv: func() (metricsMessage, error) {
+			msg, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+			return msg, nil
+		},
+		create: model.CreateRawEventsArtifact,
+		export: func(ctx context.Context, msg metricsMessage) (int64, error) {
+			var count int64
+			catcher := grip.NewBasicCatcher()
+			for _, event := range msg.(*MetricsEvent).Event {
+				pp, err := event.Export()
+				if err != nil {
+					catcher.Add(err)
+					continue
+				}
+
+				select {
+				case pipe <- *pp:
+					count++
+				case <-ctx.Done():
+					return count, catcher.Resolve()
+				}
+			}
+			return count, catcher.Resolve()
+		},
+		dump: func(ctx context.Context, record *model.PerformanceResult, output io.Writer) error {
+			return model.DumpPerformanceSeries(ctx, pipe, record, output)
+		},
+		close: func() { close(pipe) },
+	})
+}
+
+// SendCustomMetrics stores a stream of points with arbitrary named
+// metrics, for metrics that do not fit the fixed shape of the points
+// of SendMetrics, as a custom events artifact of the result.
+func (srv *perfService) SendCustomMetrics(stream CedarPerformanceMetrics_SendCustomMetricsServer) error {
+	pipe := make(chan events.Custom)
+	return srv.storeMetricsStream(stream, metricsStream{
+		recv: func() (metricsMessage, error) {
+			msg, err := stream.Recv()
+			if err != nil {
+				return nil, err
+			}
+			return msg, nil
+		},
+		create: model.CreateCustomEventsArtifact,
+		export: func(ctx context.Context, msg metricsMessage) (int64, error) {
+			var count int64
+			catcher := grip.NewBasicCatcher()
+			for _, event := range msg.(*CustomMetricsEvent).Event {
+				pp, err := event.Export()
+				if err != nil {
+					catcher.Add(err)
+					continue
+				}
+
+				select {
+				case pipe <- pp:
+					count++
+				case <-ctx.Done():
+					return count, catcher.Resolve()
+				}
+			}
+			return count, catcher.Resolve()
+		},
+		dump: func(ctx context.Context, record *model.PerformanceResult, output io.Writer) error {
+			return model.DumpCustomSeries(ctx, pipe, record, output)
+		},
+		close: func() { close(pipe) },
+	})
+}
+
+// metricsMessage is a message of a metrics stream, each of which
+// identifies the result that its events belong to.
+type metricsMessage interface {
+	GetId() string
+}
+
+// metricsStreamServer is the part of the server side of the metrics
+// streams that does not depend on the type of their messages.
+type metricsStreamServer interface {
+	Context() context.Context
+	SendAndClose(*SendResponse) error
+}
+
+// metricsStream adapts a stream of fixed or custom metrics to
+// storeMetricsStream, which handles everything that the streams have
+// in common.
+type metricsStream struct {
+	// recv returns the next message of the stream.
+	recv func() (metricsMessage, error)
+	// create creates the artifact that the stream is written to.
+	create func(context.Context, cedar.Environment, string) (*model.ArtifactInfo, io.WriteCloser, error)
+	// export passes the events of the message to the dump, and
+	// returns the number of events that it passed.
+	export func(context.Context, metricsMessage) (int64, error)
+	// dump writes the exported events to the output of the
+	// artifact, until close is called.
+	dump  func(context.Context, *model.PerformanceResult, io.Writer) error
+	close func()
+}
+
+// storeMetricsStream writes the events of a metrics stream to a new
+// artifact of the result that the stream identifies, attaches the
+// artifact to the result, and responds with the number of events
+// that were stored.
+func (srv *perfService) storeMetricsStream(server metricsStreamServer, stream metricsStream) error {
+	ctx := server.Context()
+
+	// the first message in the stream identifies the result, which
+	// we need to resolve before we can create the output file.
+	first, err := stream.recv()
+	if err == io.EOF {
+		return errors.WithStack(server.SendAndClose(&SendResponse{}))
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
 	record := &model.PerformanceResult{}
 	record.Setup(srv.env)
+	record.ID = first.GetId()
+	if err = record.Find(); err != nil {
+		return errors.Wrapf(err, "problem finding record for '%s'", first.GetId())
+	}
+
+	artifact, output, err := stream.create(ctx, srv.env, record.ID)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var count int64
+	catcher := grip.NewBasicCatcher()
 
 	go func() {
-		defer close(pipe)
+		defer stream.close()
+		msg := first
 		for {
+			if msg.GetId() != record.ID {
+				catcher.Add(errors.New("metric point in stream does not match reference, aborting"))
+				return
+			}
+
+			exported, err := stream.export(ctx, msg)
+			count += exported
+			catcher.Add(err)
 			if ctx.Err() != nil {
 				return
 			}
 
-			point, err := stream.Recv()
+			msg, err = stream.recv()
 			if err == io.EOF {
-				catcher.Add(stream.SendAndClose(&SendResponse{}))
 				return
 			}
 			if err != nil {
 				catcher.Add(errors.WithStack(err))
 				return
 			}
-
-			if record.IsNil() {
-				record.ID = point.Id
-				if err = record.Find(); err != nil {
-					catcher.Add(err)
-					return
-				}
-			} else if point.Id != record.ID {
-				catcher.Add(errors.New("metric point in stream does not match reference, aborting"))
-				return
-			}
-
-			for _, event := range point.Event {
-				pp, err := event.Export()
-				if err != nil {
-					catcher.Add(err)
-					continue
-				}
-				pipe <- *pp
-			}
 		}
 	}()
 
-	// TODO: this won't actually work: we need to get/create a
-	// writer here, we'll do this
-	catcher.Add(model.DumpPerformanceSeries(ctx, pipe, record, nil))
+	catcher.Add(stream.dump(ctx, record, output))
+	if catcher.HasErrors() {
+		// clients retry streams that fail, so the partial data is
+		// removed rather than attached to the result.
+		catcher.Add(errors.Wrapf(output.Close(), "problem flushing metrics data for '%s'", record.ID))
+		grip.Warning(errors.Wrapf(artifact.Remove(context.Background(), srv.env),
+			"problem removing partial metrics data for '%s'", record.ID))
+		return catcher.Resolve()
+	}
 
-	return catcher.Resolve()
+	if err = srv.attachStreamArtifact(record, artifact, output); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(server.SendAndClose(&SendResponse{
+		Id:      record.ID,
+		Success: true,
+		Count:   count,
+	}))
+}
+
+// attachStreamArtifact closes the output of a streamed artifact,
+// attaches the artifact to the result and schedules the calculation
+// of the rollups of the result.
+func (srv *perfService) attachStreamArtifact(record *model.PerformanceResult, artifact *model.ArtifactInfo, output io.Closer) error {
+	if err := output.Close(); err != nil {
+		return errors.Wrapf(err, "problem flushing metrics data for '%s'", record.ID)
+	}
+
+	if err := record.AppendArtifacts(*artifact); err != nil {
+		return errors.Wrapf(err, "problem attaching artifact to '%s'", record.ID)
+	}
+
+	return errors.WithStack(srv.addFTDCRollupsJob(record))
 }
 
 func (srv *perfService) CloseMetrics(ctx context.Context, end *MetricsSeriesEnd) (*MetricsResponse, error) {
@@ -208,12 +375,47 @@ func (srv *perfService) CloseMetrics(ctx context.Context, end *MetricsSeriesEnd)
 		return nil, errors.Wrapf(err, "problem finding record for %s", record.ID)
 	}
 
+	resp := &MetricsResponse{}
+	resp.Id = record.ID
+
 	record.CompletedAt = time.Now()
 	if err := record.Save(); err != nil {
-		return nil, errors.Wrapf(err, "problem saving record %s", record.ID)
+		return resp, errors.Wrapf(err, "problem saving record %s", record.ID)
+	}
+
+	if err := srv.addFTDCRollupsJob(record); err != nil {
+		return resp, errors.WithStack(err)
+	}
+
+	resp.Success = true
+	return resp, nil
+}
+
+// GetResultIDSchemas returns the result id schemas that clients may
+// set on result ids, and the newest of them.
+func (srv *perfService) GetResultIDSchemas(ctx context.Context, _ *ResultIDSchemasRequest) (*ResultIDSchemas, error) {
+	resp := &ResultIDSchemas{Latest: int32(model.LatestPerfResultIDSchema())}
+	for _, schema := range model.SupportedPerfResultIDSchemas() {
+		resp.Schemas = append(resp.Schemas, int32(schema))
+	}
+
+	return resp, nil
+}
+
+// addFTDCRollupsJob queues a job to (re)calculate the rollups for
+// the result from its FTDC artifacts, unless one is already queued for
+// the current artifacts of the result.
+func (srv *perfService) addFTDCRollupsJob(record *model.PerformanceResult) error {
+	q, err := srv.env.GetQueue()
+	if err != nil {
+		return errors.Wrap(err, "problem getting queue")
+	}
+
+	if err = units.PutJobOnce(q, units.MakeFTDCRollupsJob(srv.env, record)); err != nil {
+		return errors.Wrapf(err, "problem queuing rollups job for '%s'", record.ID)
 	}
 
-	return nil, nil
+	return nil
 }
 
 func addRollups(record *model.PerformanceResult, rollups []*RollupValue) error {
@@ -8,6 +8,7 @@ import (
 
 	"github.com/evergreen-ci/cedar"
 	"github.com/evergreen-ci/cedar/model"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/mongodb/amboy"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -54,7 +55,7 @@ func startPerfService(ctx context.Context, env cedar.Environment) error {
 		return errors.WithStack(err)
 	}
 
-	s := grpc.NewServer()
+	s := grpc.NewServer(ServerOptions()...)
 	AttachService(env, s)
 
 	go s.Serve(lis)
@@ -88,10 +89,20 @@ func createEnv(mock bool) (cedar.Environment, error) {
 	err := env.Configure(&cedar.Configuration{
 		MongoDBURI:    "mongodb://localhost:27017",
 		DatabaseName:  "grpc_test",
+		BucketName:    "grpc_test",
 		NumWorkers:    2,
 		UseLocalQueue: true,
 	})
-	return env, errors.WithStack(err)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	q, err := env.GetQueue()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	return env, errors.WithStack(q.Start(context.Background()))
 }
 
 func tearDownEnv(env cedar.Environment, mock bool) error {
@@ -315,3 +326,119 @@ func TestAttachResultData(t *testing.T) {
 		})
 	}
 }
+
+func TestSendMetrics(t *testing.T) {
+	for _, test := range []struct {
+		name          string
+		save          bool
+		id            string
+		points        int
+		expectedCount int64
+		err           bool
+	}{
+		{
+			name:          "TestSendMetrics",
+			save:          true,
+			id:            (&model.PerformanceResultInfo{}).ID(),
+			points:        10,
+			expectedCount: 10,
+		},
+		{
+			name: "TestSendMetricsDoesNotExist",
+			id:   (&model.PerformanceResultInfo{}).ID(),
+			err:  true,
+		},
+		{
+			name:   "TestSendMetricsMismatchedID",
+			save:   true,
+			id:     "DNE",
+			points: 1,
+			err:    true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			env, err := createEnv(false)
+			require.NoError(t, err)
+			defer func() {
+				require.NoError(t, tearDownEnv(env, false))
+			}()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			err = startPerfService(ctx, env)
+			require.NoError(t, err)
+			client, err := getClient(ctx)
+			require.NoError(t, err)
+
+			if test.save {
+				_, err = client.CreateMetricSeries(ctx, &ResultData{Id: &ResultID{}})
+				require.NoError(t, err)
+			}
+
+			stream, err := client.SendMetrics(ctx)
+			require.NoError(t, err)
+
+			event := &MetricsEvent{Id: test.id}
+			for i := 0; i < test.points; i++ {
+				event.Event = append(event.Event, &MetricsPoint{
+					Time:     ptypes.TimestampNow(),
+					Counters: &MetricsCounters{Ops: int64(i)},
+					Timers: &MetricsTimers{
+						Duration: ptypes.DurationProto(time.Millisecond),
+						Total:    ptypes.DurationProto(time.Millisecond),
+					},
+					Gauges: &MetricsGauges{Workers: 1},
+				})
+			}
+			if test.save && test.id != (&model.PerformanceResultInfo{}).ID() {
+				// the first message resolves the record, the
+				// second one has the wrong id.
+				require.NoError(t, stream.Send(&MetricsEvent{Id: (&model.PerformanceResultInfo{}).ID()}))
+			}
+			_ = stream.Send(event)
+
+			resp, err := stream.CloseAndRecv()
+			if test.err {
+				assert.Error(t, err)
+				if test.save {
+					// the partial data of a failed stream is not
+					// attached to the result.
+					record := &model.PerformanceResult{ID: (&model.PerformanceResultInfo{}).ID()}
+					record.Setup(env)
+					require.NoError(t, record.Find())
+					assert.Empty(t, record.Artifacts)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, resp.Success)
+			assert.Equal(t, test.id, resp.Id)
+			assert.Equal(t, test.expectedCount, resp.Count)
+
+			record := &model.PerformanceResult{ID: test.id}
+			record.Setup(env)
+			require.NoError(t, record.Find())
+			require.Len(t, record.Artifacts, 1)
+			assert.Equal(t, model.FileFTDC, record.Artifacts[0].Format)
+			assert.Equal(t, model.SchemaRawEvents, record.Artifacts[0].Schema)
+		})
+	}
+}
+
+func TestResultIDSchemas(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	srv := &perfService{env: &MockEnv{}}
+
+	resp, err := srv.GetResultIDSchemas(ctx, &ResultIDSchemasRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []int32{0, 1}, resp.Schemas)
+	assert.EqualValues(t, model.LatestPerfResultIDSchema(), resp.Latest)
+
+	data := &ResultData{Id: &ResultID{Project: "project", Schema: 1000}}
+	_, err = srv.CreateMetricSeries(ctx, data)
+	assert.Error(t, err)
+	_, err = srv.AttachResultData(ctx, data)
+	assert.Error(t, err)
+}
@@ -9,3 +9,9 @@ import (
 func AttachService(env cedar.Environment, srv *grpc.Server) {
 	internal.AttachService(env, srv)
 }
+
+// ServerOptions returns the options to create the servers that the
+// service is attached to with.
+func ServerOptions() []grpc.ServerOption {
+	return internal.ServerOptions()
+}
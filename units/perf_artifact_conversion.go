@@ -0,0 +1,127 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const artifactConversionJobName = "perf-artifact-conversion"
+
+func init() {
+	registry.AddJobType(artifactConversionJobName, func() amboy.Job {
+		return artifactConversionJobFactory()
+	})
+}
+
+type artifactConversionJob struct {
+	PerfID    string `bson:"perf_id" json:"perf_id" yaml:"perf_id"`
+	*job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	env       cedar.Environment
+}
+
+func artifactConversionJobFactory() amboy.Job {
+	j := &artifactConversionJob{
+		Base: &job.Base{
+			JobType: amboy.JobType{
+				Name:    artifactConversionJobName,
+				Version: 1,
+			},
+		},
+		env: cedar.GetEnvironment(),
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// MakeArtifactConversionJob returns a job that converts the JSON, CSV,
+// and BSON artifacts of the performance result to FTDC, attaches the
+// converted artifacts to the result, and then schedules the
+// calculation of its rollups. Artifacts that were already converted
+// are skipped, and artifacts that cannot be converted are marked with
+// the conversion error. The job is identified by the result and its
+// artifacts, so that it is only scheduled again once the artifacts
+// change.
+func MakeArtifactConversionJob(env cedar.Environment, result *model.PerformanceResult) amboy.Job {
+	j := artifactConversionJobFactory().(*artifactConversionJob)
+	j.SetID(fmt.Sprintf("%s-%s", j.Type().Name, perfResultJobKey(result)))
+	j.PerfID = result.ID
+	j.env = env
+	return j
+}
+
+func (j *artifactConversionJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.PerfID == "" {
+		j.AddError(errors.New("cannot convert artifacts without a performance result id"))
+		return
+	}
+
+	result := &model.PerformanceResult{ID: j.PerfID}
+	result.Setup(j.env)
+	if err := result.Find(); err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding performance result '%s'", j.PerfID))
+		return
+	}
+
+	artifacts := result.UnconvertedArtifacts()
+	if len(artifacts) == 0 {
+		return
+	}
+
+	catcher := grip.NewBasicCatcher()
+	converted := []model.ArtifactInfo{}
+	failed := 0
+	for idx := range artifacts {
+		artifact, err := artifacts[idx].ConvertToFTDC(ctx, j.env)
+		if err != nil {
+			// the failure is recorded on the artifact, so that the
+			// rollups of the other artifacts are not held back.
+			failed++
+			grip.Warning(message.WrapError(err, message.Fields{
+				"job":     j.ID(),
+				"perf_id": j.PerfID,
+				"path":    artifacts[idx].Path,
+				"message": "problem converting artifact to ftdc",
+			}))
+			catcher.Add(result.SetArtifactConversionError(artifacts[idx].Path, err))
+			continue
+		}
+		converted = append(converted, *artifact)
+	}
+	catcher.Add(result.AppendArtifacts(converted...))
+
+	grip.Info(message.Fields{
+		"job":       j.ID(),
+		"perf_id":   j.PerfID,
+		"converted": len(converted),
+		"failed":    failed,
+		"message":   "converted artifacts to ftdc",
+	})
+
+	// rollups are only calculated once every artifact has either
+	// been converted or failed to convert, since the rollups job
+	// schedules the conversion of any remaining artifacts.
+	if !catcher.HasErrors() {
+		q, err := j.env.GetQueue()
+		if err != nil {
+			catcher.Add(errors.Wrap(err, "problem getting queue"))
+		} else {
+			catcher.Add(errors.Wrapf(PutJobOnce(q, MakeFTDCRollupsJob(j.env, result)),
+				"problem scheduling rollups for '%s'", j.PerfID))
+		}
+	}
+
+	j.AddError(catcher.Resolve())
+}
@@ -0,0 +1,121 @@
+package units
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/amboy/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArtifactConversionJob(t *testing.T) {
+	t.Run("QueuedOncePerArtifacts", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		q := queue.NewLocalLimitedSize(1, 16)
+		require.NoError(t, q.Start(ctx))
+
+		env := cedar.GetEnvironment()
+		result := &model.PerformanceResult{
+			ID:        "foo",
+			Artifacts: []model.ArtifactInfo{{Path: "foo.json", Format: model.FileJSON}},
+		}
+		require.NoError(t, PutJobOnce(q, MakeArtifactConversionJob(env, result)))
+		require.NoError(t, PutJobOnce(q, MakeArtifactConversionJob(env, result)))
+		assert.Equal(t, 1, q.Stats().Total)
+
+		result.Artifacts = append(result.Artifacts, model.ArtifactInfo{Path: "bar.csv", Format: model.FileCSV})
+		require.NoError(t, PutJobOnce(q, MakeArtifactConversionJob(env, result)))
+		assert.Equal(t, 2, q.Stats().Total)
+	})
+	t.Run("ConvertsArtifacts", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		env, cleanup := setupUnitsTestDB(t)
+		defer cleanup()
+
+		tempDir, err := ioutil.TempDir("", "cedar-artifact-conversion")
+		require.NoError(t, err)
+		defer os.RemoveAll(tempDir)
+
+		csvData := &bytes.Buffer{}
+		gw := gzip.NewWriter(csvData)
+		_, err = gw.Write([]byte(`ts,counters.n,counters.ops,timers.dur,gauges.workers
+2019-01-01T00:00:00Z,1,10,1000000,4
+2019-01-01T00:00:01Z,2,20,2000000,4
+`))
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+
+		artifacts := []model.ArtifactInfo{}
+		for _, test := range []struct {
+			path        string
+			format      model.FileDataFormat
+			compression model.FileCompression
+			data        []byte
+		}{
+			{path: "events.csv.gz", format: model.FileCSV, compression: model.FileGz, data: csvData.Bytes()},
+			{path: "events.json", format: model.FileJSON, data: []byte(`{"ts": "2019-01-01T00:00:00Z", "counters": {"n": 1, "ops": 10}}`)},
+			{path: "invalid.json", format: model.FileJSON, data: []byte(`[{"ts": 1}, `)},
+		} {
+			artifact := model.ArtifactInfo{
+				Type:        model.PailLocal,
+				Bucket:      tempDir,
+				Path:        test.path,
+				Format:      test.format,
+				Compression: test.compression,
+				Schema:      model.SchemaRawEvents,
+			}
+			w, err := artifact.Writer(ctx, env)
+			require.NoError(t, err)
+			_, err = w.Write(test.data)
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			artifacts = append(artifacts, artifact)
+		}
+
+		result := model.CreatePerformanceResult(model.PerformanceResultInfo{Project: "project", TestName: "test"}, artifacts)
+		result.Setup(env)
+		require.NoError(t, result.Save())
+
+		j := MakeArtifactConversionJob(env, result)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		require.NoError(t, result.Find())
+		assert.Empty(t, result.UnconvertedArtifacts())
+		q, err := env.GetQueue()
+		require.NoError(t, err)
+		_, ok := q.Get(MakeFTDCRollupsJob(env, result).ID())
+		assert.True(t, ok)
+		failed := result.FailedConversions()
+		require.Len(t, failed, 1)
+		assert.Equal(t, "invalid.json", failed[0].Path)
+
+		counts := map[string]int{}
+		for _, artifact := range result.Artifacts {
+			if artifact.Format != model.FileFTDC {
+				continue
+			}
+			assert.Equal(t, model.FileUncompressed, artifact.Compression)
+			iter, err := artifact.PerformanceEvents(ctx, env)
+			require.NoError(t, err)
+			for iter.Next() {
+				counts[artifact.Path]++
+			}
+			assert.NoError(t, iter.Err())
+			iter.Close()
+		}
+		assert.Equal(t, map[string]int{
+			artifacts[0].ConvertedPath(): 2,
+			artifacts[1].ConvertedPath(): 1,
+		}, counts)
+	})
+}
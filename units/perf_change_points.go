@@ -0,0 +1,171 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	changePointDetectionJobName = "perf-change-point-detection"
+
+	// noiseMagnitudeFactor scales the noise score of a measurement to
+	// the smallest change point magnitude that is reported for it,
+	// and to the increase of the regression thresholds of the
+	// measurement, so that shifts within the noise of a series are not
+	// reported as regressions.
+	noiseMagnitudeFactor = 2
+)
+
+func init() {
+	registry.AddJobType(changePointDetectionJobName, func() amboy.Job {
+		return changePointDetectionJobFactory()
+	})
+}
+
+type changePointDetectionJob struct {
+	Series    model.PerfSeriesKey `bson:"series" json:"series" yaml:"series"`
+	*job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	env       cedar.Environment
+}
+
+func changePointDetectionJobFactory() amboy.Job {
+	j := &changePointDetectionJob{
+		Base: &job.Base{
+			JobType: amboy.JobType{
+				Name:    changePointDetectionJobName,
+				Version: 1,
+			},
+		},
+		env: cedar.GetEnvironment(),
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// MakeChangePointDetectionJob returns a job that detects the change
+// points of every rollup in the series of the performance result and
+// replaces the previously detected change points of the series. The
+// job is identified by the result and its artifacts, so that the
+// series is checked once for each new result.
+func MakeChangePointDetectionJob(env cedar.Environment, result *model.PerformanceResult) amboy.Job {
+	return makeChangePointDetectionJob(env, result, perfResultJobKey(result))
+}
+
+func makeChangePointDetectionJob(env cedar.Environment, result *model.PerformanceResult, key string) amboy.Job {
+	j := changePointDetectionJobFactory().(*changePointDetectionJob)
+	j.SetID(fmt.Sprintf("%s-%s", j.Type().Name, key))
+	j.Series = result.Info.SeriesKey()
+	j.env = env
+	return j
+}
+
+func (j *changePointDetectionJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	series := &model.PerfSeries{Key: j.Series}
+	series.Setup(j.env)
+	if err := series.Find(); err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding series '%s'", j.Series.ID()))
+		return
+	}
+
+	measurements := make([]string, 0, len(series.Measurements))
+	for name := range series.Measurements {
+		measurements = append(measurements, name)
+	}
+	sort.Strings(measurements)
+
+	noise := &model.PerfNoiseScores{}
+	noise.Setup(j.env)
+	if err := noise.Find(model.PerfNoiseFindOptions{SeriesID: j.Series.ID()}); err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"job":     j.ID(),
+			"series":  j.Series.ID(),
+			"message": "detecting change points without noise scores",
+		}))
+	}
+	scores := noise.Map()
+
+	changePoints := &model.PerfChangePoints{}
+	changePoints.Setup(j.env)
+	catcher := grip.NewBasicCatcher()
+	detected := 0
+	for _, name := range measurements {
+		if err := ctx.Err(); err != nil {
+			catcher.Add(err)
+			break
+		}
+
+		points := series.Measurements[name]
+		values := make([]float64, len(points))
+		for idx := range points {
+			values[idx] = points[idx].Value
+		}
+
+		calculatedAt := time.Now()
+		found := []model.PerfChangePoint{}
+		opts := perf.ChangePointOptions{}
+		if score, ok := scores[name]; ok {
+			opts.MinMagnitude = noiseMagnitudeFactor * score.Score
+		}
+		for _, cp := range perf.DetectChangePoints(values, opts) {
+			point := points[cp.Index]
+			changePoint := model.CreatePerfChangePoint(j.Series, name, point.PerfResultID)
+			changePoint.Version = point.Version
+			changePoint.Before = cp.Before
+			changePoint.After = cp.After
+			changePoint.Magnitude = cp.Magnitude
+			changePoint.Confidence = cp.Confidence
+			changePoint.Algorithm = model.ChangePointDetails{
+				Name:    perf.ChangePointAlgorithm,
+				Version: perf.ChangePointAlgorithmVersion,
+			}
+			changePoint.CalculatedAt = calculatedAt
+			found = append(found, *changePoint)
+		}
+
+		catcher.Add(changePoints.ReplaceSeries(j.Series, name, found))
+		detected += len(found)
+	}
+
+	grip.Info(message.Fields{
+		"job":           j.ID(),
+		"series":        j.Series.ID(),
+		"project":       j.Series.Project,
+		"variant":       j.Series.Variant,
+		"task":          j.Series.TaskName,
+		"test":          j.Series.TestName,
+		"measurements":  len(measurements),
+		"change_points": detected,
+		"message":       "detected change points",
+	})
+
+	catcher.Add(j.queueRegressionDetection())
+	j.AddError(catcher.Resolve())
+}
+
+// queueRegressionDetection schedules checking the series for
+// regressions, now that its change points are up to date.
+func (j *changePointDetectionJob) queueRegressionDetection() error {
+	q, err := j.env.GetQueue()
+	if err != nil {
+		return errors.Wrap(err, "problem getting queue")
+	}
+
+	return errors.Wrapf(PutJobOnce(q, MakeRegressionDetectionJob(j.env, j.Series, j.ID())),
+		"problem scheduling regression detection for series '%s'", j.Series.ID())
+}
@@ -0,0 +1,78 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangePointDetectionJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	env, cleanup := setupUnitsTestDB(t)
+	defer cleanup()
+
+	// the latency of the series shifts from about 100 to about 150 at
+	// the 21st version, while the other rollup does not change.
+	results := []*model.PerformanceResult{}
+	createdAt := time.Now().Add(-time.Hour)
+	for idx := 0; idx < 40; idx++ {
+		latency := 100 + float64(idx%3)
+		if idx >= 20 {
+			latency += 50
+		}
+		result := model.CreatePerformanceResult(model.PerformanceResultInfo{
+			Project:  "project",
+			Version:  fmt.Sprintf("v%d", idx),
+			TaskName: "task",
+			TaskID:   fmt.Sprintf("task%d", idx),
+			TestName: "test",
+		}, nil)
+		result.CreatedAt = createdAt.Add(time.Duration(idx) * time.Minute)
+		result.Rollups.Stats = []model.PerfRollupValue{
+			{Name: "latency", Value: latency, MetricType: model.MetricTypeLatency},
+			{Name: "workers", Value: int64(4), MetricType: model.MetricTypeMax},
+		}
+		result.Setup(env)
+		require.NoError(t, result.Save())
+		results = append(results, result)
+	}
+	series := results[0].Info.SeriesKey()
+
+	changePoints := &model.PerfChangePoints{}
+	changePoints.Setup(env)
+	for i := 0; i < 2; i++ {
+		// detecting the change points again replaces them.
+		j, ok := MakeChangePointDetectionJob(env, results[len(results)-1]).(*changePointDetectionJob)
+		require.True(t, ok)
+		assert.Equal(t, series, j.Series)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		require.NoError(t, changePoints.Find(model.PerfChangePointFindOptions{Project: "project", TestName: "test"}))
+		require.Len(t, changePoints.ChangePoints, 1)
+		cp := changePoints.ChangePoints[0]
+		assert.Equal(t, series.ID(), cp.SeriesID)
+		assert.Equal(t, "latency", cp.Measurement)
+		assert.Equal(t, results[20].ID, cp.PerfResultID)
+		assert.Equal(t, "v20", cp.Version)
+		assert.InDelta(t, 101, cp.Before, 1)
+		assert.InDelta(t, 151, cp.After, 1)
+		assert.True(t, cp.Magnitude > 0)
+		assert.Equal(t, perf.ChangePointAlgorithm, cp.Algorithm.Name)
+		assert.Equal(t, perf.ChangePointAlgorithmVersion, cp.Algorithm.Version)
+
+		// regressions are checked once the change points are up to
+		// date.
+		q, err := env.GetQueue()
+		require.NoError(t, err)
+		_, ok = q.Get(MakeRegressionDetectionJob(env, series, j.ID()).ID())
+		assert.True(t, ok)
+	}
+}
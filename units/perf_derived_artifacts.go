@@ -0,0 +1,213 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/ftdc/events"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const derivedArtifactsJobName = "perf-derived-artifacts"
+
+// summaryIntervals are the windows of the interval summary artifacts
+// that are derived from the raw events of every result.
+var summaryIntervals = []time.Duration{time.Second, 10 * time.Second}
+
+func init() {
+	registry.AddJobType(derivedArtifactsJobName, func() amboy.Job {
+		return derivedArtifactsJobFactory()
+	})
+}
+
+type derivedArtifactsJob struct {
+	PerfID    string `bson:"perf_id" json:"perf_id" yaml:"perf_id"`
+	*job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	env       cedar.Environment
+}
+
+func derivedArtifactsJobFactory() amboy.Job {
+	j := &derivedArtifactsJob{
+		Base: &job.Base{
+			JobType: amboy.JobType{
+				Name:    derivedArtifactsJobName,
+				Version: 1,
+			},
+		},
+		env: cedar.GetEnvironment(),
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// MakeDerivedArtifactsJob returns a job that reads the raw events
+// artifacts of the performance result, and writes the interval summary
+// and collapsed events artifacts, which are much smaller, for clients
+// that chart the data. The derived artifacts are attached to the
+// result. Artifacts that were already derived are skipped. The job is
+// identified by the result and its artifacts, so that it is only
+// scheduled again once the artifacts change.
+func MakeDerivedArtifactsJob(env cedar.Environment, result *model.PerformanceResult) amboy.Job {
+	j := derivedArtifactsJobFactory().(*derivedArtifactsJob)
+	j.SetID(fmt.Sprintf("%s-%s", j.Type().Name, perfResultJobKey(result)))
+	j.PerfID = result.ID
+	j.env = env
+	return j
+}
+
+func (j *derivedArtifactsJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.PerfID == "" {
+		j.AddError(errors.New("cannot derive artifacts without a performance result id"))
+		return
+	}
+
+	result := &model.PerformanceResult{ID: j.PerfID}
+	result.Setup(j.env)
+	if err := result.Find(); err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding performance result '%s'", j.PerfID))
+		return
+	}
+
+	rawEvents := []model.ArtifactInfo{}
+	derived := map[string]bool{}
+	for _, artifact := range result.Artifacts {
+		switch artifact.Schema {
+		case model.SchemaRawEvents:
+			if artifact.Format == model.FileFTDC {
+				rawEvents = append(rawEvents, artifact)
+			}
+		case model.SchemaCollapsedEvents:
+			derived[string(model.SchemaCollapsedEvents)] = true
+		case model.SchemaIntervalSummary:
+			for _, tag := range artifact.Tags {
+				derived[tag] = true
+			}
+		}
+	}
+	if len(rawEvents) == 0 {
+		return
+	}
+
+	catcher := grip.NewBasicCatcher()
+	artifacts := []model.ArtifactInfo{}
+	for _, interval := range summaryIntervals {
+		if derived[model.IntervalSummaryTag(interval)] {
+			continue
+		}
+
+		interval := interval
+		artifact, err := j.derive(ctx, rawEvents, perf.NewIntervalSummary(interval), func() (*model.ArtifactInfo, io.WriteCloser, error) {
+			return model.CreateIntervalSummaryArtifact(ctx, j.env, j.PerfID, interval)
+		})
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem summarizing '%s' over %s intervals", j.PerfID, interval))
+			continue
+		}
+		artifacts = append(artifacts, *artifact)
+	}
+	if !derived[string(model.SchemaCollapsedEvents)] {
+		artifact, err := j.derive(ctx, rawEvents, perf.NewCollapsedEvents(), func() (*model.ArtifactInfo, io.WriteCloser, error) {
+			return model.CreateCollapsedEventsArtifact(ctx, j.env, j.PerfID)
+		})
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem collapsing the events of '%s'", j.PerfID))
+		} else {
+			artifacts = append(artifacts, *artifact)
+		}
+	}
+	catcher.Add(result.AppendArtifacts(artifacts...))
+
+	grip.Info(message.Fields{
+		"job":     j.ID(),
+		"perf_id": j.PerfID,
+		"derived": len(artifacts),
+		"message": "derived artifacts from raw events",
+	})
+
+	j.AddError(catcher.Resolve())
+}
+
+// derive writes the reduction of the raw events to a new artifact,
+// and returns the artifact once it is complete. Artifacts that could
+// not be written in full are not returned, since a partial summary
+// would misrepresent the result.
+func (j *derivedArtifactsJob) derive(ctx context.Context, rawEvents []model.ArtifactInfo, reducer perf.SeriesReducer, create func() (*model.ArtifactInfo, io.WriteCloser, error)) (*model.ArtifactInfo, error) {
+	artifact, output, err := create()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	catcher := grip.NewBasicCatcher()
+	pipe := make(chan events.Performance)
+	reduced := make(chan error, 1)
+	go func() {
+		defer close(pipe)
+		reduced <- j.reduce(ctx, rawEvents, reducer, pipe)
+	}()
+
+	catcher.Add(model.DumpPerformanceSeries(ctx, pipe, nil, output))
+	// stop the reduction if the dump ended early.
+	cancel()
+	catcher.Add(<-reduced)
+	catcher.Add(errors.Wrapf(output.Close(), "problem closing '%s'", artifact.Path))
+	if catcher.HasErrors() {
+		return nil, catcher.Resolve()
+	}
+
+	return artifact, nil
+}
+
+// reduce sends the points of the reduction of the raw events, in
+// order, to the output.
+func (j *derivedArtifactsJob) reduce(ctx context.Context, rawEvents []model.ArtifactInfo, reducer perf.SeriesReducer, output chan<- events.Performance) error {
+	send := func(point events.Performance, ok bool) error {
+		if !ok {
+			return nil
+		}
+
+		select {
+		case output <- point:
+			return nil
+		case <-ctx.Done():
+			return errors.New("operation canceled")
+		}
+	}
+
+	for idx := range rawEvents {
+		iter, err := rawEvents[idx].PerformanceEvents(ctx, j.env)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		for iter.Next() {
+			if err = send(reducer.Add(*iter.Event())); err != nil {
+				iter.Close()
+				return err
+			}
+		}
+		err = iter.Err()
+		iter.Close()
+		if err != nil {
+			return errors.Wrapf(err, "problem reading '%s'", rawEvents[idx].Path)
+		}
+	}
+
+	return send(reducer.Flush())
+}
@@ -0,0 +1,97 @@
+package units
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
+	"github.com/mongodb/amboy/queue"
+	"github.com/mongodb/ftdc/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDerivedArtifactsJob(t *testing.T) {
+	t.Run("QueuedOncePerArtifacts", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		q := queue.NewLocalLimitedSize(1, 16)
+		require.NoError(t, q.Start(ctx))
+
+		env := cedar.GetEnvironment()
+		result := &model.PerformanceResult{
+			ID:        "foo",
+			Artifacts: []model.ArtifactInfo{{Path: "raw.ftdc", Format: model.FileFTDC, Schema: model.SchemaRawEvents}},
+		}
+		require.NoError(t, PutJobOnce(q, MakeDerivedArtifactsJob(env, result)))
+		require.NoError(t, PutJobOnce(q, MakeDerivedArtifactsJob(env, result)))
+		assert.Equal(t, 1, q.Stats().Total)
+
+		result.Artifacts = append(result.Artifacts, model.ArtifactInfo{Path: "more.ftdc", Format: model.FileFTDC, Schema: model.SchemaRawEvents})
+		require.NoError(t, PutJobOnce(q, MakeDerivedArtifactsJob(env, result)))
+		assert.Equal(t, 2, q.Stats().Total)
+	})
+	t.Run("Derive", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		tempDir, err := ioutil.TempDir("", "cedar-derived-artifacts")
+		require.NoError(t, err)
+		defer os.RemoveAll(tempDir)
+
+		newArtifact := func(path string, schema model.FileSchema) (*model.ArtifactInfo, io.WriteCloser, error) {
+			artifact := &model.ArtifactInfo{
+				Type:        model.PailLocal,
+				Bucket:      tempDir,
+				Path:        path,
+				Format:      model.FileFTDC,
+				Compression: model.FileUncompressed,
+				Schema:      schema,
+			}
+			output, err := artifact.Writer(ctx, nil)
+			return artifact, output, err
+		}
+
+		start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+		raw, output, err := newArtifact("raw.ftdc", model.SchemaRawEvents)
+		require.NoError(t, err)
+		stream := make(chan events.Performance, 30)
+		for i := 0; i < 30; i++ {
+			point := events.Performance{Timestamp: start.Add(time.Duration(i) * 500 * time.Millisecond)}
+			point.Counters.Operations = int64(10 * (i + 1))
+			point.Gauges.Workers = 4
+			stream <- point
+		}
+		close(stream)
+		require.NoError(t, model.DumpPerformanceSeries(ctx, stream, nil, output))
+		require.NoError(t, output.Close())
+
+		j := MakeDerivedArtifactsJob(cedar.GetEnvironment(), &model.PerformanceResult{ID: "foo"}).(*derivedArtifactsJob)
+		summary, err := j.derive(ctx, []model.ArtifactInfo{*raw}, perf.NewIntervalSummary(10*time.Second), func() (*model.ArtifactInfo, io.WriteCloser, error) {
+			return newArtifact("summary.ftdc", model.SchemaIntervalSummary)
+		})
+		require.NoError(t, err)
+
+		iter, err := summary.PerformanceEvents(ctx, nil)
+		require.NoError(t, err)
+		defer iter.Close()
+		ops := []int64{}
+		for iter.Next() {
+			ops = append(ops, iter.Event().Counters.Operations)
+		}
+		require.NoError(t, iter.Err())
+		assert.Equal(t, []int64{200, 300}, ops)
+
+		_, err = j.derive(ctx, []model.ArtifactInfo{{Type: model.PailLocal, Bucket: tempDir, Path: "missing.ftdc", Format: model.FileFTDC, Schema: model.SchemaRawEvents}},
+			perf.NewCollapsedEvents(), func() (*model.ArtifactInfo, io.WriteCloser, error) {
+				return newArtifact("collapsed.ftdc", model.SchemaCollapsedEvents)
+			})
+		assert.Error(t, err)
+	})
+}
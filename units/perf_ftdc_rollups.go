@@ -0,0 +1,368 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/ftdc"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	ftdcRollupsJobName = "ftdc-rollups"
+
+	ftdcRollupsMaxAttempts   = 10
+	ftdcRollupsRetryInterval = time.Minute
+)
+
+func init() {
+	registry.AddJobType(ftdcRollupsJobName, func() amboy.Job {
+		return ftdcRollupsJobFactory()
+	})
+}
+
+type ftdcRollupsJob struct {
+	PerfID    string `bson:"perf_id" json:"perf_id" yaml:"perf_id"`
+	Attempt   int    `bson:"attempt" json:"attempt" yaml:"attempt"`
+	*job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	env       cedar.Environment
+}
+
+func ftdcRollupsJobFactory() amboy.Job {
+	j := &ftdcRollupsJob{
+		Base: &job.Base{
+			JobType: amboy.JobType{
+				Name:    ftdcRollupsJobName,
+				Version: 1,
+			},
+		},
+		env: cedar.GetEnvironment(),
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// MakeFTDCRollupsJob returns a job that calculates the default
+// rollups from the FTDC artifacts of the performance result and saves
+// them to the result. The job is safe to run more than once for the
+// same result, and is identified by the result and its artifacts, so
+// that it is only scheduled again once the artifacts change.
+func MakeFTDCRollupsJob(env cedar.Environment, result *model.PerformanceResult) amboy.Job {
+	return makeFTDCRollupsJob(env, result, 0)
+}
+
+func makeFTDCRollupsJob(env cedar.Environment, result *model.PerformanceResult, attempt int) amboy.Job {
+	j := ftdcRollupsJobFactory().(*ftdcRollupsJob)
+	j.SetID(fmt.Sprintf("%s-%s-%d", j.Type().Name, perfResultJobKey(result), attempt))
+	j.PerfID = result.ID
+	j.Attempt = attempt
+	j.env = env
+	return j
+}
+
+// perfResultJobKey identifies the performance result, whose id
+// includes its execution, and the state of its artifacts in the ids of
+// the jobs that process the result, so that the result is processed
+// again only once artifacts are attached to it or fail to convert.
+func perfResultJobKey(result *model.PerformanceResult) string {
+	return fmt.Sprintf("%s-%d-%d", result.ID, len(result.Artifacts), len(result.FailedConversions()))
+}
+
+// PutJobOnce adds the job to the queue, unless a job with the same id
+// is already in the queue, in which case the work of the job is either
+// pending or done.
+func PutJobOnce(q amboy.Queue, j amboy.Job) error {
+	if _, ok := q.Get(j.ID()); ok {
+		return nil
+	}
+
+	return errors.WithStack(q.Put(j))
+}
+
+func (j *ftdcRollupsJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.PerfID == "" {
+		j.AddError(errors.New("cannot calculate rollups without a performance result id"))
+		return
+	}
+
+	result := &model.PerformanceResult{ID: j.PerfID}
+	result.Setup(j.env)
+	if err := result.Find(); err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding performance result '%s'", j.PerfID))
+		return
+	}
+
+	// artifacts in other formats must be converted to ftdc first, and
+	// the conversion job schedules the rollups once it is done.
+	if len(result.UnconvertedArtifacts()) > 0 {
+		j.AddError(j.queueArtifactConversion(result))
+		return
+	}
+	if failed := result.FailedConversions(); len(failed) > 0 {
+		paths := make([]string, len(failed))
+		for idx := range failed {
+			paths[idx] = failed[idx].Path
+		}
+		grip.Warning(message.Fields{
+			"job":     j.ID(),
+			"perf_id": j.PerfID,
+			"paths":   paths,
+			"message": "rolling up the artifacts that were converted to ftdc",
+		})
+	}
+
+	artifacts := getFTDCRollupArtifacts(result)
+	if artifacts.isEmpty() {
+		grip.Debug(message.Fields{
+			"job":     j.ID(),
+			"perf_id": j.PerfID,
+			"message": "no ftdc artifacts to roll up",
+		})
+		// the result may still have submitted rollups to summarize.
+		j.AddError(j.queueTrialSummary(result))
+		return
+	}
+
+	unavailable, err := rollupFTDCArtifacts(ctx, j.env, j.ID(), result, artifacts)
+	if unavailable != nil {
+		j.retry(result, unavailable)
+		return
+	}
+
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(err)
+	if err == nil {
+		catcher.Add(j.queueChangePointDetection(result))
+		catcher.Add(j.queueTrialSummary(result))
+	}
+	if len(artifacts.rawEvents) > 0 {
+		catcher.Add(j.queueDerivedArtifacts(result))
+	}
+
+	j.AddError(catcher.Resolve())
+}
+
+// ftdcRollupArtifacts are the FTDC artifacts of a performance result,
+// grouped by their schema.
+type ftdcRollupArtifacts struct {
+	rawEvents    []model.ArtifactInfo
+	histograms   []model.ArtifactInfo
+	customEvents []model.ArtifactInfo
+}
+
+func getFTDCRollupArtifacts(result *model.PerformanceResult) ftdcRollupArtifacts {
+	artifacts := ftdcRollupArtifacts{}
+	for _, artifact := range result.Artifacts {
+		if artifact.Format != model.FileFTDC {
+			continue
+		}
+
+		switch artifact.Schema {
+		case model.SchemaRawEvents:
+			artifacts.rawEvents = append(artifacts.rawEvents, artifact)
+		case model.SchemaHistogram:
+			artifacts.histograms = append(artifacts.histograms, artifact)
+		case model.SchemaCustomEvents:
+			artifacts.customEvents = append(artifacts.customEvents, artifact)
+		}
+	}
+
+	return artifacts
+}
+
+func (a ftdcRollupArtifacts) isEmpty() bool {
+	return len(a.rawEvents) == 0 && len(a.histograms) == 0 && len(a.customEvents) == 0
+}
+
+// rollupFTDCArtifacts calculates the default rollups of the FTDC
+// artifacts of the result, and the derived rollups configured for its
+// project, and saves them to the result, removing the rollups of older
+// versions of the calculators that were not recalculated. It does not
+// schedule any other work. A failure to open the artifacts, which may not yet be
+// available in their bucket, is returned separately, as unavailable,
+// so that the caller can retry.
+func rollupFTDCArtifacts(ctx context.Context, env cedar.Environment, jobID string, result *model.PerformanceResult, artifacts ftdcRollupArtifacts) (unavailable error, err error) {
+	conf := model.NewCedarConfig(env)
+	if err = conf.Find(); err != nil {
+		grip.Debug(message.WrapError(err, message.Fields{
+			"job":     jobID,
+			"perf_id": result.ID,
+			"message": "using default rollup configuration",
+		}))
+	}
+	opts := perf.RollupOptions{
+		MetricNames: conf.PerfRollups.MetricNameMap(),
+	}
+
+	rollups := []model.PerfRollupValue{}
+	for _, group := range []struct {
+		artifacts []model.ArtifactInfo
+		name      string
+		calculate func(*ftdc.ChunkIterator) ([]model.PerfRollupValue, error)
+	}{
+		{
+			artifacts: artifacts.rawEvents,
+			name:      "rollups",
+			calculate: func(chunks *ftdc.ChunkIterator) ([]model.PerfRollupValue, error) {
+				return perf.CalculateRollups(chunks, opts)
+			},
+		},
+		{
+			artifacts: artifacts.histograms,
+			name:      "histogram rollups",
+			calculate: perf.CalculateHistogramRollups,
+		},
+		{
+			artifacts: artifacts.customEvents,
+			name:      "custom rollups",
+			calculate: func(chunks *ftdc.ChunkIterator) ([]model.PerfRollupValue, error) {
+				return perf.CalculateCustomRollups(chunks, opts)
+			},
+		},
+	} {
+		if len(group.artifacts) == 0 {
+			continue
+		}
+
+		r, err := model.OpenArtifacts(ctx, env, group.artifacts)
+		if err != nil {
+			return err, nil
+		}
+		defer r.Close()
+
+		values, err := group.calculate(ftdc.ReadChunks(ctx, r))
+		if err != nil {
+			return nil, errors.Wrapf(err, "problem calculating %s for '%s'", group.name, result.ID)
+		}
+		rollups = append(rollups, values...)
+	}
+
+	catcher := grip.NewBasicCatcher()
+	result.Rollups.Setup(env)
+	for _, r := range rollups {
+		catcher.Add(result.Rollups.AddValue(r))
+	}
+	if derived := conf.PerfRollups.DerivedRollupsForProject(result.Info.Project); len(derived) > 0 && !catcher.HasErrors() {
+		// derived rollups may refer to any rollup of the result,
+		// including those that were submitted by the user.
+		values, err := perf.CalculateDerivedRollups(result.Rollups.MapFloat(), derived)
+		grip.Warning(message.WrapError(err, message.Fields{
+			"job":     jobID,
+			"perf_id": result.ID,
+			"project": result.Info.Project,
+			"message": "problem calculating derived rollups",
+		}))
+		for _, r := range values {
+			catcher.Add(result.Rollups.AddValue(r))
+		}
+	}
+	if !catcher.HasErrors() {
+		// the rollups that the current calculators no longer produce
+		// would otherwise remain stale, and be recalculated forever.
+		catcher.Add(result.Rollups.RemoveStale(perf.RollupCalculatorVersions()))
+	}
+	catcher.Add(result.Rollups.MarkProcessed(!catcher.HasErrors()))
+
+	return nil, catcher.Resolve()
+}
+
+// queueDerivedArtifacts schedules deriving the summarized artifacts
+// of the result from its raw events.
+func (j *ftdcRollupsJob) queueDerivedArtifacts(result *model.PerformanceResult) error {
+	q, err := j.env.GetQueue()
+	if err != nil {
+		return errors.Wrap(err, "problem getting queue")
+	}
+
+	return errors.Wrapf(PutJobOnce(q, MakeDerivedArtifactsJob(j.env, result)),
+		"problem scheduling derived artifacts for '%s'", j.PerfID)
+}
+
+// queueChangePointDetection schedules change point detection for the
+// series of the result, now that its rollups are available.
+func (j *ftdcRollupsJob) queueChangePointDetection(result *model.PerformanceResult) error {
+	q, err := j.env.GetQueue()
+	if err != nil {
+		return errors.Wrap(err, "problem getting queue")
+	}
+
+	return errors.Wrapf(PutJobOnce(q, MakeChangePointDetectionJob(j.env, result)),
+		"problem scheduling change point detection for '%s'", j.PerfID)
+}
+
+// queueTrialSummary schedules summarizing the trials of the parent of
+// the result, if it has one, now that the rollups of the result are
+// available.
+func (j *ftdcRollupsJob) queueTrialSummary(result *model.PerformanceResult) error {
+	if result.Info.Parent == "" {
+		return nil
+	}
+
+	q, err := j.env.GetQueue()
+	if err != nil {
+		return errors.Wrap(err, "problem getting queue")
+	}
+
+	return errors.Wrapf(PutJobOnce(q, MakeTrialSummaryJob(j.env, result)),
+		"problem scheduling trial summary for the parent of '%s'", j.PerfID)
+}
+
+// queueArtifactConversion schedules the conversion of the artifacts of
+// the result that are not yet in FTDC.
+func (j *ftdcRollupsJob) queueArtifactConversion(result *model.PerformanceResult) error {
+	q, err := j.env.GetQueue()
+	if err != nil {
+		return errors.Wrap(err, "problem getting queue")
+	}
+
+	return errors.Wrapf(PutJobOnce(q, MakeArtifactConversionJob(j.env, result)),
+		"problem scheduling artifact conversion for '%s'", j.PerfID)
+}
+
+// retry schedules another attempt of the job, with a delay, to handle
+// artifacts that are not yet available in their bucket.
+func (j *ftdcRollupsJob) retry(result *model.PerformanceResult, err error) {
+	if j.Attempt+1 >= ftdcRollupsMaxAttempts {
+		j.AddError(errors.Wrapf(err, "giving up after %d attempts", j.Attempt+1))
+		return
+	}
+
+	q, qerr := j.env.GetQueue()
+	if qerr != nil {
+		j.AddError(err)
+		j.AddError(errors.Wrap(qerr, "problem getting queue to retry job"))
+		return
+	}
+
+	next := makeFTDCRollupsJob(j.env, result, j.Attempt+1)
+	ti := next.TimeInfo()
+	ti.WaitUntil = time.Now().Add(ftdcRollupsRetryInterval)
+	next.UpdateTimeInfo(ti)
+
+	if qerr = q.Put(next); qerr != nil {
+		j.AddError(err)
+		j.AddError(errors.Wrap(qerr, "problem scheduling retry"))
+		return
+	}
+
+	grip.Info(message.WrapError(err, message.Fields{
+		"job":     j.ID(),
+		"perf_id": j.PerfID,
+		"attempt": j.Attempt,
+		"retry":   next.ID(),
+		"message": "artifact not yet available, retrying rollups",
+	}))
+}
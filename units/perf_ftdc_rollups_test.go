@@ -0,0 +1,164 @@
+package units
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/queue"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFTDCRollupsJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	env := cedar.GetEnvironment()
+
+	t.Run("QueuedOncePerArtifacts", func(t *testing.T) {
+		q := queue.NewLocalLimitedSize(1, 16)
+		require.NoError(t, q.Start(ctx))
+
+		result := &model.PerformanceResult{ID: "foo"}
+		require.NoError(t, PutJobOnce(q, MakeFTDCRollupsJob(env, result)))
+		require.NoError(t, PutJobOnce(q, MakeFTDCRollupsJob(env, result)))
+		assert.Equal(t, 1, q.Stats().Total)
+
+		result.Artifacts = append(result.Artifacts, model.ArtifactInfo{Path: "bar", Format: model.FileCSV})
+		require.NoError(t, PutJobOnce(q, MakeFTDCRollupsJob(env, result)))
+		require.NoError(t, PutJobOnce(q, MakeFTDCRollupsJob(env, result)))
+		assert.Equal(t, 2, q.Stats().Total)
+
+		result.Artifacts[0].ConversionError = "invalid"
+		require.NoError(t, PutJobOnce(q, MakeFTDCRollupsJob(env, result)))
+		assert.Equal(t, 3, q.Stats().Total)
+
+		require.NoError(t, PutJobOnce(q, MakeFTDCRollupsJob(env, &model.PerformanceResult{ID: "bar"})))
+		assert.Equal(t, 4, q.Stats().Total)
+	})
+	t.Run("RetriesAreQueued", func(t *testing.T) {
+		q := queue.NewLocalLimitedSize(1, 16)
+		require.NoError(t, q.Start(ctx))
+
+		result := &model.PerformanceResult{ID: "foo"}
+		require.NoError(t, PutJobOnce(q, MakeFTDCRollupsJob(env, result)))
+		require.NoError(t, PutJobOnce(q, makeFTDCRollupsJob(env, result, 1)))
+		require.NoError(t, PutJobOnce(q, makeFTDCRollupsJob(env, result, 1)))
+		assert.Equal(t, 2, q.Stats().Total)
+	})
+	t.Run("CalculatesRollups", func(t *testing.T) {
+		env, cleanup := setupUnitsTestDB(t)
+		defer cleanup()
+
+		tempDir, err := ioutil.TempDir("", "cedar-ftdc-rollups")
+		require.NoError(t, err)
+		defer os.RemoveAll(tempDir)
+
+		artifact := model.ArtifactInfo{
+			Type:   model.PailLocal,
+			Bucket: tempDir,
+			Path:   "events.ftdc",
+			Format: model.FileFTDC,
+			Schema: model.SchemaRawEvents,
+		}
+		writeUnitsTestEvents(ctx, t, env, artifact)
+		result := model.CreatePerformanceResult(model.PerformanceResultInfo{
+			Project:  "project",
+			TestName: "test",
+			Parent:   "parent",
+		}, []model.ArtifactInfo{artifact})
+		result.Setup(env)
+		require.NoError(t, result.Save())
+
+		j := MakeFTDCRollupsJob(env, result)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		require.NoError(t, result.Find())
+		require.NotNil(t, result.Rollups)
+		assert.NotEmpty(t, result.Rollups.Stats)
+		assert.True(t, result.Rollups.Valid)
+		assert.Equal(t, len(result.Rollups.Stats), result.Rollups.Count)
+		assert.False(t, result.Rollups.ProcessedAt.IsZero())
+		for _, rollup := range result.Rollups.Stats {
+			assert.Equal(t, perf.FTDCRollupCalculator, rollup.Calculator, rollup.Name)
+		}
+
+		q, err := env.GetQueue()
+		require.NoError(t, err)
+		for _, followUp := range []amboy.Job{
+			MakeChangePointDetectionJob(env, result),
+			MakeTrialSummaryJob(env, result),
+			MakeDerivedArtifactsJob(env, result),
+		} {
+			_, ok := q.Get(followUp.ID())
+			assert.True(t, ok, followUp.ID())
+		}
+		assert.Equal(t, 3, q.Stats().Total)
+	})
+	t.Run("QueuesConversion", func(t *testing.T) {
+		env, cleanup := setupUnitsTestDB(t)
+		defer cleanup()
+
+		result := model.CreatePerformanceResult(model.PerformanceResultInfo{Project: "project", TestName: "test"}, []model.ArtifactInfo{
+			{Type: model.PailLocal, Path: "events.json", Format: model.FileJSON, Schema: model.SchemaRawEvents},
+		})
+		result.Setup(env)
+		require.NoError(t, result.Save())
+
+		j := MakeFTDCRollupsJob(env, result)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		require.NoError(t, result.Find())
+		assert.True(t, result.Rollups.ProcessedAt.IsZero())
+		q, err := env.GetQueue()
+		require.NoError(t, err)
+		_, ok := q.Get(MakeArtifactConversionJob(env, result).ID())
+		assert.True(t, ok)
+		assert.Equal(t, 1, q.Stats().Total)
+	})
+	t.Run("RetriesMissingArtifacts", func(t *testing.T) {
+		env, cleanup := setupUnitsTestDB(t)
+		defer cleanup()
+
+		tempDir, err := ioutil.TempDir("", "cedar-ftdc-rollups")
+		require.NoError(t, err)
+		defer os.RemoveAll(tempDir)
+
+		result := model.CreatePerformanceResult(model.PerformanceResultInfo{Project: "project", TestName: "test"}, []model.ArtifactInfo{
+			{Type: model.PailLocal, Bucket: tempDir, Path: "DNE.ftdc", Format: model.FileFTDC, Schema: model.SchemaRawEvents},
+		})
+		result.Setup(env)
+		require.NoError(t, result.Save())
+
+		j := MakeFTDCRollupsJob(env, result)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		q, err := env.GetQueue()
+		require.NoError(t, err)
+		retry, ok := q.Get(makeFTDCRollupsJob(env, result, 1).ID())
+		require.True(t, ok)
+		assert.True(t, retry.TimeInfo().WaitUntil.After(time.Now()))
+		assert.Equal(t, 1, q.Stats().Total)
+
+		// the last attempt gives up rather than retrying.
+		j = makeFTDCRollupsJob(env, result, ftdcRollupsMaxAttempts-1)
+		j.Run(ctx)
+		assert.Error(t, j.Error())
+		_, ok = q.Get(makeFTDCRollupsJob(env, result, ftdcRollupsMaxAttempts).ID())
+		assert.False(t, ok)
+
+		require.NoError(t, result.Find())
+		assert.Empty(t, result.Rollups.Stats)
+		assert.True(t, result.Rollups.ProcessedAt.IsZero())
+	})
+}
@@ -0,0 +1,163 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	noiseScoringJobName = "perf-noise-scoring"
+
+	// NoiseScoringInterval is how often the noise scoring job should
+	// be scheduled.
+	NoiseScoringInterval = time.Hour
+
+	defaultNoiseScoringVersions   = 20
+	defaultNoiseScoringWindowDays = 7
+)
+
+func init() {
+	registry.AddJobType(noiseScoringJobName, func() amboy.Job {
+		return noiseScoringJobFactory()
+	})
+}
+
+type noiseScoringJob struct {
+	*job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	env       cedar.Environment
+}
+
+func noiseScoringJobFactory() amboy.Job {
+	j := &noiseScoringJob{
+		Base: &job.Base{
+			JobType: amboy.JobType{
+				Name:    noiseScoringJobName,
+				Version: 1,
+			},
+		},
+		env: cedar.GetEnvironment(),
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// MakeNoiseScoringJob returns a job that scores the noise of every
+// rollup of the series with recent results, over their recent
+// versions, and replaces the noise scores of each series. The job id
+// is derived from the scheduled time, so that each interval is
+// scheduled at most once.
+func MakeNoiseScoringJob(env cedar.Environment, scheduled time.Time) amboy.Job {
+	j := noiseScoringJobFactory().(*noiseScoringJob)
+	j.SetID(fmt.Sprintf("%s-%s", j.Type().Name, scheduled.UTC().Format("2006-01-02::15.04.05")))
+	j.env = env
+	return j
+}
+
+func (j *noiseScoringJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	opts := model.PerfNoiseConfig{
+		Threshold:  perf.DefaultNoiseThreshold,
+		Versions:   defaultNoiseScoringVersions,
+		WindowDays: defaultNoiseScoringWindowDays,
+	}
+	conf := model.NewCedarConfig(j.env)
+	if err := conf.Find(); err == nil {
+		if conf.Flags.DisableNoiseScoringJob {
+			return
+		}
+		if conf.PerfNoise.Threshold > 0 {
+			opts.Threshold = conf.PerfNoise.Threshold
+		}
+		if conf.PerfNoise.Versions > 0 {
+			opts.Versions = conf.PerfNoise.Versions
+		}
+		if conf.PerfNoise.WindowDays > 0 {
+			opts.WindowDays = conf.PerfNoise.WindowDays
+		}
+	}
+
+	keys, err := model.FindRecentPerfSeries(j.env, time.Now().AddDate(0, 0, -opts.WindowDays))
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	scores := &model.PerfNoiseScores{}
+	scores.Setup(j.env)
+	catcher := grip.NewBasicCatcher()
+	noisy := 0
+	for _, key := range keys {
+		if err = ctx.Err(); err != nil {
+			catcher.Add(err)
+			break
+		}
+
+		trials, err := model.FindPerfSeriesTrials(j.env, key, opts.Versions)
+		if err != nil {
+			catcher.Add(errors.Wrapf(err, "problem finding trials of series '%s'", key.ID()))
+			continue
+		}
+
+		found := scorePerfNoise(key, trials, opts.Threshold, time.Now())
+		for _, score := range found {
+			if score.Noisy {
+				noisy++
+			}
+		}
+		catcher.Add(scores.ReplaceSeries(key, found))
+	}
+
+	grip.Info(message.Fields{
+		"job":       j.ID(),
+		"series":    len(keys),
+		"noisy":     noisy,
+		"threshold": opts.Threshold,
+		"versions":  opts.Versions,
+		"message":   "scored noise of performance series",
+	})
+
+	j.AddError(catcher.Resolve())
+}
+
+// scorePerfNoise scores the noise of each rollup of the series from
+// its trials, sorted by rollup name.
+func scorePerfNoise(key model.PerfSeriesKey, trials map[string][][]float64, threshold float64, calculatedAt time.Time) []model.PerfNoise {
+	names := make([]string, 0, len(trials))
+	for name := range trials {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]model.PerfNoise, 0, len(names))
+	for _, name := range names {
+		score := perf.ScoreNoise(trials[name])
+
+		noise := model.CreatePerfNoise(key, name)
+		noise.Versions = score.Versions
+		noise.Trials = score.Trials
+		noise.TrialCV = score.TrialCV
+		noise.HistoryCV = score.HistoryCV
+		noise.Score = score.Score
+		noise.Threshold = threshold
+		noise.Noisy = score.Score > threshold
+		noise.CalculatedAt = calculatedAt
+		out = append(out, *noise)
+	}
+
+	return out
+}
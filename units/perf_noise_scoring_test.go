@@ -0,0 +1,48 @@
+package units
+
+import (
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNoiseScoringJob(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		scheduled := time.Now().Truncate(NoiseScoringInterval)
+
+		j, ok := MakeNoiseScoringJob(cedar.GetEnvironment(), scheduled).(*noiseScoringJob)
+		require.True(t, ok)
+		assert.Equal(t, noiseScoringJobName, j.Type().Name)
+		assert.Equal(t, j.ID(), MakeNoiseScoringJob(cedar.GetEnvironment(), scheduled).ID())
+		assert.NotEqual(t, j.ID(), MakeNoiseScoringJob(cedar.GetEnvironment(), scheduled.Add(NoiseScoringInterval)).ID())
+	})
+	t.Run("Score", func(t *testing.T) {
+		key := model.PerfSeriesKey{Project: "project", TaskName: "task", TestName: "test"}
+		now := time.Now()
+
+		scores := scorePerfNoise(key, map[string][][]float64{
+			"ops":     {{100, 101}, {100, 99}, {101}},
+			"latency": {{10, 20}, {5, 15}},
+		}, 0.1, now)
+		require.Len(t, scores, 2)
+
+		latency := scores[0]
+		assert.Equal(t, "latency", latency.Measurement)
+		assert.Equal(t, key.ID(), latency.SeriesID)
+		assert.Equal(t, 2, latency.Versions)
+		assert.Equal(t, 4, latency.Trials)
+		assert.Equal(t, 0.1, latency.Threshold)
+		assert.True(t, latency.Noisy)
+		assert.Equal(t, now, latency.CalculatedAt)
+
+		ops := scores[1]
+		assert.Equal(t, "ops", ops.Measurement)
+		assert.Equal(t, 3, ops.Versions)
+		assert.False(t, ops.Noisy)
+		assert.NotEqual(t, latency.ID, ops.ID)
+	})
+}
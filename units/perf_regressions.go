@@ -0,0 +1,366 @@
+package units
+
+import (
+	"context"
+	"crypto/sha1"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/level"
+	"github.com/mongodb/grip/message"
+	"github.com/mongodb/grip/send"
+	"github.com/pkg/errors"
+)
+
+const (
+	regressionDetectionJobName = "perf-regression-detection"
+
+	// regressionEventComponent is the component of the events that
+	// record regressions.
+	regressionEventComponent = "perf-regressions"
+)
+
+func init() {
+	registry.AddJobType(regressionDetectionJobName, func() amboy.Job {
+		return regressionDetectionJobFactory()
+	})
+}
+
+type regressionDetectionJob struct {
+	Series    model.PerfSeriesKey `bson:"series" json:"series" yaml:"series"`
+	*job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	env       cedar.Environment
+	sender    send.Sender
+}
+
+func regressionDetectionJobFactory() amboy.Job {
+	j := &regressionDetectionJob{
+		Base: &job.Base{
+			JobType: amboy.JobType{
+				Name:    regressionDetectionJobName,
+				Version: 1,
+			},
+		},
+		env: cedar.GetEnvironment(),
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// MakeRegressionDetectionJob returns a job that checks the latest
+// version of the series, and the versions with change points since
+// the baseline, against the regression rules of its project, with the
+// thresholds of the rules raised by the noise of the series, and
+// reports each regression as an event and a Slack message. Each
+// regression is reported at most once. The job is identified by the
+// id of the change point detection job that schedules it, so that the
+// series is checked once for each detection.
+func MakeRegressionDetectionJob(env cedar.Environment, series model.PerfSeriesKey, detectionID string) amboy.Job {
+	j := regressionDetectionJobFactory().(*regressionDetectionJob)
+	j.SetID(fmt.Sprintf("%s-%s", j.Type().Name, detectionID))
+	j.Series = series
+	j.env = env
+	return j
+}
+
+func (j *regressionDetectionJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.sender == nil {
+		j.sender = grip.GetSender()
+	}
+
+	conf := model.NewCedarConfig(j.env)
+	if err := conf.Find(); err != nil {
+		j.AddError(errors.Wrap(err, "problem finding regression rules"))
+		return
+	}
+	if conf.Flags.DisableRegressionDetectionJob {
+		return
+	}
+	rules := conf.PerfRegressions.RulesForProject(j.Series.Project)
+	if len(rules) == 0 {
+		return
+	}
+
+	series := &model.PerfSeries{Key: j.Series}
+	series.Setup(j.env)
+	if err := series.Find(); err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding series '%s'", j.Series.ID()))
+		return
+	}
+
+	baseline := model.CreatePerfBaseline(j.Series.BaselineKey())
+	baseline.Setup(j.env)
+	if err := baseline.Find(); err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding baseline of series '%s'", j.Series.ID()))
+		return
+	}
+
+	changePoints := &model.PerfChangePoints{}
+	changePoints.Setup(j.env)
+	err := changePoints.Find(model.PerfChangePointFindOptions{
+		Project:  j.Series.Project,
+		Variant:  j.Series.Variant,
+		TaskName: j.Series.TaskName,
+		TestName: j.Series.TestName,
+	})
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding change points of series '%s'", j.Series.ID()))
+		return
+	}
+	seriesID := j.Series.ID()
+	detected := map[string]map[string]*model.PerfChangePoint{}
+	for idx := range changePoints.ChangePoints {
+		cp := &changePoints.ChangePoints[idx]
+		if cp.SeriesID != seriesID {
+			continue
+		}
+		if _, ok := detected[cp.Measurement]; !ok {
+			detected[cp.Measurement] = map[string]*model.PerfChangePoint{}
+		}
+		detected[cp.Measurement][cp.PerfResultID] = cp
+	}
+
+	noise := &model.PerfNoiseScores{}
+	noise.Setup(j.env)
+	if err = noise.Find(model.PerfNoiseFindOptions{SeriesID: seriesID}); err != nil {
+		grip.Warning(message.WrapError(err, message.Fields{
+			"job":     j.ID(),
+			"series":  seriesID,
+			"message": "checking regressions without noise scores",
+		}))
+	}
+	scores := noise.Map()
+
+	catcher := grip.NewBasicCatcher()
+	for _, rule := range rules {
+		if err := ctx.Err(); err != nil {
+			catcher.Add(err)
+			break
+		}
+
+		selector, perfID := rule.Baseline, ""
+		if selector == "" && !baseline.IsNil() {
+			selector, perfID = baseline.Version, baseline.PerfResultID
+		}
+
+		// as with change points, changes within the noise of the
+		// measurement are not regressions.
+		var noiseMagnitude float64
+		if score, ok := scores[rule.Metric]; ok {
+			noiseMagnitude = noiseMagnitudeFactor * score.Score
+		}
+		for _, c := range regressionCandidates(series.Measurements[rule.Metric], selector, perfID, detected[rule.Metric]) {
+			regression, ok := perf.CheckRegression(rule, perf.RegressionCheck{
+				MetricType:  c.point.MetricType,
+				Baseline:    c.baseline.Value,
+				Value:       c.point.Value,
+				ChangePoint: c.changePoint,
+				Noise:       noiseMagnitude,
+			})
+			if !ok {
+				continue
+			}
+
+			catcher.Add(j.report(conf, rule, c.baseline, c.point, regression))
+		}
+	}
+
+	j.AddError(catcher.Resolve())
+}
+
+// report records the regression of the point as an event and sends it
+// to the Slack channel of the rule, unless the regression was already
+// reported.
+func (j *regressionDetectionJob) report(conf *model.CedarConfig, rule model.PerfRegressionRule, baseline, point model.PerfSeriesPoint, r perf.Regression) error {
+	hash := sha1.New()
+	_, _ = io.WriteString(hash, j.Series.ID())
+	_, _ = io.WriteString(hash, rule.Metric)
+	_, _ = io.WriteString(hash, baseline.PerfResultID)
+	_, _ = io.WriteString(hash, point.PerfResultID)
+	id := fmt.Sprintf("%x", hash.Sum(nil))
+
+	existing := &model.Event{ID: id}
+	existing.Setup(j.env)
+	if err := existing.Find(); err == nil {
+		return nil
+	}
+
+	link := regressionLink(conf.PerfRegressions.URL, baseline.Version, point.Version)
+	fields := message.Fields{
+		"message":          "performance regression",
+		"project":          j.Series.Project,
+		"variant":          j.Series.Variant,
+		"task":             j.Series.TaskName,
+		"test":             j.Series.TestName,
+		"args":             j.Series.Arguments,
+		"metric":           rule.Metric,
+		"version":          point.Version,
+		"perf_id":          point.PerfResultID,
+		"value":            r.Value,
+		"baseline_version": baseline.Version,
+		"baseline_perf_id": baseline.PerfResultID,
+		"baseline":         r.Baseline,
+		"confidence":       r.Confidence,
+	}
+	if !math.IsNaN(r.PercentChange) {
+		fields["percent_change"] = r.PercentChange
+	}
+	if r.ThresholdPercent > 0 {
+		fields["threshold_percent"] = r.ThresholdPercent
+	}
+	if link != "" {
+		fields["link"] = link
+	}
+
+	event := model.NewEvent(message.NewFields(level.Alert, fields))
+	event.ID = id
+	event.Component = regressionEventComponent
+	event.Setup(j.env)
+	if err := event.Save(); err != nil {
+		return errors.Wrapf(err, "problem saving regression of '%s' in series '%s'", rule.Metric, j.Series.ID())
+	}
+
+	channel := rule.SlackChannel
+	if channel == "" && conf.Slack.Options != nil {
+		channel = conf.Slack.Options.Channel
+	}
+	if channel != "" {
+		j.sender.Send(message.NewSlackMessage(level.Alert, channel,
+			regressionSummary(j.Series, rule.Metric, baseline, point, r),
+			[]message.SlackAttachment{regressionAttachment(j.Series, rule.Metric, link, r)}))
+	}
+
+	return nil
+}
+
+// regressionChangePointWindow is the number of the latest points of a
+// series whose change points are checked when each point is compared
+// to the previous one. Change points are only detected once enough
+// points follow them, so they never fall on the latest point.
+const regressionChangePointWindow = 10
+
+// regressionCandidate is a point of a series that is checked for a
+// regression against its baseline.
+type regressionCandidate struct {
+	baseline    model.PerfSeriesPoint
+	point       model.PerfSeriesPoint
+	changePoint *model.PerfChangePoint
+}
+
+// regressionCandidates returns the points of the series to check for
+// a regression, given the change points of the series keyed by
+// result id. The latest point is always checked, along with every
+// point with a change point after the baseline. For an empty or
+// PerfBaselinePrevious selector, each point is compared to the point
+// before it, and only the change points of the latest
+// regressionChangePointWindow points are checked.
+func regressionCandidates(points []model.PerfSeriesPoint, selector, perfID string, changePoints map[string]*model.PerfChangePoint) []regressionCandidate {
+	candidates := []regressionCandidate{}
+	latest := len(points) - 1
+
+	if selector == "" || selector == model.PerfBaselinePrevious {
+		start := len(points) - regressionChangePointWindow
+		if start < 1 {
+			start = 1
+		}
+		for idx := start; idx <= latest; idx++ {
+			cp := changePoints[points[idx].PerfResultID]
+			if idx == latest || cp != nil {
+				candidates = append(candidates, regressionCandidate{baseline: points[idx-1], point: points[idx], changePoint: cp})
+			}
+		}
+
+		return candidates
+	}
+
+	base, ok := regressionBaseline(points, selector, perfID)
+	if !ok {
+		return candidates
+	}
+	for idx := base + 1; idx <= latest; idx++ {
+		cp := changePoints[points[idx].PerfResultID]
+		if idx == latest || cp != nil {
+			candidates = append(candidates, regressionCandidate{baseline: points[base], point: points[idx], changePoint: cp})
+		}
+	}
+
+	return candidates
+}
+
+// regressionBaseline returns the index of the point of the version in
+// the series. When the baseline is a specific result, the point of
+// the result is preferred over the point of its version, which is
+// used for the series of the other tests of the task.
+func regressionBaseline(points []model.PerfSeriesPoint, version, perfID string) (int, bool) {
+	if perfID != "" {
+		for idx, point := range points {
+			if point.PerfResultID == perfID {
+				return idx, true
+			}
+		}
+	}
+	for idx := len(points) - 1; idx >= 0; idx-- {
+		if points[idx].Version == version {
+			return idx, true
+		}
+	}
+
+	return -1, false
+}
+
+// regressionLink returns the link to the comparison of the versions
+// in the REST API, or an empty string if the URL of the API is not
+// configured.
+func regressionLink(url, baseline, version string) string {
+	if url == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/v1/perf/compare/version/%s/%s", strings.TrimSuffix(url, "/"), baseline, version)
+}
+
+func regressionSummary(series model.PerfSeriesKey, metric string, baseline, point model.PerfSeriesPoint, r perf.Regression) string {
+	change := "n/a"
+	if !math.IsNaN(r.PercentChange) {
+		change = fmt.Sprintf("%+.2f%%", r.PercentChange)
+	}
+
+	return fmt.Sprintf("performance regression of '%s' in %s/%s/%s/%s: %g at version '%s' compared to %g at version '%s' (%s)",
+		metric, series.Project, series.Variant, series.TaskName, series.TestName,
+		r.Value, point.Version, r.Baseline, baseline.Version, change)
+}
+
+func regressionAttachment(series model.PerfSeriesKey, metric, link string, r perf.Regression) message.SlackAttachment {
+	fields := []*message.SlackAttachmentField{
+		{Title: "Project", Value: series.Project, Short: true},
+		{Title: "Variant", Value: series.Variant, Short: true},
+		{Title: "Task", Value: series.TaskName, Short: true},
+		{Title: "Test", Value: series.TestName, Short: true},
+		{Title: "Baseline", Value: fmt.Sprint(r.Baseline), Short: true},
+		{Title: "Value", Value: fmt.Sprint(r.Value), Short: true},
+	}
+	if r.Confidence > 0 {
+		fields = append(fields, &message.SlackAttachmentField{Title: "Confidence", Value: fmt.Sprintf("%.2f", r.Confidence), Short: true})
+	}
+
+	return message.SlackAttachment{
+		Color:     "danger",
+		Fallback:  fmt.Sprintf("performance regression of '%s'", metric),
+		Title:     metric,
+		TitleLink: link,
+		Fields:    fields,
+	}
+}
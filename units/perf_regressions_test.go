@@ -0,0 +1,240 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/mongodb/amboy/queue"
+	"github.com/mongodb/grip/send"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegressionDetectionJob(t *testing.T) {
+	series := model.PerfSeriesKey{Project: "project", TaskName: "task", TestName: "test"}
+
+	t.Run("QueuedOncePerDetection", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		q := queue.NewLocalLimitedSize(1, 16)
+		require.NoError(t, q.Start(ctx))
+
+		env := cedar.GetEnvironment()
+		j, ok := MakeRegressionDetectionJob(env, series, "detection-1").(*regressionDetectionJob)
+		require.True(t, ok)
+		assert.Equal(t, series, j.Series)
+		require.NoError(t, PutJobOnce(q, j))
+		require.NoError(t, PutJobOnce(q, MakeRegressionDetectionJob(env, series, "detection-1")))
+		assert.Equal(t, 1, q.Stats().Total)
+
+		require.NoError(t, PutJobOnce(q, MakeRegressionDetectionJob(env, series, "detection-2")))
+		assert.Equal(t, 2, q.Stats().Total)
+	})
+	t.Run("ChangePointConfidence", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		env, cleanup := setupUnitsTestDB(t)
+		defer cleanup()
+
+		saveUnitsTestConfig(t, env, `
+perf_regressions:
+  rules:
+    - project: project
+      metric: latency
+      confidence: 0.9
+      slack_channel: "#perf"
+`)
+
+		ids := []string{}
+		createdAt := time.Now().Add(-time.Hour)
+		for idx, value := range []float64{100, 100, 100, 101, 101, 101} {
+			result := model.CreatePerformanceResult(model.PerformanceResultInfo{
+				Project:  "project",
+				Version:  fmt.Sprintf("v%d", idx),
+				TaskName: "task",
+				TaskID:   fmt.Sprintf("task%d", idx),
+				TestName: "test",
+			}, nil)
+			result.CreatedAt = createdAt.Add(time.Duration(idx) * time.Minute)
+			result.Rollups.Stats = []model.PerfRollupValue{
+				{Name: "latency", Value: value, MetricType: model.MetricTypeLatency},
+			}
+			result.Setup(env)
+			require.NoError(t, result.Save())
+			ids = append(ids, result.ID)
+		}
+
+		// the change point lies before the latest version, and the
+		// change is too small for a threshold.
+		cp := model.CreatePerfChangePoint(series, "latency", ids[3])
+		cp.Version = "v3"
+		cp.Before = 100
+		cp.After = 101
+		cp.Confidence = 0.99
+		changePoints := &model.PerfChangePoints{}
+		changePoints.Setup(env)
+		require.NoError(t, changePoints.ReplaceSeries(series, "latency", []model.PerfChangePoint{*cp}))
+
+		sender := send.MakeInternalLogger()
+		j := MakeRegressionDetectionJob(env, series, "detection-1").(*regressionDetectionJob)
+		j.sender = sender
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		require.True(t, sender.HasMessage())
+		msg := sender.GetMessage()
+		assert.Contains(t, msg.Rendered, "'v3'")
+		assert.Contains(t, msg.Rendered, "'v2'")
+		assert.False(t, sender.HasMessage())
+
+		// the regression is only reported once.
+		j = MakeRegressionDetectionJob(env, series, "detection-2").(*regressionDetectionJob)
+		j.sender = sender
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+		assert.False(t, sender.HasMessage())
+	})
+	t.Run("NoiseRaisesThreshold", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		env, cleanup := setupUnitsTestDB(t)
+		defer cleanup()
+
+		saveUnitsTestConfig(t, env, `
+perf_regressions:
+  rules:
+    - project: project
+      metric: latency
+      threshold_percent: 10
+      slack_channel: "#perf"
+`)
+
+		createdAt := time.Now().Add(-time.Hour)
+		for idx, value := range []float64{100, 100, 115} {
+			result := model.CreatePerformanceResult(model.PerformanceResultInfo{
+				Project:  "project",
+				Version:  fmt.Sprintf("v%d", idx),
+				TaskName: "task",
+				TaskID:   fmt.Sprintf("task%d", idx),
+				TestName: "test",
+			}, nil)
+			result.CreatedAt = createdAt.Add(time.Duration(idx) * time.Minute)
+			result.Rollups.Stats = []model.PerfRollupValue{
+				{Name: "latency", Value: value, MetricType: model.MetricTypeLatency},
+			}
+			result.Setup(env)
+			require.NoError(t, result.Save())
+		}
+
+		// the increase of 15% is within the noise of the latency.
+		score := model.CreatePerfNoise(series, "latency")
+		score.Score = 0.05
+		noise := &model.PerfNoiseScores{}
+		noise.Setup(env)
+		require.NoError(t, noise.ReplaceSeries(series, []model.PerfNoise{*score}))
+
+		sender := send.MakeInternalLogger()
+		j := MakeRegressionDetectionJob(env, series, "detection-1").(*regressionDetectionJob)
+		j.sender = sender
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+		assert.False(t, sender.HasMessage())
+
+		// without noise, the increase exceeds the threshold.
+		require.NoError(t, noise.ReplaceSeries(series, nil))
+		j = MakeRegressionDetectionJob(env, series, "detection-2").(*regressionDetectionJob)
+		j.sender = sender
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+		require.True(t, sender.HasMessage())
+		assert.Contains(t, sender.GetMessage().Rendered, "'v2'")
+	})
+}
+
+func TestRegressionCandidates(t *testing.T) {
+	points := []model.PerfSeriesPoint{
+		{PerfResultID: "a", Version: "v1", Value: 1},
+		{PerfResultID: "b", Version: "v2", Value: 2},
+		{PerfResultID: "c", Version: "v3", Value: 3},
+		{PerfResultID: "d", Version: "v4", Value: 4},
+	}
+	changePoints := map[string]*model.PerfChangePoint{
+		"b": {PerfResultID: "b", Confidence: 0.9},
+	}
+
+	for _, selector := range []string{"", model.PerfBaselinePrevious} {
+		candidates := regressionCandidates(points, selector, "", changePoints)
+		require.Len(t, candidates, 2)
+		assert.Equal(t, points[0], candidates[0].baseline)
+		assert.Equal(t, points[1], candidates[0].point)
+		assert.Equal(t, changePoints["b"], candidates[0].changePoint)
+		assert.Equal(t, points[2], candidates[1].baseline)
+		assert.Equal(t, points[3], candidates[1].point)
+		assert.Nil(t, candidates[1].changePoint)
+	}
+
+	candidates := regressionCandidates(points, "v1", "", changePoints)
+	require.Len(t, candidates, 2)
+	for _, c := range candidates {
+		assert.Equal(t, points[0], c.baseline)
+	}
+	assert.Equal(t, points[1], candidates[0].point)
+	assert.Equal(t, points[3], candidates[1].point)
+
+	candidates = regressionCandidates(points, "v2", "", changePoints)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, points[1], candidates[0].baseline)
+	assert.Equal(t, points[3], candidates[0].point)
+
+	assert.Empty(t, regressionCandidates(points, "v4", "", changePoints))
+	assert.Empty(t, regressionCandidates(points, "v5", "", changePoints))
+	assert.Empty(t, regressionCandidates(points[:1], model.PerfBaselinePrevious, "", changePoints))
+	assert.Empty(t, regressionCandidates(nil, "", "", changePoints))
+
+	many := []model.PerfSeriesPoint{}
+	for idx := 0; idx < 2*regressionChangePointWindow; idx++ {
+		many = append(many, model.PerfSeriesPoint{PerfResultID: fmt.Sprint(idx), Version: fmt.Sprintf("v%d", idx)})
+	}
+	outside := len(many) - regressionChangePointWindow - 1
+	inside := len(many) - 3
+	candidates = regressionCandidates(many, "", "", map[string]*model.PerfChangePoint{
+		many[outside].PerfResultID: {PerfResultID: many[outside].PerfResultID},
+		many[inside].PerfResultID:  {PerfResultID: many[inside].PerfResultID},
+	})
+	require.Len(t, candidates, 2)
+	assert.Equal(t, many[inside], candidates[0].point)
+	assert.Equal(t, many[inside-1], candidates[0].baseline)
+	assert.Equal(t, many[len(many)-1], candidates[1].point)
+}
+
+func TestRegressionBaseline(t *testing.T) {
+	points := []model.PerfSeriesPoint{
+		{PerfResultID: "a", Version: "v1", Value: 1},
+		{PerfResultID: "b", Version: "v2", Value: 2},
+		{PerfResultID: "c", Version: "v3", Value: 3},
+	}
+
+	idx, ok := regressionBaseline(points, "v1", "")
+	assert.True(t, ok)
+	assert.Equal(t, 0, idx)
+
+	idx, ok = regressionBaseline(points, "v1", "b")
+	assert.True(t, ok)
+	assert.Equal(t, 1, idx)
+
+	idx, ok = regressionBaseline(points, "v1", "other")
+	assert.True(t, ok)
+	assert.Equal(t, 0, idx)
+
+	_, ok = regressionBaseline(points, "v4", "")
+	assert.False(t, ok)
+}
+
+func TestRegressionLink(t *testing.T) {
+	assert.Empty(t, regressionLink("", "v1", "v2"))
+	assert.Equal(t, "https://cedar.example.com/rest/v1/perf/compare/version/v1/v2", regressionLink("https://cedar.example.com/rest/", "v1", "v2"))
+}
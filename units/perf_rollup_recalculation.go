@@ -0,0 +1,222 @@
+package units
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const (
+	rollupRecalculationJobName = "perf-rollup-recalculation"
+
+	// RollupRecalculationInterval is how often the recalculation job
+	// should be scheduled.
+	RollupRecalculationInterval = 10 * time.Minute
+
+	defaultRollupRecalculationBatchSize = 100
+)
+
+func init() {
+	registry.AddJobType(rollupRecalculationJobName, func() amboy.Job {
+		return rollupRecalculationJobFactory()
+	})
+}
+
+type rollupRecalculationJob struct {
+	*job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	env       cedar.Environment
+}
+
+func rollupRecalculationJobFactory() amboy.Job {
+	j := &rollupRecalculationJob{
+		Base: &job.Base{
+			JobType: amboy.JobType{
+				Name:    rollupRecalculationJobName,
+				Version: 1,
+			},
+		},
+		env: cedar.GetEnvironment(),
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// MakeRollupRecalculationJob returns a job that recalculates the
+// rollups of a batch of performance results whose rollups were
+// calculated by older versions of the registered rollup calculators,
+// and records its progress in the recalculation status. The job id is
+// derived from the scheduled time, so that each interval is scheduled
+// at most once.
+func MakeRollupRecalculationJob(env cedar.Environment, scheduled time.Time) amboy.Job {
+	j := rollupRecalculationJobFactory().(*rollupRecalculationJob)
+	j.SetID(fmt.Sprintf("%s-%s", j.Type().Name, scheduled.UTC().Format("2006-01-02::15.04.05")))
+	j.env = env
+	return j
+}
+
+func (j *rollupRecalculationJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	batchSize := defaultRollupRecalculationBatchSize
+	conf := model.NewCedarConfig(j.env)
+	if err := conf.Find(); err == nil {
+		if conf.Flags.DisableRollupRecalculationJob {
+			return
+		}
+		if conf.PerfRollups.RecalculationBatchSize > 0 {
+			batchSize = conf.PerfRollups.RecalculationBatchSize
+		}
+	}
+
+	status := &model.PerfRollupRecalculationStatus{}
+	status.Setup(j.env)
+	if err := status.Find(); err != nil {
+		j.AddError(err)
+		return
+	}
+
+	versions := perf.RollupCalculatorVersions()
+	if !status.Matches(versions) {
+		status.Reset(versions)
+	}
+
+	ids, err := model.FindPerfResultsWithStaleRollups(j.env, versions, status.LastID, batchSize)
+	if err == nil && len(ids) == 0 && status.LastID != "" {
+		// start over, to retry the results that failed
+		status.LastID = ""
+		ids, err = model.FindPerfResultsWithStaleRollups(j.env, versions, status.LastID, batchSize)
+	}
+	if err != nil {
+		j.AddError(err)
+		return
+	}
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			break
+		}
+
+		recalculated, err := j.recalculate(ctx, id, versions)
+		switch {
+		case err != nil:
+			status.Failed++
+			grip.Warning(message.WrapError(err, message.Fields{
+				"job":     j.ID(),
+				"perf_id": id,
+				"message": "problem recalculating rollups",
+			}))
+		case !recalculated:
+			status.Skipped++
+		default:
+			status.Processed++
+		}
+		status.LastID = id
+	}
+
+	status.Remaining, err = model.CountPerfResultsWithStaleRollups(j.env, versions)
+	j.AddError(err)
+	status.UpdatedAt = time.Now()
+	j.AddError(status.Save())
+
+	grip.Info(message.Fields{
+		"job":         j.ID(),
+		"calculators": versions,
+		"batch":       len(ids),
+		"processed":   status.Processed,
+		"failed":      status.Failed,
+		"skipped":     status.Skipped,
+		"remaining":   status.Remaining,
+		"message":     "recalculated stale rollups",
+	})
+}
+
+// recalculate replaces the rollups of the result with the id. Only the
+// work that depends on the values of the rollups, change point
+// detection and the summary of the trials, follows a recalculation,
+// and only if the values changed. Results whose artifacts are not all
+// converted to FTDC yet are left for the rollups job that follows their
+// conversion, and are not recalculated.
+func (j *rollupRecalculationJob) recalculate(ctx context.Context, id string, versions map[string]int) (bool, error) {
+	result := &model.PerformanceResult{ID: id}
+	result.Setup(j.env)
+	if err := result.Find(); err != nil {
+		return false, errors.Wrapf(err, "problem finding performance result '%s'", id)
+	}
+
+	artifacts := getFTDCRollupArtifacts(result)
+	if len(result.UnconvertedArtifacts()) > 0 || artifacts.isEmpty() {
+		return false, nil
+	}
+
+	previous := result.Rollups.MapFloat()
+	unavailable, err := rollupFTDCArtifacts(ctx, j.env, j.ID(), result, artifacts)
+	if unavailable != nil {
+		return false, errors.Wrapf(unavailable, "problem opening artifacts of '%s'", id)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if reflect.DeepEqual(previous, result.Rollups.MapFloat()) {
+		return true, nil
+	}
+
+	return true, j.queueFollowUps(result, versions)
+}
+
+// queueFollowUps schedules change point detection for the series of
+// the result and, if it has a parent, the summary of the trials of the
+// parent. The jobs are identified by the versions of the calculators as
+// well as by the result, so that they are not mistaken for the jobs
+// that followed the first calculation of the rollups of the result.
+func (j *rollupRecalculationJob) queueFollowUps(result *model.PerformanceResult, versions map[string]int) error {
+	q, err := j.env.GetQueue()
+	if err != nil {
+		return errors.Wrap(err, "problem getting queue")
+	}
+
+	key := perfResultRecalculationJobKey(result, versions)
+	catcher := grip.NewBasicCatcher()
+	catcher.Add(errors.Wrapf(PutJobOnce(q, makeChangePointDetectionJob(j.env, result, key)),
+		"problem scheduling change point detection for '%s'", result.ID))
+	if result.Info.Parent != "" {
+		catcher.Add(errors.Wrapf(PutJobOnce(q, makeTrialSummaryJob(j.env, result, key)),
+			"problem scheduling trial summary for the parent of '%s'", result.ID))
+	}
+
+	return catcher.Resolve()
+}
+
+// perfResultRecalculationJobKey identifies the result, as
+// perfResultJobKey does, and the versions of the calculators that
+// recalculated its rollups, in the ids of the jobs that follow the
+// recalculation.
+func perfResultRecalculationJobKey(result *model.PerformanceResult, versions map[string]int) string {
+	names := make([]string, 0, len(versions))
+	for name := range versions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	calculators := make([]string, len(names))
+	for i, name := range names {
+		calculators[i] = fmt.Sprintf("%s:%d", name, versions[name])
+	}
+
+	return fmt.Sprintf("%s-recalculated-%s", perfResultJobKey(result), strings.Join(calculators, ","))
+}
@@ -0,0 +1,134 @@
+package units
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollupRecalculationJob(t *testing.T) {
+	t.Run("Constructor", func(t *testing.T) {
+		scheduled := time.Now().Truncate(RollupRecalculationInterval)
+
+		j, ok := MakeRollupRecalculationJob(cedar.GetEnvironment(), scheduled).(*rollupRecalculationJob)
+		require.True(t, ok)
+		assert.Equal(t, rollupRecalculationJobName, j.Type().Name)
+		assert.Equal(t, j.ID(), MakeRollupRecalculationJob(cedar.GetEnvironment(), scheduled).ID())
+		assert.NotEqual(t, j.ID(), MakeRollupRecalculationJob(cedar.GetEnvironment(), scheduled.Add(RollupRecalculationInterval)).ID())
+	})
+	t.Run("FollowUpJobKey", func(t *testing.T) {
+		result := &model.PerformanceResult{ID: "foo"}
+		key := perfResultRecalculationJobKey(result, map[string]int{"b": 1, "a": 2})
+		assert.Equal(t, key, perfResultRecalculationJobKey(result, map[string]int{"a": 2, "b": 1}))
+		assert.NotEqual(t, key, perfResultRecalculationJobKey(result, map[string]int{"a": 3, "b": 1}))
+		assert.NotEqual(t, perfResultJobKey(result), key)
+		assert.NotEqual(t, key, perfResultRecalculationJobKey(&model.PerformanceResult{ID: "bar"}, map[string]int{"a": 2, "b": 1}))
+	})
+	t.Run("RecalculatesOnlyRollups", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		env, cleanup := setupUnitsTestDB(t)
+		defer cleanup()
+
+		tempDir, err := ioutil.TempDir("", "cedar-rollup-recalculation")
+		require.NoError(t, err)
+		defer os.RemoveAll(tempDir)
+
+		calculators := perf.RollupCalculators()
+		require.NotEmpty(t, calculators)
+		stale := model.PerfRollupValue{
+			Name:       "stale",
+			Value:      1.0,
+			Version:    calculators[0].Version - 1,
+			Calculator: calculators[0].Name,
+		}
+
+		// the converted result is recalculated, while the result
+		// with an unconverted artifact is skipped.
+		converted := model.ArtifactInfo{
+			Type:   model.PailLocal,
+			Bucket: tempDir,
+			Path:   "events.ftdc",
+			Format: model.FileFTDC,
+			Schema: model.SchemaRawEvents,
+		}
+		writeUnitsTestEvents(ctx, t, env, converted)
+		unconverted := model.ArtifactInfo{
+			Type:   model.PailLocal,
+			Bucket: tempDir,
+			Path:   "events.json",
+			Format: model.FileJSON,
+			Schema: model.SchemaRawEvents,
+		}
+
+		ids := []string{}
+		for idx, artifact := range []model.ArtifactInfo{converted, unconverted} {
+			result := model.CreatePerformanceResult(model.PerformanceResultInfo{
+				Project:  "project",
+				TestName: "test",
+				Parent:   "parent",
+				Trial:    idx,
+			}, []model.ArtifactInfo{artifact})
+			result.Rollups.Stats = []model.PerfRollupValue{stale}
+			result.Setup(env)
+			require.NoError(t, result.Save())
+			ids = append(ids, result.ID)
+		}
+
+		j := MakeRollupRecalculationJob(env, time.Now())
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		status := &model.PerfRollupRecalculationStatus{}
+		status.Setup(env)
+		require.NoError(t, status.Find())
+		assert.Equal(t, 1, status.Processed)
+		assert.Equal(t, 1, status.Skipped)
+		assert.Zero(t, status.Failed)
+
+		result := &model.PerformanceResult{ID: ids[0]}
+		result.Setup(env)
+		require.NoError(t, result.Find())
+		assert.True(t, result.Rollups.Valid)
+		assert.True(t, len(result.Rollups.Stats) > 1)
+		assert.Equal(t, len(result.Rollups.Stats), result.Rollups.Count)
+		// the current calculator does not produce the stale rollup,
+		// so it is removed rather than left to be recalculated.
+		_, err = result.Rollups.GetFloat(stale.Name)
+		assert.Error(t, err)
+		assert.Equal(t, 1, status.Remaining)
+
+		// only the skipped result is still stale, so running again
+		// does not recalculate any result.
+		j = MakeRollupRecalculationJob(env, time.Now().Add(RollupRecalculationInterval))
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+		require.NoError(t, status.Find())
+		assert.Equal(t, 1, status.Processed)
+		assert.Equal(t, 2, status.Skipped)
+		assert.Zero(t, status.Failed)
+		assert.Equal(t, 1, status.Remaining)
+
+		// only the work that depends on the values of the rollups
+		// follows the recalculation, once, with jobs that are distinct
+		// from those that followed the first calculation.
+		q, err := env.GetQueue()
+		require.NoError(t, err)
+		key := perfResultRecalculationJobKey(result, perf.RollupCalculatorVersions())
+		_, ok := q.Get(makeChangePointDetectionJob(env, result, key).ID())
+		assert.True(t, ok)
+		_, ok = q.Get(makeTrialSummaryJob(env, result, key).ID())
+		assert.True(t, ok)
+		_, ok = q.Get(MakeChangePointDetectionJob(env, result).ID())
+		assert.False(t, ok)
+		assert.Equal(t, 2, q.Stats().Total)
+	})
+}
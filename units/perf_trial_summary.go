@@ -0,0 +1,113 @@
+package units
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/evergreen-ci/cedar/perf"
+	"github.com/mongodb/amboy"
+	"github.com/mongodb/amboy/dependency"
+	"github.com/mongodb/amboy/job"
+	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/grip"
+	"github.com/mongodb/grip/message"
+	"github.com/pkg/errors"
+)
+
+const trialSummaryJobName = "perf-trial-summary"
+
+func init() {
+	registry.AddJobType(trialSummaryJobName, func() amboy.Job {
+		return trialSummaryJobFactory()
+	})
+}
+
+type trialSummaryJob struct {
+	ParentID  string `bson:"parent_id" json:"parent_id" yaml:"parent_id"`
+	*job.Base `bson:"metadata" json:"metadata" yaml:"metadata"`
+	env       cedar.Environment
+}
+
+func trialSummaryJobFactory() amboy.Job {
+	j := &trialSummaryJob{
+		Base: &job.Base{
+			JobType: amboy.JobType{
+				Name:    trialSummaryJobName,
+				Version: 1,
+			},
+		},
+		env: cedar.GetEnvironment(),
+	}
+	j.SetDependency(dependency.NewAlways())
+
+	return j
+}
+
+// MakeTrialSummaryJob returns a job that summarizes the rollups of
+// the children of the parent of the given trial across the trials of
+// each test, and replaces the trial summaries of the parent. The job
+// is identified by the trial and its artifacts, so that the parent is
+// summarized once for each trial.
+func MakeTrialSummaryJob(env cedar.Environment, trial *model.PerformanceResult) amboy.Job {
+	return makeTrialSummaryJob(env, trial, perfResultJobKey(trial))
+}
+
+func makeTrialSummaryJob(env cedar.Environment, trial *model.PerformanceResult, key string) amboy.Job {
+	j := trialSummaryJobFactory().(*trialSummaryJob)
+	j.SetID(fmt.Sprintf("%s-%s", j.Type().Name, key))
+	j.ParentID = trial.Info.Parent
+	j.env = env
+	return j
+}
+
+func (j *trialSummaryJob) Run(ctx context.Context) {
+	defer j.MarkComplete()
+
+	if j.ParentID == "" {
+		j.AddError(errors.New("cannot summarize trials without a parent result id"))
+		return
+	}
+
+	results := &model.PerformanceResults{}
+	results.Setup(j.env)
+	err := results.Find(model.PerfFindOptions{
+		Info:     model.PerformanceResultInfo{Parent: j.ParentID},
+		MaxDepth: 0,
+	})
+	if err != nil {
+		j.AddError(errors.Wrapf(err, "problem finding children of '%s'", j.ParentID))
+		return
+	}
+
+	var parent *model.PerformanceResult
+	children := []model.PerformanceResult{}
+	for i := range results.Results {
+		switch {
+		case results.Results[i].ID == j.ParentID:
+			parent = &results.Results[i]
+		case results.Results[i].Info.Parent == j.ParentID:
+			children = append(children, results.Results[i])
+		}
+	}
+	if parent == nil {
+		j.AddError(errors.Errorf("could not find performance result '%s'", j.ParentID))
+		return
+	}
+
+	summaries := perf.SummarizeTrials(children)
+	parent.Setup(j.env)
+	if err = parent.SetTrialSummaries(summaries); err != nil {
+		j.AddError(err)
+		return
+	}
+
+	grip.Debug(message.Fields{
+		"job":       j.ID(),
+		"perf_id":   j.ParentID,
+		"children":  len(children),
+		"summaries": len(summaries),
+		"message":   "summarized trials",
+	})
+}
@@ -0,0 +1,81 @@
+package units
+
+import (
+	"context"
+	"testing"
+
+	"github.com/evergreen-ci/cedar/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrialSummaryJob(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("SummarizesTrials", func(t *testing.T) {
+		env, cleanup := setupUnitsTestDB(t)
+		defer cleanup()
+
+		parent := model.CreatePerformanceResult(model.PerformanceResultInfo{Project: "project", TestName: "parent"}, nil)
+		parent.Setup(env)
+		require.NoError(t, parent.Save())
+
+		trials := []*model.PerformanceResult{}
+		for idx, value := range []float64{10, 20, 30} {
+			trial := model.CreatePerformanceResult(model.PerformanceResultInfo{
+				Project:  "project",
+				TestName: "test",
+				Parent:   parent.ID,
+				Trial:    idx,
+			}, nil)
+			trial.Rollups.Stats = []model.PerfRollupValue{
+				{Name: "latency", Value: value, MetricType: model.MetricTypeLatency},
+			}
+			trial.Setup(env)
+			require.NoError(t, trial.Save())
+			trials = append(trials, trial)
+		}
+		// results of other parents are not summarized.
+		other := model.CreatePerformanceResult(model.PerformanceResultInfo{Project: "project", TestName: "test", Parent: "other"}, nil)
+		other.Rollups.Stats = []model.PerfRollupValue{{Name: "latency", Value: 1000.0}}
+		other.Setup(env)
+		require.NoError(t, other.Save())
+
+		j, ok := MakeTrialSummaryJob(env, trials[0]).(*trialSummaryJob)
+		require.True(t, ok)
+		assert.Equal(t, parent.ID, j.ParentID)
+		j.Run(ctx)
+		require.NoError(t, j.Error())
+
+		require.NoError(t, parent.Find())
+		require.Len(t, parent.TrialSummaries, 1)
+		summary := parent.TrialSummaries[0]
+		assert.Equal(t, "test", summary.TestName)
+		assert.Equal(t, "latency", summary.Rollup)
+		assert.Equal(t, 3, summary.Trials)
+		assert.Equal(t, 20.0, summary.Mean)
+		assert.Equal(t, 20.0, summary.Median)
+		assert.Equal(t, 10.0, summary.Min)
+		assert.Equal(t, 30.0, summary.Max)
+		assert.True(t, summary.ConfidenceLower < summary.Mean)
+		assert.True(t, summary.ConfidenceUpper > summary.Mean)
+		assert.False(t, parent.TrialSummariesUpdatedAt.IsZero())
+	})
+	t.Run("MissingParent", func(t *testing.T) {
+		env, cleanup := setupUnitsTestDB(t)
+		defer cleanup()
+
+		trial := model.CreatePerformanceResult(model.PerformanceResultInfo{Project: "project", TestName: "test", Parent: "DNE"}, nil)
+		j := MakeTrialSummaryJob(env, trial)
+		j.Run(ctx)
+		assert.True(t, j.Status().Completed)
+		assert.Error(t, j.Error())
+	})
+	t.Run("NoParent", func(t *testing.T) {
+		j := trialSummaryJobFactory().(*trialSummaryJob)
+		j.Run(ctx)
+		assert.True(t, j.Status().Completed)
+		assert.Error(t, j.Error())
+	})
+}
@@ -10,7 +10,6 @@ import (
 
 	"github.com/evergreen-ci/cedar"
 	"github.com/evergreen-ci/cedar/model"
-	"github.com/evergreen-ci/pail"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/dependency"
 	"github.com/mongodb/amboy/job"
@@ -105,7 +104,7 @@ func (j *mergeSimpleLogJob) Run(ctx context.Context) {
 		return
 	}
 
-	bucket, err := pail.NewS3Bucket(pail.S3Options{Name: conf.BucketName})
+	bucket, err := model.PailS3.Create(j.env, conf.BucketName)
 	if err != nil {
 		j.AddError(errors.WithStack(err))
 		return
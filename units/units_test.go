@@ -1,12 +1,20 @@
 package units
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
 	"testing"
+	"time"
 
+	"github.com/evergreen-ci/cedar"
+	"github.com/evergreen-ci/cedar/model"
 	"github.com/mongodb/amboy"
 	"github.com/mongodb/amboy/registry"
+	"github.com/mongodb/ftdc/events"
 	"github.com/mongodb/grip"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAllRegisteredUnitsAreRemoteSafe(t *testing.T) {
@@ -35,3 +43,67 @@ func TestAllRegisteredUnitsAreRemoteSafe(t *testing.T) {
 		}
 	}
 }
+
+// setupUnitsTestDB configures the environment with a test database for
+// the tests of jobs that read from and write to the database, and
+// returns a function that drops the database. The queue of the
+// environment accepts jobs but never runs them, so that the tests can
+// check the jobs that the job under test schedules.
+func setupUnitsTestDB(t *testing.T) (cedar.Environment, func()) {
+	env := cedar.GetEnvironment()
+	require.NoError(t, env.Configure(&cedar.Configuration{
+		MongoDBURI:    "mongodb://localhost:27017",
+		DatabaseName:  "cedar_test_units",
+		NumWorkers:    2,
+		UseLocalQueue: true,
+	}))
+	q, err := env.GetQueue()
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, q.Start(ctx))
+
+	return env, func() {
+		conf, session, err := cedar.GetSessionWithConfig(env)
+		require.NoError(t, err)
+		defer session.Close()
+		if err := session.DB(conf.DatabaseName).DropDatabase(); err != nil {
+			assert.Contains(t, err.Error(), "not found")
+		}
+	}
+}
+
+// saveUnitsTestConfig saves the application configuration described by
+// the YAML document to the test database.
+func saveUnitsTestConfig(t *testing.T, env cedar.Environment, doc string) {
+	file, err := ioutil.TempFile("", "cedar-config")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(doc)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	conf, err := model.LoadCedarConfig(file.Name())
+	require.NoError(t, err)
+	conf.Setup(env)
+	require.NoError(t, conf.Save())
+}
+
+// writeUnitsTestEvents writes a few raw performance events to the FTDC
+// artifact.
+func writeUnitsTestEvents(ctx context.Context, t *testing.T, env cedar.Environment, artifact model.ArtifactInfo) {
+	w, err := artifact.Writer(ctx, env)
+	require.NoError(t, err)
+
+	stream := make(chan events.Performance, 3)
+	for i := 0; i < 3; i++ {
+		point := events.Performance{Timestamp: time.Now().Add(time.Duration(i) * time.Second)}
+		point.Counters.Operations = int64(10 * (i + 1))
+		point.Counters.Number = int64(i + 1)
+		point.Timers.Duration = time.Duration(i+1) * time.Millisecond
+		stream <- point
+	}
+	close(stream)
+	require.NoError(t, model.DumpPerformanceSeries(ctx, stream, nil, w))
+	require.NoError(t, w.Close())
+}